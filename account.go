@@ -0,0 +1,243 @@
+package giota
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+)
+
+// AccountData is the state of the addresses of a seed.
+type AccountData struct {
+	// Addresses are the used addresses of the account.
+	Addresses []Address
+	// LatestAddress is the first unused address of the account.
+	LatestAddress Address
+	// Balances are the non-zero balances of Addresses.
+	Balances Balances
+	// Bundles are all bundles which touch Addresses.
+	Bundles Bundles
+	// Confirmed is true for the bundle hashes of confirmed Bundles.
+	Confirmed map[BundleHash]bool
+}
+
+// GetAccountData scans the addresses of seed until the first unused one and
+// collects their balances and the bundles which touch them.
+func GetAccountData(api APIClient, seed Trytes, security SecurityLevel) (*AccountData, error) {
+	latest, used, err := GetUsedAddress(api, seed, security)
+	if err != nil {
+		return nil, err
+	}
+
+	ad := &AccountData{
+		Addresses:     used,
+		LatestAddress: latest,
+	}
+	if err := ad.load(api); err != nil {
+		return nil, err
+	}
+	return ad, nil
+}
+
+// load collects the balances and bundles of ad.Addresses.
+func (ad *AccountData) load(api APIClient) error {
+	ad.Confirmed = make(map[BundleHash]bool)
+	if len(ad.Addresses) == 0 {
+		return nil
+	}
+
+	err := apiOf(api).fanOut(
+		func() (err error) {
+			ad.Balances, err = api.Balances(ad.Addresses)
+			return err
+		},
+		func() (err error) {
+			ad.Bundles, err = api.GetBundlesFromAddresses(ad.Addresses)
+			return err
+		},
+	)
+	if err != nil || len(ad.Bundles) == 0 {
+		return err
+	}
+
+	tails := make([]Hash, len(ad.Bundles))
+	for i, b := range ad.Bundles {
+		tails[i] = b[0].Hash()
+	}
+
+	states, err := api.GetLatestInclusion(tails)
+	if err != nil {
+		return err
+	}
+
+	for i, b := range ad.Bundles {
+		if i < len(states) && states[i] {
+			ad.Confirmed[b[0].Bundle] = true
+		}
+	}
+	return nil
+}
+
+// WatchOnlyAccount is an account of addresses without their seed, e.g. of a
+// cold wallet. It can track balances and transfers, but not send.
+type WatchOnlyAccount struct {
+	Addresses []Address
+}
+
+// NewWatchOnlyAccount returns the account of adrs, which may have checksums.
+// Duplicates are dropped.
+func NewWatchOnlyAccount(adrs ...Trytes) (*WatchOnlyAccount, error) {
+	w := &WatchOnlyAccount{}
+	seen := make(map[Address]bool, len(adrs))
+	for i, t := range adrs {
+		adr, err := parseAddress(string(t))
+		if err != nil {
+			return nil, &ValidationError{Func: "NewWatchOnlyAccount", Arg: "adrs", Index: i, Err: err}
+		}
+		if !seen[adr] {
+			seen[adr] = true
+			w.Addresses = append(w.Addresses, adr)
+		}
+	}
+	return w, nil
+}
+
+// NewWatchOnlyAccountFromDigests returns the account of the addresses of ds,
+// e.g. exported by NewAddressDigest on an offline device.
+func NewWatchOnlyAccountFromDigests(ds ...*AddressDigest) (*WatchOnlyAccount, error) {
+	adrs := make([]Trytes, len(ds))
+	for i, d := range ds {
+		adr, err := d.Address()
+		if err != nil {
+			return nil, &ValidationError{Func: "NewWatchOnlyAccountFromDigests", Arg: "ds", Index: i, Err: err}
+		}
+		adrs[i] = Trytes(adr)
+	}
+	return NewWatchOnlyAccount(adrs...)
+}
+
+// AccountData collects the balances of the addresses of w and the bundles
+// which touch them like GetAccountData. Its LatestAddress is empty, and
+// Addresses are all addresses of w, used or not.
+func (w *WatchOnlyAccount) AccountData(api APIClient) (*AccountData, error) {
+	ad := &AccountData{Addresses: w.Addresses}
+	if err := ad.load(api); err != nil {
+		return nil, err
+	}
+	return ad, nil
+}
+
+// Direction is the direction of a transfer seen from an account.
+type Direction string
+
+// Directions of transfers.
+const (
+	DirectionSent     Direction = "sent"
+	DirectionReceived Direction = "received"
+)
+
+// HistoryEntry is a single transfer of an account.
+type HistoryEntry struct {
+	Timestamp time.Time  `json:"timestamp"`
+	Bundle    BundleHash `json:"bundle"`
+	Direction Direction  `json:"direction"`
+	Value     int64      `json:"value"`
+	// Address is the counterpart: the first foreign output of sent transfers
+	// and the first input of received ones.
+	Address   Address `json:"address"`
+	Confirmed bool    `json:"confirmed"`
+}
+
+// History returns the transfer history of the account, one entry per bundle.
+func (ad *AccountData) History() []HistoryEntry {
+	adrs := make([]Trytes, len(ad.Addresses))
+	for i, adr := range ad.Addresses {
+		adrs[i] = Trytes(adr)
+	}
+
+	hs := make([]HistoryEntry, 0, len(ad.Bundles))
+	for _, b := range ad.Bundles {
+		if len(b) == 0 {
+			continue
+		}
+
+		c := b.CategorizeAddresses(adrs...)
+
+		h := HistoryEntry{
+			Timestamp: b[0].Timestamp,
+			Bundle:    b[0].Bundle,
+			Direction: DirectionReceived,
+			Confirmed: ad.Confirmed[b[0].Bundle],
+		}
+
+		own := AddressFilter(ad.Addresses...)
+		switch {
+		case len(c.Sent) > 0:
+			h.Direction = DirectionSent
+			for _, tx := range b {
+				if tx.Value <= 0 || own.Match(&tx) {
+					continue
+				}
+				if h.Address == "" {
+					h.Address = tx.Address
+				}
+				h.Value += tx.Value
+			}
+		default:
+			for _, tx := range c.Received {
+				h.Value += tx.Value
+			}
+			for _, tx := range b {
+				if tx.Value < 0 {
+					h.Address = tx.Address
+					break
+				}
+			}
+		}
+		hs = append(hs, h)
+	}
+	return hs
+}
+
+// Incoming returns the entries of History received by the account.
+func (ad *AccountData) Incoming() []HistoryEntry {
+	var in []HistoryEntry
+	for _, h := range ad.History() {
+		if h.Direction == DirectionReceived {
+			in = append(in, h)
+		}
+	}
+	return in
+}
+
+// ExportJSON writes History as a JSON array to w.
+func (ad *AccountData) ExportJSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(ad.History())
+}
+
+// ExportCSV writes History as CSV with a header line to w.
+func (ad *AccountData) ExportCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	err := cw.Write([]string{"timestamp", "bundle", "direction", "value", "address", "confirmed"})
+	if err != nil {
+		return err
+	}
+
+	for _, h := range ad.History() {
+		err = cw.Write([]string{
+			h.Timestamp.UTC().Format(time.RFC3339),
+			string(h.Bundle),
+			string(h.Direction),
+			strconv.FormatInt(h.Value, 10),
+			string(h.Address),
+			strconv.FormatBool(h.Confirmed),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,172 @@
+package giota
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+const accountTestSeed Trytes = "WQNZOHUT99PWKEBFSKQSYNC9XHT9GEBMOSJAQDQAXPEZPJNDIUB9TSNWVMHKWICW9WVZXSMDFGISOD9FZ"
+
+// newAccountTestNode returns a fake node on which the first address of
+// accountTestSeed has received 100i with bundle bs.
+func newAccountTestNode(t *testing.T, bs Bundle) (*API, func()) {
+	adr, err := NewAddress(accountTestSeed, 0, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txs := make(map[Hash]Transaction)
+	var hashes []Hash
+	for _, tx := range bs {
+		txs[tx.Hash()] = tx
+		hashes = append(hashes, tx.Hash())
+	}
+
+	return newFakeNode(t, map[string]fakeNodeHandler{
+		"findTransactions": func(req map[string]json.RawMessage) interface{} {
+			var adrs []Address
+			json.Unmarshal(req["addresses"], &adrs)
+			if len(req["bundles"]) == 0 && (len(adrs) == 0 || adrs[0] != adr) {
+				return &FindTransactionsResponse{Hashes: []Hash{}}
+			}
+			return &FindTransactionsResponse{Hashes: hashes}
+		},
+		"getTrytes": func(req map[string]json.RawMessage) interface{} {
+			var hs []Hash
+			json.Unmarshal(req["hashes"], &hs)
+			resp := &GetTrytesResponse{}
+			for _, h := range hs {
+				resp.Trytes = append(resp.Trytes, txs[h])
+			}
+			return resp
+		},
+		"getBalances": func(req map[string]json.RawMessage) interface{} {
+			return map[string]interface{}{"balances": []string{"100"}}
+		},
+		"getNodeInfo": func(map[string]json.RawMessage) interface{} {
+			return &GetNodeInfoResponse{LatestMilestone: EmptyHash}
+		},
+		"getInclusionStates": func(map[string]json.RawMessage) interface{} {
+			return &GetInclusionStatesResponse{States: []bool{true}}
+		},
+	})
+}
+
+func TestAccountDataHistory(t *testing.T) {
+	adr, err := NewAddress(accountTestSeed, 0, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var bs Bundle
+	bs.Add(1, adr, 100, time.Unix(1500000000, 0), "")
+	bs.Add(2, filterAddr2, -100, time.Unix(1500000000, 0), "")
+	bs.Finalize(nil)
+
+	api, done := newAccountTestNode(t, bs)
+	defer done()
+
+	ad, err := GetAccountData(api, accountTestSeed, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	switch {
+	case len(ad.Addresses) != 1 || ad.Addresses[0] != adr:
+		t.Fatalf("GetAccountData() returned addresses %v", ad.Addresses)
+	case ad.Balances.Total() != 100:
+		t.Errorf("GetAccountData() returned balance %d, expected 100", ad.Balances.Total())
+	case len(ad.Bundles) != 1 || len(ad.Bundles[0]) != 3:
+		t.Fatalf("GetAccountData() returned %d bundles, expected 1", len(ad.Bundles))
+	}
+
+	hs := ad.History()
+	if len(hs) != 1 {
+		t.Fatalf("History() returned %d entries, expected 1", len(hs))
+	}
+
+	want := HistoryEntry{
+		Timestamp: time.Unix(1500000000, 0),
+		Bundle:    bs[0].Bundle,
+		Direction: DirectionReceived,
+		Value:     100,
+		Address:   filterAddr2,
+		Confirmed: true,
+	}
+	if !hs[0].Timestamp.Equal(want.Timestamp) || hs[0].Bundle != want.Bundle || hs[0].Direction != want.Direction ||
+		hs[0].Value != want.Value || hs[0].Address != want.Address || hs[0].Confirmed != want.Confirmed {
+		t.Errorf("History() returned %+v, expected %+v", hs[0], want)
+	}
+
+	var buf bytes.Buffer
+	if err := ad.ExportCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || lines[1] != "2017-07-14T02:40:00Z,"+string(bs[0].Bundle)+",received,100,"+string(filterAddr2)+",true" {
+		t.Errorf("ExportCSV() returned unexpected output:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := ad.ExportJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var out []HistoryEntry
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil || len(out) != 1 || out[0].Value != 100 {
+		t.Errorf("ExportJSON() returned unexpected output %s (%v)", buf.String(), err)
+	}
+}
+
+func TestWatchOnlyAccount(t *testing.T) {
+	adr, err := NewAddress(accountTestSeed, 0, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dg, err := NewAddressDigest(accountTestSeed, 0, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var bs Bundle
+	bs.Add(1, adr, 100, time.Unix(1500000000, 0), "")
+	bs.Add(2, filterAddr2, -100, time.Unix(1500000000, 0), "")
+	bs.Finalize(nil)
+
+	fromAdrs, err := NewWatchOnlyAccount(adr.WithChecksum(), Trytes(adr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromDigests, err := NewWatchOnlyAccountFromDigests(dg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, w := range []*WatchOnlyAccount{fromAdrs, fromDigests} {
+		api, done := newAccountTestNode(t, bs)
+		ad, err := w.AccountData(api)
+		done()
+
+		switch {
+		case err != nil:
+			t.Fatal(err)
+		case len(ad.Addresses) != 1 || ad.Addresses[0] != adr || ad.LatestAddress != "":
+			t.Errorf("AccountData() returned addresses %v, latest %q", ad.Addresses, ad.LatestAddress)
+		case ad.Balances.Total() != 100:
+			t.Errorf("AccountData() returned balance %d, expected 100", ad.Balances.Total())
+		case len(ad.Incoming()) != 1 || ad.Incoming()[0].Value != 100 || !ad.Incoming()[0].Confirmed:
+			t.Errorf("Incoming() returned %+v", ad.Incoming())
+		}
+	}
+
+	if _, err := NewWatchOnlyAccount(Trytes(adr) + "999999999"); err == nil {
+		t.Error("NewWatchOnlyAccount() accepted a bad checksum")
+	}
+	if _, err := NewWatchOnlyAccountFromDigests(&AddressDigest{Security: 2, Digest: "ABC"}); err == nil {
+		t.Error("NewWatchOnlyAccountFromDigests() accepted a bad digest")
+	}
+}
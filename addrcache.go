@@ -0,0 +1,121 @@
+package giota
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"sync"
+)
+
+// AddressKey identifies a derived address in an AddressCache. The seed is
+// only kept as its SHA-256 digest, which doesn't reveal it.
+type AddressKey struct {
+	SeedDigest [sha256.Size]byte
+	Index      uint64
+	Security   SecurityLevel
+}
+
+// NewAddressKey returns the key of the address of seed at index with
+// security.
+func NewAddressKey(seed Trytes, index uint64, security SecurityLevel) AddressKey {
+	return AddressKey{
+		SeedDigest: sha256.Sum256([]byte(seed)),
+		Index:      index,
+		Security:   security,
+	}
+}
+
+// AddressCache keeps derived addresses by their AddressKey. As the
+// derivation never changes, a cached address is as good as a derived one.
+// It must be safe for concurrent use.
+type AddressCache interface {
+	Get(key AddressKey) (Address, bool)
+	Add(key AddressKey, adr Address)
+}
+
+// addressCache is the cache set by SetAddressCache, or nil.
+var addressCache AddressCache
+
+// SetAddressCache makes NewAddress look up addresses in c before deriving
+// them, and add the derived ones to c. This covers NewAddresses and the
+// funcs scanning the addresses of seeds, like GetUsedAddress, GetInputs,
+// DiscoverAccountState and AddressIndexResolver. A nil c, the default,
+// disables the cache. SetAddressCache must not be called concurrently with
+// the derivation of addresses.
+func SetAddressCache(c AddressCache) {
+	addressCache = c
+}
+
+// cachedAddress returns the cached address of key, if any.
+func cachedAddress(key AddressKey) (Address, bool) {
+	if addressCache == nil {
+		return "", false
+	}
+	return addressCache.Get(key)
+}
+
+// cacheAddress adds adr to the cache, if any.
+func cacheAddress(key AddressKey, adr Address) {
+	if addressCache != nil {
+		addressCache.Add(key, adr)
+	}
+}
+
+// LRUAddressCache is an AddressCache of a fixed size, which drops the least
+// recently used addresses.
+type LRUAddressCache struct {
+	size int
+
+	mu    sync.Mutex
+	order *list.List
+	adrs  map[AddressKey]*list.Element
+}
+
+type lruAddress struct {
+	key AddressKey
+	adr Address
+}
+
+// NewLRUAddressCache returns a cache keeping up to size addresses.
+func NewLRUAddressCache(size int) *LRUAddressCache {
+	return &LRUAddressCache{
+		size:  size,
+		order: list.New(),
+		adrs:  make(map[AddressKey]*list.Element),
+	}
+}
+
+// Get returns the address of key, if it is cached.
+func (c *LRUAddressCache) Get(key AddressKey) (Address, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.adrs[key]
+	if !ok {
+		return "", false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*lruAddress).adr, true
+}
+
+// Add caches adr, dropping the least recently used address if the cache is
+// full.
+func (c *LRUAddressCache) Add(key AddressKey, adr Address) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.adrs[key]; ok {
+		c.order.MoveToFront(e)
+		return
+	}
+	c.adrs[key] = c.order.PushFront(&lruAddress{key: key, adr: adr})
+	for c.order.Len() > c.size {
+		e := c.order.Back()
+		c.order.Remove(e)
+		delete(c.adrs, e.Value.(*lruAddress).key)
+	}
+}
+
+// Len returns the number of cached addresses.
+func (c *LRUAddressCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
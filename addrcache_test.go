@@ -0,0 +1,77 @@
+package giota
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLRUAddressCache(t *testing.T) {
+	c := NewLRUAddressCache(2)
+	keys := []AddressKey{
+		NewAddressKey(accountTestSeed, 0, SecurityLevelMedium),
+		NewAddressKey(accountTestSeed, 1, SecurityLevelMedium),
+		NewAddressKey(accountTestSeed, 0, SecurityLevelHigh),
+	}
+
+	c.Add(keys[0], filterAddr1)
+	c.Add(keys[1], filterAddr2)
+	c.Get(keys[0])
+	c.Add(keys[2], filterAddr1)
+
+	tests := []struct {
+		key AddressKey
+		adr Address
+		ok  bool
+	}{
+		{key: keys[0], adr: filterAddr1, ok: true},
+		{key: keys[1], ok: false},
+		{key: keys[2], adr: filterAddr1, ok: true},
+	}
+	for i, tt := range tests {
+		if adr, ok := c.Get(tt.key); ok != tt.ok || adr != tt.adr {
+			t.Errorf("Get(keys[%d]) returned %s, %t", i, adr, ok)
+		}
+	}
+	if c.Len() != 2 {
+		t.Errorf("cache holds %d addresses, expected 2", c.Len())
+	}
+}
+
+func TestAddressCache(t *testing.T) {
+	want, err := NewAddress(accountTestSeed, 3, SecurityLevelMedium)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewLRUAddressCache(100)
+	SetAddressCache(c)
+	defer SetAddressCache(nil)
+
+	adrs, err := NewAddresses(accountTestSeed, 0, 4, SecurityLevelMedium)
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case adrs[3] != want:
+		t.Errorf("NewAddresses() returned %s, expected %s", adrs[3], want)
+	case c.Len() != 4:
+		t.Errorf("cache holds %d addresses, expected 4", c.Len())
+	}
+
+	// a planted address proves that the cache is consulted
+	planted := Address(strings.Repeat("A", 81))
+	for _, sec := range []SecurityLevel{SecurityLevelLow, SecurityLevelMedium, SecurityLevelHigh} {
+		c.Add(NewAddressKey(accountTestSeed, 7, sec), planted)
+	}
+	if adr, err := NewAddress(accountTestSeed, 7, SecurityLevelMedium); err != nil || adr != planted {
+		t.Errorf("NewAddress() returned %s, %v, expected the cached address", adr, err)
+	}
+
+	r := &AddressIndexResolver{Seed: accountTestSeed, MaxIndex: 8, Workers: 1}
+	info, err := r.Resolve(Trytes(planted))
+	if err != nil || info.Index != 7 {
+		t.Errorf("Resolve() returned %+v, %v, expected the cached index 7", info, err)
+	}
+	if _, ok := c.Get(NewAddressKey(accountTestSeed, 5, SecurityLevelHigh)); !ok {
+		t.Error("Resolve() didn't cache the derived addresses")
+	}
+}
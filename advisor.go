@@ -0,0 +1,155 @@
+package giota
+
+import (
+	"errors"
+	"time"
+)
+
+// ResendAdvice is the recommended action for an unconfirmed tail transaction.
+type ResendAdvice int
+
+// Advices returned by EstimateConfirmation.
+const (
+	// AdviceNone means the tail is already confirmed.
+	AdviceNone ResendAdvice = iota
+	// AdviceWait means the tail is likely to be confirmed without any action.
+	AdviceWait
+	// AdvicePromote means the tail should be promoted.
+	AdvicePromote
+	// AdviceReattach means the tail can't be confirmed anymore and the bundle
+	// must be reattached.
+	AdviceReattach
+)
+
+func (a ResendAdvice) String() string {
+	switch a {
+	case AdviceNone:
+		return "none"
+	case AdviceWait:
+		return "wait"
+	case AdvicePromote:
+		return "promote"
+	case AdviceReattach:
+		return "reattach"
+	}
+	return "unknown"
+}
+
+// ResendParams are the heuristics used by EstimateConfirmation.
+type ResendParams struct {
+	// PromoteAfter is the attachment age after which a tail with too few
+	// approvers should be promoted.
+	PromoteAfter time.Duration
+	// Depth decides when a tail is below the max depth of tip selection
+	// and must be reattached.
+	Depth AttachmentDepthPolicy
+	// MinApprovers is the number of direct approvers at which a tail is
+	// considered to be well on its way to confirmation.
+	MinApprovers int
+	// MinWeightMagnitude is the current MWM of the network. Tails with less
+	// weight are not accepted by the nodes and must be reattached.
+	MinWeightMagnitude int64
+}
+
+// DefaultResendParams are used by EstimateConfirmation if no params are given.
+var DefaultResendParams = ResendParams{
+	PromoteAfter:       2 * time.Minute,
+	Depth:              DefaultDepthPolicy,
+	MinApprovers:       2,
+	MinWeightMagnitude: DefaultMinWeightMagnitude,
+}
+
+// ConfirmationEstimate is the result of EstimateConfirmation.
+type ConfirmationEstimate struct {
+	Tail       Hash
+	Confirmed  bool
+	Consistent bool
+	// Age is the time since the tail was attached.
+	Age time.Duration
+	// Approvers is the number of direct approvers of the tail.
+	Approvers int
+	// Likelihood is a rough estimate between 0 and 1 of the tail getting
+	// confirmed without further action.
+	Likelihood float64
+	Advice     ResendAdvice
+}
+
+// EstimateConfirmation estimates how likely tail is to be confirmed and
+// recommends whether to wait, promote or reattach. If p is nil,
+// DefaultResendParams is used.
+func (api *API) EstimateConfirmation(tail Hash, p *ResendParams) (*ConfirmationEstimate, error) {
+	if p == nil {
+		p = &DefaultResendParams
+	}
+
+	inc, err := api.GetLatestInclusion([]Hash{tail})
+	if err != nil {
+		return nil, err
+	}
+
+	est := &ConfirmationEstimate{
+		Tail:       tail,
+		Consistent: true,
+		Likelihood: 1,
+		Advice:     AdviceNone,
+	}
+	if inc[0] {
+		est.Confirmed = true
+		return est, nil
+	}
+
+	gt, err := api.GetTrytes([]Hash{tail})
+	if err != nil {
+		return nil, err
+	}
+	if len(gt.Trytes) == 0 {
+		return nil, errors.New("tail transaction is not found while GetTrytes")
+	}
+	tx := &gt.Trytes[0]
+
+	at := tx.AttachmentTime()
+	if at.IsZero() {
+		at = tx.Timestamp
+	}
+	est.Age = time.Since(at)
+
+	cc, err := api.CheckConsistency([]Hash{tail})
+	if err != nil {
+		return nil, err
+	}
+	est.Consistent = cc.State
+
+	ft, err := api.FindTransactions(&FindTransactionsRequest{Approvees: []Hash{tail}})
+	if err != nil {
+		return nil, err
+	}
+	est.Approvers = len(ft.Hashes)
+
+	estimateAdvice(est, tx, p)
+	return est, nil
+}
+
+func estimateAdvice(est *ConfirmationEstimate, tx *Transaction, p *ResendParams) {
+	switch {
+	case !est.Consistent, est.Age < 0, est.Age >= p.Depth.MaxAge(), !tx.HasValidNonce(p.MinWeightMagnitude):
+		est.Likelihood = 0
+		est.Advice = AdviceReattach
+		return
+	}
+
+	// the remaining time until the max depth and the approvers both
+	// increase the chance of being confirmed.
+	remaining := 1 - float64(est.Age)/float64(p.Depth.MaxAge())
+	approval := 1.0
+	if p.MinApprovers > 0 && est.Approvers < p.MinApprovers {
+		approval = float64(est.Approvers+1) / float64(p.MinApprovers+1)
+	}
+	est.Likelihood = remaining * approval
+
+	switch {
+	case est.Age >= p.PromoteAfter && est.Approvers < p.MinApprovers:
+		est.Advice = AdvicePromote
+	default:
+		est.Advice = AdviceWait
+	}
+}
@@ -0,0 +1,78 @@
+package giota
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestEstimateAdvice(t *testing.T) {
+	p := DefaultResendParams
+	p.MinWeightMagnitude = 0
+
+	tests := []struct {
+		name       string
+		consistent bool
+		age        time.Duration
+		approvers  int
+		advice     ResendAdvice
+	}{
+		{name: "fresh", consistent: true, age: time.Minute, approvers: 0, advice: AdviceWait},
+		{name: "approved", consistent: true, age: 5 * time.Minute, approvers: 3, advice: AdviceWait},
+		{name: "stale", consistent: true, age: 5 * time.Minute, approvers: 0, advice: AdvicePromote},
+		{name: "too old", consistent: true, age: 20 * time.Minute, approvers: 3, advice: AdviceReattach},
+		{name: "inconsistent", consistent: false, age: time.Minute, approvers: 3, advice: AdviceReattach},
+	}
+
+	for _, tt := range tests {
+		est := &ConfirmationEstimate{Consistent: tt.consistent, Age: tt.age, Approvers: tt.approvers}
+		estimateAdvice(est, &Transaction{}, &p)
+
+		if est.Advice != tt.advice {
+			t.Errorf("%s: advice is %s, expected %s", tt.name, est.Advice, tt.advice)
+		}
+		if est.Likelihood < 0 || est.Likelihood > 1 || (tt.advice == AdviceReattach) != (est.Likelihood == 0) {
+			t.Errorf("%s: likelihood %f is out of range", tt.name, est.Likelihood)
+		}
+	}
+}
+
+func TestEstimateConfirmation(t *testing.T) {
+	bs := filterTestBundle()
+	bs[0].AttachmentTimestamp = Int2Trits(time.Now().Add(-5*time.Minute).UnixNano()/int64(time.Millisecond), TimestampTrinarySize).Trytes()
+
+	api, done := newFakeNode(t, map[string]fakeNodeHandler{
+		"getTrytes": func(map[string]json.RawMessage) interface{} {
+			return &GetTrytesResponse{Trytes: bs[:1]}
+		},
+		"getNodeInfo": func(map[string]json.RawMessage) interface{} {
+			return &GetNodeInfoResponse{LatestMilestone: EmptyHash}
+		},
+		"getInclusionStates": func(map[string]json.RawMessage) interface{} {
+			return &GetInclusionStatesResponse{States: []bool{false}}
+		},
+		"checkConsistency": func(map[string]json.RawMessage) interface{} {
+			return &CheckConsistencyResponse{State: true}
+		},
+		"findTransactions": func(map[string]json.RawMessage) interface{} {
+			return &FindTransactionsResponse{Hashes: []Hash{}}
+		},
+	})
+	defer done()
+
+	p := DefaultResendParams
+	p.MinWeightMagnitude = 0
+	est, err := api.EstimateConfirmation(bs[0].Hash(), &p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	switch {
+	case est.Confirmed:
+		t.Error("EstimateConfirmation() returned a confirmed tail")
+	case est.Age < 4*time.Minute || est.Age > 6*time.Minute:
+		t.Errorf("EstimateConfirmation() returned age %s", est.Age)
+	case est.Advice != AdvicePromote:
+		t.Errorf("EstimateConfirmation() advised %s, expected promote", est.Advice)
+	}
+}
@@ -0,0 +1,165 @@
+package giota
+
+import (
+	"errors"
+)
+
+// SeedAccount is a seed of an AggregateAccount with the security level of
+// its addresses.
+type SeedAccount struct {
+	Seed     Trytes
+	Security SecurityLevel
+}
+
+// AggregateAccount combines the addresses of several seeds and watch-only
+// accounts, e.g. of wallets migrated to a new seed, into one account.
+type AggregateAccount struct {
+	Seeds     []SeedAccount
+	WatchOnly []*WatchOnlyAccount
+}
+
+// NewAggregateAccount returns the account of seeds.
+func NewAggregateAccount(seeds ...SeedAccount) (*AggregateAccount, error) {
+	for i, s := range seeds {
+		if err := s.Security.IsValid(); err != nil {
+			return nil, &ValidationError{Func: "NewAggregateAccount", Arg: "seeds", Index: i, Err: err}
+		}
+		if err := s.Seed.IsValid(); err != nil || len(s.Seed) != 81 {
+			return nil, &ValidationError{Func: "NewAggregateAccount", Arg: "seeds", Index: i, Err: errors.New("seed must be 81 trytes")}
+		}
+	}
+	return &AggregateAccount{Seeds: seeds}, nil
+}
+
+// AggregateData is the state of an AggregateAccount.
+type AggregateData struct {
+	// AccountData is the unified state of all seeds and watch-only
+	// accounts. Its LatestAddress is the one of the first seed, and
+	// bundles touching several seeds are included once.
+	*AccountData
+	// Seeds are the states of the seeds, in the order of the seeds of the
+	// account.
+	Seeds []*AccountData
+
+	seeds []SeedAccount
+}
+
+// AccountData collects the state of all seeds of a, like GetAccountData, and
+// the state of its watch-only accounts.
+func (a *AggregateAccount) AccountData(api APIClient) (*AggregateData, error) {
+	d := &AggregateData{
+		AccountData: &AccountData{Confirmed: make(map[BundleHash]bool)},
+		seeds:       a.Seeds,
+	}
+	for _, s := range a.Seeds {
+		ad, err := GetAccountData(api, s.Seed, s.Security)
+		if err != nil {
+			return nil, err
+		}
+		d.Seeds = append(d.Seeds, ad)
+		d.add(ad)
+	}
+	if len(d.Seeds) > 0 {
+		d.LatestAddress = d.Seeds[0].LatestAddress
+	}
+
+	for _, w := range a.WatchOnly {
+		ad, err := w.AccountData(api)
+		if err != nil {
+			return nil, err
+		}
+		d.add(ad)
+	}
+	return d, nil
+}
+
+// add merges ad into the unified state.
+func (d *AggregateData) add(ad *AccountData) {
+	known := make(map[Address]bool, len(d.Addresses))
+	for _, adr := range d.Addresses {
+		known[adr] = true
+	}
+	for _, adr := range ad.Addresses {
+		if !known[adr] {
+			known[adr] = true
+			d.Addresses = append(d.Addresses, adr)
+		}
+	}
+
+	counted := make(map[Address]bool, len(d.Balances))
+	for _, b := range d.Balances {
+		counted[b.Address] = true
+	}
+	for _, b := range ad.Balances {
+		if !counted[b.Address] {
+			d.Balances = append(d.Balances, b)
+		}
+	}
+
+	bundles := make(map[BundleHash]bool, len(d.Bundles))
+	for _, b := range d.Bundles {
+		bundles[b[0].Bundle] = true
+	}
+	for _, b := range ad.Bundles {
+		if len(b) > 0 && !bundles[b[0].Bundle] {
+			bundles[b[0].Bundle] = true
+			d.Bundles = append(d.Bundles, b)
+		}
+	}
+	for h, c := range ad.Confirmed {
+		d.Confirmed[h] = d.Confirmed[h] || c
+	}
+}
+
+// ConsolidationTransfer is a transfer of the balances of some addresses of a
+// seed to the consolidation address.
+type ConsolidationTransfer struct {
+	Seed     SeedAccount
+	Inputs   []AddressInfo
+	Transfer Transfer
+}
+
+// Prepare prepares the bundle of c. It has no remainder.
+func (c *ConsolidationTransfer) Prepare(api APIClient) (Bundle, error) {
+	return PrepareTransfers(api, c.Seed.Seed, []Transfer{c.Transfer}, c.Inputs, "", c.Seed.Security)
+}
+
+// PlanConsolidation returns the transfers moving the balances of all seeds
+// to dest, which may have a checksum. If dest is empty, the LatestAddress
+// of the first seed is used. Each transfer spends at most
+// MaxInputsPerBundle inputs of one seed, and a balance on dest itself stays.
+func (d *AggregateData) PlanConsolidation(dest Trytes) ([]ConsolidationTransfer, error) {
+	adr := d.LatestAddress
+	if dest != "" {
+		var err error
+		adr, err = parseAddress(string(dest))
+		if err != nil {
+			return nil, &ValidationError{Func: "PlanConsolidation", Arg: "dest", Index: -1, Err: err}
+		}
+	}
+	if adr == "" {
+		return nil, &ValidationError{Func: "PlanConsolidation", Arg: "dest", Index: -1, Err: errors.New("no seed to consolidate to")}
+	}
+
+	var plan []ConsolidationTransfer
+	for i, ad := range d.Seeds {
+		var ct *ConsolidationTransfer
+		for _, b := range ad.Balances {
+			if b.Address == adr || b.Value <= 0 {
+				continue
+			}
+			if ct == nil || len(ct.Inputs) == MaxInputsPerBundle {
+				plan = append(plan, ConsolidationTransfer{
+					Seed:     d.seeds[i],
+					Transfer: Transfer{Address: adr},
+				})
+				ct = &plan[len(plan)-1]
+			}
+			// the addresses of an account are the ones of indices 0 to n-1,
+			// so the balance index is the key index
+			ct.Inputs = append(ct.Inputs, AddressInfo{Seed: d.seeds[i].Seed, Index: b.Index, Security: d.seeds[i].Security})
+			ct.Transfer.Value += b.Value
+		}
+	}
+	return plan, nil
+}
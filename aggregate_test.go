@@ -0,0 +1,86 @@
+package giota
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAggregateAccount(t *testing.T) {
+	seedB := Trytes(strings.Repeat("B", 81))
+	adrA0, _ := NewAddress(accountTestSeed, 0, SecurityLevelMedium)
+	adrA1, _ := NewAddress(accountTestSeed, 1, SecurityLevelMedium)
+	adrB0, _ := NewAddress(seedB, 0, SecurityLevelLow)
+
+	var bs Bundle
+	bs.Add(1, adrA0, 60, time.Unix(1500000000, 0), "")
+	bs.Add(1, adrB0, 40, time.Unix(1500000000, 0), "")
+	bs.Add(1, filterAddr2, -100, time.Unix(1500000000, 0), "")
+	bs.Finalize(nil)
+
+	api, done := newFakeTangle(t, bs)
+	defer done()
+
+	acc, err := NewAggregateAccount(
+		SeedAccount{Seed: accountTestSeed, Security: SecurityLevelMedium},
+		SeedAccount{Seed: seedB, Security: SecurityLevelLow},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := acc.AccountData(api)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	switch {
+	case len(d.Seeds) != 2 || len(d.Addresses) != 2 || d.LatestAddress != adrA1:
+		t.Fatalf("AccountData() returned addresses %v and latest %s", d.Addresses, d.LatestAddress)
+	case d.Balances.Total() != 400 || d.Seeds[1].Balances.Total() != 200:
+		t.Errorf("AccountData() returned balances %v", d.Balances)
+	case len(d.Bundles) != 1 || !d.Confirmed[bs[0].Bundle]:
+		t.Errorf("AccountData() returned %d bundles", len(d.Bundles))
+	}
+
+	tests := []struct {
+		name  string
+		dest  Trytes
+		seeds []Trytes
+		valid bool
+	}{
+		{name: "latest address", seeds: []Trytes{accountTestSeed, seedB}, valid: true},
+		{name: "to own input", dest: adrA0.WithChecksum(), seeds: []Trytes{seedB}, valid: true},
+		{name: "bad checksum", dest: Trytes(adrA0) + "999999999"},
+	}
+	for _, tt := range tests {
+		plan, err := d.PlanConsolidation(tt.dest)
+		switch {
+		case !tt.valid && err == nil:
+			t.Errorf("%s: PlanConsolidation() accepted %s", tt.name, tt.dest)
+		case !tt.valid:
+		case err != nil:
+			t.Errorf("%s: %s", tt.name, err)
+		case len(plan) != len(tt.seeds):
+			t.Errorf("%s: PlanConsolidation() returned %d transfers", tt.name, len(plan))
+		default:
+			for i, ct := range plan {
+				if ct.Seed.Seed != tt.seeds[i] || ct.Transfer.Value != 200 || len(ct.Inputs) != 1 || ct.Inputs[0].Index != 0 {
+					t.Errorf("%s: PlanConsolidation() returned %+v", tt.name, ct)
+				}
+			}
+		}
+	}
+
+	plan, _ := d.PlanConsolidation("")
+	bdl, err := plan[1].Prepare(api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bdl) != 2 || bdl[0].Address != adrA1 || bdl[1].Address != adrB0 {
+		t.Errorf("Prepare() returned bundle of %d transactions", len(bdl))
+	}
+
+	if _, err := NewAggregateAccount(SeedAccount{Seed: seedB}); err == nil {
+		t.Error("NewAggregateAccount() accepted a seed without security level")
+	}
+}
@@ -31,10 +31,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io/ioutil"
 	"net/http"
-	"strconv"
-	"sync"
 )
 
 // PublicNodes is a list of known public nodes from http://iotasupport.com/lightwallet.shtml.
@@ -73,33 +70,51 @@ func RandomNode() string {
 	return PublicNodes[int(b[0])%len(PublicNodes)]
 }
 
-// API is for calling APIs.
+// API is for calling APIs. An API is safe for concurrent use by multiple
+// goroutines, as are the hashing, signing and PoW funcs of this package,
+// except where noted. Curl and Kerl instances are not safe for concurrent
+// use.
 type API struct {
-	client   *http.Client
-	endpoint string
+	client      *http.Client
+	endpoint    string
+	validation  Validation
+	coordinator Address
+	node        NodeKind
+	userAgent   string
+	permanode   *API
+	fallback    []*API
+	tracer      Tracer
+	policy      TransferPolicy
+	compliance  ComplianceFilter
+	dedup       *BroadcastDedup
+	events      eventBus
+	onCall      func(*CallInfo)
+	callHeaders []string
+	headers     http.Header
+	retry       Retry
+	logger      Logger
+	cache       TransactionCache
+
+	maxResponseSize int64
+	limits          Limits
+	breaker         breaker
+	approverCache   approverCache
+	pool            workerPool
 }
 
 // NewAPI takes an (optional) endpoint and optional http.Client and returns
 // an API struct. If an empty endpoint is supplied, then "http://localhost:14265"
-// is used.
+// is used. It is NewAPIWithOptions with WithHTTPClient(c).
 func NewAPI(endpoint string, c *http.Client) *API {
-	if c == nil {
-		c = http.DefaultClient
-	}
-
-	if endpoint == "" {
-		endpoint = "http://localhost:14265/"
-	}
-
-	return &API{client: c, endpoint: endpoint}
+	return NewAPIWithOptions(endpoint, WithHTTPClient(c))
 }
 
 func handleError(err *ErrorResponse, err1, err2 error) error {
 	switch {
 	case err.Error != "":
-		return errors.New(err.Error)
+		return fmt.Errorf("%w: %s", ErrNodeResponse, err.Error)
 	case err.Exception != "":
-		return errors.New(err.Exception)
+		return fmt.Errorf("%w: %s", ErrNodeResponse, err.Exception)
 	case err1 != nil:
 		return err1
 	}
@@ -107,7 +122,49 @@ func handleError(err *ErrorResponse, err1, err2 error) error {
 	return err2
 }
 
-func (api *API) do(cmd interface{}, out interface{}) error {
+func (api *API) do(cmd interface{}, out interface{}) (err error) {
+	if api.traced() {
+		name := commandName(cmd)
+		sp := api.startSpan("giota."+name,
+			Attribute{AttrCommand, name},
+			Attribute{AttrEndpoint, api.endpoint},
+			Attribute{AttrNode, api.node.String()},
+		)
+		defer func() { sp.End(err) }()
+	}
+
+	api.pool.acquire()
+	defer api.pool.release()
+	if err = api.doIRI(cmd, out); err != nil {
+		err = &CallError{Command: commandName(cmd), Endpoint: api.endpoint, Err: err}
+	}
+	return err
+}
+
+// commandName returns the name of the IRI command cmd.
+func commandName(cmd interface{}) string {
+	var name string
+	if args, err := toArgs(cmd); err == nil {
+		json.Unmarshal(args["command"], &name)
+	}
+	return name
+}
+
+// toArgs converts the command struct cmd to a map of its JSON fields.
+func toArgs(cmd interface{}) (map[string]json.RawMessage, error) {
+	b, err := json.Marshal(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	args := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(b, &args); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
+
+func (api *API) doIRI(cmd interface{}, out interface{}) error {
 	b, err := json.Marshal(cmd)
 	if err != nil {
 		return err
@@ -115,14 +172,14 @@ func (api *API) do(cmd interface{}, out interface{}) error {
 
 	rd := bytes.NewReader(b)
 
-	req, err := http.NewRequest("POST", api.endpoint, rd)
+	req, err := api.newRequest("POST", api.endpoint, rd)
 	if err != nil {
 		return err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("X-IOTA-API-Version", "1")
-	resp, err := api.client.Do(req)
+	resp, err := api.roundTrip(req, cmd)
 	if err != nil {
 		return err
 	}
@@ -133,27 +190,14 @@ func (api *API) do(cmd interface{}, out interface{}) error {
 		}
 	}()
 
-	bs, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return err
-	}
-
+	body := api.limitResponse(resp.Body)
 	if resp.StatusCode != http.StatusOK {
 		errResp := &ErrorResponse{}
-		err = json.Unmarshal(bs, errResp)
-		return handleError(errResp, err, fmt.Errorf("http status %d while calling API", resp.StatusCode))
-	}
-
-	if bytes.Contains(bs, []byte(`"error"`)) || bytes.Contains(bs, []byte(`"exception"`)) {
-		errResp := &ErrorResponse{}
-		err = json.Unmarshal(bs, errResp)
-		return handleError(errResp, err, fmt.Errorf("unknown error occured while calling API"))
+		err = json.NewDecoder(body).Decode(errResp)
+		return handleError(errResp, err, fmt.Errorf("%w: http status %d", ErrNodeResponse, resp.StatusCode))
 	}
 
-	if out == nil {
-		return nil
-	}
-	return json.Unmarshal(bs, out)
+	return decodeResponse(body, out)
 }
 
 // ErrorResponse is for an exception occurring while calling API.
@@ -177,9 +221,9 @@ type GetNodeInfoResponse struct {
 	JREFreeMemory                      int64  `json:"jreFreeMemory"`
 	JREMaxMemory                       int64  `json:"jreMaxMemory"`
 	JRETotalMemory                     int64  `json:"jreTotalMemory"`
-	LatestMilestone                    Trytes `json:"latestMilestone"`
+	LatestMilestone                    Hash   `json:"latestMilestone"`
 	LatestMilestoneIndex               int64  `json:"latestMilestoneIndex"`
-	LatestSolidSubtangleMilestone      Trytes `json:"latestSolidSubtangleMilestone"`
+	LatestSolidSubtangleMilestone      Hash   `json:"latestSolidSubtangleMilestone"`
 	LatestSolidSubtangleMilestoneIndex int64  `json:"latestSolidSubtangleMilestoneIndex"`
 	Neighbors                          int64  `json:"neighbors"`
 	PacketQueueSize                    int64  `json:"packetQueueSize"`
@@ -200,11 +244,15 @@ func (api *API) GetNodeInfo() (*GetNodeInfoResponse, error) {
 
 // CheckConsistency calls CheckConsistency API which returns true if confirming
 // the specified tails would result in a consistent ledger state.
-func (api *API) CheckConsistency(tails []Trytes) (*CheckConsistencyResponse, error) {
+func (api *API) CheckConsistency(tails []Hash) (*CheckConsistencyResponse, error) {
+	if err := api.validateHashes("CheckConsistency", "tails", tails); err != nil {
+		return nil, err
+	}
+
 	resp := &CheckConsistencyResponse{}
 	err := api.do(&struct {
-		Command string   `json:"command"`
-		Tails   []Trytes `json:"tails"`
+		Command string `json:"command"`
+		Tails   []Hash `json:"tails"`
 	}{
 		"checkConsistency",
 		tails,
@@ -317,8 +365,8 @@ type GetTipsRequest struct {
 
 // GetTipsResponse is for GetTips API response.
 type GetTipsResponse struct {
-	Duration int64    `json:"duration"`
-	Hashes   []Trytes `json:"hashes"`
+	Duration int64  `json:"duration"`
+	Hashes   []Hash `json:"hashes"`
 }
 
 // GetTips calls GetTips API.
@@ -333,17 +381,17 @@ func (api *API) GetTips() (*GetTipsResponse, error) {
 
 // FindTransactionsRequest is for FindTransactions API request.
 type FindTransactionsRequest struct {
-	Command   string    `json:"command"`
-	Bundles   []Trytes  `json:"bundles,omitempty"`
-	Addresses []Address `json:"addresses,omitempty"`
-	Tags      []Trytes  `json:"tags,omitempty"`
-	Approvees []Trytes  `json:"approvees,omitempty"`
+	Command   string       `json:"command"`
+	Bundles   []BundleHash `json:"bundles,omitempty"`
+	Addresses []Address    `json:"addresses,omitempty"`
+	Tags      []Trytes     `json:"tags,omitempty"`
+	Approvees []Hash       `json:"approvees,omitempty"`
 }
 
 // FindTransactionsResponse is for FindTransaction API response.
 type FindTransactionsResponse struct {
-	Duration int64    `json:"duration"`
-	Hashes   []Trytes `json:"hashes"`
+	Duration int64  `json:"duration"`
+	Hashes   []Hash `json:"hashes"`
 }
 
 // FindTransactions calls FindTransactions API.
@@ -360,10 +408,29 @@ func (api *API) FindTransactions(ft *FindTransactionsRequest) (*FindTransactions
 	return resp, err
 }
 
+// FindTransactionObjects calls FindTransactions API and then GetTrytes API
+// for the returned hashes to return the transactions themselves.
+func (api *API) FindTransactionObjects(ft *FindTransactionsRequest) ([]Transaction, error) {
+	found, err := api.FindTransactions(ft)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(found.Hashes) == 0 {
+		return []Transaction{}, nil
+	}
+
+	resp, err := api.GetTrytes(found.Hashes)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Trytes, nil
+}
+
 // GetTrytesRequest is for GetTrytes API request.
 type GetTrytesRequest struct {
-	Command string   `json:"command"`
-	Hashes  []Trytes `json:"hashes"`
+	Command string `json:"command"`
+	Hashes  []Hash `json:"hashes"`
 }
 
 // GetTrytesResponse is for GetTrytes API response.
@@ -372,25 +439,104 @@ type GetTrytesResponse struct {
 	Trytes   []Transaction `json:"trytes"`
 }
 
-// GetTrytes calls GetTrytes API.
-func (api *API) GetTrytes(hashes []Trytes) (*GetTrytesResponse, error) {
+// GetTrytes calls GetTrytes API. Transactions unknown to the node are
+// looked up on the nodes set by SetTrytesFallback.
+func (api *API) GetTrytes(hashes []Hash) (*GetTrytesResponse, error) {
+	if err := api.validateHashes("GetTrytes", "hashes", hashes); err != nil {
+		return nil, err
+	}
+
+	if api.cache != nil {
+		return api.getCachedTrytes(hashes)
+	}
+
+	resp, err := api.getTrytes(hashes)
+	if err != nil {
+		return resp, err
+	}
+	api.fillMissingTrytes(hashes, resp)
+	return resp, nil
+}
+
+// getCachedTrytes is GetTrytes asking the node only for the transactions
+// missing in the cache of api.
+func (api *API) getCachedTrytes(hashes []Hash) (*GetTrytesResponse, error) {
+	txs := make([]Transaction, len(hashes))
+	var missing []Hash
+	var index []int
+	for i, h := range hashes {
+		tx, ok := api.cache.Get(h)
+		if !ok {
+			missing = append(missing, h)
+			index = append(index, i)
+		}
+		txs[i] = tx
+	}
+	if len(missing) == 0 {
+		return &GetTrytesResponse{Trytes: txs}, nil
+	}
+
+	resp, err := api.getTrytes(missing)
+	if err != nil {
+		return resp, err
+	}
+	api.fillMissingTrytes(missing, resp)
+
+	for j := range resp.Trytes {
+		txs[index[j]] = resp.Trytes[j]
+		// unknown transactions may be found later
+		if !resp.Trytes[j].IsNull() {
+			api.cache.Add(missing[j], resp.Trytes[j])
+		}
+	}
+	resp.Trytes = txs
+	return resp, nil
+}
+
+// ErrTransactionNotFound is returned by GetTransactionObjects if the node
+// doesn't know a transaction.
+var ErrTransactionNotFound = errors.New("transaction is not found")
+
+// GetTransactionObjects calls GetTrytes API and returns the transactions of
+// hashes. If a transaction is unknown, it returns an error wrapping
+// ErrTransactionNotFound with its hash.
+func (api *API) GetTransactionObjects(hashes []Hash) ([]Transaction, error) {
+	gt, err := api.GetTrytes(hashes)
+	if err != nil {
+		return nil, err
+	}
+	if len(gt.Trytes) != len(hashes) {
+		return nil, errors.New("GetTrytes returned a wrong number of transactions")
+	}
+	for i := range gt.Trytes {
+		if gt.Trytes[i].IsNull() {
+			return nil, fmt.Errorf("%w: %s", ErrTransactionNotFound, hashes[i])
+		}
+	}
+	return gt.Trytes, nil
+}
+
+// getTrytes calls getTrytes API of the node of api only.
+func (api *API) getTrytes(hashes []Hash) (*GetTrytesResponse, error) {
 	resp := &GetTrytesResponse{}
 	err := api.do(&struct {
-		Command string   `json:"command"`
-		Hashes  []Trytes `json:"hashes"`
+		Command string `json:"command"`
+		Hashes  []Hash `json:"hashes"`
 	}{
 		"getTrytes",
 		hashes,
 	}, resp)
-
+	if err == nil {
+		err = checkResponseLen("transactions", len(resp.Trytes), "hashes", len(hashes))
+	}
 	return resp, err
 }
 
 // GetInclusionStatesRequest is for GetInclusionStates API request.
 type GetInclusionStatesRequest struct {
-	Command      string   `json:"command"`
-	Transactions []Trytes `json:"transactions"`
-	Tips         []Trytes `json:"tips"`
+	Command      string `json:"command"`
+	Transactions []Hash `json:"transactions"`
+	Tips         []Hash `json:"tips"`
 }
 
 // GetInclusionStatesResponse is for GetInclusionStates API response.
@@ -400,18 +546,27 @@ type GetInclusionStatesResponse struct {
 }
 
 // GetInclusionStates calls GetInclusionStates API.
-func (api *API) GetInclusionStates(tx []Trytes, tips []Trytes) (*GetInclusionStatesResponse, error) {
+func (api *API) GetInclusionStates(tx []Hash, tips []Hash) (*GetInclusionStatesResponse, error) {
+	if err := api.validateHashes("GetInclusionStates", "tx", tx); err != nil {
+		return nil, err
+	}
+	if err := api.validateHashes("GetInclusionStates", "tips", tips); err != nil {
+		return nil, err
+	}
+
 	resp := &GetInclusionStatesResponse{}
 	err := api.do(&struct {
-		Command      string   `json:"command"`
-		Transactions []Trytes `json:"transactions"`
-		Tips         []Trytes `json:"tips"`
+		Command      string `json:"command"`
+		Transactions []Hash `json:"transactions"`
+		Tips         []Hash `json:"tips"`
 	}{
 		"getInclusionStates",
 		tx,
 		tips,
 	}, resp)
-
+	if err == nil {
+		err = checkResponseLen("states", len(resp.States), "transactions", len(tx))
+	}
 	return resp, err
 }
 
@@ -419,7 +574,8 @@ func (api *API) GetInclusionStates(tx []Trytes, tips []Trytes) (*GetInclusionSta
 type Balance struct {
 	Address Address
 	Value   int64
-	Index   int
+	// Index is the key index of Address if it is an address of a seed.
+	Index uint64
 }
 
 // Balances is a slice of Balance.
@@ -445,7 +601,7 @@ type GetBalancesRequest struct {
 type GetBalancesResponse struct {
 	Duration       int64   `json:"duration"`
 	Balances       []int64 `json:"balances"`
-	Milestone      Trytes  `json:"milestone"`
+	Milestone      Hash    `json:"milestone"`
 	MilestoneIndex int64   `json:"milestoneIndex"`
 }
 
@@ -464,7 +620,7 @@ func (api *API) Balances(adr []Address) (Balances, error) {
 		b := Balance{
 			Address: adr[i],
 			Value:   bal,
-			Index:   i,
+			Index:   uint64(i),
 		}
 		bs = append(bs, b)
 	}
@@ -477,11 +633,12 @@ func (api *API) GetBalances(adr []Address, threshold int64) (*GetBalancesRespons
 		threshold = 100
 	}
 
+	// nodes encode the numbers as strings or JSON numbers
 	type getBalancesResponse struct {
-		Duration       int64    `json:"duration"`
-		Balances       []string `json:"balances"`
-		Milestone      Trytes   `json:"milestone"`
-		MilestoneIndex int64    `json:"milestoneIndex"`
+		Duration       jsonInt64   `json:"duration"`
+		Balances       []jsonValue `json:"balances"`
+		Milestone      Hash        `json:"milestone"`
+		MilestoneIndex jsonInt64   `json:"milestoneIndex"`
 	}
 
 	resp := &getBalancesResponse{}
@@ -494,23 +651,50 @@ func (api *API) GetBalances(adr []Address, threshold int64) (*GetBalancesRespons
 		adr,
 		threshold,
 	}, resp)
+	if err == nil {
+		err = checkResponseLen("balances", len(resp.Balances), "addresses", len(adr))
+	}
 
 	r := &GetBalancesResponse{
-		Duration:       resp.Duration,
+		Duration:       int64(resp.Duration),
 		Balances:       make([]int64, len(resp.Balances)),
 		Milestone:      resp.Milestone,
-		MilestoneIndex: resp.MilestoneIndex,
+		MilestoneIndex: int64(resp.MilestoneIndex),
 	}
 
 	for i, ba := range resp.Balances {
-		r.Balances[i], err = strconv.ParseInt(ba, 10, 64)
-		if err != nil {
-			return nil, err
+		if ba < 0 {
+			return nil, fmt.Errorf("negative balance %d", ba)
 		}
+		r.Balances[i] = int64(ba)
 	}
 	return r, err
 }
 
+// WereAddressesSpentFromResponse is for WereAddressesSpentFrom API response.
+type WereAddressesSpentFromResponse struct {
+	Duration int64  `json:"duration"`
+	States   []bool `json:"states"`
+}
+
+// WereAddressesSpentFrom calls WereAddressesSpentFrom API which returns
+// whether each of adrs was spent from. Unlike the transactions of adrs, the
+// node keeps this across its snapshots.
+func (api *API) WereAddressesSpentFrom(adrs []Address) (*WereAddressesSpentFromResponse, error) {
+	resp := &WereAddressesSpentFromResponse{}
+	err := api.do(&struct {
+		Command   string    `json:"command"`
+		Addresses []Address `json:"addresses"`
+	}{
+		"wereAddressesSpentFrom",
+		adrs,
+	}, resp)
+	if err == nil {
+		err = checkResponseLen("states", len(resp.States), "addresses", len(adrs))
+	}
+	return resp, err
+}
+
 // GetTransactionsToApproveRequest is for GetTransactionsToApprove API request.
 type GetTransactionsToApproveRequest struct {
 	Command string `json:"command"`
@@ -519,34 +703,49 @@ type GetTransactionsToApproveRequest struct {
 
 // GetTransactionsToApproveResponse is for GetTransactionsToApprove API response.
 type GetTransactionsToApproveResponse struct {
-	Duration          int64  `json:"duration"`
-	TrunkTransaction  Trytes `json:"trunkTransaction"`
-	BranchTransaction Trytes `json:"branchTransaction"`
+	Duration          int64 `json:"duration"`
+	TrunkTransaction  Hash  `json:"trunkTransaction"`
+	BranchTransaction Hash  `json:"branchTransaction"`
 }
 
 // GetTransactionsToApprove calls GetTransactionsToApprove API.
-func (api *API) GetTransactionsToApprove(depth, numWalks int64, reference Trytes) (*GetTransactionsToApproveResponse, error) {
+func (api *API) GetTransactionsToApprove(depth, numWalks int64, reference Hash) (*GetTransactionsToApproveResponse, error) {
+	if err := api.validateDepth("GetTransactionsToApprove", depth); err != nil {
+		return nil, err
+	}
+	if reference != "" {
+		if err := api.validateHash("GetTransactionsToApprove", "reference", reference); err != nil {
+			return nil, err
+		}
+	}
+
 	resp := &GetTransactionsToApproveResponse{}
 	err := api.do(&struct {
 		Command   string `json:"command"`
 		Depth     int64  `json:"depth"`
 		NumWalks  int64  `json:"numWalks,omitempty"`
-		Reference Trytes `json:"reference,omitempty"`
+		Reference Hash   `json:"reference,omitempty"`
 	}{
 		"getTransactionsToApprove",
 		depth,
 		numWalks,
 		reference,
 	}, resp)
+	if err != nil {
+		return resp, err
+	}
 
-	return resp, err
+	if resp.TrunkTransaction.IsValid() != nil || resp.BranchTransaction.IsValid() != nil {
+		return resp, fmt.Errorf("%w: invalid tips %q and %q", ErrNodeResponse, resp.TrunkTransaction, resp.BranchTransaction)
+	}
+	return resp, nil
 }
 
 // AttachToTangleRequest is for AttachToTangle API request.
 type AttachToTangleRequest struct {
 	Command            string        `json:"command"`
-	TrunkTransaction   Trytes        `json:"trunkTransaction"`
-	BranchTransaction  Trytes        `json:"branchTransaction"`
+	TrunkTransaction   Hash          `json:"trunkTransaction"`
+	BranchTransaction  Hash          `json:"branchTransaction"`
 	MinWeightMagnitude int64         `json:"minWeightMagnitude"`
 	Trytes             []Transaction `json:"trytes"`
 }
@@ -559,6 +758,16 @@ type AttachToTangleResponse struct {
 
 // AttachToTangle calls AttachToTangle API.
 func (api *API) AttachToTangle(att *AttachToTangleRequest) (*AttachToTangleResponse, error) {
+	if err := api.validateHash("AttachToTangle", "TrunkTransaction", att.TrunkTransaction); err != nil {
+		return nil, err
+	}
+	if err := api.validateHash("AttachToTangle", "BranchTransaction", att.BranchTransaction); err != nil {
+		return nil, err
+	}
+	if err := api.validateMWM("AttachToTangle", att.MinWeightMagnitude); err != nil {
+		return nil, err
+	}
+
 	resp := &AttachToTangleResponse{}
 	err := api.do(&struct {
 		Command string `json:"command"`
@@ -567,7 +776,9 @@ func (api *API) AttachToTangle(att *AttachToTangleRequest) (*AttachToTangleRespo
 		"attachToTangle",
 		att,
 	}, resp)
-
+	if err == nil {
+		err = checkResponseLen("transactions", len(resp.Trytes), "transactions", len(att.Trytes))
+	}
 	return resp, err
 }
 
@@ -591,16 +802,11 @@ type BroadcastTransactionsRequest struct {
 	Trytes  []Transaction `json:"trytes"`
 }
 
-// BroadcastTransactions calls BroadcastTransactions API.
+// BroadcastTransactions calls BroadcastTransactions API. The transactions
+// are checked by the ComplianceFilter of api before, and the ones broadcasted
+// recently are skipped if SetBroadcastDedup is used.
 func (api *API) BroadcastTransactions(trytes []Transaction) error {
-	err := api.do(&struct {
-		Command string        `json:"command"`
-		Trytes  []Transaction `json:"trytes"`
-	}{
-		"broadcastTransactions",
-		trytes,
-	}, nil)
-
+	_, err := api.BroadcastTransactionsOnce(trytes)
 	return err
 }
 
@@ -625,39 +831,29 @@ func (api *API) StoreTransactions(trytes []Transaction) error {
 
 // GetLatestInclusion takes the most recent solid milestone as returned by getNodeInfo
 // and uses it to get the inclusion states of a list of transaction hashes
-func (api *API) GetLatestInclusion(hash []Trytes) ([]bool, error) {
+func (api *API) GetLatestInclusion(hash []Hash) ([]bool, error) {
 	var (
-		gt   *GetTrytesResponse
-		ni   *GetNodeInfoResponse
-		err1 error
-		err2 error
+		gt *GetTrytesResponse
+		ni *GetNodeInfoResponse
+	)
+	err := api.fanOut(
+		func() (err error) {
+			gt, err = api.GetTrytes(hash)
+			return err
+		},
+		func() (err error) {
+			ni, err = api.GetNodeInfo()
+			return err
+		},
 	)
-
-	wd := sync.WaitGroup{}
-	wd.Add(2)
-
-	go func() {
-		gt, err1 = api.GetTrytes(hash)
-		wd.Done()
-	}()
-
-	go func() {
-		ni, err2 = api.GetNodeInfo()
-		wd.Done()
-	}()
-
-	wd.Wait()
-
 	switch {
-	case err1 != nil:
-		return nil, err1
-	case err2 != nil:
-		return nil, err2
+	case err != nil:
+		return nil, err
 	case len(gt.Trytes) == 0:
 		return nil, errors.New("transaction is not found while GetTrytes")
 	}
 
-	resp, err := api.GetInclusionStates(hash, []Trytes{ni.LatestMilestone})
+	resp, err := api.GetInclusionStates(hash, []Hash{ni.LatestMilestone})
 	if err != nil {
 		return nil, err
 	}
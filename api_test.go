@@ -25,33 +25,59 @@ SOFTWARE.
 
 package giota
 
-import "testing"
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// fakeNodeHandler answers a single API command. The returned value is
+// encoded as the JSON response body.
+type fakeNodeHandler func(req map[string]json.RawMessage) interface{}
+
+// newFakeNode starts an in-process stand-in for an IRI node which answers
+// the commands in handlers, and returns an API talking to it.
+func newFakeNode(t *testing.T, handlers map[string]fakeNodeHandler) (*API, func()) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := make(map[string]json.RawMessage)
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("fake node could not decode request: %s", err)
+		}
 
-func TestAPIGetNodeInfo(t *testing.T) {
-	if testing.Short() {
-		t.Skip("skipping in short mode")
-	}
+		var cmd string
+		json.Unmarshal(req["command"], &cmd)
 
-	var err error
-	var resp *GetNodeInfoResponse
+		h, ok := handlers[cmd]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(&ErrorResponse{Error: "unknown command " + cmd})
+			return
+		}
 
-	for i := 0; i < 5; i++ {
-		var server = RandomNode()
-		api := NewAPI(server, nil)
-		resp, err = api.GetNodeInfo()
-		if err == nil {
-			break
+		if err := json.NewEncoder(w).Encode(h(req)); err != nil {
+			t.Errorf("fake node could not encode response: %s", err)
 		}
-	}
+	}))
 
-	if err != nil {
-		t.Fatalf("GetNodeInfo() expected err to be nil but got %v", err)
-	}
+	return NewAPI(srv.URL, nil), srv.Close
+}
 
-	if resp.AppName == "" {
+func TestAPIGetNodeInfo(t *testing.T) {
+	api, done := newFakeNode(t, map[string]fakeNodeHandler{
+		"getNodeInfo": func(map[string]json.RawMessage) interface{} {
+			return &GetNodeInfoResponse{AppName: "IRI", LatestMilestone: EmptyHash, LatestMilestoneIndex: 42}
+		},
+	})
+	defer done()
+
+	resp, err := api.GetNodeInfo()
+	switch {
+	case err != nil:
+		t.Fatalf("GetNodeInfo() expected err to be nil but got %v", err)
+	case resp.AppName != "IRI" || resp.LatestMilestoneIndex != 42:
 		t.Errorf("GetNodeInfo() returned invalid response: %#v", resp)
 	}
-
 }
 
 /*
@@ -100,166 +126,140 @@ func TestAPIGetTips(t *testing.T) {
 }
 */
 func TestAPIFindTransactions(t *testing.T) {
-	if testing.Short() {
-		t.Skip("skipping in short mode")
-	}
-
-	var err error
-	var resp *FindTransactionsResponse
-
-	ftr := &FindTransactionsRequest{Bundles: []Trytes{"DEXRPLKGBROUQMKCLMRPG9HFKCACDZ9AB9HOJQWERTYWERJNOYLW9PKLOGDUPC9DLGSUH9UHSKJOASJRU"}}
-	for i := 0; i < 5; i++ {
-		var server = RandomNode()
-		api := NewAPI(server, nil)
-
-		resp, err = api.FindTransactions(ftr)
-		if err == nil {
-			break
-		}
-	}
-
-	if err != nil {
+	const bundle = "DEXRPLKGBROUQMKCLMRPG9HFKCACDZ9AB9HOJQWERTYWERJNOYLW9PKLOGDUPC9DLGSUH9UHSKJOASJRU"
+
+	api, done := newFakeNode(t, map[string]fakeNodeHandler{
+		"findTransactions": func(req map[string]json.RawMessage) interface{} {
+			var bundles []Trytes
+			json.Unmarshal(req["bundles"], &bundles)
+			if len(bundles) != 1 || bundles[0] != bundle {
+				t.Errorf("findTransactions was called with bundles %v", bundles)
+			}
+			return &FindTransactionsResponse{Hashes: []Hash{EmptyHash}}
+		},
+	})
+	defer done()
+
+	resp, err := api.FindTransactions(&FindTransactionsRequest{Bundles: []BundleHash{bundle}})
+	switch {
+	case err != nil:
 		t.Errorf("FindTransactions([]) expected err to be nil but got %v", err)
+	case len(resp.Hashes) != 1 || resp.Hashes[0] != EmptyHash:
+		t.Errorf("FindTransactions() = %#v", resp)
 	}
-
-	t.Logf("FindTransactions() = %#v", resp)
 }
 
 func TestAPIGetTrytes(t *testing.T) {
-	if testing.Short() {
-		t.Skip("skipping in short mode")
-	}
-
-	var err error
-	var resp *GetTrytesResponse
-
-	for i := 0; i < 5; i++ {
-		var server = RandomNode()
-		api := NewAPI(server, nil)
-
-		resp, err = api.GetTrytes([]Trytes{})
-		if err == nil {
-			break
-		}
-	}
-
-	if err != nil {
-		t.Errorf("GetTrytes([]) expected err to be nil but got %v", err)
+	tx := filterTestBundle()[0]
+	api, done := newFakeNode(t, map[string]fakeNodeHandler{
+		"getTrytes": func(map[string]json.RawMessage) interface{} {
+			return &GetTrytesResponse{Trytes: []Transaction{tx}}
+		},
+	})
+	defer done()
+
+	resp, err := api.GetTrytes([]Hash{tx.Hash()})
+	switch {
+	case err != nil:
+		t.Errorf("GetTrytes() expected err to be nil but got %v", err)
+	case len(resp.Trytes) != 1 || resp.Trytes[0].Hash() != tx.Hash():
+		t.Errorf("GetTrytes() = %#v", resp)
 	}
-
-	t.Logf("GetTrytes() = %#v", resp)
 }
 
 func TestAPIGetInclusionStates(t *testing.T) {
-	if testing.Short() {
-		t.Skip("skipping in short mode")
-	}
-
-	var err error
-	var resp *GetInclusionStatesResponse
-
-	for i := 0; i < 5; i++ {
-		var server = RandomNode()
-		api := NewAPI(server, nil)
-		resp, err = api.GetInclusionStates([]Trytes{}, []Trytes{})
-		if err == nil {
-			break
-		}
-	}
-
-	if err != nil {
-		t.Errorf("GetInclusionStates([]) expected err to be nil but got %v", err)
+	api, done := newFakeNode(t, map[string]fakeNodeHandler{
+		"getInclusionStates": func(map[string]json.RawMessage) interface{} {
+			return &GetInclusionStatesResponse{States: []bool{true, false}}
+		},
+	})
+	defer done()
+
+	resp, err := api.GetInclusionStates([]Hash{EmptyHash, EmptyHash}, []Hash{EmptyHash})
+	switch {
+	case err != nil:
+		t.Errorf("GetInclusionStates() expected err to be nil but got %v", err)
+	case len(resp.States) != 2 || !resp.States[0] || resp.States[1]:
+		t.Errorf("GetInclusionStates() = %#v", resp)
 	}
-
-	t.Logf("GetInclusionStates() = %#v", resp)
 }
 
 func TestAPIGetBalances(t *testing.T) {
-	if testing.Short() {
-		t.Skip("skipping in short mode")
-	}
-
-	var err error
-	var resp *GetBalancesResponse
-
-	for i := 0; i < 5; i++ {
-		var server = RandomNode()
-		api := NewAPI(server, nil)
-
-		resp, err = api.GetBalances([]Address{}, 100)
-		if err == nil {
-			break
-		}
-	}
-
-	if err != nil {
-		t.Errorf("GetBalances([]) expected err to be nil but got %v", err)
+	api, done := newFakeNode(t, map[string]fakeNodeHandler{
+		"getBalances": func(map[string]json.RawMessage) interface{} {
+			return map[string]interface{}{"balances": []string{"0", "100"}, "milestoneIndex": 42}
+		},
+	})
+	defer done()
+
+	resp, err := api.GetBalances([]Address{filterAddr1, filterAddr2}, 100)
+	switch {
+	case err != nil:
+		t.Errorf("GetBalances() expected err to be nil but got %v", err)
+	case len(resp.Balances) != 2 || resp.Balances[1] != 100 || resp.MilestoneIndex != 42:
+		t.Errorf("GetBalances() = %#v", resp)
 	}
-
-	t.Logf("GetBalances() = %#v", resp)
 }
 
 func TestAPIGetTransactionsToApprove(t *testing.T) {
-	if testing.Short() {
-		t.Skip("skipping in short mode")
-	}
-
-	var err error
-	var resp *GetTransactionsToApproveResponse
-
-	for i := 0; i < 5; i++ {
-		var server = RandomNode()
-		api := NewAPI(server, nil)
-
-		resp, err = api.GetTransactionsToApprove(Depth, DefaultNumberOfWalks, "")
-		if err == nil {
-			break
-		}
-	}
-
+	tx := filterTestBundle()[0]
+	api, done := newFakeNode(t, map[string]fakeNodeHandler{
+		"getTransactionsToApprove": func(req map[string]json.RawMessage) interface{} {
+			if string(req["depth"]) != "3" {
+				t.Errorf("getTransactionsToApprove was called with depth %s", req["depth"])
+			}
+			return &GetTransactionsToApproveResponse{TrunkTransaction: tx.Hash(), BranchTransaction: EmptyHash}
+		},
+	})
+	defer done()
+
+	resp, err := api.GetTransactionsToApprove(Depth, DefaultNumberOfWalks, "")
 	switch {
 	case err != nil:
 		t.Errorf("GetTransactionsToApprove() expected err to be nil but got %v", err)
-	case resp.BranchTransaction == "" || resp.TrunkTransaction == "":
-		t.Errorf("GetTransactionsToApprove() return empty branch and/or trunk transactions\n%#v", resp)
+	case resp.TrunkTransaction != tx.Hash() || resp.BranchTransaction != EmptyHash:
+		t.Errorf("GetTransactionsToApprove() returned wrong branch and/or trunk transactions\n%#v", resp)
 	}
 }
 
 func TestAPIGetLatestInclusion(t *testing.T) {
-	if testing.Short() {
-		t.Skip("skipping in short mode")
-	}
-
-	var err error
-	var resp []bool
-
-	for i := 0; i < 5; i++ {
-		var server = RandomNode()
-		api := NewAPI(server, nil)
-
-		resp, err = api.GetLatestInclusion([]Trytes{"B9OETFYOEIUYEVB9WWCMGIHIJLFU9IJOBYYGSTZBLFBZLGZRKBIREYTIPPFGC9SPEOJFIYFRRSPX99999"})
-		if err == nil && len(resp) > 0 {
-			break
-		}
-	}
-
+	tx := filterTestBundle()[0]
+	api, done := newFakeNode(t, map[string]fakeNodeHandler{
+		"getTrytes": func(map[string]json.RawMessage) interface{} {
+			return &GetTrytesResponse{Trytes: []Transaction{tx}}
+		},
+		"getNodeInfo": func(map[string]json.RawMessage) interface{} {
+			return &GetNodeInfoResponse{LatestMilestone: EmptyHash}
+		},
+		"getInclusionStates": func(req map[string]json.RawMessage) interface{} {
+			var tips []Trytes
+			json.Unmarshal(req["tips"], &tips)
+			if len(tips) != 1 || tips[0] != EmptyHash {
+				t.Errorf("getInclusionStates was called with tips %v", tips)
+			}
+			return &GetInclusionStatesResponse{States: []bool{true}}
+		},
+	})
+	defer done()
+
+	resp, err := api.GetLatestInclusion([]Hash{tx.Hash()})
 	switch {
 	case err != nil:
 		t.Errorf("GetLatestInclustion() expected err to be nil but got %v", err)
-	case len(resp) == 0:
+	case len(resp) != 1 || !resp[0]:
 		t.Error("GetLatestInclustion() is invalid resp:", resp)
 	}
 }
 
 func TestAPICheckConsistency(t *testing.T) {
-	if testing.Short() {
-		t.Skip("skipping in short mode")
-	}
-	var server = RandomNode()
-	api := NewAPI(server, nil)
-
-	resp, err := api.CheckConsistency([]Trytes{"NLNRYUTSLRQONSQEXBAJI9AIOJOEEJDOFJTETPFMB9AEEPUDIXXOTKXG9BYALEXOMSUYJEJSCZTY99999"})
-
+	api, done := newFakeNode(t, map[string]fakeNodeHandler{
+		"checkConsistency": func(map[string]json.RawMessage) interface{} {
+			return &CheckConsistencyResponse{State: true}
+		},
+	})
+	defer done()
+
+	resp, err := api.CheckConsistency([]Hash{"NLNRYUTSLRQONSQEXBAJI9AIOJOEEJDOFJTETPFMB9AEEPUDIXXOTKXG9BYALEXOMSUYJEJSCZTY99999"})
 	switch {
 	case err != nil:
 		t.Errorf("CheckConsistency() expected err to be nil but got '%v'", err)
@@ -0,0 +1,12 @@
+// Package apimock has a mock of giota.APIClient for tests of code using the
+// high-level funcs of giota without a node.
+package apimock
+
+import (
+	"errors"
+)
+
+//go:generate go run gen.go
+
+// ErrNotMocked is returned by the methods of Client whose func is not set.
+var ErrNotMocked = errors.New("apimock: method not mocked")
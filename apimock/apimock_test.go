@@ -0,0 +1,64 @@
+package apimock
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/iotaledger/giota"
+)
+
+const testSeed giota.Trytes = "WQNZOHUT99PWKEBFSKQSYNC9XHT9GEBMOSJAQDQAXPEZPJNDIUB9TSNWVMHKWICW9WVZXSMDFGISOD9FZ"
+
+const testAddress giota.Address = "PQZKJKZAHOPZLXRZSPKFHZDLITQTQYDCHSYJYECIFGIISVJXKDGTSSMZE9XAXVUVQWIYLLJLKQDJBMMTZ"
+
+func TestClient(t *testing.T) {
+	input, err := giota.NewAddress(testSeed, 2, giota.SecurityLevelLow)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var broadcasted []giota.Transaction
+	c := &Client{
+		BalancesFunc: func(adrs []giota.Address) (giota.Balances, error) {
+			if len(adrs) != 1 || adrs[0] != input {
+				t.Errorf("balances of %v requested", adrs)
+			}
+			return giota.Balances{{Address: input, Value: 100, Index: 0}}, nil
+		},
+		GetTransactionsToApproveFunc: func(depth, numWalks int64, reference giota.Hash) (*giota.GetTransactionsToApproveResponse, error) {
+			return &giota.GetTransactionsToApproveResponse{TrunkTransaction: giota.EmptyHash, BranchTransaction: giota.EmptyHash}, nil
+		},
+		AttachToTangleFunc: func(att *giota.AttachToTangleRequest) (*giota.AttachToTangleResponse, error) {
+			return &giota.AttachToTangleResponse{Trytes: att.Trytes}, nil
+		},
+		BroadcastTransactionsFunc: func(txs []giota.Transaction) error {
+			broadcasted = txs
+			return nil
+		},
+		StoreTransactionsFunc: func(txs []giota.Transaction) error {
+			return nil
+		},
+	}
+
+	trs := []giota.Transfer{{Address: testAddress, Value: 60}}
+	inputs := []giota.AddressInfo{{Seed: testSeed, Index: 2, Security: giota.SecurityLevelLow}}
+	bdl, err := giota.PrepareTransfers(c, testSeed, trs, inputs, testAddress, giota.SecurityLevelLow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bdl) != 3 || bdl[2].Address != testAddress || bdl[2].Value != 40 {
+		t.Fatalf("PrepareTransfers() returned %+v", bdl)
+	}
+
+	res, err := giota.SendTrytes(c, 3, bdl, 14, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(broadcasted) != 3 || res.Tail != broadcasted[0].Hash() {
+		t.Errorf("broadcasted %d transactions with tail %s", len(broadcasted), res.Tail)
+	}
+
+	if _, err := giota.GetAccountData(c, testSeed, giota.SecurityLevelLow); !errors.Is(err, ErrNotMocked) {
+		t.Errorf("GetAccountData() returned %v, expected ErrNotMocked", err)
+	}
+}
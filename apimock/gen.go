@@ -0,0 +1,145 @@
+//go:build ignore
+
+// gen writes mock.go from the APIClient interface of ../client.go.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"log"
+	"os"
+	"strings"
+)
+
+func main() {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "../client.go", nil, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var iface *ast.InterfaceType
+	ast.Inspect(f, func(n ast.Node) bool {
+		if ts, ok := n.(*ast.TypeSpec); ok && ts.Name.Name == "APIClient" {
+			iface = ts.Type.(*ast.InterfaceType)
+		}
+		return iface == nil
+	})
+	if iface == nil {
+		log.Fatal("no APIClient in ../client.go")
+	}
+
+	var b bytes.Buffer
+	b.WriteString(`// Code generated by gen.go from ../client.go. DO NOT EDIT.
+
+package apimock
+
+import (
+	"github.com/iotaledger/giota"
+)
+
+// Client is a giota.APIClient calling the func of each method. Methods
+// whose func is nil return ErrNotMocked.
+type Client struct {
+`)
+	for _, m := range iface.Methods.List {
+		fmt.Fprintf(&b, "\t%sFunc %s\n", m.Names[0].Name, qualify(m.Type))
+	}
+	b.WriteString("}\n\nvar _ giota.APIClient = (*Client)(nil)\n")
+
+	for _, m := range iface.Methods.List {
+		name := m.Names[0].Name
+		ft := m.Type.(*ast.FuncType)
+		var params, args []string
+		for _, p := range ft.Params.List {
+			for range p.Names {
+				arg := fmt.Sprintf("a%d", len(args))
+				params = append(params, arg+" "+qualify(p.Type))
+				args = append(args, arg)
+			}
+		}
+		var zero []string
+		for _, r := range ft.Results.List {
+			if t := qualify(r.Type); t != "error" {
+				zero = append(zero, zeroOf(t))
+			}
+		}
+		zero = append(zero, "ErrNotMocked")
+
+		fmt.Fprintf(&b, "\n// %s calls %sFunc.\n", name, name)
+		fmt.Fprintf(&b, "func (c *Client) %s(%s) %s {\n", name, strings.Join(params, ", "), results(ft))
+		fmt.Fprintf(&b, "\tif c.%sFunc == nil {\n\t\treturn %s\n\t}\n", name, strings.Join(zero, ", "))
+		fmt.Fprintf(&b, "\treturn c.%sFunc(%s)\n}\n", name, strings.Join(args, ", "))
+	}
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("mock.go", src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// qualify prints the type expr with the exported identifiers of giota
+// qualified.
+func qualify(expr ast.Expr) string {
+	return types.ExprString(qualifyExpr(expr))
+}
+
+func qualifyExpr(expr ast.Expr) ast.Expr {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if e.IsExported() {
+			return &ast.SelectorExpr{X: ast.NewIdent("giota"), Sel: ast.NewIdent(e.Name)}
+		}
+	case *ast.StarExpr:
+		return &ast.StarExpr{X: qualifyExpr(e.X)}
+	case *ast.ArrayType:
+		return &ast.ArrayType{Len: e.Len, Elt: qualifyExpr(e.Elt)}
+	case *ast.FuncType:
+		return &ast.FuncType{Params: qualifyFields(e.Params), Results: qualifyFields(e.Results)}
+	}
+	return expr
+}
+
+func qualifyFields(fl *ast.FieldList) *ast.FieldList {
+	if fl == nil {
+		return nil
+	}
+	out := &ast.FieldList{}
+	for _, f := range fl.List {
+		out.List = append(out.List, &ast.Field{Names: f.Names, Type: qualifyExpr(f.Type)})
+	}
+	return out
+}
+
+func results(ft *ast.FuncType) string {
+	var rs []string
+	for _, r := range ft.Results.List {
+		rs = append(rs, qualify(r.Type))
+	}
+	if len(rs) == 1 {
+		return rs[0]
+	}
+	return "(" + strings.Join(rs, ", ") + ")"
+}
+
+func zeroOf(t string) string {
+	if strings.HasPrefix(t, "*") || strings.HasPrefix(t, "[]") {
+		return "nil"
+	}
+	switch t {
+	case "giota.Balances", "giota.Bundles":
+		return "nil"
+	case "bool":
+		return "false"
+	}
+	log.Fatalf("no zero value of %s", t)
+	return ""
+}
@@ -0,0 +1,185 @@
+// Code generated by gen.go from ../client.go. DO NOT EDIT.
+
+package apimock
+
+import (
+	"github.com/iotaledger/giota"
+)
+
+// Client is a giota.APIClient calling the func of each method. Methods
+// whose func is nil return ErrNotMocked.
+type Client struct {
+	GetNodeInfoFunc                func() (*giota.GetNodeInfoResponse, error)
+	CheckConsistencyFunc           func(tails []giota.Hash) (*giota.CheckConsistencyResponse, error)
+	GetNeighborsFunc               func() (*giota.GetNeighborsResponse, error)
+	AddNeighborsFunc               func(uris []string) (*giota.AddNeighborsResponse, error)
+	RemoveNeighborsFunc            func(uris []string) (*giota.RemoveNeighborsResponse, error)
+	GetTipsFunc                    func() (*giota.GetTipsResponse, error)
+	FindTransactionsFunc           func(ft *giota.FindTransactionsRequest) (*giota.FindTransactionsResponse, error)
+	FindTransactionObjectsFunc     func(ft *giota.FindTransactionsRequest) ([]giota.Transaction, error)
+	GetTrytesFunc                  func(hashes []giota.Hash) (*giota.GetTrytesResponse, error)
+	GetInclusionStatesFunc         func(tx []giota.Hash, tips []giota.Hash) (*giota.GetInclusionStatesResponse, error)
+	GetLatestInclusionFunc         func(hash []giota.Hash) ([]bool, error)
+	GetBalancesFunc                func(adr []giota.Address, threshold int64) (*giota.GetBalancesResponse, error)
+	BalancesFunc                   func(adr []giota.Address) (giota.Balances, error)
+	GetTransactionsToApproveFunc   func(depth, numWalks int64, reference giota.Hash) (*giota.GetTransactionsToApproveResponse, error)
+	AttachToTangleFunc             func(att *giota.AttachToTangleRequest) (*giota.AttachToTangleResponse, error)
+	InterruptAttachingToTangleFunc func() error
+	BroadcastTransactionsFunc      func(trytes []giota.Transaction) error
+	StoreTransactionsFunc          func(trytes []giota.Transaction) error
+	GetBundlesFromAddressesFunc    func(adrs []giota.Address) (giota.Bundles, error)
+}
+
+var _ giota.APIClient = (*Client)(nil)
+
+// GetNodeInfo calls GetNodeInfoFunc.
+func (c *Client) GetNodeInfo() (*giota.GetNodeInfoResponse, error) {
+	if c.GetNodeInfoFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return c.GetNodeInfoFunc()
+}
+
+// CheckConsistency calls CheckConsistencyFunc.
+func (c *Client) CheckConsistency(a0 []giota.Hash) (*giota.CheckConsistencyResponse, error) {
+	if c.CheckConsistencyFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return c.CheckConsistencyFunc(a0)
+}
+
+// GetNeighbors calls GetNeighborsFunc.
+func (c *Client) GetNeighbors() (*giota.GetNeighborsResponse, error) {
+	if c.GetNeighborsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return c.GetNeighborsFunc()
+}
+
+// AddNeighbors calls AddNeighborsFunc.
+func (c *Client) AddNeighbors(a0 []string) (*giota.AddNeighborsResponse, error) {
+	if c.AddNeighborsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return c.AddNeighborsFunc(a0)
+}
+
+// RemoveNeighbors calls RemoveNeighborsFunc.
+func (c *Client) RemoveNeighbors(a0 []string) (*giota.RemoveNeighborsResponse, error) {
+	if c.RemoveNeighborsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return c.RemoveNeighborsFunc(a0)
+}
+
+// GetTips calls GetTipsFunc.
+func (c *Client) GetTips() (*giota.GetTipsResponse, error) {
+	if c.GetTipsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return c.GetTipsFunc()
+}
+
+// FindTransactions calls FindTransactionsFunc.
+func (c *Client) FindTransactions(a0 *giota.FindTransactionsRequest) (*giota.FindTransactionsResponse, error) {
+	if c.FindTransactionsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return c.FindTransactionsFunc(a0)
+}
+
+// FindTransactionObjects calls FindTransactionObjectsFunc.
+func (c *Client) FindTransactionObjects(a0 *giota.FindTransactionsRequest) ([]giota.Transaction, error) {
+	if c.FindTransactionObjectsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return c.FindTransactionObjectsFunc(a0)
+}
+
+// GetTrytes calls GetTrytesFunc.
+func (c *Client) GetTrytes(a0 []giota.Hash) (*giota.GetTrytesResponse, error) {
+	if c.GetTrytesFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return c.GetTrytesFunc(a0)
+}
+
+// GetInclusionStates calls GetInclusionStatesFunc.
+func (c *Client) GetInclusionStates(a0 []giota.Hash, a1 []giota.Hash) (*giota.GetInclusionStatesResponse, error) {
+	if c.GetInclusionStatesFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return c.GetInclusionStatesFunc(a0, a1)
+}
+
+// GetLatestInclusion calls GetLatestInclusionFunc.
+func (c *Client) GetLatestInclusion(a0 []giota.Hash) ([]bool, error) {
+	if c.GetLatestInclusionFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return c.GetLatestInclusionFunc(a0)
+}
+
+// GetBalances calls GetBalancesFunc.
+func (c *Client) GetBalances(a0 []giota.Address, a1 int64) (*giota.GetBalancesResponse, error) {
+	if c.GetBalancesFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return c.GetBalancesFunc(a0, a1)
+}
+
+// Balances calls BalancesFunc.
+func (c *Client) Balances(a0 []giota.Address) (giota.Balances, error) {
+	if c.BalancesFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return c.BalancesFunc(a0)
+}
+
+// GetTransactionsToApprove calls GetTransactionsToApproveFunc.
+func (c *Client) GetTransactionsToApprove(a0 int64, a1 int64, a2 giota.Hash) (*giota.GetTransactionsToApproveResponse, error) {
+	if c.GetTransactionsToApproveFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return c.GetTransactionsToApproveFunc(a0, a1, a2)
+}
+
+// AttachToTangle calls AttachToTangleFunc.
+func (c *Client) AttachToTangle(a0 *giota.AttachToTangleRequest) (*giota.AttachToTangleResponse, error) {
+	if c.AttachToTangleFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return c.AttachToTangleFunc(a0)
+}
+
+// InterruptAttachingToTangle calls InterruptAttachingToTangleFunc.
+func (c *Client) InterruptAttachingToTangle() error {
+	if c.InterruptAttachingToTangleFunc == nil {
+		return ErrNotMocked
+	}
+	return c.InterruptAttachingToTangleFunc()
+}
+
+// BroadcastTransactions calls BroadcastTransactionsFunc.
+func (c *Client) BroadcastTransactions(a0 []giota.Transaction) error {
+	if c.BroadcastTransactionsFunc == nil {
+		return ErrNotMocked
+	}
+	return c.BroadcastTransactionsFunc(a0)
+}
+
+// StoreTransactions calls StoreTransactionsFunc.
+func (c *Client) StoreTransactions(a0 []giota.Transaction) error {
+	if c.StoreTransactionsFunc == nil {
+		return ErrNotMocked
+	}
+	return c.StoreTransactionsFunc(a0)
+}
+
+// GetBundlesFromAddresses calls GetBundlesFromAddressesFunc.
+func (c *Client) GetBundlesFromAddresses(a0 []giota.Address) (giota.Bundles, error) {
+	if c.GetBundlesFromAddressesFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return c.GetBundlesFromAddressesFunc(a0)
+}
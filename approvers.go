@@ -0,0 +1,135 @@
+package giota
+
+import (
+	"sync"
+	"time"
+)
+
+var (
+	// MaxApproverWalk is the max number of approvers CountApprovers
+	// visits.
+	MaxApproverWalk = 10000
+	// MaxApproverFanOut is the max number of direct approvers of a
+	// transaction CountApprovers follows.
+	MaxApproverFanOut = 100
+	// ApproverCacheTTL is the time CountApprovers keeps the approvers of a
+	// transaction found on the node. New approvers arrive all the time, so
+	// it is short.
+	ApproverCacheTTL = 30 * time.Second
+)
+
+// ApproverCount is an estimate of the cumulative weight of a transaction.
+type ApproverCount struct {
+	// Approvers is the number of transactions approving the transaction
+	// directly or indirectly.
+	Approvers int
+	// Direct is the number of transactions approving it directly.
+	Direct int
+	// Depth is the number of levels of approvers found.
+	Depth int
+	// Truncated is true if the walk stopped at maxDepth, MaxApproverWalk or
+	// MaxApproverFanOut with approvers left, so Approvers is a lower bound.
+	Truncated bool
+}
+
+// CountApprovers walks the transactions approving hash level by level, up to
+// maxDepth levels, and counts them. The more approvers, the closer a
+// transaction is to being confirmed. Approvers found on the node are cached
+// for ApproverCacheTTL.
+func (api *API) CountApprovers(hash Hash, maxDepth int) (*ApproverCount, error) {
+	if err := api.validateHash("CountApprovers", "hash", hash); err != nil {
+		return nil, err
+	}
+
+	c := &ApproverCount{}
+	visited := map[Hash]bool{hash: true}
+	frontier := []Hash{hash}
+	for level := 1; level <= maxDepth && len(frontier) > 0; level++ {
+		var next []Hash
+		for _, h := range frontier {
+			aps, err := api.approvers(h)
+			if err != nil {
+				return nil, err
+			}
+			if len(aps) > MaxApproverFanOut {
+				aps = aps[:MaxApproverFanOut]
+				c.Truncated = true
+			}
+
+			for _, a := range aps {
+				if visited[a] {
+					continue
+				}
+				visited[a] = true
+				next = append(next, a)
+				c.Approvers++
+				if c.Approvers >= MaxApproverWalk {
+					c.Depth = level
+					c.Truncated = true
+					return c, nil
+				}
+			}
+		}
+		if level == 1 {
+			c.Direct = len(next)
+		}
+		if len(next) > 0 {
+			c.Depth = level
+		}
+		frontier = next
+	}
+	c.Truncated = c.Truncated || len(frontier) > 0
+	return c, nil
+}
+
+// approvers returns the direct approvers of hash.
+func (api *API) approvers(hash Hash) ([]Hash, error) {
+	if aps, ok := api.approverCache.get(hash); ok {
+		return aps, nil
+	}
+	ft, err := api.FindTransactions(&FindTransactionsRequest{Approvees: []Hash{hash}})
+	if err != nil {
+		return nil, err
+	}
+	api.approverCache.add(hash, ft.Hashes)
+	return ft.Hashes, nil
+}
+
+type cachedApprovers struct {
+	at        time.Time
+	approvers []Hash
+}
+
+// approverCache caches the approvers of transactions for ApproverCacheTTL.
+type approverCache struct {
+	mu      sync.Mutex
+	entries map[Hash]cachedApprovers
+}
+
+func (c *approverCache) get(hash Hash) ([]Hash, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[hash]
+	if !ok || time.Since(e.at) > ApproverCacheTTL {
+		return nil, false
+	}
+	return e.approvers, true
+}
+
+func (c *approverCache) add(hash Hash, aps []Hash) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[Hash]cachedApprovers)
+	}
+	c.entries[hash] = cachedApprovers{at: time.Now(), approvers: aps}
+
+	// drop expired entries now and then
+	if len(c.entries)%1024 == 0 {
+		for h, e := range c.entries {
+			if time.Since(e.at) > ApproverCacheTTL {
+				delete(c.entries, h)
+			}
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package giota
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCountApprovers(t *testing.T) {
+	h := func(i int64) Hash { return Hash(Int2Trits(i+1, HashSize).Trytes()) }
+	// 0 is approved by 1 and 2, 1 by 3, 2 by 3 and 4, 4 by 5
+	graph := map[Hash][]Hash{
+		h(0): {h(1), h(2)},
+		h(1): {h(3)},
+		h(2): {h(3), h(4)},
+		h(4): {h(5)},
+	}
+	calls := 0
+	api, done := newFakeNode(t, map[string]fakeNodeHandler{
+		"findTransactions": func(req map[string]json.RawMessage) interface{} {
+			calls++
+			var approvees []Hash
+			json.Unmarshal(req["approvees"], &approvees)
+			return &FindTransactionsResponse{Hashes: append([]Hash{}, graph[approvees[0]]...)}
+		},
+	})
+	defer done()
+
+	defer func(fanOut int) { MaxApproverFanOut = fanOut }(MaxApproverFanOut)
+	tests := []struct {
+		name     string
+		maxDepth int
+		fanOut   int
+		want     ApproverCount
+		calls    int
+	}{
+		{name: "all", maxDepth: 10, fanOut: 100, want: ApproverCount{Approvers: 5, Direct: 2, Depth: 3}, calls: 6},
+		{name: "cached", maxDepth: 10, fanOut: 100, want: ApproverCount{Approvers: 5, Direct: 2, Depth: 3}},
+		{name: "depth", maxDepth: 2, fanOut: 100, want: ApproverCount{Approvers: 4, Direct: 2, Depth: 2, Truncated: true}},
+		{name: "fan-out", maxDepth: 10, fanOut: 1, want: ApproverCount{Approvers: 2, Direct: 1, Depth: 2, Truncated: true}},
+	}
+	for _, tt := range tests {
+		calls = 0
+		MaxApproverFanOut = tt.fanOut
+		c, err := api.CountApprovers(h(0), tt.maxDepth)
+		switch {
+		case err != nil:
+			t.Errorf("%s: %s", tt.name, err)
+		case *c != tt.want:
+			t.Errorf("%s: CountApprovers() = %+v, expected %+v", tt.name, *c, tt.want)
+		case calls != tt.calls:
+			t.Errorf("%s: called findTransactions %d times", tt.name, calls)
+		}
+	}
+
+	if _, err := api.CountApprovers("INVALID", 1); err == nil {
+		t.Error("CountApprovers() should fail for an invalid hash")
+	}
+}
@@ -0,0 +1,187 @@
+package giota
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Risk notes of an AddressAudit.
+const (
+	// RiskReusedAfterSpend notes that the address received a transfer
+	// after it was spent from. Its key is partly revealed, so the funds can
+	// be stolen.
+	RiskReusedAfterSpend = "reused after spend"
+	// RiskBalanceAfterSpend notes a balance on a spent address.
+	RiskBalanceAfterSpend = "balance on spent address"
+	// RiskDoubleSpend notes that the address is spent by conflicting
+	// bundles, see Bundles.DoubleSpends.
+	RiskDoubleSpend = "spent by conflicting bundles"
+)
+
+// AddressAudit is the state of an address in an AuditReport.
+type AddressAudit struct {
+	Address  Address `json:"address"`
+	Checksum Trytes  `json:"checksum"`
+	// Index is the key index of the address, or -1 if the seed is unknown.
+	Index   int   `json:"index"`
+	Balance int64 `json:"balance"`
+	// Spent is true if a bundle spends from the address, even if it is not
+	// confirmed.
+	Spent bool `json:"spent"`
+	// IncomingBundles and OutgoingBundles are the numbers of bundles
+	// depositing to and spending from the address. Zero-value transactions
+	// are deposits unless they carry the signature of a spend.
+	IncomingBundles int      `json:"incomingBundles"`
+	OutgoingBundles int      `json:"outgoingBundles"`
+	Risks           []string `json:"risks,omitempty"`
+}
+
+// AuditReport is the state of addresses for compliance reviews.
+type AuditReport struct {
+	Time      time.Time      `json:"time"`
+	Addresses []AddressAudit `json:"addresses"`
+}
+
+// AuditSeed returns the report of the used addresses of seed, with their key
+// indices.
+func AuditSeed(api APIClient, seed Trytes, security SecurityLevel) (*AuditReport, error) {
+	ad, err := GetAccountData(api, seed, security)
+	if err != nil {
+		return nil, err
+	}
+	return newAuditReport(ad, true), nil
+}
+
+// AuditAddresses returns the report of adrs, which may have checksums.
+// Duplicates are dropped.
+func AuditAddresses(api APIClient, adrs ...Trytes) (*AuditReport, error) {
+	w, err := NewWatchOnlyAccount(adrs...)
+	if err != nil {
+		return nil, err
+	}
+	ad, err := w.AccountData(api)
+	if err != nil {
+		return nil, err
+	}
+	return newAuditReport(ad, false), nil
+}
+
+// newAuditReport returns the report of the addresses of ad, which are the
+// ones of the key indices 0 to n-1 if indexed.
+func newAuditReport(ad *AccountData, indexed bool) *AuditReport {
+	balances := make(map[Address]int64, len(ad.Balances))
+	for _, b := range ad.Balances {
+		balances[b.Address] = b.Value
+	}
+	doubleSpent := make(map[Address]bool)
+	for _, ds := range ad.Bundles.DoubleSpends(ad.Addresses...) {
+		doubleSpent[ds.Address] = true
+	}
+
+	r := &AuditReport{Time: time.Now().UTC()}
+	for i, adr := range ad.Addresses {
+		a := AddressAudit{
+			Address:  adr,
+			Checksum: adr.Checksum(),
+			Index:    -1,
+			Balance:  balances[adr],
+		}
+		if indexed {
+			a.Index = i
+		}
+
+		// the first spend and the last deposit, by bundle timestamp
+		var firstSpend, lastDeposit time.Time
+		seen := make(map[BundleHash]bool)
+		for _, b := range ad.Bundles {
+			if len(b) == 0 || seen[b[0].Bundle] {
+				continue
+			}
+			sent, received := b.Categorize(adr)
+			if len(sent) == 0 && len(received) == 0 {
+				continue
+			}
+			seen[b[0].Bundle] = true
+			ts := b[0].Timestamp
+			if len(sent) > 0 {
+				a.OutgoingBundles++
+				if firstSpend.IsZero() || ts.Before(firstSpend) {
+					firstSpend = ts
+				}
+			}
+			if deposits(sent, received) {
+				a.IncomingBundles++
+				if ts.After(lastDeposit) {
+					lastDeposit = ts
+				}
+			}
+		}
+		a.Spent = a.OutgoingBundles > 0
+
+		if a.Spent && lastDeposit.After(firstSpend) {
+			a.Risks = append(a.Risks, RiskReusedAfterSpend)
+		}
+		if a.Spent && a.Balance > 0 {
+			a.Risks = append(a.Risks, RiskBalanceAfterSpend)
+		}
+		if doubleSpent[adr] {
+			a.Risks = append(a.Risks, RiskDoubleSpend)
+		}
+		r.Addresses = append(r.Addresses, a)
+	}
+	return r
+}
+
+// deposits returns true if the transactions of a bundle categorized as sent
+// and received deposit to the address.
+func deposits(sent, received Bundle) bool {
+	for i := range received {
+		if received[i].Value > 0 {
+			return true
+		}
+	}
+	// the zero-value transactions of a spend hold the rest of its signature
+	return len(sent) == 0 && len(received) > 0
+}
+
+// ExportJSON writes r as JSON to w.
+func (r *AuditReport) ExportJSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(r)
+}
+
+// ExportCSV writes the addresses of r as CSV with a header line to w. Risks
+// are separated by semicolons.
+func (r *AuditReport) ExportCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	err := cw.Write([]string{"address", "checksum", "index", "balance", "spent", "incoming", "outgoing", "risks"})
+	if err != nil {
+		return err
+	}
+
+	for _, a := range r.Addresses {
+		index := ""
+		if a.Index >= 0 {
+			index = strconv.Itoa(a.Index)
+		}
+		err = cw.Write([]string{
+			string(a.Address),
+			string(a.Checksum),
+			index,
+			strconv.FormatInt(a.Balance, 10),
+			strconv.FormatBool(a.Spent),
+			strconv.Itoa(a.IncomingBundles),
+			strconv.Itoa(a.OutgoingBundles),
+			strings.Join(a.Risks, ";"),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,71 @@
+package giota
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAudit(t *testing.T) {
+	first := lintTestBundle(t, true, nil)
+	second := lintTestBundle(t, true, func(bs Bundle) {
+		bs[0].Address = filterAddr2
+	})
+	input := first[1].Address
+
+	var deposit Bundle
+	deposit.Add(1, input, 0, time.Now().Add(time.Hour), "DEPOSIT")
+	deposit.Finalize(nil)
+
+	var txs []Transaction
+	for _, bs := range []Bundle{first, second, deposit} {
+		txs = append(txs, bs...)
+	}
+	api, done := newFakeTangle(t, txs)
+	defer done()
+
+	r, err := AuditAddresses(api, Trytes(input.WithChecksum()), Trytes(filterAddr1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []AddressAudit{
+		{
+			Address: input, Checksum: input.Checksum(), Index: -1, Balance: 200, Spent: true,
+			IncomingBundles: 1, OutgoingBundles: 2,
+			Risks: []string{RiskReusedAfterSpend, RiskBalanceAfterSpend, RiskDoubleSpend},
+		},
+		{Address: filterAddr1, Checksum: filterAddr1.Checksum(), Index: -1, Balance: 200, IncomingBundles: 1},
+	}
+	if !reflect.DeepEqual(r.Addresses, want) {
+		t.Errorf("AuditAddresses() = %+v", r.Addresses)
+	}
+
+	r, err = AuditSeed(api, accountTestSeed, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Addresses) != 1 || r.Addresses[0].Index != 0 || r.Addresses[0].Address != input {
+		t.Errorf("AuditSeed() = %+v", r.Addresses)
+	}
+
+	var b bytes.Buffer
+	if err := r.ExportCSV(&b); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&b).ReadAll()
+	if err != nil || len(rows) != 2 || rows[1][2] != "0" || rows[1][7] != RiskReusedAfterSpend+";"+RiskBalanceAfterSpend+";"+RiskDoubleSpend {
+		t.Errorf("ExportCSV() = %q, %v", rows, err)
+	}
+
+	b.Reset()
+	var decoded AuditReport
+	if err := r.ExportJSON(&b); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.NewDecoder(&b).Decode(&decoded); err != nil || !reflect.DeepEqual(decoded.Addresses, r.Addresses) {
+		t.Errorf("ExportJSON() decoded to %+v, %v", decoded, err)
+	}
+}
@@ -0,0 +1,128 @@
+package giota
+
+import (
+	"hash/fnv"
+	"math"
+	"strings"
+	"sync"
+)
+
+// AddressBloom is a bloom filter of addresses. MayContain never misses an
+// added address, but also returns true for a small fraction of the other
+// ones. It is not safe for concurrent use.
+type AddressBloom struct {
+	bits []uint64
+	m    uint64
+	k    uint64
+}
+
+// NewAddressBloom returns a filter sized for n addresses with a false
+// positive rate of fpRate, e.g. 0.001.
+func NewAddressBloom(n int, fpRate float64) *AddressBloom {
+	if n < 1 {
+		n = 1
+	}
+	if fpRate <= 0 || fpRate >= 1 {
+		fpRate = 0.001
+	}
+	m := uint64(math.Ceil(-float64(n) * math.Log(fpRate) / (math.Ln2 * math.Ln2)))
+	k := uint64(math.Round(float64(m) / float64(n) * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+	return &AddressBloom{bits: make([]uint64, (m+63)/64), m: m, k: k}
+}
+
+// hashes returns the two hashes of adr combined to the k indices of the
+// filter.
+func (f *AddressBloom) hashes(adr Address) (uint64, uint64) {
+	h := fnv.New64a()
+	h.Write([]byte(adr))
+	h1 := h.Sum64()
+	h = fnv.New64()
+	h.Write([]byte(adr))
+	return h1, h.Sum64() | 1
+}
+
+// Add adds adr, which must not have a checksum.
+func (f *AddressBloom) Add(adr Address) {
+	h1, h2 := f.hashes(adr)
+	for i := uint64(0); i < f.k; i++ {
+		b := (h1 + i*h2) % f.m
+		f.bits[b/64] |= 1 << (b % 64)
+	}
+}
+
+// MayContain returns false if adr was not added, and true if it probably
+// was.
+func (f *AddressBloom) MayContain(adr Address) bool {
+	h1, h2 := f.hashes(adr)
+	for i := uint64(0); i < f.k; i++ {
+		b := (h1 + i*h2) % f.m
+		if f.bits[b/64]&(1<<(b%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// AddressWatcher picks the transactions of a large set of watched addresses
+// from a stream of transactions, e.g. the tx events of a node's ZMQ feed.
+// Its bloom filter cheaply rejects nearly all others, so that only few
+// transactions need the exact check of Contains. It is safe for concurrent
+// use.
+type AddressWatcher struct {
+	// Contains reports whether adr is watched, e.g. by a lookup in a
+	// database. It is only called for addresses passing the filter. If
+	// nil, the filter decides alone.
+	Contains func(adr Address) bool
+
+	mu     sync.RWMutex
+	filter *AddressBloom
+}
+
+// NewAddressWatcher returns a watcher of adrs, which may have checksums,
+// sized for n addresses with the false positive rate fpRate of its filter.
+func NewAddressWatcher(adrs []Address, n int, fpRate float64) *AddressWatcher {
+	if n < len(adrs) {
+		n = len(adrs)
+	}
+	w := &AddressWatcher{filter: NewAddressBloom(n, fpRate)}
+	w.Add(adrs...)
+	return w
+}
+
+// Add watches adrs, which may have checksums.
+func (w *AddressWatcher) Add(adrs ...Address) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, adr := range adrs {
+		w.filter.Add(withoutChecksum(Trytes(adr)))
+	}
+}
+
+// Watches reports whether adr is watched.
+func (w *AddressWatcher) Watches(adr Address) bool {
+	w.mu.RLock()
+	ok := w.filter.MayContain(adr)
+	w.mu.RUnlock()
+	return ok && (w.Contains == nil || w.Contains(adr))
+}
+
+// Filter returns a Filter matching the transactions on watched addresses.
+func (w *AddressWatcher) Filter() Filter {
+	return func(tx *Transaction) bool {
+		return w.Watches(tx.Address)
+	}
+}
+
+// MatchZMQ checks a tx message of the ZMQ feed of a node, "tx <hash>
+// <address> <value> ...", and returns its address if it is watched.
+func (w *AddressWatcher) MatchZMQ(msg string) (Address, bool) {
+	fields := strings.SplitN(msg, " ", 4)
+	if len(fields) < 3 || fields[0] != "tx" {
+		return "", false
+	}
+	adr := Address(fields[2])
+	return adr, w.Watches(adr)
+}
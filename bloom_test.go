@@ -0,0 +1,69 @@
+package giota
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestAddressBloom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	const n = 10000
+
+	f := NewAddressBloom(n, 0.01)
+	added := make([]Address, n)
+	for i := range added {
+		added[i] = Address(randomTrytes(r, 81))
+		f.Add(added[i])
+	}
+	for _, adr := range added {
+		if !f.MayContain(adr) {
+			t.Fatalf("MayContain(%s) = false for an added address", adr)
+		}
+	}
+
+	fp := 0
+	for i := 0; i < n; i++ {
+		if f.MayContain(Address(randomTrytes(r, 81))) {
+			fp++
+		}
+	}
+	if fp > n*2/100 {
+		t.Errorf("%d false positives of %d, expected about 1%%", fp, n)
+	}
+}
+
+func TestAddressWatcher(t *testing.T) {
+	bs := filterTestBundle()
+	w := NewAddressWatcher([]Address{Address(filterAddr1.WithChecksum())}, 100, 0.001)
+
+	if m := w.Filter().Apply(bs); len(m) == 0 || m[0].Address != filterAddr1 {
+		t.Errorf("Filter() matched %d transactions", len(m))
+	}
+	for _, tx := range w.Filter().Apply(bs) {
+		if tx.Address != filterAddr1 {
+			t.Errorf("Filter() matched a transaction of %s", tx.Address)
+		}
+	}
+
+	w.Add(filterAddr2)
+	tests := []struct {
+		msg   string
+		match bool
+	}{
+		{msg: "tx " + string(EmptyHash) + " " + string(filterAddr2) + " 10 9 1500000000 0 0", match: true},
+		{msg: "tx " + string(EmptyHash) + " " + string(EmptyHash) + " 10 9 1500000000 0 0"},
+		{msg: "sn 1 " + string(EmptyHash) + " " + string(filterAddr2)},
+		{msg: "tx"},
+	}
+	for _, tt := range tests {
+		if _, ok := w.MatchZMQ(tt.msg); ok != tt.match {
+			t.Errorf("MatchZMQ(%.20s) = %v", tt.msg, ok)
+		}
+	}
+
+	// the exact check rejects false positives of the filter
+	w.Contains = func(adr Address) bool { return adr == filterAddr1 }
+	if w.Watches(filterAddr2) || !w.Watches(filterAddr1) {
+		t.Error("Watches() ignored Contains")
+	}
+}
@@ -27,6 +27,7 @@ package giota
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"time"
 )
 
@@ -43,44 +44,127 @@ func pad(orig Trytes, size int) Trytes {
 // Bundle is transactions that are bundled (grouped) together when creating a transfer.
 type Bundle []Transaction
 
-// Add adds a bundle to bundle slice. Elements which are not specified are filled with
-// zeroed trits.
-func (bs *Bundle) Add(num int, address Address, value int64, timestamp time.Time, tag Trytes) {
-	if tag == "" {
-		tag = EmptyHash[:27]
+// EntryParams are the parameters of an entry of a bundle, see AddEntry.
+type EntryParams struct {
+	// Address may have a checksum, which is cut off.
+	Address Address
+	Value   int64
+	// Timestamp is the time of the transactions of the entry.
+	Timestamp time.Time
+	// Tag has at most 27 trytes, it is padded with 9s.
+	Tag Trytes
+	// FragmentCount is the number of transactions of the entry: the number
+	// of fragments of its message, or the security level of an input. If it
+	// is zero, one transaction is added.
+	FragmentCount int
+}
+
+// AddEntry adds an entry of p.FragmentCount transactions to the bundle, the
+// first one with the value p.Value and the others with zero value. The
+// signature message fragments, the bundle hash and the last index are set by
+// Finalize.
+func (bs *Bundle) AddEntry(p EntryParams) error {
+	p.Address = withoutChecksum(Trytes(p.Address))
+
+	var err error
+	switch {
+	case IsHash(Trytes(p.Address)) != nil:
+		err = &ValidationError{Func: "AddEntry", Arg: "Address", Index: -1, Err: IsHash(Trytes(p.Address))}
+	case p.Value > MaxSupply || p.Value < -MaxSupply:
+		err = &ValidationError{Func: "AddEntry", Arg: "Value", Index: -1, Err: errors.New("value exceeds the supply")}
+	case len(p.Tag) > TagTrinarySize/3 || IsTrytes(p.Tag) != nil:
+		err = &ValidationError{Func: "AddEntry", Arg: "Tag", Index: -1, Err: errors.New("tag must be at most 27 trytes")}
+	case p.FragmentCount < 0:
+		err = &ValidationError{Func: "AddEntry", Arg: "FragmentCount", Index: -1, Err: errors.New("fragment count must not be negative")}
+	}
+	if err != nil {
+		return err
 	}
 
-	for i := 0; i < num; i++ {
+	if p.FragmentCount == 0 {
+		p.FragmentCount = 1
+	}
+	bs.addEntry(p)
+	return nil
+}
+
+// Add adds num transactions for the address to the bundle. Elements which
+// are not specified are filled with zeroed trits.
+//
+// Deprecated: Add doesn't validate its arguments, use AddEntry.
+func (bs *Bundle) Add(num int, address Address, value int64, timestamp time.Time, tag Trytes) {
+	bs.addEntry(EntryParams{
+		Address:       address,
+		Value:         value,
+		Timestamp:     timestamp,
+		Tag:           tag,
+		FragmentCount: num,
+	})
+}
+
+func (bs *Bundle) addEntry(p EntryParams) {
+	tag := Tag(pad(p.Tag, TagTrinarySize/3))
+	for i := 0; i < p.FragmentCount; i++ {
 		var v int64
 
 		if i == 0 {
-			v = value
+			v = p.Value
 		}
 
 		b := Transaction{
 			SignatureMessageFragment:      emptySig,
-			Address:                       address,
+			Address:                       p.Address,
 			Value:                         v,
-			ObsoleteTag:                   pad(tag, TagTrinarySize/3),
-			Timestamp:                     timestamp,
-			CurrentIndex:                  int64(len(*bs) - 1),
+			ObsoleteTag:                   tag,
+			Timestamp:                     p.Timestamp,
+			CurrentIndex:                  int64(len(*bs)),
 			LastIndex:                     0,
 			Bundle:                        EmptyHash,
 			TrunkTransaction:              EmptyHash,
 			BranchTransaction:             EmptyHash,
-			Tag:                           pad(tag, TagTrinarySize/3),
+			Tag:                           tag,
 			AttachmentTimestamp:           EmptyHash,
 			AttachmentTimestampLowerBound: EmptyHash,
 			AttachmentTimestampUpperBound: EmptyHash,
-			Nonce: EmptyHash,
+			Nonce:                         EmptyHash,
 		}
 		*bs = append(*bs, b)
 	}
 }
 
-// Finalize filled sigs, bundlehash, and indices elements in bundle.
-func (bs Bundle) Finalize(sig []Trytes) {
-	h := bs.GetValidHash()
+// Clone returns a copy of bs which can be changed without affecting bs.
+func (bs Bundle) Clone() Bundle {
+	if bs == nil {
+		return nil
+	}
+	c := make(Bundle, len(bs))
+	copy(c, bs)
+	return c
+}
+
+// ResetAttachment clears the fields set by attaching the transactions of bs,
+// so that the bundle can be attached again.
+func (bs Bundle) ResetAttachment() {
+	for i := range bs {
+		bs[i] = bs[i].WithNewAttachment(EmptyHash, EmptyHash, EmptyHash, EmptyHash, EmptyHash, EmptyHash)
+	}
+}
+
+// Finalize filled sigs, bundlehash, and indices elements in bundle. Finalizing a
+// finalized bundle again doesn't change it. Signed and attached bundles can't
+// be finalized with other signature message fragments, which would invalidate
+// their signatures and nonces, ErrWrongState is returned then.
+func (bs Bundle) Finalize(sig []Trytes) error {
+	if st := bs.State(); st >= BundleSigned {
+		for i := range bs {
+			if len(sig) > i && sig[i] != "" && pad(sig[i], SignatureMessageFragmentTrinarySize/3) != bs[i].SignatureMessageFragment {
+				return wrongState("finalized", st)
+			}
+		}
+		return nil
+	}
+
+	h := bs.NormalizeObsoleteTag()
 
 	for i := range bs {
 		if len(sig) > i && sig[i] != "" {
@@ -91,57 +175,67 @@ func (bs Bundle) Finalize(sig []Trytes) {
 		bs[i].LastIndex = int64(len(bs) - 1)
 		bs[i].Bundle = h
 	}
+	return nil
 }
 
 // Hash calculates hash of Bundle.
-func (bs Bundle) Hash() Trytes {
+func (bs Bundle) Hash() BundleHash {
 	k := NewKerl()
-	buf := make(Trits, 243+81*3)
-
-	for i, b := range bs {
-		getTritsToHash(buf, &b, i, len(bs))
-		k.Absorb(buf)
-	}
-
+	k.Absorb(bs.Essence())
 	h, _ := k.Squeeze(HashSize)
-	return h.Trytes()
+	return BundleHash(h.Trytes())
 }
 
 // GetValidHash calculates hash of Bundle and increases ObsoleteTag value
-// until normalized hash doesn't have any 13
+// until normalized hash doesn't have any 13.
+//
+// Deprecated: the name hides that it changes the ObsoleteTag of the first
+// transaction. Use NormalizeObsoleteTag, or Hash to only compute the hash.
 func (bs Bundle) GetValidHash() Trytes {
-	k := NewKerl()
-	hashedLen := BundleTrinaryOffset - AddressTrinaryOffset
-
-	buf := make(Trits, hashedLen*len(bs))
-	for i, b := range bs {
-		getTritsToHash(buf[i*hashedLen:], &b, i, len(bs))
-	}
+	return Trytes(bs.NormalizeObsoleteTag())
+}
 
+// NormalizeObsoleteTag increments the ObsoleteTag of the first transaction
+// of bs until the normalized hash of bs doesn't contain 13, an M, and
+// returns the hash. Signatures of bundle hashes with M reveal a whole key
+// fragment, so nodes reject them.
+func (bs Bundle) NormalizeObsoleteTag() BundleHash {
+	k := NewKerl()
+	buf := bs.Essence()
+	offset := ObsoleteTagTrinaryOffset - AddressTrinaryOffset
 	for {
 		k.Absorb(buf)
 		hashTrits, _ := k.Squeeze(HashSize)
 		h := hashTrits.Trytes()
-		n := h.Normalize()
-		valid := true
 
-		for _, v := range n {
-			if v == 13 {
-				valid = false
-				break
-			}
+		if !HasM(h.Normalize()) {
+			bs[0].ObsoleteTag = Tag(buf[offset : offset+ObsoleteTagTrinarySize].Trytes())
+			return BundleHash(h)
 		}
 
-		offset := ObsoleteTagTrinaryOffset - AddressTrinaryOffset
+		k.Reset()
+		IncTrits(buf[offset : offset+ObsoleteTagTrinarySize])
+	}
+}
 
-		if valid {
-			bs[0].ObsoleteTag = buf[offset : offset+ObsoleteTagTrinarySize].Trytes()
-			return h
-		}
+// Essence returns the essence of t, the trits of its address, value,
+// obsolete tag, timestamp, current and last index, which are hashed for the
+// bundle hash.
+func (t *Transaction) Essence() Trits {
+	buf := make(Trits, EssenceTrinarySize)
+	getTritsToHash(buf, t, int(t.CurrentIndex), int(t.LastIndex)+1)
+	return buf
+}
 
-		k.Reset()
-		incTrits(buf[offset : offset+ObsoleteTagTrinarySize])
+// Essence returns the essences of the transactions of bs, which are hashed
+// for its bundle hash. Like Hash, it uses the positions of the transactions
+// in bs as their indices, not CurrentIndex and LastIndex.
+func (bs Bundle) Essence() Trits {
+	buf := make(Trits, len(bs)*EssenceTrinarySize)
+	for i := range bs {
+		getTritsToHash(buf[i*EssenceTrinarySize:], &bs[i], i, len(bs))
 	}
+	return buf
 }
 
 func getTritsToHash(buf Trits, b *Transaction, i, l int) {
@@ -155,8 +249,10 @@ func getTritsToHash(buf Trits, b *Transaction, i, l int) {
 
 // Categorize categorizes a list of transfers into sent and received. It is important to
 // note that zero value transfers (which for example, are being used for storing
-// addresses in the Tangle), are seen as received in this function.
+// addresses in the Tangle), are seen as received in this function. adr may have a
+// checksum.
 func (bs Bundle) Categorize(adr Address) (send Bundle, received Bundle) {
+	adr = withoutChecksum(Trytes(adr))
 	send = make(Bundle, 0, len(bs))
 	received = make(Bundle, 0, len(bs))
 
@@ -173,25 +269,228 @@ func (bs Bundle) Categorize(adr Address) (send Bundle, received Bundle) {
 	return
 }
 
+// Categories are the transactions of a bundle with the addresses of an
+// account, as returned by CategorizeAddresses.
+type Categories struct {
+	// Sent are the inputs, and the further signature fragments of inputs.
+	Sent Bundle
+	// Received are the outputs to the account in bundles which don't spend
+	// from it, including zero value transactions.
+	Received Bundle
+	// Remainder are the outputs to the account in bundles which spend from
+	// it, i.e. the change of the inputs.
+	Remainder Bundle
+}
+
+// CategorizeAddresses categorizes the transactions of bs with one of the
+// addresses adrs, which may have checksums.
+func (bs Bundle) CategorizeAddresses(adrs ...Trytes) *Categories {
+	own := make(map[Address]bool, len(adrs))
+	for _, adr := range adrs {
+		own[withoutChecksum(adr)] = true
+	}
+
+	spent := make(map[Address]bool)
+	for i := range bs {
+		if bs[i].Value < 0 && own[bs[i].Address] {
+			spent[bs[i].Address] = true
+		}
+	}
+
+	c := &Categories{}
+	for _, b := range bs {
+		switch {
+		case !own[b.Address]:
+			continue
+		case b.Value < 0 || (b.Value == 0 && spent[b.Address]):
+			c.Sent = append(c.Sent, b)
+		case b.Value > 0 && len(spent) > 0:
+			c.Remainder = append(c.Remainder, b)
+		default:
+			c.Received = append(c.Received, b)
+		}
+	}
+	return c
+}
+
+// withoutChecksum returns the address of adr, cutting off the checksum if adr
+// has one.
+func withoutChecksum(adr Trytes) Address {
+	if len(adr) == 90 {
+		adr = adr[:81]
+	}
+	return Address(adr)
+}
+
+// GroupTransactionsIntoBundles groups txs into the attachments of their
+// bundles, in order of first appearance of the bundle hash. An attachment is
+// the chain of transactions from a tail (CurrentIndex 0) along the trunk
+// transactions up to LastIndex, so each reattachment of a bundle forms its
+// own bundle ordered by CurrentIndex. Transactions of a bundle hash which are
+// not part of a complete attachment are grouped into a last, possibly
+// incomplete bundle of that hash. Duplicates of transactions are dropped.
+func GroupTransactionsIntoBundles(txs []Transaction) []Bundle {
+	var groups [][]Transaction
+	idx := make(map[BundleHash]int)
+	for _, tx := range txs {
+		i, ok := idx[tx.Bundle]
+		if !ok {
+			i = len(groups)
+			idx[tx.Bundle] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], tx)
+	}
+
+	var bundles []Bundle
+	for _, g := range groups {
+		bundles = append(bundles, groupAttachments(g)...)
+	}
+	return bundles
+}
+
+// groupAttachments splits the transactions txs of a bundle hash into their
+// attachments.
+func groupAttachments(txs []Transaction) []Bundle {
+	hashes := make([]Hash, len(txs))
+	byHash := make(map[Hash]int, len(txs))
+	for i := range txs {
+		hashes[i] = txs[i].Hash()
+		if _, ok := byHash[hashes[i]]; !ok {
+			byHash[hashes[i]] = i
+		}
+	}
+
+	var bundles []Bundle
+	used := make(map[Hash]bool, len(txs))
+	for i := range txs {
+		if txs[i].CurrentIndex != 0 || used[hashes[i]] || byHash[hashes[i]] != i {
+			continue
+		}
+
+		chain := []int{i}
+		for cur := i; txs[cur].CurrentIndex < txs[cur].LastIndex; {
+			next, ok := byHash[txs[cur].TrunkTransaction]
+			if !ok || used[hashes[next]] || txs[next].CurrentIndex != txs[cur].CurrentIndex+1 ||
+				txs[next].LastIndex != txs[i].LastIndex {
+				chain = nil
+				break
+			}
+			chain = append(chain, next)
+			cur = next
+		}
+		if chain == nil {
+			continue
+		}
+
+		b := make(Bundle, len(chain))
+		for j, c := range chain {
+			b[j] = txs[c]
+			used[hashes[c]] = true
+		}
+		bundles = append(bundles, b)
+	}
+
+	var rest Bundle
+	for i := range txs {
+		if !used[hashes[i]] {
+			used[hashes[i]] = true
+			rest = append(rest, txs[i])
+		}
+	}
+	if len(rest) > 0 {
+		sort.SliceStable(rest, func(i, j int) bool {
+			return rest[i].CurrentIndex < rest[j].CurrentIndex
+		})
+		bundles = append(bundles, rest)
+	}
+	return bundles
+}
+
+// BundlesByAttachment sorts bundles by the attachment time of their tail
+// transactions, the oldest first. Bundles without transactions come first.
+type BundlesByAttachment []Bundle
+
+func (b BundlesByAttachment) Len() int      { return len(b) }
+func (b BundlesByAttachment) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
+func (b BundlesByAttachment) Less(i, j int) bool {
+	switch {
+	case len(b[j]) == 0:
+		return false
+	case len(b[i]) == 0:
+		return true
+	}
+	return b[i][0].AttachmentTime().Before(b[j][0].AttachmentTime())
+}
+
+// Errors of Bundle.IsValid. They are wrapped with the details of the
+// transaction.
+var (
+	// ErrBundleIndex is returned for wrong current or last indices.
+	ErrBundleIndex = errors.New("invalid index in bundle")
+	// ErrBundleHash is returned if the bundle hash of a transaction doesn't
+	// match the hash of the bundle.
+	ErrBundleHash = errors.New("invalid bundle hash")
+	// ErrBundleBalance is returned if the values don't sum up to 0.
+	ErrBundleBalance = errors.New("total balance of Bundle is not 0")
+	// ErrInsecureBundleHash is returned for bundles with inputs whose
+	// normalized hash contains 13, an M, which reveals a whole key fragment
+	// by the signature. Nodes reject them.
+	ErrInsecureBundleHash = errors.New("normalized bundle hash contains M")
+	// ErrInvalidSignature is returned if the signature of an input doesn't
+	// match its address.
+	ErrInvalidSignature = errors.New("invalid signature")
+)
+
 // IsValid checks the validity of Bundle.
-// It checks that total balance==0 and that its has a valid signature.
-// The caller must call Finalize() beforehand.
-// nolint: gocyclo
+// It checks the indices and bundle hashes of the transactions, that total
+// balance==0 and that its has a valid signature. The errors wrap the Err
+// vars above. The caller must call Finalize() beforehand.
 func (bs Bundle) IsValid() error {
+	h := bs.Hash()
+
 	var total int64
-	sigs := make(map[Address][]Trytes)
+	inputs := false
 	for index, b := range bs {
 		total += b.Value
+		inputs = inputs || b.Value < 0
 
 		switch {
 		case b.CurrentIndex != int64(index):
-			return fmt.Errorf("CurrentIndex of index %d is not correct", b.CurrentIndex)
+			return fmt.Errorf("%w: CurrentIndex of index %d is %d", ErrBundleIndex, index, b.CurrentIndex)
 		case b.LastIndex != int64(len(bs)-1):
-			return fmt.Errorf("LastIndex of index %d is not correct", b.CurrentIndex)
-		case b.Value >= 0:
+			return fmt.Errorf("%w: LastIndex of index %d is %d", ErrBundleIndex, index, b.LastIndex)
+		case b.Bundle != h:
+			return fmt.Errorf("%w: bundle hash of index %d is %s instead of %s", ErrBundleHash, index, b.Bundle, h)
+		}
+	}
+
+	if total != 0 {
+		return fmt.Errorf("%w: it is %d", ErrBundleBalance, total)
+	}
+	if inputs && HasM(h.Normalize()) {
+		return ErrInsecureBundleHash
+	}
+
+	return bs.ValidateSignatures()
+}
+
+// ValidateSignatures checks the signatures of the inputs of bs against its
+// hash. The fragments of an input are the signature message fragments of
+// the input and of the following transactions with the same address and
+// zero value. It returns an error wrapping ErrInvalidSignature for the
+// first wrong one.
+func (bs Bundle) ValidateSignatures() error {
+	sigs := make(map[Address][]Trytes)
+	var adrs []Address
+	for index, b := range bs {
+		if b.Value >= 0 {
 			continue
 		}
 
+		if _, ok := sigs[b.Address]; !ok {
+			adrs = append(adrs, b.Address)
+		}
 		sigs[b.Address] = append(sigs[b.Address], b.SignatureMessageFragment)
 
 		// Find the subsequent txs with the remaining signature fragment
@@ -205,17 +504,11 @@ func (bs Bundle) IsValid() error {
 		}
 	}
 
-	// Validate the signatures
 	h := bs.Hash()
-	for adr, sig := range sigs {
-		if !IsValidSig(adr, sig, h) {
-			return errors.New("invalid signature")
+	for _, adr := range adrs {
+		if !IsValidSig(adr, sigs[adr], Trytes(h)) {
+			return fmt.Errorf("%w of input %s", ErrInvalidSignature, adr)
 		}
 	}
-
-	if total != 0 {
-		return errors.New("total balance of Bundle is not 0")
-	}
-
 	return nil
 }
@@ -0,0 +1,97 @@
+package giota
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// FieldDiff is a field of a transaction which differs between two bundles.
+type FieldDiff struct {
+	// Index is the index of the transaction in the bundles.
+	Index int
+	// Field is the name of the field of Transaction. It is empty if the
+	// transaction is missing in one of the bundles.
+	Field string
+	// A and B are the values in the bundles, as trytes or numbers. The
+	// Timestamp is given in Unix seconds, missing transactions are empty.
+	A, B string
+}
+
+func (d FieldDiff) String() string {
+	if d.Field == "" {
+		return fmt.Sprintf("transaction %d: %q != %q", d.Index, d.A, d.B)
+	}
+	return fmt.Sprintf("transaction %d: %s %s != %s", d.Index, d.Field, d.A, d.B)
+}
+
+// attachmentFields are the fields of a transaction set by attachToTangle.
+var attachmentFields = map[string]bool{
+	"TrunkTransaction":              true,
+	"BranchTransaction":             true,
+	"AttachmentTimestamp":           true,
+	"AttachmentTimestampLowerBound": true,
+	"AttachmentTimestampUpperBound": true,
+	"Nonce":                         true,
+}
+
+// Attachment reports whether the field of d is set by the attachment to the
+// Tangle, so that it differs between reattachments of a bundle.
+func (d FieldDiff) Attachment() bool {
+	return attachmentFields[d.Field]
+}
+
+// DiffBundles returns the fields of the transactions of a and b which
+// differ, ordered by transaction and field. To check that b is a
+// reattachment of a, check that all diffs are Attachment ones.
+func DiffBundles(a, b Bundle) []FieldDiff {
+	var diffs []FieldDiff
+	n := len(a)
+	if len(b) > n {
+		n = len(b)
+	}
+
+	for i := 0; i < n; i++ {
+		if i >= len(a) || i >= len(b) {
+			d := FieldDiff{Index: i}
+			if i < len(a) {
+				d.A = string(a[i].Trytes())
+			} else {
+				d.B = string(b[i].Trytes())
+			}
+			diffs = append(diffs, d)
+			continue
+		}
+
+		va, vb := reflect.ValueOf(a[i]), reflect.ValueOf(b[i])
+		for f := 0; f < va.NumField(); f++ {
+			fa, fb := fieldString(va.Field(f)), fieldString(vb.Field(f))
+			if fa != fb {
+				diffs = append(diffs, FieldDiff{Index: i, Field: va.Type().Field(f).Name, A: fa, B: fb})
+			}
+		}
+	}
+	return diffs
+}
+
+// fieldString formats a field of a transaction as it is in its trytes.
+func fieldString(v reflect.Value) string {
+	if t, ok := v.Interface().(interface{ Unix() int64 }); ok {
+		return fmt.Sprint(t.Unix())
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// EssenceEquals reports whether a and b have the same essence, the fields
+// hashed for the bundle hash and so signed: the addresses, values, obsolete
+// tags, timestamps and indices of their transactions.
+func (bs Bundle) EssenceEquals(b Bundle) bool {
+	if len(bs) != len(b) {
+		return false
+	}
+	for i := range bs {
+		if !bs[i].Essence().Equal(b[i].Essence()) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,53 @@
+package giota
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDiffBundles(t *testing.T) {
+	var a Bundle
+	a.Add(1, filterAddr1, 10, time.Unix(1500000000, 0), "DIFF")
+	a.Add(1, filterAddr2, -10, time.Unix(1500000000, 0), "")
+	a.Finalize(nil)
+
+	reattached := a.Clone()
+	for i := range reattached {
+		reattached[i] = reattached[i].WithNewAttachment(EmptyHash, EmptyHash, "NONCE", "A", "B", "C")
+	}
+	tampered := a.Clone()
+	tampered[1].Value = -11
+	tampered[1].Tag = "TAMPERED"
+
+	tests := []struct {
+		name       string
+		b          Bundle
+		diffs      int
+		attachment bool
+		essence    bool
+	}{
+		{name: "same", b: a.Clone(), attachment: true, essence: true},
+		{name: "reattached", b: reattached, diffs: 8, attachment: true, essence: true},
+		{name: "tampered", b: tampered, diffs: 2},
+		{name: "shorter", b: a[:1], diffs: 1},
+	}
+	for _, tt := range tests {
+		diffs := DiffBundles(a, tt.b)
+		attachment := true
+		for _, d := range diffs {
+			attachment = attachment && d.Attachment()
+		}
+		switch {
+		case len(diffs) != tt.diffs:
+			t.Errorf("%s: DiffBundles() = %v", tt.name, diffs)
+		case attachment != tt.attachment:
+			t.Errorf("%s: DiffBundles() = %v, expected attachment diffs only: %v", tt.name, diffs, tt.attachment)
+		case a.EssenceEquals(tt.b) != tt.essence:
+			t.Errorf("%s: EssenceEquals() = %v", tt.name, !tt.essence)
+		}
+	}
+
+	if d := DiffBundles(a, tampered); d[0].Field != "Value" || d[0].A != "-10" || d[0].B != "-11" || d[1].Field != "Tag" {
+		t.Errorf("DiffBundles() = %v", d)
+	}
+}
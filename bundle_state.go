@@ -0,0 +1,75 @@
+package giota
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// BundleState is the state of a bundle on its way to the Tangle. It is
+// derived from the transactions, see Bundle.State.
+type BundleState int
+
+// States of bundles in the order they are reached.
+const (
+	// BundleDraft is a bundle whose entries are added, but whose hash or
+	// indices are not set by Finalize.
+	BundleDraft BundleState = iota
+	// BundleFinalized is a finalized bundle with unsigned inputs.
+	BundleFinalized
+	// BundleSigned is a finalized bundle whose inputs are signed, or which
+	// has no inputs. It is ready to be attached.
+	BundleSigned
+	// BundleAttached is a signed bundle whose transactions are attached to
+	// the Tangle by DoPoW.
+	BundleAttached
+)
+
+func (s BundleState) String() string {
+	switch s {
+	case BundleDraft:
+		return "draft"
+	case BundleFinalized:
+		return "finalized"
+	case BundleSigned:
+		return "signed"
+	case BundleAttached:
+		return "attached"
+	}
+	return "unknown"
+}
+
+// ErrWrongState is returned if a bundle is finalized, signed or attached in
+// a state which doesn't allow it, e.g. if a draft is signed.
+var ErrWrongState = errors.New("bundle is in the wrong state")
+
+func wrongState(op string, s BundleState) error {
+	return fmt.Errorf("%w: %s bundle can't be %s", ErrWrongState, s, op)
+}
+
+// State returns the state of bs. Empty bundles are drafts.
+func (bs Bundle) State() BundleState {
+	if len(bs) == 0 {
+		return BundleDraft
+	}
+
+	h := bs.Hash()
+	for i := range bs {
+		if bs[i].Bundle != h || bs[i].CurrentIndex != int64(i) || bs[i].LastIndex != int64(len(bs)-1) {
+			return BundleDraft
+		}
+	}
+
+	for i := range bs {
+		if bs[i].Value < 0 && strings.Trim(string(bs[i].SignatureMessageFragment), "9") == "" {
+			return BundleFinalized
+		}
+	}
+
+	for i := range bs {
+		if bs[i].TrunkTransaction == EmptyHash || bs[i].TrunkTransaction == "" {
+			return BundleSigned
+		}
+	}
+	return BundleAttached
+}
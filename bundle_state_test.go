@@ -0,0 +1,63 @@
+package giota
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBundleState(t *testing.T) {
+	in := AddressInfo{Seed: accountTestSeed, Index: 1, Security: 2}
+	adr, err := in.Address()
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, err := NewKeys([]AddressInfo{in})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pow := func(Trytes, int) (Trytes, error) { return "NONCE", nil }
+
+	var bs Bundle
+	bs.AddEntry(EntryParams{Address: filterAddr1, Value: 10, Timestamp: time.Now()})
+	bs.AddEntry(EntryParams{Address: adr, Value: -10, Timestamp: time.Now(), FragmentCount: 2})
+
+	var attached Bundle
+	steps := []struct {
+		name string
+		f    func() error
+		err  bool
+		want BundleState
+	}{
+		{"sign draft", func() error { return bs.SignInputs(keys) }, true, BundleDraft},
+		{"attach draft", func() error { _, err := bs.DoPoW(EmptyHash, EmptyHash, 1, pow); return err }, true, BundleDraft},
+		{"finalize", func() error { return bs.Finalize(nil) }, false, BundleFinalized},
+		{"finalize again", func() error { return bs.Finalize(nil) }, false, BundleFinalized},
+		{"attach unsigned", func() error { _, err := bs.DoPoW(EmptyHash, EmptyHash, 1, pow); return err }, true, BundleFinalized},
+		{"sign", func() error { return bs.SignInputs(keys) }, false, BundleSigned},
+		{"finalize signed", func() error { return bs.Finalize(nil) }, false, BundleSigned},
+		{"finalize signed with message", func() error { return bs.Finalize([]Trytes{"FOO"}) }, true, BundleSigned},
+		{"attach", func() error {
+			attached, err = bs.DoPoW(filterTestBundle()[0].Hash(), EmptyHash, 1, pow)
+			bs = attached
+			return err
+		}, false, BundleAttached},
+		{"sign attached", func() error { return bs.SignInputs(keys) }, true, BundleAttached},
+	}
+
+	for _, s := range steps {
+		err := s.f()
+		switch {
+		case s.err && !errors.Is(err, ErrWrongState):
+			t.Errorf("%s: expected ErrWrongState, got %v", s.name, err)
+		case !s.err && err != nil:
+			t.Errorf("%s: unexpected error %s", s.name, err)
+		case bs.State() != s.want:
+			t.Errorf("%s: state is %s, want %s", s.name, bs.State(), s.want)
+		}
+	}
+
+	if err := bs.IsValid(); err != nil {
+		t.Errorf("attached bundle is invalid: %s", err)
+	}
+}
@@ -24,6 +24,13 @@ SOFTWARE.
 package giota
 
 import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 )
@@ -80,7 +87,7 @@ func TestBundle(t *testing.T) {
 			bs.Add(1, tx.addr, tx.value, parsedTime, "")
 		}
 
-		if bs.Hash() != tt.hash {
+		if Trytes(bs.Hash()) != tt.hash {
 			t.Errorf("%s: hash of bundles is illegal: %s", tt.name, bs.Hash())
 		}
 
@@ -93,3 +100,386 @@ func TestBundle(t *testing.T) {
 	}
 
 }
+
+func TestBundleClone(t *testing.T) {
+	bs := Bundle(filterTestBundle())
+	c := bs.Clone()
+	c[0].Value = 12345
+
+	if bs[0].Value == 12345 {
+		t.Error("Clone() shares transactions with the original")
+	}
+	if Bundle(nil).Clone() != nil {
+		t.Error("Clone() of nil should be nil")
+	}
+}
+
+// attachTestBundle returns a copy of bs attached to trunk with the attachment
+// time ms.
+func attachTestBundle(bs Bundle, trunk Hash, ms int64) Bundle {
+	ts := Int2Trits(ms, TimestampTrinarySize).Trytes()
+	att := make(Bundle, len(bs))
+	for i := len(bs) - 1; i >= 0; i-- {
+		att[i] = bs[i].WithNewAttachment(trunk, EmptyHash, "", ts, "", maxTimestampTrytes)
+		trunk = att[i].Hash()
+	}
+	return att
+}
+
+func TestGroupTransactionsIntoBundles(t *testing.T) {
+	bs := filterTestBundle()
+	first := attachTestBundle(bs, EmptyHash, 2000)
+	second := attachTestBundle(bs, EmptyHash, 1000)
+
+	// an incomplete attachment without its tail
+	orphan := attachTestBundle(bs, EmptyHash, 3000)[2]
+
+	txs := []Transaction{first[2], second[1], orphan, first[0], second[0], first[1], second[2], first[0]}
+	groups := GroupTransactionsIntoBundles(txs)
+
+	tests := []struct {
+		group Bundle
+		want  Bundle
+	}{
+		{groups[0], first},
+		{groups[1], second},
+		{groups[2], Bundle{orphan}},
+	}
+	if len(groups) != len(tests) {
+		t.Fatalf("GroupTransactionsIntoBundles() returned %d bundles, expected %d", len(groups), len(tests))
+	}
+	for i, tt := range tests {
+		if len(tt.group) != len(tt.want) {
+			t.Errorf("bundle %d has %d transactions, expected %d", i, len(tt.group), len(tt.want))
+			continue
+		}
+		for j := range tt.want {
+			if tt.group[j].Hash() != tt.want[j].Hash() {
+				t.Errorf("transaction %d of bundle %d is wrong", j, i)
+			}
+		}
+	}
+
+	sort.Sort(BundlesByAttachment(groups))
+	if groups[0][0].Hash() != second[0].Hash() || groups[2][0].Hash() != orphan.Hash() {
+		t.Error("BundlesByAttachment didn't sort by attachment time")
+	}
+}
+
+func TestCategorizeAddresses(t *testing.T) {
+	// filterAddr2 spends 100, 50 go to filterAddr1 twice
+	bs := filterTestBundle()
+
+	tests := []struct {
+		name      string
+		adrs      []Trytes
+		sent      int
+		received  int
+		remainder int
+	}{
+		{"receiver", []Trytes{Trytes(filterAddr1)}, 0, 2, 0},
+		{"receiver with checksum", []Trytes{filterAddr1.WithChecksum()}, 0, 2, 0},
+		{"sender", []Trytes{Trytes(filterAddr2)}, 1, 0, 0},
+		{"sender with remainder", []Trytes{filterAddr1.WithChecksum(), Trytes(filterAddr2)}, 1, 0, 2},
+		{"foreign", []Trytes{Trytes(EmptyAddress)}, 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		c := bs.CategorizeAddresses(tt.adrs...)
+		if len(c.Sent) != tt.sent || len(c.Received) != tt.received || len(c.Remainder) != tt.remainder {
+			t.Errorf("%s: got %d sent, %d received, %d remainder", tt.name, len(c.Sent), len(c.Received), len(c.Remainder))
+		}
+	}
+
+	if s, r := bs.Categorize(Address(filterAddr1.WithChecksum())); len(s) != 0 || len(r) != 2 {
+		t.Errorf("Categorize() with checksum returned %d sent and %d received", len(s), len(r))
+	}
+}
+
+func TestBundleAddEntry(t *testing.T) {
+	ts := time.Unix(1500000000, 0)
+	tests := []struct {
+		name string
+		p    EntryParams
+		n    int
+		ok   bool
+	}{
+		{"output", EntryParams{Address: filterAddr1, Value: 10, Timestamp: ts, Tag: "FOO"}, 1, true},
+		{"input", EntryParams{Address: filterAddr2, Value: -10, Timestamp: ts, FragmentCount: 2}, 2, true},
+		{"checksum", EntryParams{Address: Address(filterAddr1.WithChecksum()), Timestamp: ts}, 1, true},
+		{"short address", EntryParams{Address: "ABC", Timestamp: ts}, 0, false},
+		{"value", EntryParams{Address: filterAddr1, Value: MaxSupply + 1}, 0, false},
+		{"long tag", EntryParams{Address: filterAddr1, Tag: Trytes(EmptyHash[:28])}, 0, false},
+		{"invalid tag", EntryParams{Address: filterAddr1, Tag: "foo"}, 0, false},
+		{"fragment count", EntryParams{Address: filterAddr1, FragmentCount: -1}, 0, false},
+	}
+
+	for _, tt := range tests {
+		bs := filterTestBundle()
+		l := len(bs)
+		err := bs.AddEntry(tt.p)
+		switch {
+		case (err == nil) != tt.ok:
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		case len(bs) != l+tt.n:
+			t.Errorf("%s: added %d transactions, expected %d", tt.name, len(bs)-l, tt.n)
+		}
+
+		for i := l; i < len(bs); i++ {
+			switch {
+			case bs[i].CurrentIndex != int64(i):
+				t.Errorf("%s: transaction %d has CurrentIndex %d", tt.name, i, bs[i].CurrentIndex)
+			case bs[i].Address != filterAddr1 && bs[i].Address != filterAddr2:
+				t.Errorf("%s: transaction %d has address %s", tt.name, i, bs[i].Address)
+			case i > l && bs[i].Value != 0:
+				t.Errorf("%s: transaction %d has value %d", tt.name, i, bs[i].Value)
+			}
+		}
+	}
+}
+
+var updateMalformed = flag.Bool("update-malformed", false, "rewrite testdata/malformed/bundles.json")
+
+// malformedBundle is an entry of testdata/malformed/bundles.json. Err is the
+// name of the error IsValid must return, empty for valid bundles.
+type malformedBundle struct {
+	Name   string   `json:"name"`
+	Err    string   `json:"error"`
+	Trytes []Trytes `json:"trytes"`
+}
+
+var malformedErrs = map[string]error{
+	"ErrBundleIndex":        ErrBundleIndex,
+	"ErrBundleHash":         ErrBundleHash,
+	"ErrBundleBalance":      ErrBundleBalance,
+	"ErrInsecureBundleHash": ErrInsecureBundleHash,
+	"ErrInvalidSignature":   ErrInvalidSignature,
+}
+
+// newMalformedBundles returns the entries of the corpus, derived from a
+// signed bundle with fixed seed and timestamp.
+func newMalformedBundles(t *testing.T) []malformedBundle {
+	ts := time.Unix(1500000000, 0)
+	in := AddressInfo{Seed: accountTestSeed, Index: 0, Security: 2}
+	other := AddressInfo{Seed: accountTestSeed, Index: 1, Security: 2}
+	keys, err := NewKeys([]AddressInfo{in, other})
+	if err != nil {
+		t.Fatal(err)
+	}
+	adr, _ := in.Address()
+	emptySig := Trytes(strings.Repeat("9", sigSize))
+
+	// signed returns the signed bundle, prepared by modify before it is
+	// finalized.
+	signed := func(modify func(Bundle)) Bundle {
+		var bs Bundle
+		bs.Add(1, filterAddr1, 60, ts, "MALFORMED")
+		bs.Add(2, adr, -100, ts, "")
+		bs.Add(1, filterAddr2, 40, ts, "")
+		if modify != nil {
+			modify(bs)
+		}
+		bs.Finalize(nil)
+		if err := bs.SignInputs(keys); err != nil {
+			t.Fatal(err)
+		}
+		return bs
+	}
+	// rehash sets a new bundle hash after tampering, keeping the
+	// signatures.
+	rehash := func(bs Bundle) {
+		h := bs.NormalizeObsoleteTag()
+		for i := range bs {
+			bs[i].Bundle = h
+		}
+	}
+
+	entries := []struct {
+		name   string
+		err    string
+		tamper func(Bundle) Bundle
+	}{
+		{name: "valid", tamper: func(bs Bundle) Bundle { return bs }},
+		{name: "wrong current index", err: "ErrBundleIndex", tamper: func(bs Bundle) Bundle {
+			bs[1].CurrentIndex = 2
+			return bs
+		}},
+		{name: "wrong last index", err: "ErrBundleIndex", tamper: func(bs Bundle) Bundle {
+			for i := range bs {
+				bs[i].LastIndex = 5
+			}
+			return bs
+		}},
+		{name: "reordered transactions", err: "ErrBundleIndex", tamper: func(bs Bundle) Bundle {
+			bs[0], bs[3] = bs[3], bs[0]
+			return bs
+		}},
+		{name: "missing transaction", err: "ErrBundleIndex", tamper: func(bs Bundle) Bundle {
+			return bs[:3]
+		}},
+		{name: "tampered value", err: "ErrBundleHash", tamper: func(bs Bundle) Bundle {
+			bs[0].Value, bs[3].Value = 90, 10
+			return bs
+		}},
+		{name: "tampered address", err: "ErrBundleHash", tamper: func(bs Bundle) Bundle {
+			bs[0].Address = filterAddr2
+			return bs
+		}},
+		{name: "tampered value with new hash", err: "ErrInvalidSignature", tamper: func(bs Bundle) Bundle {
+			bs[0].Value, bs[3].Value = 90, 10
+			rehash(bs)
+			return bs
+		}},
+		{name: "unbalanced", err: "ErrBundleBalance", tamper: func(bs Bundle) Bundle {
+			bs[0].Value = 61
+			rehash(bs)
+			return bs
+		}},
+		{name: "forged signature", err: "ErrInvalidSignature", tamper: func(bs Bundle) Bundle {
+			otherAdr, _ := other.Address()
+			nh, _ := Trytes(bs.Hash()).ToNormalized()
+			frags, err := keys.Sign(otherAdr, nh)
+			if err != nil {
+				t.Fatal(err)
+			}
+			bs[1].SignatureMessageFragment, bs[2].SignatureMessageFragment = frags[0], frags[1]
+			return bs
+		}},
+		{name: "unsigned input", err: "ErrInvalidSignature", tamper: func(bs Bundle) Bundle {
+			bs[1].SignatureMessageFragment = emptySig
+			bs[2].SignatureMessageFragment = emptySig
+			return bs
+		}},
+		{name: "missing signature fragment", err: "ErrInvalidSignature", tamper: func(bs Bundle) Bundle {
+			bs[2].SignatureMessageFragment = emptySig
+			return bs
+		}},
+		{name: "signature of another bundle", err: "ErrInvalidSignature", tamper: func(bs Bundle) Bundle {
+			ob := signed(func(ob Bundle) { ob[0].Timestamp = ts.Add(time.Second) })
+			bs[1].SignatureMessageFragment = ob[1].SignatureMessageFragment
+			bs[2].SignatureMessageFragment = ob[2].SignatureMessageFragment
+			return bs
+		}},
+		{name: "normalized hash with M", err: "ErrInsecureBundleHash", tamper: func(bs Bundle) Bundle {
+			for tag := int64(0); ; tag++ {
+				bs[0].ObsoleteTag = Tag(Int2Trits(tag, ObsoleteTagTrinarySize).Trytes())
+				if h := bs.Hash(); HasM(h.Normalize()) {
+					for i := range bs {
+						bs[i].Bundle = h
+					}
+					return bs
+				}
+			}
+		}},
+	}
+
+	corpus := make([]malformedBundle, len(entries))
+	for i, e := range entries {
+		bs := e.tamper(signed(nil))
+		corpus[i] = malformedBundle{Name: e.name, Err: e.err}
+		for _, tx := range bs {
+			corpus[i].Trytes = append(corpus[i].Trytes, tx.Trytes())
+		}
+	}
+	return corpus
+}
+
+func TestMalformedBundles(t *testing.T) {
+	fn := filepath.Join("testdata", "malformed", "bundles.json")
+	if *updateMalformed {
+		b, err := json.MarshalIndent(newMalformedBundles(t), "", "\t")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fn, append(b, '\n'), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	b, err := os.ReadFile(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var corpus []malformedBundle
+	if err := json.Unmarshal(b, &corpus); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, mb := range corpus {
+		bs := make(Bundle, len(mb.Trytes))
+		for i, tr := range mb.Trytes {
+			tx, err := NewTransaction(tr)
+			if err != nil {
+				t.Fatalf("%s: %s", mb.Name, err)
+			}
+			bs[i] = *tx
+		}
+
+		err := bs.IsValid()
+		switch want, ok := malformedErrs[mb.Err]; {
+		case mb.Err == "" && err != nil:
+			t.Errorf("%s: IsValid() = %v", mb.Name, err)
+		case mb.Err != "" && !ok:
+			t.Errorf("%s: unknown error %s", mb.Name, mb.Err)
+		case mb.Err != "" && !errors.Is(err, want):
+			t.Errorf("%s: IsValid() = %v, expected %s", mb.Name, err, mb.Err)
+		}
+
+		if mb.Err == "ErrInvalidSignature" {
+			if err := bs.ValidateSignatures(); !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("%s: ValidateSignatures() = %v", mb.Name, err)
+			}
+		}
+	}
+}
+
+func TestBundleNormalizeObsoleteTag(t *testing.T) {
+	var bs Bundle
+	bs.Add(1, filterAddr1, 0, time.Unix(1500000000, 0), "")
+	// the hash with this tag has an M when normalized
+	for tag := int64(0); !HasM(bs.Hash().Normalize()); tag++ {
+		bs[0].ObsoleteTag = Tag(Int2Trits(tag, ObsoleteTagTrinarySize).Trytes())
+	}
+
+	tag := bs[0].ObsoleteTag
+	h := bs.Hash()
+	if bs[0].ObsoleteTag != tag {
+		t.Error("Hash() changed the obsolete tag")
+	}
+
+	nh := bs.NormalizeObsoleteTag()
+	switch {
+	case bs[0].ObsoleteTag == tag:
+		t.Error("NormalizeObsoleteTag() kept the obsolete tag")
+	case nh == h || nh != bs.Hash():
+		t.Errorf("NormalizeObsoleteTag() = %s, Hash() = %s", nh, bs.Hash())
+	case HasM(nh.Normalize()):
+		t.Errorf("normalized hash %s contains M", nh)
+	}
+}
+
+func TestBundleEssence(t *testing.T) {
+	var bs Bundle
+	bs.Add(1, filterAddr1, 10, time.Unix(1500000000, 0), "ESSENCE")
+	bs.Add(1, filterAddr2, -10, time.Unix(1500000001, 0), "")
+	bs.Finalize(nil)
+
+	e := bs.Essence()
+	if len(e) != len(bs)*EssenceTrinarySize {
+		t.Fatalf("len(Essence()) = %d", len(e))
+	}
+
+	// recompute the bundle hash from the essences of the transactions
+	k := NewKerl()
+	for i := range bs {
+		te := bs[i].Essence()
+		if !te.Equal(e[i*EssenceTrinarySize : (i+1)*EssenceTrinarySize]) {
+			t.Errorf("Essence() of transaction %d differs from the one of the bundle", i)
+		}
+		k.Absorb(te)
+	}
+	h, _ := k.Squeeze(HashSize)
+	if h.Trytes() != Trytes(bs[0].Bundle) {
+		t.Errorf("hash of essences = %s, expected %s", h.Trytes(), bs[0].Bundle)
+	}
+}
@@ -0,0 +1,118 @@
+package giota
+
+import (
+	"sort"
+)
+
+// Bundles is a list of bundles, e.g. the ones of GroupTransactionsIntoBundles
+// or AccountData.
+type Bundles []Bundle
+
+// FilterByAddress returns the bundles with a transaction from or to one of
+// adrs.
+func (bs Bundles) FilterByAddress(adrs ...Address) Bundles {
+	set := make(map[Address]bool, len(adrs))
+	for _, adr := range adrs {
+		set[adr] = true
+	}
+
+	var r Bundles
+	for _, b := range bs {
+		for i := range b {
+			if set[b[i].Address] {
+				r = append(r, b)
+				break
+			}
+		}
+	}
+	return r
+}
+
+// FilterConfirmed returns the bundles whose tail transaction is confirmed by
+// the latest milestone. Bundles without tail transaction are dropped.
+func (bs Bundles) FilterConfirmed(api *API) (Bundles, error) {
+	var (
+		tails    []Hash
+		withTail Bundles
+	)
+	for _, b := range bs {
+		if len(b) == 0 || b[0].CurrentIndex != 0 {
+			continue
+		}
+		tails = append(tails, b[0].Hash())
+		withTail = append(withTail, b)
+	}
+	if len(tails) == 0 {
+		return nil, nil
+	}
+
+	states, err := api.GetLatestInclusion(tails)
+	if err != nil {
+		return nil, err
+	}
+
+	var r Bundles
+	for i, b := range withTail {
+		if i < len(states) && states[i] {
+			r = append(r, b)
+		}
+	}
+	return r, nil
+}
+
+// TotalValueFor returns the sum of the values of all transactions of bs with
+// the address adr, i.e. the change of its balance if all bundles were
+// confirmed. Reattachments of a bundle are counted once, as at most one of
+// them can be confirmed.
+func (bs Bundles) TotalValueFor(adr Address) int64 {
+	var total int64
+	seen := make(map[BundleHash]bool)
+	for _, b := range bs {
+		if len(b) == 0 || seen[b[0].Bundle] {
+			continue
+		}
+		seen[b[0].Bundle] = true
+		for i := range b {
+			if b[i].Address == adr {
+				total += b[i].Value
+			}
+		}
+	}
+	return total
+}
+
+// FindByTail returns the bundle whose first transaction has the hash tail.
+func (bs Bundles) FindByTail(tail Hash) (Bundle, bool) {
+	for _, b := range bs {
+		if len(b) > 0 && b[0].Hash() == tail {
+			return b, true
+		}
+	}
+	return nil, false
+}
+
+// SortStable sorts bs by less, keeping the order of equal bundles. Bundles
+// passed to less are not empty, empty bundles are sorted first.
+func (bs Bundles) SortStable(less func(a, b Bundle) bool) {
+	sort.SliceStable(bs, func(i, j int) bool {
+		switch {
+		case len(bs[j]) == 0:
+			return false
+		case len(bs[i]) == 0:
+			return true
+		}
+		return less(bs[i], bs[j])
+	})
+}
+
+// ByTimestamp orders bundles by the timestamp of their first transaction, the
+// oldest first. It is meant for SortStable.
+func ByTimestamp(a, b Bundle) bool {
+	return a[0].Timestamp.Before(b[0].Timestamp)
+}
+
+// ByAttachment orders bundles by the attachment time of their first
+// transaction, the oldest first. It is meant for SortStable.
+func ByAttachment(a, b Bundle) bool {
+	return a[0].AttachmentTime().Before(b[0].AttachmentTime())
+}
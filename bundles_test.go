@@ -0,0 +1,85 @@
+package giota
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func bundlesTestData() Bundles {
+	var other Bundle
+	other.Add(1, filterAddr2, 30, time.Unix(1400000000, 0), "OTHER")
+	other.Add(1, filterAddr2, -30, time.Unix(1400000000, 0), "OTHER")
+	other.Finalize(nil)
+
+	return Bundles{filterTestBundle(), other}
+}
+
+func TestBundlesQueries(t *testing.T) {
+	bs := bundlesTestData()
+
+	tests := []struct {
+		name string
+		got  int
+		want int
+	}{
+		{"FilterByAddress addr1", len(bs.FilterByAddress(filterAddr1)), 1},
+		{"FilterByAddress addr2", len(bs.FilterByAddress(filterAddr2)), 2},
+		{"FilterByAddress none", len(bs.FilterByAddress(EmptyAddress)), 0},
+		{"TotalValueFor addr1", int(bs.TotalValueFor(filterAddr1)), 100},
+		{"TotalValueFor addr2", int(bs.TotalValueFor(filterAddr2)), -100},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %d, want %d", tt.name, tt.got, tt.want)
+		}
+	}
+
+	// a reattachment of the first bundle doesn't change the totals
+	reattached := filterTestBundle()
+	for i := range reattached {
+		reattached[i].TrunkTransaction = Hash(EmptyHash)
+		reattached[i].Nonce = "REATTACHED"
+	}
+	withReattachment := append(bundlesTestData(), reattached)
+	if v := withReattachment.TotalValueFor(filterAddr1); v != 100 {
+		t.Errorf("TotalValueFor() with a reattachment returned %d, expected 100", v)
+	}
+
+	if b, ok := bs.FindByTail(bs[1][0].Hash()); !ok || b[0].Bundle != bs[1][0].Bundle {
+		t.Error("FindByTail() didn't find the second bundle")
+	}
+	if _, ok := bs.FindByTail(EmptyHash); ok {
+		t.Error("FindByTail() found an unknown tail")
+	}
+
+	bs = append(bs, nil)
+	bs.SortStable(ByTimestamp)
+	if len(bs[0]) != 0 || bs[1][0].Timestamp.Unix() != 1400000000 {
+		t.Errorf("SortStable() sorted wrong: %v", bs)
+	}
+}
+
+func TestBundlesFilterConfirmed(t *testing.T) {
+	bs := bundlesTestData()
+	api, done := newFakeNode(t, map[string]fakeNodeHandler{
+		"getNodeInfo": func(map[string]json.RawMessage) interface{} {
+			return &GetNodeInfoResponse{LatestMilestone: EmptyHash}
+		},
+		"getTrytes": func(map[string]json.RawMessage) interface{} {
+			return &GetTrytesResponse{Trytes: []Transaction{bs[0][0], bs[1][0]}}
+		},
+		"getInclusionStates": func(map[string]json.RawMessage) interface{} {
+			return &GetInclusionStatesResponse{States: []bool{false, true}}
+		},
+	})
+	defer done()
+
+	confirmed, err := bs.FilterConfirmed(api)
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case len(confirmed) != 1 || confirmed[0][0].Bundle != bs[1][0].Bundle:
+		t.Errorf("FilterConfirmed() returned %d bundles", len(confirmed))
+	}
+}
@@ -0,0 +1,94 @@
+package giota
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultCallInfoHeaders are the response headers captured for CallInfo if
+// SetCallInfoHandler is called without headers. Names ending with * match
+// all headers with the prefix.
+var DefaultCallInfoHeaders = []string{"X-IOTA-*", "X-RateLimit-*", "RateLimit-*", "Retry-After"}
+
+// CallInfo is the metadata of a response of the node.
+type CallInfo struct {
+	// Command is the IRI command of the call.
+	Command    string
+	StatusCode int
+	// Header holds the captured response headers.
+	Header http.Header
+	// Duration is the time until the response headers arrived.
+	Duration time.Duration
+}
+
+// RetryAfter returns the time to wait before the next call of the
+// Retry-After header, or 0.
+func (ci *CallInfo) RetryAfter() time.Duration {
+	v := ci.Header.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	if s, err := strconv.Atoi(v); err == nil && s > 0 {
+		return time.Duration(s) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// RateLimitRemaining returns the number of calls left in the rate limit
+// window of the node, from the X-RateLimit-Remaining or RateLimit-Remaining
+// header. ok is false if the node didn't send it.
+func (ci *CallInfo) RateLimitRemaining() (n int, ok bool) {
+	for _, h := range []string{"X-RateLimit-Remaining", "RateLimit-Remaining"} {
+		if n, err := strconv.Atoi(ci.Header.Get(h)); err == nil {
+			return n, true
+		}
+	}
+	return 0, false
+}
+
+// SetCallInfoHandler makes api call fn with the metadata of every response
+// of the node, e.g. to slow down when the node throttles. Only the headers
+// matching headers are captured, DefaultCallInfoHeaders if none are given.
+// fn is called concurrently by concurrent calls, a nil fn removes the
+// handler. SetCallInfoHandler must not be called concurrently with API
+// calls.
+func (api *API) SetCallInfoHandler(fn func(*CallInfo), headers ...string) {
+	if len(headers) == 0 {
+		headers = DefaultCallInfoHeaders
+	}
+	api.onCall = fn
+	api.callHeaders = make([]string, len(headers))
+	for i, h := range headers {
+		api.callHeaders[i] = http.CanonicalHeaderKey(h)
+	}
+}
+
+// reportCall calls the CallInfo handler of api with the response of cmd.
+func (api *API) reportCall(cmd interface{}, resp *http.Response, d time.Duration) {
+	if api.onCall == nil {
+		return
+	}
+
+	ci := &CallInfo{
+		Command:    commandName(cmd),
+		StatusCode: resp.StatusCode,
+		Header:     make(http.Header),
+		Duration:   d,
+	}
+	for name, vs := range resp.Header {
+		for _, h := range api.callHeaders {
+			if name == h || strings.HasSuffix(h, "*") && strings.HasPrefix(name, strings.TrimSuffix(h, "*")) {
+				ci.Header[name] = vs
+				break
+			}
+		}
+	}
+	api.onCall(ci)
+}
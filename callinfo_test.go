@@ -0,0 +1,62 @@
+package giota
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCallInfo(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-IOTA-API-Version", "1")
+		w.Header().Set("X-RateLimit-Remaining", "7")
+		w.Header().Set("Retry-After", "30")
+		w.Header().Set("X-Other", "x")
+		w.Write([]byte(`{"hashes":[]}`))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name    string
+		headers []string
+		want    []string
+	}{
+		{name: "default", want: []string{"X-Iota-Api-Version", "X-Ratelimit-Remaining", "Retry-After"}},
+		{name: "configured", headers: []string{"x-other", "X-IOTA-API-*"}, want: []string{"X-Other", "X-Iota-Api-Version"}},
+	}
+
+	for _, tt := range tests {
+		api := NewAPI(srv.URL, nil)
+		var ci *CallInfo
+		api.SetCallInfoHandler(func(c *CallInfo) { ci = c }, tt.headers...)
+
+		if _, err := api.FindTransactions(&FindTransactionsRequest{Tags: []Trytes{"FOO"}}); err != nil {
+			t.Fatal(err)
+		}
+		switch {
+		case ci == nil:
+			t.Fatalf("%s: handler wasn't called", tt.name)
+		case ci.Command != "findTransactions" || ci.StatusCode != http.StatusOK || ci.Duration <= 0:
+			t.Errorf("%s: CallInfo is %+v", tt.name, ci)
+		case len(ci.Header) != len(tt.want):
+			t.Errorf("%s: captured headers %v, expected %v", tt.name, ci.Header, tt.want)
+		}
+		for _, h := range tt.want {
+			if _, ok := ci.Header[h]; !ok {
+				t.Errorf("%s: header %s wasn't captured", tt.name, h)
+			}
+		}
+	}
+
+	ci := &CallInfo{Header: http.Header{"X-Ratelimit-Remaining": {"7"}, "Retry-After": {"30"}}}
+	if n, ok := ci.RateLimitRemaining(); n != 7 || !ok {
+		t.Errorf("RateLimitRemaining() = %d, %v", n, ok)
+	}
+	if d := ci.RetryAfter(); d != 30*time.Second {
+		t.Errorf("RetryAfter() = %s", d)
+	}
+	if n, ok := (&CallInfo{}).RateLimitRemaining(); ok {
+		t.Errorf("RateLimitRemaining() without header = %d", n)
+	}
+}
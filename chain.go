@@ -0,0 +1,97 @@
+package giota
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrChainInconsistent is returned by ChainBuilder.Append if the last bundle
+// of the chain can't be approved anymore, e.g. because it is below the max
+// depth. A new chain must be started.
+var ErrChainInconsistent = errors.New("last bundle of the chain is not consistent")
+
+// ChainBuilder attaches bundles as a chain, where the tail of each bundle
+// approves the tail of the previous one as trunk, e.g. to promote itself or to
+// link the bundles of a data stream. The branch is selected by the node.
+// A ChainBuilder is safe for concurrent use; Appends are serialized.
+type ChainBuilder struct {
+	api   *API
+	depth int64
+	mwm   int64
+	pow   PowFunc
+
+	mu   sync.Mutex
+	last Hash
+}
+
+// NewChainBuilder returns a ChainBuilder attaching bundles with depth, mwm
+// and pow like SendTrytes does. If last is not empty, the first appended
+// bundle approves it, otherwise the chain starts with regular tips.
+func NewChainBuilder(api *API, depth, mwm int64, pow PowFunc, last Hash) *ChainBuilder {
+	return &ChainBuilder{
+		api:   api,
+		depth: depth,
+		mwm:   mwm,
+		pow:   pow,
+		last:  last,
+	}
+}
+
+// Last returns the tail hash of the last bundle of the chain.
+func (c *ChainBuilder) Last() Hash {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.last
+}
+
+// Append attaches trytes on top of the chain and broadcasts them. If the
+// node rejects the selected branch, a new one is selected up to
+// DefaultSendAttempts times. trytes itself is not changed.
+func (c *ChainBuilder) Append(trytes []Transaction) (*SendResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.last == "" {
+		res, err := SendTrytes(c.api, c.depth, trytes, c.mwm, c.pow)
+		if err != nil {
+			return nil, err
+		}
+		c.last = res.Tail
+		return res, nil
+	}
+
+	if err := c.api.validateMWM("ChainBuilder.Append", c.mwm); err != nil {
+		return nil, err
+	}
+
+	cc, err := c.api.CheckConsistency([]Hash{c.last})
+	switch {
+	case err != nil:
+		return nil, err
+	case !cc.State:
+		return nil, ErrChainInconsistent
+	}
+
+	for i := 0; i < DefaultSendAttempts; i++ {
+		var tra *GetTransactionsToApproveResponse
+		tra, err = c.api.GetTransactionsToApprove(c.depth, DefaultNumberOfWalks, c.last)
+		if err == nil {
+			// the selected tips approve the last bundle, so they are
+			// consistent with it
+			tra.BranchTransaction, tra.TrunkTransaction = tra.TrunkTransaction, c.last
+
+			var res *SendResult
+			res, err = attachAndBroadcast(c.api, tra, c.depth, trytes, c.mwm, c.pow)
+			if err == nil {
+				res.Attempts = i + 1
+				c.last = res.Tail
+				return res, nil
+			}
+		}
+
+		if !IsReferenceError(err) {
+			return nil, err
+		}
+	}
+	return nil, err
+}
@@ -0,0 +1,62 @@
+package giota
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestChainBuilder(t *testing.T) {
+	var (
+		consistent = true
+		references []Hash
+	)
+
+	tip := filterTestBundle()[2].Hash()
+	api, done := newFakeNode(t, map[string]fakeNodeHandler{
+		"getTransactionsToApprove": func(req map[string]json.RawMessage) interface{} {
+			var ref Hash
+			json.Unmarshal(req["reference"], &ref)
+			references = append(references, ref)
+			return &GetTransactionsToApproveResponse{TrunkTransaction: tip, BranchTransaction: EmptyHash}
+		},
+		"checkConsistency": func(map[string]json.RawMessage) interface{} {
+			return &CheckConsistencyResponse{State: consistent}
+		},
+		"broadcastTransactions": func(map[string]json.RawMessage) interface{} {
+			return struct{}{}
+		},
+		"storeTransactions": func(map[string]json.RawMessage) interface{} {
+			return struct{}{}
+		},
+	})
+	defer done()
+
+	pow := func(Trytes, int) (Trytes, error) { return "NONCE", nil }
+	c := NewChainBuilder(api, 3, 1, pow, "")
+
+	first, err := c.Append(filterTestBundle())
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := c.Append(filterTestBundle())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	last := second.Transactions[len(second.Transactions)-1]
+	switch {
+	case c.Last() != second.Tail:
+		t.Errorf("Last() returned %s, expected %s", c.Last(), second.Tail)
+	case second.Trunk != first.Tail || last.TrunkTransaction != Hash(first.Tail):
+		t.Errorf("second bundle approves %s, expected %s", last.TrunkTransaction, first.Tail)
+	case second.Branch != tip || last.BranchTransaction != Hash(tip):
+		t.Errorf("second bundle has branch %s, expected %s", last.BranchTransaction, tip)
+	case len(references) != 2 || references[0] != "" || references[1] != first.Tail:
+		t.Errorf("tips were selected with references %v", references)
+	}
+
+	consistent = false
+	if _, err := c.Append(filterTestBundle()); err != ErrChainInconsistent {
+		t.Errorf("Append() returned %v, expected ErrChainInconsistent", err)
+	}
+}
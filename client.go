@@ -0,0 +1,64 @@
+package giota
+
+// APIClient is the node layer used by the high-level funcs like
+// PrepareTransfers, SendTrytes and GetAccountData: the commands of the node
+// and their conveniences. *API implements it; the apimock package has a mock
+// for tests.
+//
+// Tracing, events, transfer policies and the validation settings are
+// features of *API. They stay enabled for clients embedding an *API, like
+// PermanodeAPI, and for decorators returning the client they wrap by
+// Unwrap, see UnwrapClient. Other implementations go without them.
+type APIClient interface {
+	GetNodeInfo() (*GetNodeInfoResponse, error)
+	CheckConsistency(tails []Hash) (*CheckConsistencyResponse, error)
+	GetNeighbors() (*GetNeighborsResponse, error)
+	AddNeighbors(uris []string) (*AddNeighborsResponse, error)
+	RemoveNeighbors(uris []string) (*RemoveNeighborsResponse, error)
+	GetTips() (*GetTipsResponse, error)
+	FindTransactions(ft *FindTransactionsRequest) (*FindTransactionsResponse, error)
+	FindTransactionObjects(ft *FindTransactionsRequest) ([]Transaction, error)
+	GetTrytes(hashes []Hash) (*GetTrytesResponse, error)
+	GetInclusionStates(tx []Hash, tips []Hash) (*GetInclusionStatesResponse, error)
+	GetLatestInclusion(hash []Hash) ([]bool, error)
+	GetBalances(adr []Address, threshold int64) (*GetBalancesResponse, error)
+	Balances(adr []Address) (Balances, error)
+	GetTransactionsToApprove(depth, numWalks int64, reference Hash) (*GetTransactionsToApproveResponse, error)
+	AttachToTangle(att *AttachToTangleRequest) (*AttachToTangleResponse, error)
+	InterruptAttachingToTangle() error
+	BroadcastTransactions(trytes []Transaction) error
+	StoreTransactions(trytes []Transaction) error
+	GetBundlesFromAddresses(adrs []Address) (Bundles, error)
+}
+
+var _ APIClient = (*API)(nil)
+
+// UnwrapClient is implemented by APIClients decorating another APIClient,
+// e.g. to retry or log the commands. Unwrap returns the decorated client, so
+// that the features of an *API below it stay enabled.
+type UnwrapClient interface {
+	APIClient
+	Unwrap() APIClient
+}
+
+// self returns api. As it is promoted to structs embedding an *API, apiOf
+// finds the *API of those.
+func (api *API) self() *API {
+	return api
+}
+
+// apiOf returns the *API of c, unwrapping decorators and structs embedding
+// an *API, else nil, whose tracing, events and validation are disabled.
+func apiOf(c APIClient) *API {
+	for c != nil {
+		switch w := c.(type) {
+		case interface{ self() *API }:
+			return w.self()
+		case UnwrapClient:
+			c = w.Unwrap()
+		default:
+			return nil
+		}
+	}
+	return nil
+}
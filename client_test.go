@@ -0,0 +1,64 @@
+package giota
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// embeddingClient adds a method to an embedded *API.
+type embeddingClient struct {
+	*API
+}
+
+// wrappingClient decorates the client it holds.
+type wrappingClient struct {
+	APIClient
+}
+
+func (c *wrappingClient) Unwrap() APIClient {
+	return c.APIClient
+}
+
+// opaqueClient hides the client it holds.
+type opaqueClient struct {
+	APIClient
+}
+
+func TestAPIOf(t *testing.T) {
+	api := NewAPI("", nil)
+	tests := []struct {
+		name string
+		c    APIClient
+		api  *API
+	}{
+		{name: "API", c: api, api: api},
+		{name: "embedding client", c: &embeddingClient{api}, api: api},
+		{name: "permanode", c: &PermanodeAPI{api}, api: api},
+		{name: "wrapping client", c: &wrappingClient{&embeddingClient{api}}, api: api},
+		{name: "opaque client", c: &opaqueClient{api}},
+		{name: "nil", c: nil},
+	}
+	for _, tt := range tests {
+		if got := apiOf(tt.c); got != tt.api {
+			t.Errorf("%s: apiOf() returned %p, expected %p", tt.name, got, tt.api)
+		}
+	}
+}
+
+func TestTransferPolicyOfWrappedAPI(t *testing.T) {
+	api, done := newFakeNode(t, map[string]fakeNodeHandler{
+		"getBalances": func(map[string]json.RawMessage) interface{} {
+			return map[string]interface{}{"balances": []string{"100"}}
+		},
+	})
+	defer done()
+	api.SetTransferPolicy(denyValue(50))
+
+	inputs := []AddressInfo{{Seed: accountTestSeed, Index: 0, Security: SecurityLevelLow}}
+	trs := []Transfer{{Address: filterAddr1, Value: 51}}
+	for _, c := range []APIClient{&embeddingClient{api}, &wrappingClient{api}} {
+		if _, err := PrepareTransfers(c, accountTestSeed, trs, inputs, filterAddr2, SecurityLevelLow); err == nil {
+			t.Errorf("PrepareTransfers() with a %T ignored the policy", c)
+		}
+	}
+}
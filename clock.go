@@ -0,0 +1,26 @@
+package giota
+
+import "time"
+
+// Clock tells the time used to stamp the transactions of new bundles.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// SystemClock is the Clock of the system, which is used by default.
+var SystemClock Clock = systemClock{}
+
+// FixedClock is a Clock which always tells the same time, e.g. to get
+// deterministic bundle hashes in tests or for reproducible offline signing.
+type FixedClock time.Time
+
+// Now returns the time of c.
+func (c FixedClock) Now() time.Time {
+	return time.Time(c)
+}
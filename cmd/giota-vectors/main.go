@@ -0,0 +1,245 @@
+// Command giota-vectors writes deterministic test vectors computed by giota
+// as JSON, so that other IOTA libraries and auditors can cross-check their
+// outputs. The format is the one read from testdata/compat by the giota tests.
+//
+// Usage:
+//
+//	giota-vectors [-o file] [-addresses n] [-mwm n]
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/iotaledger/giota"
+)
+
+// seeds are the fixed seeds all vectors are derived from.
+var seeds = []giota.Trytes{
+	"WQNZOHUT99PWKEBFSKQSYNC9XHT9GEBMOSJAQDQAXPEZPJNDIUB9TSNWVMHKWICW9WVZXSMDFGISOD9FZ",
+	"ABCDEFGHIJKLMNOPQRSTUVWXYZ9ABCDEFGHIJKLMNOPQRSTUVWXYZ9ABCDEFGHIJKLMNOPQRSTUVWXYZ9",
+	"999999999999999999999999999999999999999999999999999999999999999999999999999999999",
+}
+
+// bundleHash is the fixed hash signed by the signature vectors.
+const bundleHash giota.Trytes = "QYTRDVKPBDNQUIBAAZMMIKRBOIKZ9NRV9BAMWJEJDWBWEUKNQDRLSNMIWFHHGQZNFVCCOQOJRNHANVAMZ"
+
+// timestamp is the fixed timestamp of the bundle vectors.
+var timestamp = time.Unix(1500000000, 0)
+
+type addressVector struct {
+	Seed     giota.Trytes        `json:"seed"`
+	Index    uint64              `json:"index"`
+	Security giota.SecurityLevel `json:"security"`
+	Address  giota.Address       `json:"address"`
+	Digest   giota.Trytes        `json:"digest"`
+}
+
+type signatureVector struct {
+	Seed       giota.Trytes        `json:"seed"`
+	Index      uint64              `json:"index"`
+	Security   giota.SecurityLevel `json:"security"`
+	BundleHash giota.Trytes        `json:"bundleHash"`
+	Signature  []giota.Trytes      `json:"signature"`
+}
+
+type bundleEntry struct {
+	Address   giota.Address `json:"address"`
+	Value     int64         `json:"value"`
+	Tag       giota.Trytes  `json:"tag"`
+	Timestamp int64         `json:"timestamp"`
+	Count     int           `json:"count"`
+}
+
+type bundleVector struct {
+	Entries     []bundleEntry `json:"entries"`
+	ObsoleteTag giota.Trytes  `json:"obsoleteTag"`
+	Hash        giota.Trytes  `json:"hash"`
+}
+
+type nonceVector struct {
+	Trytes             giota.Trytes `json:"trytes"`
+	MinWeightMagnitude int64        `json:"mwm"`
+	Nonce              giota.Trytes `json:"nonce"`
+	Hash               giota.Hash   `json:"hash"`
+}
+
+type vectors struct {
+	Source     string            `json:"source"`
+	Addresses  []addressVector   `json:"addresses"`
+	Signatures []signatureVector `json:"signatures"`
+	Bundles    []bundleVector    `json:"bundles"`
+	Nonces     []nonceVector     `json:"nonces"`
+}
+
+func main() {
+	out := flag.String("o", "", "write the vectors to `file` instead of stdout")
+	n := flag.Int("addresses", 2, "number of address indexes per seed and security level")
+	mwm := flag.Int64("mwm", 5, "min weight magnitude of the nonce vectors")
+	flag.Parse()
+
+	if *n < 1 {
+		log.Fatal("-addresses must be at least 1")
+	}
+
+	v, err := generate(*n, *mwm)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func generate(n int, mwm int64) (*vectors, error) {
+	v := &vectors{Source: "giota-vectors"}
+
+	for _, seed := range seeds {
+		for sec := giota.SecurityLevel(1); sec <= 3; sec++ {
+			for i := uint64(0); i < uint64(n); i++ {
+				a, err := addressVectorOf(seed, i, sec)
+				if err != nil {
+					return nil, err
+				}
+				v.Addresses = append(v.Addresses, *a)
+			}
+
+			s, err := signatureVectorOf(seed, 0, sec)
+			if err != nil {
+				return nil, err
+			}
+			v.Signatures = append(v.Signatures, *s)
+		}
+	}
+
+	bs, b, err := bundleVectorOf(v.Addresses)
+	if err != nil {
+		return nil, err
+	}
+	v.Bundles = append(v.Bundles, *b)
+
+	pow, err := giota.GetPowFuncWithOptions("PowGo", &giota.PowOptions{Procs: 1})
+	if err != nil {
+		return nil, err
+	}
+	for i := range bs {
+		nv, err := nonceVectorOf(&bs[i], mwm, pow)
+		if err != nil {
+			return nil, err
+		}
+		v.Nonces = append(v.Nonces, *nv)
+	}
+	return v, nil
+}
+
+func addressVectorOf(seed giota.Trytes, index uint64, sec giota.SecurityLevel) (*addressVector, error) {
+	d, err := giota.NewAddressDigest(seed, index, sec)
+	if err != nil {
+		return nil, err
+	}
+	adr, err := d.Address()
+	if err != nil {
+		return nil, err
+	}
+	return &addressVector{
+		Seed:     seed,
+		Index:    index,
+		Security: sec,
+		Address:  adr,
+		Digest:   d.Digest,
+	}, nil
+}
+
+func signatureVectorOf(seed giota.Trytes, index uint64, sec giota.SecurityLevel) (*signatureVector, error) {
+	in := giota.AddressInfo{Seed: seed, Index: index, Security: sec}
+	keys, err := giota.NewKeys([]giota.AddressInfo{in})
+	if err != nil {
+		return nil, err
+	}
+	adr, err := in.Address()
+	if err != nil {
+		return nil, err
+	}
+	normalized, err := bundleHash.ToNormalized()
+	if err != nil {
+		return nil, err
+	}
+	sig, err := keys.Sign(adr, normalized)
+	if err != nil {
+		return nil, err
+	}
+	return &signatureVector{
+		Seed:       seed,
+		Index:      index,
+		Security:   sec,
+		BundleHash: bundleHash,
+		Signature:  sig,
+	}, nil
+}
+
+// bundleVectorOf builds a bundle moving a fixed value between the first
+// addresses of as, and returns it together with its vector.
+func bundleVectorOf(as []addressVector) (giota.Bundle, *bundleVector, error) {
+	es := []bundleEntry{
+		{Address: as[1].Address, Value: 100, Tag: "GIOTA9VECTORS", Timestamp: timestamp.Unix(), Count: 1},
+		{Address: as[0].Address, Value: -100, Timestamp: timestamp.Unix(), Count: int(as[0].Security)},
+		{Address: as[2].Address, Timestamp: timestamp.Unix(), Count: 1},
+	}
+
+	var bs giota.Bundle
+	for _, e := range es {
+		err := bs.AddEntry(giota.EntryParams{
+			Address:       e.Address,
+			Value:         e.Value,
+			Timestamp:     time.Unix(e.Timestamp, 0),
+			Tag:           e.Tag,
+			FragmentCount: e.Count,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := bs.Finalize(nil); err != nil {
+		return nil, nil, err
+	}
+
+	return bs, &bundleVector{
+		Entries:     es,
+		ObsoleteTag: giota.Trytes(bs[0].ObsoleteTag),
+		Hash:        giota.Trytes(bs[0].Bundle),
+	}, nil
+}
+
+func nonceVectorOf(tx *giota.Transaction, mwm int64, pow giota.PowFunc) (*nonceVector, error) {
+	tr := tx.Trytes()
+	nonce, err := pow(tr, int(mwm))
+	if err != nil {
+		return nil, err
+	}
+
+	t := *tx
+	t.Nonce = nonce
+	return &nonceVector{
+		Trytes:             tr,
+		MinWeightMagnitude: mwm,
+		Nonce:              nonce,
+		Hash:               t.Hash(),
+	}, nil
+}
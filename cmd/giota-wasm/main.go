@@ -0,0 +1,170 @@
+//go:build js && wasm
+
+// Command giota-wasm exports address generation, bundle signing and PoW of
+// giota to JavaScript as the global object giota. Build it with
+//
+//	GOOS=js GOARCH=wasm go build -o giota.wasm ./cmd/giota-wasm
+//
+// and run it with wasm_exec.js of the Go distribution. All functions return
+// promises, which are rejected with an Error if the arguments are invalid:
+//
+//	giota.newAddress(seed, index, security, checksum) -> address
+//	giota.signBundle(trytes, [{seed, index, security}]) -> trytes
+//	giota.pow(trytes, mwm) -> nonce
+//
+// trytes are the trytes of the transactions of a finalized bundle. PoW runs
+// on the pure Go PoW, which is the only one without cgo.
+//
+// js/wasm runs on a single thread, so a PoW blocks the page it runs in until
+// it is done. Load giota.wasm in a Web Worker to keep the page responsive.
+package main
+
+import (
+	"errors"
+	"syscall/js"
+
+	"github.com/iotaledger/giota"
+)
+
+func main() {
+	js.Global().Set("giota", js.ValueOf(map[string]interface{}{
+		"newAddress": promise(newAddress),
+		"signBundle": promise(signBundle),
+		"pow":        promise(pow),
+	}))
+	// keep the exported funcs alive
+	select {}
+}
+
+// promise returns a JavaScript function returning a promise of the result
+// of f. f runs on its own goroutine, as callbacks from JavaScript must not
+// block, but shares the single thread of js/wasm with the event loop.
+func promise(f func(args []js.Value) (interface{}, error)) js.Func {
+	return js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		handler := js.FuncOf(func(this js.Value, cbs []js.Value) interface{} {
+			resolve, reject := cbs[0], cbs[1]
+			go func() {
+				res, err := call(f, args)
+				if err != nil {
+					reject.Invoke(js.Global().Get("Error").New(err.Error()))
+					return
+				}
+				resolve.Invoke(res)
+			}()
+			return nil
+		})
+		p := js.Global().Get("Promise").New(handler)
+		handler.Release()
+		return p
+	})
+}
+
+// call calls f, turning panics of syscall/js about values of wrong types
+// into errors.
+func call(f func(args []js.Value) (interface{}, error), args []js.Value) (res interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if e, ok := r.(*js.ValueError); ok {
+				err = e
+				return
+			}
+			panic(r)
+		}
+	}()
+	return f(args)
+}
+
+func arg(args []js.Value, i int) js.Value {
+	if i < len(args) {
+		return args[i]
+	}
+	return js.Undefined()
+}
+
+// keyIndex returns the key index v, which must not be negative.
+func keyIndex(v js.Value) (uint64, error) {
+	i := v.Int()
+	if i < 0 {
+		return 0, errors.New("index must not be negative")
+	}
+	return uint64(i), nil
+}
+
+func newAddress(args []js.Value) (interface{}, error) {
+	seed, err := giota.ToTrytes(arg(args, 0).String())
+	if err != nil {
+		return nil, err
+	}
+	index, err := keyIndex(arg(args, 1))
+	if err != nil {
+		return nil, err
+	}
+	adr, err := giota.NewAddress(seed, index, giota.SecurityLevel(arg(args, 2).Int()))
+	if err != nil {
+		return nil, err
+	}
+	if arg(args, 3).Truthy() {
+		return string(adr.WithChecksum()), nil
+	}
+	return string(adr), nil
+}
+
+func signBundle(args []js.Value) (interface{}, error) {
+	jtxs, jinputs := arg(args, 0), arg(args, 1)
+
+	bdl := make(giota.Bundle, jtxs.Length())
+	for i := range bdl {
+		tx, err := giota.NewTransaction(giota.Trytes(jtxs.Index(i).String()))
+		if err != nil {
+			return nil, err
+		}
+		bdl[i] = *tx
+	}
+
+	inputs := make([]giota.AddressInfo, jinputs.Length())
+	for i := range inputs {
+		in := jinputs.Index(i)
+		seed, err := giota.ToTrytes(in.Get("seed").String())
+		if err != nil {
+			return nil, err
+		}
+		index, err := keyIndex(in.Get("index"))
+		if err != nil {
+			return nil, err
+		}
+		inputs[i] = giota.AddressInfo{
+			Seed:     seed,
+			Index:    index,
+			Security: giota.SecurityLevel(in.Get("security").Int()),
+		}
+	}
+
+	keys, err := giota.NewKeys(inputs)
+	if err != nil {
+		return nil, err
+	}
+	if err := bdl.SignInputs(keys); err != nil {
+		return nil, err
+	}
+
+	res := make([]interface{}, len(bdl))
+	for i := range bdl {
+		res[i] = string(bdl[i].Trytes())
+	}
+	return res, nil
+}
+
+func pow(args []js.Value) (interface{}, error) {
+	trytes, err := giota.ToTrytes(arg(args, 0).String())
+	if err != nil {
+		return nil, err
+	}
+	if len(trytes) != giota.TransactionTrinarySize/3 {
+		return nil, errors.New("trytes must be a transaction")
+	}
+	nonce, err := giota.PowGo(trytes, arg(args, 1).Int())
+	if err != nil {
+		return nil, err
+	}
+	return string(nonce), nil
+}
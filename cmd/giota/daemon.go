@@ -0,0 +1,57 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/iotaledger/giota"
+)
+
+// daemonFlags are the flags shared by the commands running a daemon with
+// the seed of GIOTA_SEED.
+type daemonFlags struct {
+	node     *string
+	security *int
+	depth    *int64
+	mwm      *int64
+	localPow *bool
+}
+
+func addDaemonFlags(fs *flag.FlagSet) *daemonFlags {
+	return &daemonFlags{
+		node:     fs.String("node", "http://localhost:14265", "`url` of the node"),
+		security: fs.Int("security", int(giota.SecurityLevelMedium), "security level of the addresses of the seed"),
+		depth:    fs.Int64("depth", giota.DefaultDepth, "depth of the tip selection"),
+		mwm:      fs.Int64("mwm", giota.DefaultMinWeightMagnitude, "min weight magnitude"),
+		localPow: fs.Bool("pow", true, "do the PoW locally instead of calling attachToTangle"),
+	}
+}
+
+// seed returns the seed of GIOTA_SEED.
+func (f *daemonFlags) seed() (giota.Trytes, error) {
+	seed := giota.Trytes(os.Getenv("GIOTA_SEED"))
+	if err := seed.IsValid(); err != nil || len(seed) != 81 {
+		return "", errors.New("GIOTA_SEED must be set to a seed of 81 trytes")
+	}
+	return seed, nil
+}
+
+// pow returns the best PoW func if the PoW is done locally, else nil.
+func (f *daemonFlags) pow() giota.PowFunc {
+	if !*f.localPow {
+		return nil
+	}
+	name, pow := giota.GetBestPoW()
+	log.Printf("using %s", name)
+	return pow
+}
+
+// api returns the API of the node, identifying the daemon of cmd by its
+// User-Agent.
+func (f *daemonFlags) api(cmd string) *giota.API {
+	api := giota.NewAPI(*f.node, nil)
+	api.SetUserAgent("giota-" + cmd)
+	return api
+}
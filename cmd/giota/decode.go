@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/iotaledger/giota"
+	"github.com/iotaledger/giota/decode"
+)
+
+var decodeCmd = &command{
+	name:  "decode",
+	usage: "annotate raw transaction trytes",
+	run:   runDecode,
+}
+
+func runDecode(args []string) error {
+	fs := flag.NewFlagSet("decode", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "print the decoded transactions as JSON")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: giota decode [-json] [trytes...]\n\nTrytes are read from stdin, one transaction per line, if none are given.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	trytes := fs.Args()
+	if len(trytes) == 0 {
+		s := bufio.NewScanner(os.Stdin)
+		s.Buffer(nil, 1<<20)
+		for s.Scan() {
+			if l := strings.TrimSpace(s.Text()); l != "" {
+				trytes = append(trytes, l)
+			}
+		}
+		if err := s.Err(); err != nil {
+			return err
+		}
+	}
+
+	for i, t := range trytes {
+		tx, err := decode.Decode(giota.Trytes(t))
+		if err != nil {
+			return fmt.Errorf("transaction %d: %s", i, err)
+		}
+
+		if *asJSON {
+			err = json.NewEncoder(os.Stdout).Encode(tx)
+		} else {
+			if i > 0 {
+				fmt.Println()
+			}
+			err = printDecoded(os.Stdout, tx)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// previewSize is the number of characters of long values which are printed,
+// enough for an address with checksum.
+const previewSize = 90
+
+func printDecoded(w io.Writer, tx *decode.Transaction) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "hash\t\t\t%s\n", tx.Hash)
+	fmt.Fprintf(tw, "weight\t\t\t%d\n", tx.Weight)
+	fmt.Fprintln(tw, "\noffset\tsize\tfield\tvalue")
+
+	for _, f := range tx.Fields {
+		v := f.Value
+		if len(v) > previewSize {
+			v = v[:previewSize] + "..."
+		}
+		fmt.Fprintf(tw, "%d\t%d\t%s\t%s\n", f.Offset/3, f.Size/3, f.Name, v)
+	}
+
+	fmt.Fprintln(tw)
+	for _, c := range tx.Checks {
+		res := "ok"
+		if !c.OK {
+			res = "FAILED: " + c.Detail
+		}
+		fmt.Fprintf(tw, "check\t\t%s\t%s\n", c.Name, res)
+	}
+
+	if tx.Message != "" {
+		fmt.Fprintf(tw, "\nmessage\t\t\t%q\n", tx.Message)
+	}
+	return tw.Flush()
+}
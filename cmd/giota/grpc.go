@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+
+	"github.com/iotaledger/giota"
+	"github.com/iotaledger/giota/grpcapi"
+)
+
+var grpcCmd = &command{
+	name:  "grpc",
+	usage: "run the gRPC signing and transfer daemon",
+	run:   runGRPC,
+}
+
+func runGRPC(args []string) error {
+	fs := flag.NewFlagSet("grpc", flag.ExitOnError)
+	listen := fs.String("listen", "127.0.0.1:14700", "`address` to listen on")
+	df := addDaemonFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: giota grpc [flags]\n\nThe seed is read from the GIOTA_SEED environment variable.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	seed, err := df.seed()
+	if err != nil {
+		return err
+	}
+
+	srv := &grpcapi.Server{
+		API:      df.api("grpc"),
+		Seed:     seed,
+		Security: giota.SecurityLevel(*df.security),
+		Depth:    *df.depth,
+		MWM:      *df.mwm,
+		Pow:      df.pow(),
+	}
+
+	lis, err := net.Listen("tcp", *listen)
+	if err != nil {
+		return err
+	}
+	log.Printf("serving on %s", lis.Addr())
+	return grpcapi.NewServer(srv).Serve(lis)
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/iotaledger/giota"
+	"github.com/iotaledger/giota/jsonrpc"
+)
+
+var jsonrpcCmd = &command{
+	name:  "jsonrpc",
+	usage: "run the JSON-RPC wallet daemon",
+	run:   runJSONRPC,
+}
+
+func runJSONRPC(args []string) error {
+	fs := flag.NewFlagSet("jsonrpc", flag.ExitOnError)
+	listen := fs.String("listen", "127.0.0.1:14701", "`address` to listen on, or path of a unix socket with -unix")
+	unix := fs.Bool("unix", false, "listen on a unix socket")
+	df := addDaemonFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: giota jsonrpc [flags]\n\nThe seed is read from the GIOTA_SEED environment variable. If\nGIOTA_JSONRPC_TOKEN is set, clients must send it as bearer token.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	seed, err := df.seed()
+	if err != nil {
+		return err
+	}
+
+	srv := &jsonrpc.Server{
+		API:      df.api("jsonrpc"),
+		Seed:     seed,
+		Security: giota.SecurityLevel(*df.security),
+		Depth:    *df.depth,
+		MWM:      *df.mwm,
+		Pow:      df.pow(),
+		Token:    os.Getenv("GIOTA_JSONRPC_TOKEN"),
+	}
+
+	network := "tcp"
+	if *unix {
+		network = "unix"
+	}
+	log.Printf("serving on %s %s", network, *listen)
+	return srv.ListenAndServe(network, *listen)
+}
@@ -0,0 +1,57 @@
+// Command giota is a command line tool for working with IOTA transactions.
+//
+// Usage:
+//
+//	giota <command> [arguments]
+//
+// The commands are:
+//
+//	decode     annotate raw transaction trytes
+//	grpc       run the gRPC signing and transfer daemon
+//	jsonrpc    run the JSON-RPC wallet daemon
+//	signserver run the cold signing daemon
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) error
+}
+
+var commands = []*command{
+	decodeCmd,
+	grpcCmd,
+	jsonrpcCmd,
+	signserverCmd,
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: giota <command> [arguments]\n\ncommands:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "\t%-10s %s\n", c.name, c.usage)
+	}
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	for _, c := range commands {
+		if c.name != os.Args[1] {
+			continue
+		}
+		if err := c.run(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "giota %s: %s\n", c.name, err)
+			os.Exit(1)
+		}
+		return
+	}
+	usage()
+}
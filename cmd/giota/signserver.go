@@ -0,0 +1,62 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/iotaledger/giota"
+	"github.com/iotaledger/giota/signserver"
+)
+
+var signserverCmd = &command{
+	name:  "signserver",
+	usage: "run the cold signing daemon",
+	run:   runSignserver,
+}
+
+func runSignserver(args []string) error {
+	fs := flag.NewFlagSet("signserver", flag.ExitOnError)
+	listen := fs.String("listen", "127.0.0.1:14702", "`address` to listen on, or path of a unix socket with -unix")
+	unix := fs.Bool("unix", false, "listen on a unix socket")
+	maxPerDay := fs.Int64("max-per-day", 0, "max value signed per day, 0 for no limit")
+	allow := fs.String("allow", "", "comma separated `addresses` outputs may go to, empty for all")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: giota signserver [flags]\n\nThe seed is read from the GIOTA_SEED environment variable.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	seed, err := (&daemonFlags{}).seed()
+	if err != nil {
+		return err
+	}
+
+	srv := &signserver.Server{
+		Seed:   seed,
+		Policy: signserver.Policy{MaxValuePerDay: *maxPerDay},
+	}
+	if *allow != "" {
+		for _, s := range strings.Split(*allow, ",") {
+			s = strings.TrimSpace(s)
+			adr, err := giota.ToAddress(s)
+			if len(s) == 90 {
+				adr, err = giota.ValidateChecksummedString(s)
+			}
+			if err != nil {
+				return errors.New("invalid address in -allow: " + s)
+			}
+			srv.Policy.Destinations = append(srv.Policy.Destinations, adr)
+		}
+	}
+
+	network := "tcp"
+	if *unix {
+		network = "unix"
+	}
+	log.Printf("serving on %s %s", network, *listen)
+	return srv.ListenAndServe(network, *listen)
+}
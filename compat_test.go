@@ -0,0 +1,177 @@
+package giota
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// compatVectors is the format of the shared test vectors in testdata/compat.
+// Vectors exported from other IOTA libraries catch divergences in key
+// derivation, signing, bundle hashing and PoW, and Source records where they
+// were taken from. The vectors of giota itself are written by
+// cmd/giota-vectors and only guard against regressions. Empty fields are not
+// checked.
+type compatVectors struct {
+	Source    string `json:"source"`
+	Addresses []struct {
+		Seed     Trytes        `json:"seed"`
+		Index    uint64        `json:"index"`
+		Security SecurityLevel `json:"security"`
+		Address  Address       `json:"address"`
+		Digest   Trytes        `json:"digest"`
+	} `json:"addresses"`
+	Signatures []struct {
+		Seed       Trytes        `json:"seed"`
+		Index      uint64        `json:"index"`
+		Security   SecurityLevel `json:"security"`
+		BundleHash Trytes        `json:"bundleHash"`
+		Signature  []Trytes      `json:"signature"`
+	} `json:"signatures"`
+	Validations []struct {
+		Address    Address  `json:"address"`
+		BundleHash Trytes   `json:"bundleHash"`
+		Signature  []Trytes `json:"signature"`
+	} `json:"validations"`
+	Bundles []struct {
+		Entries []struct {
+			Address   Address `json:"address"`
+			Value     int64   `json:"value"`
+			Tag       Trytes  `json:"tag"`
+			Timestamp int64   `json:"timestamp"`
+			Count     int     `json:"count"`
+		} `json:"entries"`
+		ObsoleteTag Trytes `json:"obsoleteTag"`
+		Hash        Trytes `json:"hash"`
+	} `json:"bundles"`
+	Nonces []struct {
+		Trytes             Trytes `json:"trytes"`
+		MinWeightMagnitude int64  `json:"mwm"`
+		Nonce              Trytes `json:"nonce"`
+		Hash               Hash   `json:"hash"`
+	} `json:"nonces"`
+}
+
+func loadCompatVectors(t *testing.T) map[string]*compatVectors {
+	files, err := filepath.Glob(filepath.Join("testdata", "compat", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vs := make(map[string]*compatVectors, len(files))
+	for _, fn := range files {
+		f, err := os.Open(fn)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		v := &compatVectors{}
+		err = json.NewDecoder(f).Decode(v)
+		f.Close()
+		if err != nil {
+			t.Fatalf("%s: %s", fn, err)
+		}
+		vs[fn] = v
+	}
+	return vs
+}
+
+func TestCompatVectors(t *testing.T) {
+	for fn, v := range loadCompatVectors(t) {
+		for i, a := range v.Addresses {
+			d, err := NewAddressDigest(a.Seed, a.Index, a.Security)
+			if err != nil {
+				t.Errorf("%s: address %d: %s", fn, i, err)
+				continue
+			}
+			if a.Digest != "" && d.Digest != a.Digest {
+				t.Errorf("%s: address %d: digest %s, expected %s", fn, i, d.Digest, a.Digest)
+			}
+
+			adr, err := d.Address()
+			switch {
+			case err != nil:
+				t.Errorf("%s: address %d: %s", fn, i, err)
+			case adr != a.Address:
+				t.Errorf("%s: address %d: %s, expected %s", fn, i, adr, a.Address)
+			}
+		}
+
+		for i, s := range v.Signatures {
+			in := AddressInfo{Seed: s.Seed, Index: s.Index, Security: s.Security}
+			keys, err := NewKeys([]AddressInfo{in})
+			if err != nil {
+				t.Errorf("%s: signature %d: %s", fn, i, err)
+				continue
+			}
+
+			adr, err := in.Address()
+			if err != nil {
+				t.Errorf("%s: signature %d: %s", fn, i, err)
+				continue
+			}
+
+			frags, err := keys.Sign(adr, s.BundleHash.Normalize())
+			if err != nil {
+				t.Errorf("%s: signature %d: %s", fn, i, err)
+				continue
+			}
+			if len(frags) != len(s.Signature) {
+				t.Errorf("%s: signature %d: %d fragments, expected %d", fn, i, len(frags), len(s.Signature))
+				continue
+			}
+			for j := range frags {
+				if frags[j] != s.Signature[j] {
+					t.Errorf("%s: signature %d: fragment %d differs", fn, i, j)
+				}
+			}
+			if !IsValidSig(adr, s.Signature, s.BundleHash) {
+				t.Errorf("%s: signature %d: vector signature doesn't validate", fn, i)
+			}
+		}
+
+		for i, s := range v.Validations {
+			if !IsValidSig(s.Address, s.Signature, s.BundleHash) {
+				t.Errorf("%s: validation %d: signature doesn't validate", fn, i)
+			}
+		}
+
+		for i, b := range v.Bundles {
+			var bs Bundle
+			for _, e := range b.Entries {
+				bs.Add(e.Count, e.Address, e.Value, time.Unix(e.Timestamp, 0), e.Tag)
+			}
+			if err := bs.Finalize(nil); err != nil {
+				t.Errorf("%s: bundle %d: %s", fn, i, err)
+				continue
+			}
+
+			switch {
+			case len(bs) == 0:
+				t.Errorf("%s: bundle %d: no entries", fn, i)
+			case Trytes(bs[0].Bundle) != b.Hash:
+				t.Errorf("%s: bundle %d: hash %s, expected %s", fn, i, bs[0].Bundle, b.Hash)
+			case b.ObsoleteTag != "" && Trytes(bs[0].ObsoleteTag) != b.ObsoleteTag:
+				t.Errorf("%s: bundle %d: obsolete tag %s, expected %s", fn, i, bs[0].ObsoleteTag, b.ObsoleteTag)
+			}
+		}
+
+		for i, n := range v.Nonces {
+			tx, err := NewTransaction(n.Trytes)
+			if err != nil {
+				t.Errorf("%s: nonce %d: %s", fn, i, err)
+				continue
+			}
+			tx.Nonce = n.Nonce
+
+			switch {
+			case !tx.HasValidNonce(n.MinWeightMagnitude):
+				t.Errorf("%s: nonce %d: doesn't satisfy mwm %d", fn, i, n.MinWeightMagnitude)
+			case n.Hash != "" && tx.Hash() != n.Hash:
+				t.Errorf("%s: nonce %d: hash %s, expected %s", fn, i, tx.Hash(), n.Hash)
+			}
+		}
+	}
+}
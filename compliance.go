@@ -0,0 +1,93 @@
+package giota
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrSanctionedAddress is returned by AddressListFilter for transfers to
+// addresses which may not receive values.
+var ErrSanctionedAddress = errors.New("address may not receive values")
+
+// ComplianceFilter vetoes broadcasts of value transfers, e.g. to sanctioned
+// addresses. Exchanges use it to check every bundle leaving the system,
+// however it was created.
+type ComplianceFilter interface {
+	// Check returns an error if the outputs must not be broadcasted. The
+	// outputs are all transactions with positive value, including the
+	// change.
+	Check(outputs []Transfer) error
+}
+
+// SetComplianceFilter sets the filter which BroadcastTransactions, and so
+// SendTrytes and Send, evaluate before broadcasting with api. A nil f
+// allows all broadcasts. SetComplianceFilter must not be called
+// concurrently with API calls.
+func (api *API) SetComplianceFilter(f ComplianceFilter) {
+	api.compliance = f
+}
+
+// checkCompliance evaluates the compliance filter of api for the outputs of
+// txs.
+func (api *API) checkCompliance(txs []Transaction) error {
+	if api.compliance == nil {
+		return nil
+	}
+
+	var outputs []Transfer
+	for _, tx := range txs {
+		if tx.Value > 0 {
+			outputs = append(outputs, Transfer{Address: tx.Address, Value: tx.Value, Tag: Trytes(tx.Tag)})
+		}
+	}
+	if len(outputs) == 0 {
+		return nil
+	}
+	if err := api.compliance.Check(outputs); err != nil {
+		return fmt.Errorf("broadcast denied: %w", err)
+	}
+	return nil
+}
+
+// AddressListFilter is a ComplianceFilter of lists of addresses.
+type AddressListFilter struct {
+	// Blacklist are the addresses which may not receive values.
+	Blacklist map[Address]bool
+	// Whitelist, if not empty, are the only addresses which may receive
+	// values. It must contain the addresses receiving the change.
+	Whitelist map[Address]bool
+}
+
+// Check returns an error wrapping ErrSanctionedAddress for the first output
+// not allowed by the lists.
+func (f *AddressListFilter) Check(outputs []Transfer) error {
+	for _, out := range outputs {
+		if f.Blacklist[out.Address] || len(f.Whitelist) > 0 && !f.Whitelist[out.Address] {
+			return fmt.Errorf("%w: %s", ErrSanctionedAddress, out.Address)
+		}
+	}
+	return nil
+}
+
+// ReadAddressList reads a list of addresses, one per line and with or
+// without checksum, for AddressListFilter. Empty lines and lines starting
+// with # are skipped.
+func ReadAddressList(r io.Reader) (map[Address]bool, error) {
+	adrs := make(map[Address]bool)
+	s := bufio.NewScanner(r)
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		adr, err := parseAddress(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		adrs[adr] = true
+	}
+	return adrs, s.Err()
+}
@@ -0,0 +1,62 @@
+package giota
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestComplianceFilter(t *testing.T) {
+	broadcasts := 0
+	api, done := newFakeNode(t, map[string]fakeNodeHandler{
+		"broadcastTransactions": func(map[string]json.RawMessage) interface{} {
+			broadcasts++
+			return struct{}{}
+		},
+	})
+	defer done()
+
+	list, err := ReadAddressList(strings.NewReader("# sanctioned\n\n" + string(filterAddr2.WithChecksum()) + "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	api.SetComplianceFilter(&AddressListFilter{Blacklist: list})
+
+	tests := []struct {
+		name    string
+		to      Address
+		value   int64
+		allowed bool
+	}{
+		{name: "allowed address", to: filterAddr1, value: 10, allowed: true},
+		{name: "blacklisted address", to: filterAddr2, value: 10},
+		{name: "message to blacklisted address", to: filterAddr2, allowed: true},
+	}
+	for _, tt := range tests {
+		var bs Bundle
+		bs.Add(1, tt.to, tt.value, time.Now(), "")
+		bs.Finalize(nil)
+
+		broadcasts = 0
+		err := api.BroadcastTransactions(bs)
+		switch {
+		case tt.allowed && (err != nil || broadcasts != 1):
+			t.Errorf("%s: BroadcastTransactions() = %v", tt.name, err)
+		case !tt.allowed && (!errors.Is(err, ErrSanctionedAddress) || broadcasts != 0):
+			t.Errorf("%s: BroadcastTransactions() = %v with %d broadcasts", tt.name, err, broadcasts)
+		}
+	}
+
+	f := &AddressListFilter{Whitelist: map[Address]bool{filterAddr1: true}}
+	if err := f.Check([]Transfer{{Address: filterAddr1, Value: 1}}); err != nil {
+		t.Error(err)
+	}
+	if err := f.Check([]Transfer{{Address: filterAddr2, Value: 1}}); !errors.Is(err, ErrSanctionedAddress) {
+		t.Errorf("whitelist allowed %s", filterAddr2)
+	}
+	if _, err := ReadAddressList(strings.NewReader("NOADDRESS\n")); err == nil {
+		t.Error("ReadAddressList() accepted an invalid address")
+	}
+}
@@ -0,0 +1,91 @@
+package giota
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+)
+
+func TestConcurrentSigning(t *testing.T) {
+	exp, err := NewAddresses(accountTestSeed, 0, 4, SecurityLevelMedium)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			adr, err := NewAddress(accountTestSeed, uint64(i%4), SecurityLevelMedium)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if adr != exp[i%4] {
+				t.Errorf("concurrent NewAddress(%d) = %s, expected %s", i%4, adr, exp[i%4])
+			}
+
+			key, err := NewKey(accountTestSeed, uint64(i%4), SecurityLevelMedium)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			h := Trytes(EmptyHash)
+			frags, err := Keys{adr: key}.Sign(adr, h.Normalize())
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if !IsValidSig(adr, frags, h) {
+				t.Errorf("concurrent signature of %d is invalid", i%4)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestConcurrentPowGo(t *testing.T) {
+	tx := filterTestBundle()[0].Trytes()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			nonce, err := PowGo(tx, 5)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			h := (tx[:len(tx)-NonceTrinarySize/3] + nonce).Hash()
+			if h.Trits().TrailingZeros() < 5 {
+				t.Errorf("concurrent PowGo() returned invalid nonce %s", nonce)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestConcurrentAPI(t *testing.T) {
+	api, done := newFakeNode(t, map[string]fakeNodeHandler{
+		"getNodeInfo": func(map[string]json.RawMessage) interface{} {
+			return &GetNodeInfoResponse{AppName: "IRI"}
+		},
+	})
+	defer done()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := api.GetNodeInfo(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+}
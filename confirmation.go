@@ -0,0 +1,90 @@
+package giota
+
+import (
+	"sort"
+)
+
+// bundleTails returns the tail transactions of all attachments of bundle.
+func (api *API) bundleTails(fn string, bundle BundleHash) ([]Transaction, error) {
+	if err := api.validateHash(fn, "bundle", Hash(bundle)); err != nil {
+		return nil, err
+	}
+
+	txs, err := api.FindTransactionObjects(&FindTransactionsRequest{Bundles: []BundleHash{bundle}})
+	if err != nil {
+		return nil, err
+	}
+
+	var tails []Transaction
+	for _, tx := range txs {
+		if tx.CurrentIndex == 0 && tx.Bundle == bundle {
+			tails = append(tails, tx)
+		}
+	}
+	return tails, nil
+}
+
+// IsBundleConfirmed finds all attachments of bundle and returns the hash of
+// the tail of the confirmed one, if any.
+func (api *API) IsBundleConfirmed(bundle BundleHash) (Hash, bool, error) {
+	tails, err := api.bundleTails("IsBundleConfirmed", bundle)
+	if err != nil || len(tails) == 0 {
+		return "", false, err
+	}
+
+	hashes := make([]Hash, len(tails))
+	for i := range tails {
+		hashes[i] = tails[i].Hash()
+	}
+
+	inc, err := api.GetLatestInclusion(hashes)
+	if err != nil {
+		return "", false, err
+	}
+	for i, confirmed := range inc {
+		if confirmed && i < len(hashes) {
+			api.publish(Event{Kind: EventConfirmed, Bundle: bundle, Tail: hashes[i]})
+			return hashes[i], true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// TailState is a tail of an attachment of a bundle returned by FindAllTails.
+type TailState struct {
+	Hash        Hash
+	Transaction Transaction
+	// Consistent is the result of checkConsistency for the tail alone. If it
+	// is false, Info holds the reason given by the node.
+	Consistent bool
+	Info       string
+}
+
+// FindAllTails returns the tails of all attachments of bundle ordered by
+// their attachment timestamp, the oldest first, with their consistency.
+func (api *API) FindAllTails(bundle BundleHash) ([]TailState, error) {
+	tails, err := api.bundleTails("FindAllTails", bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(tails, func(i, j int) bool {
+		return tails[i].AttachmentTime().Before(tails[j].AttachmentTime())
+	})
+
+	states := make([]TailState, len(tails))
+	for i := range tails {
+		h := tails[i].Hash()
+		cc, err := api.CheckConsistency([]Hash{h})
+		if err != nil {
+			return nil, err
+		}
+		states[i] = TailState{
+			Hash:        h,
+			Transaction: tails[i],
+			Consistent:  cc.State,
+			Info:        cc.Info,
+		}
+	}
+	return states, nil
+}
@@ -0,0 +1,115 @@
+package giota
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestIsBundleConfirmed(t *testing.T) {
+	bs := filterTestBundle()
+	reattached := bs[0]
+	reattached.AttachmentTimestamp = Int2Trits(1, TimestampTrinarySize).Trytes()
+
+	tests := []struct {
+		name      string
+		txs       []Transaction
+		states    []bool
+		tail      Hash
+		confirmed bool
+	}{
+		{name: "unknown bundle"},
+		{name: "pending", txs: bs, states: []bool{false}},
+		{name: "confirmed", txs: bs, states: []bool{true}, tail: bs[0].Hash(), confirmed: true},
+		{
+			name:      "confirmed reattachment",
+			txs:       append([]Transaction{reattached}, bs...),
+			states:    []bool{false, true},
+			tail:      bs[0].Hash(),
+			confirmed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		api, done := newFakeNode(t, map[string]fakeNodeHandler{
+			"findTransactions": func(map[string]json.RawMessage) interface{} {
+				hashes := []Hash{}
+				for i := range tt.txs {
+					hashes = append(hashes, tt.txs[i].Hash())
+				}
+				return &FindTransactionsResponse{Hashes: hashes}
+			},
+			"getTrytes": func(req map[string]json.RawMessage) interface{} {
+				var hashes []Hash
+				json.Unmarshal(req["hashes"], &hashes)
+
+				txs := []Transaction{}
+				for _, h := range hashes {
+					for i := range tt.txs {
+						if tt.txs[i].Hash() == h {
+							txs = append(txs, tt.txs[i])
+						}
+					}
+				}
+				return &GetTrytesResponse{Trytes: txs}
+			},
+			"getNodeInfo": func(map[string]json.RawMessage) interface{} {
+				return &GetNodeInfoResponse{LatestMilestone: EmptyHash}
+			},
+			"getInclusionStates": func(map[string]json.RawMessage) interface{} {
+				return &GetInclusionStatesResponse{States: tt.states}
+			},
+		})
+
+		tail, confirmed, err := api.IsBundleConfirmed(bs[0].Bundle)
+		done()
+
+		switch {
+		case err != nil:
+			t.Errorf("%s: IsBundleConfirmed() returned %s", tt.name, err)
+		case tail != tt.tail || confirmed != tt.confirmed:
+			t.Errorf("%s: IsBundleConfirmed() returned %s, %v", tt.name, tail, confirmed)
+		}
+	}
+}
+
+func TestFindAllTails(t *testing.T) {
+	bs := filterTestBundle()
+	bs[0].AttachmentTimestamp = Int2Trits(2000, TimestampTrinarySize).Trytes()
+	older := bs[0]
+	older.AttachmentTimestamp = Int2Trits(1000, TimestampTrinarySize).Trytes()
+	txs := append(bs, older)
+
+	api, done := newFakeNode(t, map[string]fakeNodeHandler{
+		"findTransactions": func(map[string]json.RawMessage) interface{} {
+			hashes := []Hash{}
+			for i := range txs {
+				hashes = append(hashes, txs[i].Hash())
+			}
+			return &FindTransactionsResponse{Hashes: hashes}
+		},
+		"getTrytes": func(map[string]json.RawMessage) interface{} {
+			return &GetTrytesResponse{Trytes: txs}
+		},
+		"checkConsistency": func(req map[string]json.RawMessage) interface{} {
+			var tails []Hash
+			json.Unmarshal(req["tails"], &tails)
+			if tails[0] == older.Hash() {
+				return &CheckConsistencyResponse{State: false, Info: "below max depth"}
+			}
+			return &CheckConsistencyResponse{State: true}
+		},
+	})
+	defer done()
+
+	states, err := api.FindAllTails(bs[0].Bundle)
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case len(states) != 2:
+		t.Fatalf("FindAllTails() returned %d tails", len(states))
+	case states[0].Hash != older.Hash() || states[0].Consistent || states[0].Info == "":
+		t.Errorf("FindAllTails() returned %+v as first tail", states[0])
+	case states[1].Hash != bs[0].Hash() || !states[1].Consistent:
+		t.Errorf("FindAllTails() returned %+v as second tail", states[1])
+	}
+}
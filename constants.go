@@ -32,9 +32,31 @@ const (
 	MaxTryteValue             = 13
 	SignatureSize             = 6561
 	HashSize                  = 243
-	Depth                     = 3
 	Radix                     = 3
-	DefaultMinWeightMagnitude = 14
+	DefaultMinWeightMagnitude = MainnetMWM
+
+	// Depth is the default depth of the tip selection.
+	//
+	// Deprecated: use DefaultDepth.
+	Depth = DefaultDepth
+)
+
+// Defaults and limits of sending transactions. SendTrytes and Send use
+// DefaultDepth and DefaultMinWeightMagnitude if zero is passed.
+const (
+	// DefaultDepth is the default depth of the tip selection.
+	DefaultDepth = 3
+	// MainnetMWM is the min weight magnitude of the mainnet.
+	MainnetMWM = 14
+	// DevnetMWM is the min weight magnitude of the devnet.
+	DevnetMWM = 9
+	// MaxInputsPerBundle is the max number of inputs PrepareTransfers adds
+	// to a bundle.
+	MaxInputsPerBundle = 30
+	// MaxBundleSize is the max number of transactions of a bundle prepared
+	// by PrepareTransfers. Larger bundles take long to attach and are
+	// rarely confirmed.
+	MaxBundleSize = 100
 )
 
 // Units for iota token.
@@ -46,11 +68,17 @@ const (
 	Pi = 1000000000000000
 )
 
+// MaxSupply is the total number of iotas, the max absolute value of a
+// transaction.
+const MaxSupply = 2779530283277761
+
+// EmptyHash represents an empty hash. It is untyped to be usable as Trytes,
+// Hash and BundleHash.
+const EmptyHash = "999999999999999999999999999999999999999999999999999999999999999999999999999999999"
+
 var (
 	// emptySig represents an empty signature.
 	emptySig Trytes
-	// EmptyHash represents an empty hash.
-	EmptyHash Trytes = "999999999999999999999999999999999999999999999999999999999999999999999999999999999"
 	// EmptyAddress represents an empty address.
 	EmptyAddress Address = "999999999999999999999999999999999999999999999999999999999999999999999999999999999"
 )
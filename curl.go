@@ -25,6 +25,8 @@ SOFTWARE.
 
 package giota
 
+import "sync"
+
 // constants for Sizes.
 const (
 	stateSize      = 729
@@ -115,9 +117,17 @@ func (c *Curl) Reset() {
 	}
 }
 
+// curlPool reuses Curl instances for Hash.
+var curlPool = sync.Pool{
+	New: func() interface{} { return NewCurl() },
+}
+
 // Hash returns hash of t.
 func (t Trytes) Hash() Trytes {
-	c := NewCurl()
+	c := curlPool.Get().(*Curl)
+	defer curlPool.Put(c)
+
+	c.Reset()
 	c.Absorb(t)
 	return c.Squeeze()
 }
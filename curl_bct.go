@@ -0,0 +1,134 @@
+package giota
+
+import (
+	"errors"
+)
+
+// BCTBatchSize is the number of trytes a BCTCurl hashes in parallel.
+const BCTBatchSize = 64
+
+// BCTCurl is a binary-coded ternary Curl sponge which hashes up to BCTBatchSize
+// inputs at once. Each trit of the state is stored as a pair of low/high bits
+// in every lane of two uint64 words, the same layout which is used by PowGo.
+type BCTCurl struct {
+	l [stateSize]uint64
+	h [stateSize]uint64
+	n int
+}
+
+// NewBCTCurl initializes a new instance with an empty state.
+func NewBCTCurl() *BCTCurl {
+	c := &BCTCurl{}
+	c.Reset()
+	return c
+}
+
+// Reset the internal state of the sponge by filling it with all 0's.
+func (c *BCTCurl) Reset() {
+	for i := 0; i < stateSize; i++ {
+		c.l[i] = hBits
+		c.h[i] = hBits
+	}
+	c.n = 0
+}
+
+// Transform does Transform in sponge func for all lanes.
+func (c *BCTCurl) Transform() {
+	transform64(&c.l, &c.h)
+}
+
+// Absorb fills the internal state of the sponge with the given trytes, one
+// lane per element. All elements must be of the same length.
+func (c *BCTCurl) Absorb(in []Trytes) error {
+	switch {
+	case len(in) == 0:
+		return nil
+	case len(in) > BCTBatchSize:
+		return errors.New("too many trytes for one BCT batch")
+	}
+
+	size := len(in[0])
+	trits := make([]Trits, len(in))
+	for i := range in {
+		if len(in[i]) != size {
+			return errors.New("trytes in BCT batch must have the same length")
+		}
+		tr, err := in[i].ToTrits()
+		if err != nil {
+			return err
+		}
+		trits[i] = tr
+	}
+	c.n = len(in)
+
+	var lenn int
+	for i := 0; i < size*3; i += lenn {
+		lenn = TritHashLength
+		if size*3-i < TritHashLength {
+			lenn = size*3 - i
+		}
+
+		for j := 0; j < lenn; j++ {
+			var l, h uint64
+			for k := range trits {
+				switch trits[k][i+j] {
+				case 1:
+					h |= 1 << uint(k)
+				case -1:
+					l |= 1 << uint(k)
+				default:
+					l |= 1 << uint(k)
+					h |= 1 << uint(k)
+				}
+			}
+			c.l[j] = l
+			c.h[j] = h
+		}
+		c.Transform()
+	}
+	return nil
+}
+
+// Squeeze do Squeeze in sponge func and returns one hash per absorbed lane.
+func (c *BCTCurl) Squeeze() []Trytes {
+	ret := make([]Trytes, c.n)
+	tr := make(Trits, HashSize)
+	for k := 0; k < c.n; k++ {
+		for i := 0; i < HashSize; i++ {
+			l := (c.l[i] >> uint(k)) & 1
+			h := (c.h[i] >> uint(k)) & 1
+			switch {
+			case l == 1 && h == 0:
+				tr[i] = -1
+			case l == 0 && h == 1:
+				tr[i] = 1
+			default:
+				tr[i] = 0
+			}
+		}
+		ret[k] = tr.Trytes()
+	}
+	c.Transform()
+	return ret
+}
+
+// HashBatch returns the Curl hashes of trytes, e.g. of transaction trytes,
+// computing up to BCTBatchSize hashes at once. Trytes in the same batch
+// must have the same length.
+func HashBatch(trytes []Trytes) ([]Trytes, error) {
+	hashes := make([]Trytes, 0, len(trytes))
+	c := NewBCTCurl()
+	for i := 0; i < len(trytes); i += BCTBatchSize {
+		end := i + BCTBatchSize
+		if end > len(trytes) {
+			end = len(trytes)
+		}
+
+		c.Reset()
+		if err := c.Absorb(trytes[i:end]); err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, c.Squeeze()...)
+	}
+	return hashes, nil
+}
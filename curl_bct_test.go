@@ -0,0 +1,56 @@
+package giota
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func randomTrytes(r *rand.Rand, n int) Trytes {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = TryteAlphabet[r.Intn(len(TryteAlphabet))]
+	}
+	return Trytes(b)
+}
+
+func TestHashBatch(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	tests := []struct {
+		name   string
+		count  int
+		length int
+	}{
+		{name: "single transaction", count: 1, length: TransactionTrinarySize / 3},
+		{name: "more than one batch", count: BCTBatchSize + 3, length: TransactionTrinarySize / 3},
+		{name: "partial chunk", count: 5, length: 100},
+	}
+
+	for _, tt := range tests {
+		in := make([]Trytes, tt.count)
+		for i := range in {
+			in[i] = randomTrytes(r, tt.length)
+		}
+
+		hashes, err := HashBatch(in)
+		if err != nil {
+			t.Fatalf("%s: HashBatch() failed: %s", tt.name, err)
+		}
+
+		if len(hashes) != len(in) {
+			t.Fatalf("%s: HashBatch() returned %d hashes, expected %d", tt.name, len(hashes), len(in))
+		}
+
+		for i := range in {
+			if hashes[i] != in[i].Hash() {
+				t.Errorf("%s: hash %d is %s, expected %s", tt.name, i, hashes[i], in[i].Hash())
+			}
+		}
+	}
+}
+
+func TestHashBatchLengthMismatch(t *testing.T) {
+	if _, err := HashBatch([]Trytes{"ABC", "ABCDEF"}); err == nil {
+		t.Error("HashBatch() should fail for trytes of different lengths")
+	}
+}
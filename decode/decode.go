@@ -0,0 +1,218 @@
+// Package decode annotates raw transaction trytes for explorers and
+// debugging: every field with its position, its human readable value and
+// checks of the values which a node would reject.
+package decode
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iotaledger/giota"
+)
+
+// MaxSupply is the total number of iotas.
+const MaxSupply = giota.MaxSupply
+
+// Kind is the way a field is interpreted.
+type Kind int
+
+// Kinds of fields.
+const (
+	KindTrytes Kind = iota
+	KindInt
+	// KindTime is a Unix timestamp in seconds.
+	KindTime
+	// KindTimeMillis is a Unix timestamp in milliseconds.
+	KindTimeMillis
+)
+
+// Field is a decoded field of a transaction.
+type Field struct {
+	Name string
+	Kind Kind
+	// Offset and Size are in trits. Divide by 3 to get them in trytes.
+	Offset int
+	Size   int
+	Trytes giota.Trytes
+	// Value is the human readable value, e.g. a formatted time.
+	Value string
+}
+
+// Check is the result of a consistency check of field values.
+type Check struct {
+	Name   string
+	OK     bool
+	Detail string
+}
+
+// Transaction is the annotated form of transaction trytes.
+type Transaction struct {
+	Hash   giota.Hash
+	Fields []Field
+	Checks []Check
+	// Weight is the number of trailing zero trits of Hash, i.e. the highest
+	// min weight magnitude the nonce satisfies.
+	Weight int64
+	// Message is the signature message fragment decoded as ASCII if it is a
+	// printable message, else empty.
+	Message string
+	// Transaction is the parsed transaction, or nil if the trytes are
+	// rejected by giota.NewTransaction.
+	Transaction *giota.Transaction
+}
+
+// Valid returns true if all checks of t passed.
+func (t *Transaction) Valid() bool {
+	for _, c := range t.Checks {
+		if !c.OK {
+			return false
+		}
+	}
+	return true
+}
+
+// Field returns the field named name, or nil.
+func (t *Transaction) Field(name string) *Field {
+	for i := range t.Fields {
+		if t.Fields[i].Name == name {
+			return &t.Fields[i]
+		}
+	}
+	return nil
+}
+
+type layout struct {
+	name   string
+	kind   Kind
+	offset int
+	size   int
+}
+
+// layouts is the trinary layout of a transaction.
+var layouts = []layout{
+	{"signatureMessageFragment", KindTrytes, giota.SignatureMessageFragmentTrinaryOffset, giota.SignatureMessageFragmentTrinarySize},
+	{"address", KindTrytes, giota.AddressTrinaryOffset, giota.AddressTrinarySize},
+	{"value", KindInt, giota.ValueTrinaryOffset, giota.ValueTrinarySize},
+	{"obsoleteTag", KindTrytes, giota.ObsoleteTagTrinaryOffset, giota.ObsoleteTagTrinarySize},
+	{"timestamp", KindTime, giota.TimestampTrinaryOffset, giota.TimestampTrinarySize},
+	{"currentIndex", KindInt, giota.CurrentIndexTrinaryOffset, giota.CurrentIndexTrinarySize},
+	{"lastIndex", KindInt, giota.LastIndexTrinaryOffset, giota.LastIndexTrinarySize},
+	{"bundle", KindTrytes, giota.BundleTrinaryOffset, giota.BundleTrinarySize},
+	{"trunkTransaction", KindTrytes, giota.TrunkTransactionTrinaryOffset, giota.TrunkTransactionTrinarySize},
+	{"branchTransaction", KindTrytes, giota.BranchTransactionTrinaryOffset, giota.BranchTransactionTrinarySize},
+	{"tag", KindTrytes, giota.TagTrinaryOffset, giota.TagTrinarySize},
+	{"attachmentTimestamp", KindTimeMillis, giota.AttachmentTimestampTrinaryOffset, giota.AttachmentTimestampTrinarySize},
+	{"attachmentTimestampLowerBound", KindTimeMillis, giota.AttachmentTimestampLowerBoundTrinaryOffset, giota.AttachmentTimestampLowerBoundTrinarySize},
+	{"attachmentTimestampUpperBound", KindTimeMillis, giota.AttachmentTimestampUpperBoundTrinaryOffset, giota.AttachmentTimestampUpperBoundTrinarySize},
+	{"nonce", KindTrytes, giota.NonceTrinaryOffset, giota.NonceTrinarySize},
+}
+
+// Decode annotates the trytes of a transaction. It only returns an error if
+// trytes can't be split into fields, i.e. if they contain invalid characters
+// or have the wrong length. Invalid values are reported by failed checks.
+func Decode(trytes giota.Trytes) (*Transaction, error) {
+	if err := giota.IsTransactionTrytes(trytes); err != nil {
+		return nil, err
+	}
+
+	trits, err := trytes.ToTrits()
+	if err != nil {
+		return nil, err
+	}
+	t := &Transaction{
+		Hash:   giota.Hash(trytes.Hash()),
+		Fields: make([]Field, len(layouts)),
+	}
+	t.Weight = t.Hash.Trits().TrailingZeros()
+
+	ints := make(map[string]int64)
+	for i, l := range layouts {
+		f := Field{
+			Name:   l.name,
+			Kind:   l.kind,
+			Offset: l.offset,
+			Size:   l.size,
+			Trytes: trytes[l.offset/3 : (l.offset+l.size)/3],
+		}
+
+		v := trits[l.offset : l.offset+l.size].Int()
+		switch l.kind {
+		case KindTrytes:
+			f.Value = string(f.Trytes)
+		case KindInt:
+			f.Value = strconv.FormatInt(v, 10)
+		case KindTime:
+			f.Value = time.Unix(v, 0).UTC().Format(time.RFC3339)
+		case KindTimeMillis:
+			f.Value = time.Unix(0, v*int64(time.Millisecond)).UTC().Format(time.RFC3339Nano)
+		}
+		if l.kind != KindTrytes {
+			ints[l.name] = v
+		}
+		t.Fields[i] = f
+	}
+
+	if adr, err := t.Field("address").Trytes.ToAddress(); err == nil {
+		t.Field("address").Value = string(adr.WithChecksum())
+	}
+	t.Message = messagePreview(t.Field("signatureMessageFragment").Trytes)
+	t.Checks = checks(trytes, trits, ints)
+
+	if tx, err := giota.NewTransaction(trytes); err == nil {
+		t.Transaction = tx
+	}
+	return t, nil
+}
+
+func checks(trytes giota.Trytes, trits giota.Trits, v map[string]int64) []Check {
+	value, cur, last := v["value"], v["currentIndex"], v["lastIndex"]
+	at := v["attachmentTimestamp"]
+	lower, upper := v["attachmentTimestampLowerBound"], v["attachmentTimestampUpperBound"]
+
+	// only the lower 33 trytes of the value may be used
+	unused := trytes[(giota.ValueTrinaryOffset/3)+11 : (giota.ValueTrinaryOffset+giota.ValueTrinarySize)/3]
+
+	return []Check{
+		{
+			Name:   "value",
+			OK:     strings.Trim(string(unused), "9") == "" && value >= -MaxSupply && value <= MaxSupply,
+			Detail: "value must be within the total supply",
+		},
+		{
+			Name:   "index",
+			OK:     cur >= 0 && cur <= last,
+			Detail: "currentIndex must be between 0 and lastIndex",
+		},
+		{
+			Name:   "attachment",
+			OK:     at == 0 || (lower <= at && (upper == 0 || at <= upper)),
+			Detail: "attachmentTimestamp must be within its bounds",
+		},
+		{
+			Name:   "address",
+			OK:     trits[giota.AddressTrinaryOffset+giota.AddressTrinarySize-1] == 0,
+			Detail: "the last trit of the address must be 0",
+		},
+	}
+}
+
+// messagePreview returns the message encoded in frag without its padding if
+// it is printable ASCII text, else an empty string.
+func messagePreview(frag giota.Trytes) string {
+	t := strings.TrimRight(string(frag), "9")
+	if len(t)%2 != 0 {
+		t += "9"
+	}
+
+	b, err := giota.DecodeMessage(giota.Trytes(t))
+	if err != nil {
+		return ""
+	}
+	for _, c := range b {
+		if (c < ' ' || c > '~') && c != '\n' && c != '\r' && c != '\t' {
+			return ""
+		}
+	}
+	return string(b)
+}
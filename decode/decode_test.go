@@ -0,0 +1,86 @@
+package decode
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iotaledger/giota"
+)
+
+const testAddress giota.Address = "AYYNHWWNZQOFYXNQSLVULU9ARZCSXNWWAFYEWEL9LIXYDFS9KDSRZF9ZID9AQWSLAEUAJSTQKGPGXNWCD"
+
+func testTransaction() giota.Transaction {
+	var bs giota.Bundle
+	bs.Add(1, testAddress, 42, time.Unix(1500000000, 0), "DECODE")
+	bs.Finalize([]giota.Trytes{giota.EncodeMessage([]byte("hello, tangle"))})
+	return bs[0]
+}
+
+func TestDecode(t *testing.T) {
+	tx := testTransaction()
+	d, err := Decode(tx.Trytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		offset int
+		value  string
+	}{
+		{name: "address", offset: 2187, value: string(testAddress.WithChecksum())},
+		{name: "value", offset: 2268, value: "42"},
+		{name: "timestamp", offset: 2322, value: "2017-07-14T02:40:00Z"},
+		{name: "lastIndex", offset: 2340, value: "0"},
+		{name: "tag", offset: 2592, value: "DECODE999999999999999999999"},
+	}
+	for _, tt := range tests {
+		f := d.Field(tt.name)
+		switch {
+		case f == nil:
+			t.Errorf("%s: field is missing", tt.name)
+		case f.Offset/3 != tt.offset:
+			t.Errorf("%s: offset %d, expected %d", tt.name, f.Offset/3, tt.offset)
+		case f.Value != tt.value:
+			t.Errorf("%s: value %s, expected %s", tt.name, f.Value, tt.value)
+		}
+	}
+
+	switch {
+	case d.Hash != tx.Hash():
+		t.Errorf("hash %s, expected %s", d.Hash, tx.Hash())
+	case d.Message != "hello, tangle":
+		t.Errorf("message %q", d.Message)
+	case !d.Valid():
+		t.Errorf("checks failed: %+v", d.Checks)
+	case d.Transaction == nil || d.Transaction.Value != 42:
+		t.Errorf("transaction %+v", d.Transaction)
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	tx := testTransaction()
+	tx.CurrentIndex = 2
+	tx.Value = -MaxSupply - 1
+	tx.SignatureMessageFragment = giota.EncodeMessage([]byte{0x01, 0xff})
+
+	d, err := Decode(tx.Trytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	failed := make(map[string]bool)
+	for _, c := range d.Checks {
+		failed[c.Name] = !c.OK
+	}
+	if !failed["value"] || !failed["index"] || failed["attachment"] || failed["address"] {
+		t.Errorf("checks %+v", d.Checks)
+	}
+	if d.Message != "" {
+		t.Errorf("message %q of a binary fragment", d.Message)
+	}
+
+	if _, err := Decode(tx.Trytes()[1:]); err == nil {
+		t.Error("Decode() accepted short trytes")
+	}
+}
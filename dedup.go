@@ -0,0 +1,139 @@
+package giota
+
+import (
+	"sync"
+	"time"
+)
+
+// BroadcastStore remembers the broadcasted transactions for the
+// deduplication of BroadcastTransactions. It must be safe for concurrent
+// use, implementations may persist the times, e.g. to deduplicate across
+// restarts of retry loops.
+type BroadcastStore interface {
+	// Sent returns when the transaction with hash was broadcasted last.
+	Sent(hash Hash) (time.Time, bool)
+	// MarkSent records that the transaction with hash was broadcasted at t.
+	MarkSent(hash Hash, t time.Time)
+}
+
+// BroadcastDedup configures the deduplication of BroadcastTransactions.
+type BroadcastDedup struct {
+	// Window is the time in which a transaction is broadcasted once.
+	Window time.Duration
+	// Store remembers the broadcasted transactions. If nil, a
+	// MemoryBroadcastStore is used.
+	Store BroadcastStore
+	// Clock tells the time of broadcasts. If nil, SystemClock is used.
+	Clock Clock
+}
+
+// SetBroadcastDedup makes BroadcastTransactions of api skip transactions
+// which were broadcasted within d.Window, so that retry loops don't flood
+// the node with identical trytes. Concurrent broadcasts of the same
+// transaction may both be sent. A nil d disables the deduplication.
+// SetBroadcastDedup must not be called concurrently with API calls.
+func (api *API) SetBroadcastDedup(d *BroadcastDedup) {
+	if d == nil {
+		api.dedup = nil
+		return
+	}
+
+	dd := *d
+	if dd.Store == nil {
+		dd.Store = NewMemoryBroadcastStore(dd.Window)
+	}
+	if dd.Clock == nil {
+		dd.Clock = SystemClock
+	}
+	api.dedup = &dd
+}
+
+// BroadcastTransactionsOnce is like BroadcastTransactions, but returns the
+// hashes of the transactions which were sent, without the ones skipped by
+// the deduplication of SetBroadcastDedup. If all are skipped, the node is
+// not called.
+func (api *API) BroadcastTransactionsOnce(trytes []Transaction) ([]Hash, error) {
+	if err := api.checkCompliance(trytes); err != nil {
+		return nil, err
+	}
+
+	hashes := make([]Hash, 0, len(trytes))
+	send := make([]Transaction, 0, len(trytes))
+	var now time.Time
+	if api.dedup != nil {
+		now = api.dedup.Clock.Now()
+	}
+	for _, tx := range trytes {
+		h := tx.Hash()
+		if api.dedup != nil {
+			if t, ok := api.dedup.Store.Sent(h); ok && now.Sub(t) < api.dedup.Window {
+				continue
+			}
+		}
+		hashes = append(hashes, h)
+		send = append(send, tx)
+	}
+	if len(send) == 0 {
+		return hashes, nil
+	}
+
+	err := api.do(&struct {
+		Command string        `json:"command"`
+		Trytes  []Transaction `json:"trytes"`
+	}{
+		"broadcastTransactions",
+		send,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if api.dedup != nil {
+		for _, h := range hashes {
+			api.dedup.Store.MarkSent(h, now)
+		}
+	}
+	return hashes, nil
+}
+
+// MemoryBroadcastStore is a BroadcastStore in memory, which forgets
+// transactions broadcasted longer than its max age ago.
+type MemoryBroadcastStore struct {
+	maxAge time.Duration
+
+	mu      sync.Mutex
+	sent    map[Hash]time.Time
+	inserts int
+}
+
+// NewMemoryBroadcastStore returns an empty store forgetting transactions
+// after maxAge.
+func NewMemoryBroadcastStore(maxAge time.Duration) *MemoryBroadcastStore {
+	return &MemoryBroadcastStore{maxAge: maxAge, sent: make(map[Hash]time.Time)}
+}
+
+// Sent returns when the transaction with hash was broadcasted last.
+func (s *MemoryBroadcastStore) Sent(hash Hash) (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.sent[hash]
+	return t, ok
+}
+
+// MarkSent records that the transaction with hash was broadcasted at t.
+func (s *MemoryBroadcastStore) MarkSent(hash Hash, t time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sent[hash] = t
+	s.inserts++
+	// forget the old transactions from time to time
+	if s.inserts%1024 == 0 {
+		for h, st := range s.sent {
+			if t.Sub(st) >= s.maxAge {
+				delete(s.sent, h)
+			}
+		}
+	}
+}
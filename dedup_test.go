@@ -0,0 +1,73 @@
+package giota
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// testClock is a clock which can be advanced.
+type testClock struct {
+	t time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.t
+}
+
+func TestBroadcastDedup(t *testing.T) {
+	sent := 0
+	api, done := newFakeNode(t, map[string]fakeNodeHandler{
+		"broadcastTransactions": func(req map[string]json.RawMessage) interface{} {
+			var trytes []Transaction
+			json.Unmarshal(req["trytes"], &trytes)
+			sent += len(trytes)
+			return struct{}{}
+		},
+	})
+	defer done()
+
+	var a, b Bundle
+	a.Add(1, filterAddr1, 0, time.Unix(1500000000, 0), "")
+	a.Finalize(nil)
+	b.Add(1, filterAddr2, 0, time.Unix(1500000000, 0), "")
+	b.Finalize(nil)
+
+	clock := &testClock{t: time.Unix(1500000000, 0)}
+	api.SetBroadcastDedup(&BroadcastDedup{Window: time.Minute, Clock: clock})
+
+	tests := []struct {
+		name  string
+		txs   Bundle
+		after time.Duration
+		sent  []Hash
+	}{
+		{name: "first", txs: a, sent: []Hash{a[0].Hash()}},
+		{name: "retry", txs: a, after: 30 * time.Second},
+		{name: "retry with another", txs: append(a.Clone(), b...), after: 10 * time.Second, sent: []Hash{b[0].Hash()}},
+		{name: "after window", txs: a, after: 30 * time.Second, sent: []Hash{a[0].Hash()}},
+	}
+	for _, tt := range tests {
+		clock.t = clock.t.Add(tt.after)
+		sent = 0
+		hashes, err := api.BroadcastTransactionsOnce(tt.txs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(hashes) != len(tt.sent) || sent != len(tt.sent) {
+			t.Errorf("%s: sent %d transactions, returned %v, expected %v", tt.name, sent, hashes, tt.sent)
+			continue
+		}
+		for i := range hashes {
+			if hashes[i] != tt.sent[i] {
+				t.Errorf("%s: sent %s, expected %s", tt.name, hashes[i], tt.sent[i])
+			}
+		}
+	}
+
+	api.SetBroadcastDedup(nil)
+	sent = 0
+	if err := api.BroadcastTransactions(a); err != nil || sent != 1 {
+		t.Errorf("BroadcastTransactions() without deduplication sent %d transactions: %v", sent, err)
+	}
+}
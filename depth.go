@@ -0,0 +1,51 @@
+package giota
+
+import (
+	"time"
+)
+
+// AttachmentDepthPolicy describes for how long after attachment a transaction
+// stays above the max depth of tip selection, i.e. for how long it can be
+// promoted before it must be reattached. The policy of a network is set by
+// NetworkProfile.DepthPolicy, which PromoteWithOptions and
+// WaitForConfirmationWithOptions follow, and ResendParams.Depth for
+// EstimateConfirmation.
+type AttachmentDepthPolicy struct {
+	// MilestoneInterval is the average time between two milestones.
+	MilestoneInterval time.Duration
+	// MaxDepth is the number of milestones a transaction may fall behind.
+	MaxDepth int64
+}
+
+// DefaultDepthPolicy matches the mainnet coordinator and the defaults of IRI
+// as used by the official libraries.
+var DefaultDepthPolicy = AttachmentDepthPolicy{
+	MilestoneInterval: time.Minute,
+	MaxDepth:          11,
+}
+
+// MaxAge returns the time after which an attached transaction falls below
+// the max depth.
+func (p AttachmentDepthPolicy) MaxAge() time.Duration {
+	return p.MilestoneInterval * time.Duration(p.MaxDepth)
+}
+
+// IsAboveMaxDepth returns true if a transaction attached at at is still
+// above the max depth. Attachment times in the future are rejected.
+func (p AttachmentDepthPolicy) IsAboveMaxDepth(at time.Time) bool {
+	age := time.Since(at)
+	return age >= 0 && age < p.MaxAge()
+}
+
+// IsPromotable returns true if tx is attached and above the max depth.
+// Nodes may still refuse the promotion if tx is inconsistent, which can be
+// checked with CheckConsistency.
+func (p AttachmentDepthPolicy) IsPromotable(tx *Transaction) bool {
+	at := tx.AttachmentTime()
+	return !at.IsZero() && p.IsAboveMaxDepth(at)
+}
+
+// NeedsReattach returns true if tx can't be promoted anymore.
+func (p AttachmentDepthPolicy) NeedsReattach(tx *Transaction) bool {
+	return !p.IsPromotable(tx)
+}
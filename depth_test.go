@@ -0,0 +1,40 @@
+package giota
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAttachmentDepthPolicy(t *testing.T) {
+	p := AttachmentDepthPolicy{MilestoneInterval: 10 * time.Second, MaxDepth: 3}
+	if p.MaxAge() != 30*time.Second {
+		t.Fatalf("MaxAge() is %s, expected 30s", p.MaxAge())
+	}
+
+	tests := []struct {
+		name       string
+		age        time.Duration
+		attached   bool
+		promotable bool
+	}{
+		{name: "fresh", age: time.Second, attached: true, promotable: true},
+		{name: "too old", age: time.Minute, attached: true, promotable: false},
+		{name: "in the future", age: -time.Minute, attached: true, promotable: false},
+		{name: "not attached", attached: false, promotable: false},
+	}
+
+	for _, tt := range tests {
+		tx := &Transaction{AttachmentTimestamp: Trytes(EmptyHash[:27])}
+		if tt.attached {
+			ms := time.Now().Add(-tt.age).UnixNano() / int64(time.Millisecond)
+			tx.AttachmentTimestamp = Int2Trits(ms, AttachmentTimestampTrinarySize).Trytes()
+		}
+
+		if p.IsPromotable(tx) != tt.promotable {
+			t.Errorf("%s: IsPromotable() should be %v", tt.name, tt.promotable)
+		}
+		if p.NeedsReattach(tx) == tt.promotable {
+			t.Errorf("%s: NeedsReattach() should be %v", tt.name, !tt.promotable)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package giota
+
+import (
+	"errors"
+)
+
+// AddressDigest is the digest of the private key of an address. It can be
+// shared instead of the seed: the address follows from it, and multisig
+// participants combine their digests into a common address.
+type AddressDigest struct {
+	Index    uint64        `json:"index"`
+	Security SecurityLevel `json:"security"`
+	// Digest holds one 81-tryte digest per security level.
+	Digest Trytes `json:"digest"`
+}
+
+// NewAddressDigest computes the key digest of the address of seed at index.
+func NewAddressDigest(seed Trytes, index uint64, security SecurityLevel) (*AddressDigest, error) {
+	key, err := newKeyTrits(seed, index, security)
+	if err != nil {
+		return nil, err
+	}
+
+	dg, err := Digests(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AddressDigest{
+		Index:    index,
+		Security: security,
+		Digest:   dg.Trytes(),
+	}, nil
+}
+
+// IsValid checks that the digest matches its security level.
+func (d *AddressDigest) IsValid() error {
+	if err := d.Security.IsValid(); err != nil {
+		return err
+	}
+	if err := d.Digest.IsValid(); err != nil {
+		return err
+	}
+	if len(d.Digest) != int(d.Security)*HashSize/3 {
+		return errors.New("digest length doesn't match the security level")
+	}
+	return nil
+}
+
+// Fragments returns the digest of each security level.
+func (d *AddressDigest) Fragments() []Trytes {
+	fs := make([]Trytes, len(d.Digest)/(HashSize/3))
+	for i := range fs {
+		fs[i] = d.Digest[i*HashSize/3 : (i+1)*HashSize/3]
+	}
+	return fs
+}
+
+// Address returns the address derived from the digest, which lets auditors
+// verify an address without knowing the seed.
+func (d *AddressDigest) Address() (Address, error) {
+	if err := d.IsValid(); err != nil {
+		return "", err
+	}
+	return AddressFromDigests(d.Digest)
+}
+
+// AddressFromDigests absorbs the digests in order and returns the resulting
+// address. With the digests of several participants it is the multisig
+// address of them.
+func AddressFromDigests(digests ...Trytes) (Address, error) {
+	if len(digests) == 0 {
+		return "", errors.New("no digests given")
+	}
+
+	k := NewKerl()
+	for _, d := range digests {
+		if err := d.IsValid(); err != nil {
+			return "", err
+		}
+		if len(d) == 0 || len(d)%(HashSize/3) != 0 {
+			return "", errors.New("digest length must be a multiple of 81 trytes")
+		}
+		if err := k.Absorb(d.Trits()); err != nil {
+			return "", err
+		}
+	}
+
+	h, err := k.Squeeze(HashSize)
+	if err != nil {
+		return "", err
+	}
+	return h.Trytes().ToAddress()
+}
@@ -0,0 +1,71 @@
+package giota
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAddressDigest(t *testing.T) {
+	for sec := SecurityLevelLow; sec <= SecurityLevelHigh; sec++ {
+		d, err := NewAddressDigest(accountTestSeed, 3, sec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(d.Fragments()) != int(sec) {
+			t.Errorf("level %d: digest has %d fragments", sec, len(d.Fragments()))
+		}
+
+		b, err := json.Marshal(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var dd AddressDigest
+		if err := json.Unmarshal(b, &dd); err != nil {
+			t.Fatal(err)
+		}
+
+		adr, err := dd.Address()
+		if err != nil {
+			t.Fatal(err)
+		}
+		exp, err := NewAddress(accountTestSeed, 3, sec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if adr != exp {
+			t.Errorf("level %d: digest address %s, expected %s", sec, adr, exp)
+		}
+	}
+
+	d := &AddressDigest{Security: SecurityLevelHigh, Digest: EmptyHash}
+	if _, err := d.Address(); err == nil {
+		t.Error("Address() should fail for a digest not matching its security level")
+	}
+}
+
+func TestAddressFromDigests(t *testing.T) {
+	d1, err := NewAddressDigest(accountTestSeed, 0, SecurityLevelMedium)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d2, err := NewAddressDigest(accountTestSeed, 1, SecurityLevelMedium)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := AddressFromDigests(d1.Digest, d2.Digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := AddressFromDigests(d1.Digest + d2.Digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a != b {
+		t.Error("AddressFromDigests() depends on how digests are split")
+	}
+
+	if _, err := AddressFromDigests(); err == nil {
+		t.Error("AddressFromDigests() should fail without digests")
+	}
+}
@@ -0,0 +1,137 @@
+package giota
+
+import (
+	"errors"
+	"fmt"
+)
+
+// DefaultGapLimit is the number of unused addresses after which
+// DiscoverAccountState stops scanning.
+const DefaultGapLimit = 20
+
+// AccountState is the state of the addresses of a seed found by
+// DiscoverAccountState. It is also its checkpoint: a scan resumed from a
+// state continues after its scanned addresses.
+type AccountState struct {
+	Security SecurityLevel `json:"security"`
+	// Scanned is the number of scanned addresses, starting with index 0.
+	Scanned uint64 `json:"scanned"`
+	// LastUsed is the highest index of an address with transactions or
+	// balance, or -1.
+	LastUsed int64 `json:"lastUsed"`
+	// Balances are the non-zero balances, their Index is the key index.
+	Balances Balances `json:"balances"`
+	// Spent are the indices of the addresses spent from, which must not
+	// receive funds anymore.
+	Spent []uint64 `json:"spent"`
+}
+
+// Balance returns the total balance of the account.
+func (s *AccountState) Balance() int64 {
+	return s.Balances.Total()
+}
+
+// NextIndex returns the index of the first address after the used ones.
+func (s *AccountState) NextIndex() uint64 {
+	return uint64(s.LastUsed + 1)
+}
+
+// DiscoverOptions are options of DiscoverAccountStateWithOptions.
+type DiscoverOptions struct {
+	// Resume is the state of a former scan to continue.
+	Resume *AccountState
+	// Checkpoint is called with the state after each scanned batch of
+	// gapLimit addresses, e.g. to store it for resuming. An error stops
+	// the scan.
+	Checkpoint func(*AccountState) error
+}
+
+// DiscoverAccountState scans the addresses of seed, e.g. after restoring it
+// on a new device, until gapLimit addresses in a row have neither
+// transactions nor balance. If gapLimit is zero, DefaultGapLimit is used.
+func (api *API) DiscoverAccountState(seed Trytes, security SecurityLevel, gapLimit int) (*AccountState, error) {
+	return api.DiscoverAccountStateWithOptions(seed, security, gapLimit, nil)
+}
+
+// DiscoverAccountStateWithOptions is like DiscoverAccountState, but with
+// checkpoints and resuming. opts may be nil.
+func (api *API) DiscoverAccountStateWithOptions(seed Trytes, security SecurityLevel, gapLimit int, opts *DiscoverOptions) (*AccountState, error) {
+	if err := api.validateSecurity("DiscoverAccountState", security); err != nil {
+		return nil, err
+	}
+	if gapLimit == 0 {
+		gapLimit = DefaultGapLimit
+	}
+	if gapLimit < 0 || gapLimit > 500 {
+		return nil, &ValidationError{Func: "DiscoverAccountState", Arg: "gapLimit", Index: -1, Err: errors.New("gap limit must be between 1 and 500")}
+	}
+	if opts == nil {
+		opts = &DiscoverOptions{}
+	}
+
+	s := &AccountState{Security: security, LastUsed: -1}
+	if opts.Resume != nil {
+		if opts.Resume.Security != security {
+			return nil, fmt.Errorf("resumed state has security %d, not %d", opts.Resume.Security, security)
+		}
+		r := *opts.Resume
+		r.Balances = append(Balances(nil), r.Balances...)
+		r.Spent = append([]uint64(nil), r.Spent...)
+		s = &r
+	}
+
+	for s.Scanned-s.NextIndex() < uint64(gapLimit) {
+		if err := api.discoverBatch(s, seed, gapLimit); err != nil {
+			return nil, err
+		}
+		if opts.Checkpoint != nil {
+			if err := opts.Checkpoint(s); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return s, nil
+}
+
+// discoverBatch scans the next n addresses of seed and adds them to s.
+func (api *API) discoverBatch(s *AccountState, seed Trytes, n int) error {
+	adrs, err := NewAddresses(seed, s.Scanned, n, s.Security)
+	if err != nil {
+		return err
+	}
+
+	txs, err := api.FindTransactionObjects(&FindTransactionsRequest{Addresses: adrs})
+	if err != nil {
+		return err
+	}
+	used := make(map[Address]bool)
+	for i := range txs {
+		used[txs[i].Address] = true
+	}
+	spent, err := api.spentFrom(adrs, txs)
+	if err != nil {
+		return err
+	}
+
+	bals, err := api.Balances(adrs)
+	if err != nil {
+		return err
+	}
+	for _, b := range bals {
+		used[b.Address] = true
+		b.Index += s.Scanned
+		s.Balances = append(s.Balances, b)
+	}
+
+	for i, adr := range adrs {
+		// a spent address may have no transactions left after a snapshot
+		if used[adr] || spent[i] {
+			s.LastUsed = int64(s.Scanned) + int64(i)
+		}
+		if spent[i] {
+			s.Spent = append(s.Spent, s.Scanned+uint64(i))
+		}
+	}
+	s.Scanned += uint64(n)
+	return nil
+}
@@ -0,0 +1,155 @@
+package giota
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestDiscoverAccountState(t *testing.T) {
+	adrs, err := NewAddresses(accountTestSeed, 0, 3, SecurityLevelLow)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// index 0 received, index 1 was spent from, index 2 has a balance from
+	// a snapshot without transactions
+	received := filterTestBundle()[0]
+	received.Address, received.Value = adrs[0], 0
+	sent := filterTestBundle()[1]
+	sent.Address, sent.Value = adrs[1], -10
+	txs := []Transaction{received, sent}
+	balances := map[Address]string{adrs[2]: "50"}
+
+	var scans int
+	api, done := newFakeNode(t, map[string]fakeNodeHandler{
+		"findTransactions": func(req map[string]json.RawMessage) interface{} {
+			var as []Address
+			json.Unmarshal(req["addresses"], &as)
+			hashes := []Hash{}
+			for i := range txs {
+				for _, a := range as {
+					if txs[i].Address == a {
+						hashes = append(hashes, txs[i].Hash())
+					}
+				}
+			}
+			return &FindTransactionsResponse{Hashes: hashes}
+		},
+		"getTrytes": func(req map[string]json.RawMessage) interface{} {
+			var hashes []Hash
+			json.Unmarshal(req["hashes"], &hashes)
+			found := []Transaction{}
+			for _, h := range hashes {
+				for i := range txs {
+					if txs[i].Hash() == h {
+						found = append(found, txs[i])
+					}
+				}
+			}
+			return &GetTrytesResponse{Trytes: found}
+		},
+		"getBalances": func(req map[string]json.RawMessage) interface{} {
+			scans++
+			var as []Address
+			json.Unmarshal(req["addresses"], &as)
+			bals := make([]string, len(as))
+			for i, a := range as {
+				bals[i] = "0"
+				if b, ok := balances[a]; ok {
+					bals[i] = b
+				}
+			}
+			return map[string]interface{}{"balances": bals}
+		},
+	})
+	defer done()
+
+	var checkpoints []AccountState
+	s, err := api.DiscoverAccountStateWithOptions(accountTestSeed, SecurityLevelLow, 3, &DiscoverOptions{
+		Checkpoint: func(s *AccountState) error {
+			checkpoints = append(checkpoints, *s)
+			return nil
+		},
+	})
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case s.LastUsed != 2 || s.NextIndex() != 3 || s.Scanned != 6:
+		t.Errorf("DiscoverAccountState() returned last used %d, scanned %d", s.LastUsed, s.Scanned)
+	case s.Balance() != 50 || s.Balances[0].Index != 2 || s.Balances[0].Address != adrs[2]:
+		t.Errorf("DiscoverAccountState() returned balances %+v", s.Balances)
+	case len(s.Spent) != 1 || s.Spent[0] != 1:
+		t.Errorf("DiscoverAccountState() returned spent %v", s.Spent)
+	case len(checkpoints) != 2 || checkpoints[0].Scanned != 3:
+		t.Fatalf("DiscoverAccountState() made checkpoints %+v", checkpoints)
+	}
+
+	// resuming after the first batch scans only the second one
+	scans = 0
+	resumed, err := api.DiscoverAccountStateWithOptions(accountTestSeed, SecurityLevelLow, 3, &DiscoverOptions{Resume: &checkpoints[0]})
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case scans != 1:
+		t.Errorf("resumed scan requested %d batches, expected 1", scans)
+	case resumed.LastUsed != s.LastUsed || resumed.Scanned != s.Scanned || resumed.Balance() != s.Balance():
+		t.Errorf("resumed scan returned %+v, expected %+v", resumed, s)
+	}
+
+	stop := errors.New("stop")
+	_, err = api.DiscoverAccountStateWithOptions(accountTestSeed, SecurityLevelLow, 3, &DiscoverOptions{
+		Checkpoint: func(*AccountState) error { return stop },
+	})
+	if err != stop {
+		t.Errorf("DiscoverAccountState() returned %v, expected the checkpoint error", err)
+	}
+
+	if _, err := api.DiscoverAccountStateWithOptions(accountTestSeed, SecurityLevelMedium, 3, &DiscoverOptions{Resume: s}); err == nil {
+		t.Error("DiscoverAccountState() resumed a state of another security level")
+	}
+}
+
+func TestDiscoverAccountStateSpentBeforeSnapshot(t *testing.T) {
+	adrs, err := NewAddresses(accountTestSeed, 0, 3, SecurityLevelLow)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// index 1 was spent from, but the snapshot of the node pruned its
+	// transactions
+	api, done := newFakeNode(t, map[string]fakeNodeHandler{
+		"findTransactions": func(map[string]json.RawMessage) interface{} {
+			return &FindTransactionsResponse{Hashes: []Hash{}}
+		},
+		"getBalances": func(req map[string]json.RawMessage) interface{} {
+			var as []Address
+			json.Unmarshal(req["addresses"], &as)
+			bals := make([]string, len(as))
+			for i := range bals {
+				bals[i] = "0"
+			}
+			return map[string]interface{}{"balances": bals}
+		},
+		"wereAddressesSpentFrom": func(req map[string]json.RawMessage) interface{} {
+			var as []Address
+			json.Unmarshal(req["addresses"], &as)
+			states := make([]bool, len(as))
+			for i := range as {
+				states[i] = as[i] == adrs[1]
+			}
+			return &WereAddressesSpentFromResponse{States: states}
+		},
+	})
+	defer done()
+
+	s, err := api.DiscoverAccountState(accountTestSeed, SecurityLevelLow, 3)
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case len(s.Spent) != 1 || s.Spent[0] != 1:
+		t.Errorf("DiscoverAccountState() returned spent %v", s.Spent)
+	case s.LastUsed != 1 || s.NextIndex() != 2:
+		t.Errorf("DiscoverAccountState() returned last used %d", s.LastUsed)
+	}
+}
@@ -0,0 +1,65 @@
+package giota
+
+// DoubleSpend is an address spent by several bundles. At most one of them
+// can be confirmed, and each spend reveals more of the key of the address.
+type DoubleSpend struct {
+	Address Address
+	// Bundles are the conflicting bundles, one attachment each, ordered
+	// by timestamp.
+	Bundles Bundles
+}
+
+// DoubleSpends returns the addresses of adrs spent by bundles of bs with
+// different bundle hashes. Reattachments of a bundle share its hash and
+// don't conflict. Invalid bundles, which nodes never confirm, are ignored.
+func (bs Bundles) DoubleSpends(adrs ...Address) []DoubleSpend {
+	spends := make(map[Address]Bundles, len(adrs))
+	seen := make(map[Address]map[BundleHash]bool, len(adrs))
+	for _, adr := range adrs {
+		seen[adr] = make(map[BundleHash]bool)
+	}
+
+	for _, b := range bs {
+		if len(b) == 0 || b.IsValid() != nil {
+			continue
+		}
+		for i := range b {
+			adr := b[i].Address
+			if b[i].Value >= 0 || seen[adr] == nil || seen[adr][b[i].Bundle] {
+				continue
+			}
+			seen[adr][b[i].Bundle] = true
+			spends[adr] = append(spends[adr], b)
+		}
+	}
+
+	var r []DoubleSpend
+	for _, adr := range adrs {
+		if s := spends[adr]; len(s) > 1 {
+			s.SortStable(ByTimestamp)
+			r = append(r, DoubleSpend{Address: adr, Bundles: s})
+			delete(spends, adr)
+		}
+	}
+	return r
+}
+
+// DetectDoubleSpends looks up the bundles of adrs, which may have checksums,
+// and returns the addresses spent by conflicting bundles, see
+// Bundles.DoubleSpends. Wallets should warn before spending such an address
+// again.
+func (api *API) DetectDoubleSpends(adrs []Address) ([]DoubleSpend, error) {
+	plain := make([]Address, len(adrs))
+	for i, adr := range adrs {
+		var err error
+		if plain[i], err = parseAddress(string(adr)); err != nil {
+			return nil, &ValidationError{Func: "DetectDoubleSpends", Arg: "adrs", Index: i, Err: err}
+		}
+	}
+
+	bs, err := api.GetBundlesFromAddresses(plain)
+	if err != nil {
+		return nil, err
+	}
+	return bs.DoubleSpends(plain...), nil
+}
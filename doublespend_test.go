@@ -0,0 +1,52 @@
+package giota
+
+import (
+	"testing"
+)
+
+func TestDoubleSpends(t *testing.T) {
+	first := lintTestBundle(t, true, nil)
+	second := lintTestBundle(t, true, func(bs Bundle) {
+		bs[0].Address = filterAddr2
+	})
+	input := first[1].Address
+
+	reattached := first.Clone()
+	for i := range reattached {
+		reattached[i] = reattached[i].WithNewAttachment(EmptyHash, EmptyHash, "NONCE", "", "", "")
+	}
+	forged := second.Clone()
+	forged[0].Value = 1
+
+	tests := []struct {
+		name    string
+		bundles Bundles
+		// conflicts is the number of conflicting bundles, or 0
+		conflicts int
+	}{
+		{name: "single spend", bundles: Bundles{first}},
+		{name: "reattachment", bundles: Bundles{first, reattached}},
+		{name: "invalid bundle", bundles: Bundles{first, forged}},
+		{name: "double spend", bundles: Bundles{second, reattached, first}, conflicts: 2},
+	}
+	for _, tt := range tests {
+		ds := tt.bundles.DoubleSpends(input, filterAddr1)
+		switch {
+		case tt.conflicts == 0 && len(ds) != 0:
+			t.Errorf("%s: DoubleSpends() = %v, expected none", tt.name, ds)
+		case tt.conflicts == 0:
+		case len(ds) != 1 || ds[0].Address != input || len(ds[0].Bundles) != tt.conflicts:
+			t.Errorf("%s: DoubleSpends() = %v", tt.name, ds)
+		}
+	}
+
+	api, done := newFakeTangle(t, append(append(Bundle{}, first...), second...))
+	defer done()
+	ds, err := api.DetectDoubleSpends([]Address{Address(input.WithChecksum())})
+	if err != nil || len(ds) != 1 || ds[0].Bundles[0][0].Bundle == ds[0].Bundles[1][0].Bundle {
+		t.Errorf("DetectDoubleSpends() = %v, %v", ds, err)
+	}
+	if _, err := api.DetectDoubleSpends([]Address{"INVALID"}); err == nil {
+		t.Error("DetectDoubleSpends() should fail for an invalid address")
+	}
+}
@@ -0,0 +1,76 @@
+package giota
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNodeResponse is wrapped by the errors reported by a node, either in
+// the error field of its response or with an HTTP error status.
+var ErrNodeResponse = errors.New("node returned an error")
+
+// Sentinels of the steps of SendTrytes. The errors of SendTrytes, and of
+// the funcs calling it, wrap the one of the failed step, so that
+// errors.Is(err, ErrBroadcast) tells that the bundle was attached but not
+// broadcasted.
+var (
+	ErrTipSelection = errors.New("tip selection failed")
+	ErrAttach       = errors.New("attaching failed")
+	ErrBroadcast    = errors.New("broadcasting failed")
+	ErrStore        = errors.New("storing failed")
+)
+
+// CallError is returned by API calls if the call of a command at a node
+// fails. It wraps the reason, e.g. ErrNodeResponse, ErrResponseTooLarge,
+// ErrNodeUnhealthy or a network error.
+type CallError struct {
+	// Command is the name of the IRI command, e.g. getTrytes.
+	Command string
+	// Endpoint is the endpoint of the node.
+	Endpoint string
+	Err      error
+}
+
+func (e *CallError) Error() string {
+	return fmt.Sprintf("%s at %s: %s", e.Command, e.Endpoint, e.Err)
+}
+
+// Unwrap returns the reason of the failure.
+func (e *CallError) Unwrap() error {
+	return e.Err
+}
+
+// SendError is returned by SendTrytes if a step of sending a bundle fails.
+type SendError struct {
+	// Step is the sentinel of the failed step, e.g. ErrBroadcast.
+	Step error
+	// Tail is the hash of the attached tail transaction, or empty if the
+	// bundle wasn't attached.
+	Tail Hash
+	Err  error
+}
+
+func (e *SendError) Error() string {
+	if e.Tail == "" {
+		return fmt.Sprintf("send: %s: %s", e.Step, e.Err)
+	}
+	return fmt.Sprintf("send: %s for tail %s: %s", e.Step, e.Tail, e.Err)
+}
+
+// Is returns true if target is the sentinel of the failed step.
+func (e *SendError) Is(target error) bool {
+	return target == e.Step
+}
+
+// Unwrap returns the reason of the failure.
+func (e *SendError) Unwrap() error {
+	return e.Err
+}
+
+// sendError wraps err, if any, as SendError of step.
+func sendError(step error, tail Hash, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &SendError{Step: step, Tail: tail, Err: err}
+}
@@ -0,0 +1,71 @@
+package giota
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSendErrors(t *testing.T) {
+	tips := func(map[string]json.RawMessage) interface{} {
+		return &GetTransactionsToApproveResponse{
+			TrunkTransaction:  Hash(strings.Repeat("A", 81)),
+			BranchTransaction: Hash(strings.Repeat("B", 81)),
+		}
+	}
+	ok := func(map[string]json.RawMessage) interface{} {
+		return map[string]interface{}{}
+	}
+	pow := func(Trytes, int) (Trytes, error) {
+		return Trytes(strings.Repeat("9", NonceTrinarySize/3)), nil
+	}
+
+	tests := []struct {
+		name     string
+		handlers map[string]fakeNodeHandler
+		step     error
+		command  string
+		attached bool
+	}{
+		{
+			name:     "tip selection",
+			handlers: map[string]fakeNodeHandler{},
+			step:     ErrTipSelection,
+			command:  "getTransactionsToApprove",
+		},
+		{
+			name:     "broadcast",
+			handlers: map[string]fakeNodeHandler{"getTransactionsToApprove": tips},
+			step:     ErrBroadcast,
+			command:  "broadcastTransactions",
+			attached: true,
+		},
+		{
+			name:     "store",
+			handlers: map[string]fakeNodeHandler{"getTransactionsToApprove": tips, "broadcastTransactions": ok},
+			step:     ErrStore,
+			command:  "storeTransactions",
+			attached: true,
+		},
+	}
+
+	for _, tt := range tests {
+		api, done := newFakeNode(t, tt.handlers)
+		_, err := SendTrytes(api, 3, filterTestBundle(), 1, pow)
+		done()
+
+		var serr *SendError
+		var cerr *CallError
+		switch {
+		case !errors.Is(err, tt.step):
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.step, err)
+		case !errors.Is(err, ErrNodeResponse):
+			t.Errorf("%s: expected ErrNodeResponse, got %v", tt.name, err)
+		case !errors.As(err, &serr) || (serr.Tail != "") != tt.attached:
+			t.Errorf("%s: unexpected tail in %v", tt.name, err)
+		case !errors.As(err, &cerr) || cerr.Command != tt.command || cerr.Endpoint != api.endpoint:
+			t.Errorf("%s: unexpected call in %v", tt.name, err)
+		}
+	}
+}
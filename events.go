@@ -0,0 +1,131 @@
+package giota
+
+import (
+	"sync"
+	"time"
+)
+
+// EventKind is the kind of an Event.
+type EventKind int
+
+// Kinds of events.
+const (
+	// EventTransferPrepared is published by PrepareTransfers for a
+	// prepared bundle.
+	EventTransferPrepared EventKind = iota + 1
+	// EventBundleSigned is published by PrepareTransfers when the inputs of
+	// a bundle are signed.
+	EventBundleSigned
+	// EventPowStarted is published by SendTrytes before the PoW of a
+	// bundle, which is done locally or by the node.
+	EventPowStarted
+	// EventPowFinished is published by SendTrytes after the PoW of a
+	// bundle. Err is set if the PoW failed.
+	EventPowFinished
+	// EventBroadcasted is published by SendTrytes when an attached bundle
+	// is broadcast and stored.
+	EventBroadcasted
+	// EventReattached is published by SendTrytes after EventBroadcasted if
+	// the bundle was attached before.
+	EventReattached
+	// EventConfirmed is published by IsBundleConfirmed, and so by the funcs
+	// waiting for confirmations, when it finds a bundle confirmed.
+	EventConfirmed
+)
+
+var eventNames = map[EventKind]string{
+	EventTransferPrepared: "TransferPrepared",
+	EventBundleSigned:     "BundleSigned",
+	EventPowStarted:       "PowStarted",
+	EventPowFinished:      "PowFinished",
+	EventBroadcasted:      "Broadcasted",
+	EventReattached:       "Reattached",
+	EventConfirmed:        "Confirmed",
+}
+
+func (k EventKind) String() string {
+	if n, ok := eventNames[k]; ok {
+		return n
+	}
+	return "unknown"
+}
+
+// Event is an event of a bundle on its way to the Tangle.
+type Event struct {
+	Kind EventKind
+	Time time.Time
+	// Bundle is the hash of the bundle.
+	Bundle BundleHash
+	// Tail is the hash of the tail transaction if the bundle is attached.
+	Tail Hash
+	// Transactions is the number of transactions of the bundle. It is zero
+	// for EventConfirmed.
+	Transactions int
+	// Err is the error of a failed PoW.
+	Err error
+}
+
+// eventBus holds the subscribers of the events of an API.
+type eventBus struct {
+	mu   sync.RWMutex
+	next int
+	subs map[int]func(Event)
+}
+
+// SubscribeEvents calls fn with the events of the funcs using api, e.g. to
+// collect metrics or to track transfers. fn is called synchronously by the
+// goroutine causing the event, so it must not block or call api. Calling the
+// returned func unsubscribes fn.
+func (api *API) SubscribeEvents(fn func(Event)) func() {
+	b := &api.events
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.subs == nil {
+		b.subs = make(map[int]func(Event))
+	}
+	id := b.next
+	b.next++
+	b.subs[id] = fn
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subs, id)
+			b.mu.Unlock()
+		})
+	}
+}
+
+// publish sends e to the subscribers of api, setting its time.
+func (api *API) publish(e Event) {
+	if api == nil {
+		return
+	}
+	b := &api.events
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if len(b.subs) == 0 {
+		return
+	}
+	e.Time = time.Now()
+	for _, fn := range b.subs {
+		fn(e)
+	}
+}
+
+// publishBundle publishes an event of kind for the transactions txs.
+func (api *API) publishBundle(kind EventKind, txs []Transaction, err error) {
+	if api == nil || len(txs) == 0 {
+		return
+	}
+	e := Event{Kind: kind, Bundle: txs[0].Bundle, Transactions: len(txs), Err: err}
+	for i := range txs {
+		if txs[i].CurrentIndex == 0 && txs[i].TrunkTransaction != "" && txs[i].TrunkTransaction != EmptyHash {
+			e.Tail = txs[i].Hash()
+		}
+	}
+	api.publish(e)
+}
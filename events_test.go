@@ -0,0 +1,88 @@
+package giota
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSubscribeEvents(t *testing.T) {
+	api, done := newFakeTangle(t, filterTestBundle())
+	defer done()
+
+	var kinds []EventKind
+	var events []Event
+	unsubscribe := api.SubscribeEvents(func(e Event) {
+		kinds = append(kinds, e.Kind)
+		events = append(events, e)
+	})
+
+	inputs := []AddressInfo{{Seed: accountTestSeed, Index: 0, Security: SecurityLevelLow}}
+	bd, err := PrepareTransfers(api, accountTestSeed, []Transfer{{Address: filterAddr1, Value: 100}}, inputs, filterAddr2, SecurityLevelLow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pow := func(Trytes, int) (Trytes, error) { return "NONCE", nil }
+	res, err := SendTrytes(api, 0, bd, 1, pow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SendTrytes(api, 0, res.Transactions, 1, pow); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []EventKind{
+		EventBundleSigned, EventTransferPrepared,
+		EventPowStarted, EventPowFinished, EventBroadcasted,
+		EventPowStarted, EventPowFinished, EventBroadcasted, EventReattached,
+	}
+	if len(kinds) != len(want) {
+		t.Fatalf("published %v, expected %v", kinds, want)
+	}
+	for i, e := range events {
+		switch {
+		case e.Kind != want[i]:
+			t.Errorf("event %d is %s, expected %s", i, e.Kind, want[i])
+		case e.Bundle != bd[0].Bundle || e.Transactions != len(bd) || e.Time.IsZero():
+			t.Errorf("%s has bundle %s with %d transactions", e.Kind, e.Bundle, e.Transactions)
+		case (e.Kind == EventBroadcasted || e.Kind == EventPowFinished) && e.Tail == "":
+			t.Errorf("%s has no tail", e.Kind)
+		}
+	}
+
+	unsubscribe()
+	unsubscribe()
+	if _, err := PrepareTransfers(api, accountTestSeed, []Transfer{{Address: filterAddr1}}, nil, "", SecurityLevelLow); err != nil {
+		t.Fatal(err)
+	}
+	if len(kinds) != len(want) {
+		t.Errorf("unsubscribed func received %s", kinds[len(want)])
+	}
+}
+
+func TestEventConfirmed(t *testing.T) {
+	bs := filterTestBundle()
+	api, done := newFakeNode(t, map[string]fakeNodeHandler{
+		"findTransactions": func(map[string]json.RawMessage) interface{} {
+			return &FindTransactionsResponse{Hashes: []Hash{bs[0].Hash()}}
+		},
+		"getTrytes": func(map[string]json.RawMessage) interface{} {
+			return &GetTrytesResponse{Trytes: []Transaction{bs[0]}}
+		},
+		"getNodeInfo": func(map[string]json.RawMessage) interface{} {
+			return &GetNodeInfoResponse{LatestMilestone: EmptyHash}
+		},
+		"getInclusionStates": func(map[string]json.RawMessage) interface{} {
+			return &GetInclusionStatesResponse{States: []bool{true}}
+		},
+	})
+	defer done()
+
+	var got []Event
+	api.SubscribeEvents(func(e Event) { got = append(got, e) })
+	if _, _, err := api.IsBundleConfirmed(bs[0].Bundle); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Kind != EventConfirmed || got[0].Bundle != bs[0].Bundle || got[0].Tail != bs[0].Hash() {
+		t.Errorf("IsBundleConfirmed() published %+v", got)
+	}
+}
@@ -32,17 +32,17 @@ func main() {
 	}
 
 	fmt.Print("\nhow many addresses should we check: ")
-	var offset int
+	var offset uint64
 	n, err := fmt.Scanf("%d\n", &offset)
 	if err != nil || n < 1 {
 		log.Fatal("expecting integer offset")
 	}
 
-	fmt.Print("which security level should the addresses be generated at (0, 1, 2; default is 2): ")
-	var slevel int
+	fmt.Print("which security level should the addresses be generated at (1, 2, 3; default is 2): ")
+	var slevel giota.SecurityLevel
 	n, err = fmt.Scanf("%d\n", &slevel)
-	if err != nil || n < 1 || slevel < 0 || slevel > 2 {
-		slevel = 2
+	if err != nil || n < 1 || slevel.IsValid() != nil {
+		slevel = giota.SecurityLevelMedium
 	}
 
 	println("Getting balances")
@@ -0,0 +1,69 @@
+package explorerhttp
+
+import (
+	"sync"
+	"time"
+)
+
+type cacheEntry struct {
+	value   interface{}
+	expires time.Time
+}
+
+// cache stores successful responses by key for ttl.
+type cache struct {
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+func newCache(ttl time.Duration, maxEntries int) *cache {
+	return &cache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]cacheEntry),
+	}
+}
+
+// get returns the cached value of key, or calls fetch and caches its value
+// if it doesn't return an error.
+func (c *cache) get(key string, fetch func() (interface{}, error)) (interface{}, error) {
+	if c.ttl <= 0 {
+		return fetch()
+	}
+
+	now := time.Now()
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && now.Before(e.expires) {
+		return e.value, nil
+	}
+
+	v, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.maxEntries > 0 && len(c.entries) >= c.maxEntries {
+		c.evict(now)
+	}
+	c.entries[key] = cacheEntry{value: v, expires: now.Add(c.ttl)}
+	return v, nil
+}
+
+// evict removes expired entries, or all entries if none is expired.
+func (c *cache) evict(now time.Time) {
+	for k, e := range c.entries {
+		if !now.Before(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+	if len(c.entries) >= c.maxEntries {
+		c.entries = make(map[string]cacheEntry)
+	}
+}
@@ -0,0 +1,269 @@
+// Package explorerhttp provides net/http handlers of a minimal Tangle
+// explorer backed by a node, to be embedded into other services:
+//
+//	GET /tx/{hash}          a transaction and its confirmation state
+//	GET /bundle/{hash}      all transactions of a bundle, including reattachments
+//	GET /address/{address}  the balance and transactions of an address
+//
+// Responses are JSON and cached, see NewWithCache. To serve the explorer
+// below a prefix, use http.StripPrefix:
+//
+//	http.Handle("/explorer/", http.StripPrefix("/explorer", explorerhttp.New(api)))
+package explorerhttp
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/iotaledger/giota"
+	"github.com/iotaledger/giota/decode"
+)
+
+// Defaults of the cache of an Explorer.
+const (
+	DefaultCacheTTL        = 30 * time.Second
+	DefaultMaxCacheEntries = 1000
+)
+
+// ErrNotFound is returned for hashes which are unknown to the node.
+var ErrNotFound = errors.New("not found")
+
+// Explorer is an http.Handler serving the explorer endpoints.
+type Explorer struct {
+	api   *giota.API
+	cache *cache
+}
+
+// New returns an Explorer querying api, with a cache using the default
+// settings.
+func New(api *giota.API) *Explorer {
+	return NewWithCache(api, DefaultCacheTTL, DefaultMaxCacheEntries)
+}
+
+// NewWithCache returns an Explorer querying api, which caches up to
+// maxEntries responses for ttl. A ttl of 0 disables caching, a maxEntries
+// of 0 doesn't limit the number of entries.
+func NewWithCache(api *giota.API, ttl time.Duration, maxEntries int) *Explorer {
+	return &Explorer{
+		api:   api,
+		cache: newCache(ttl, maxEntries),
+	}
+}
+
+// TransactionResponse is the response of /tx/{hash}.
+type TransactionResponse struct {
+	Hash        giota.Hash        `json:"hash"`
+	Transaction giota.Transaction `json:"transaction"`
+	Confirmed   bool              `json:"confirmed"`
+	// Message is the message of the transaction as text if it is printable.
+	Message string `json:"message,omitempty"`
+}
+
+// BundleResponse is the response of /bundle/{hash}.
+type BundleResponse struct {
+	Hash giota.BundleHash `json:"hash"`
+	// Transactions are sorted by attachment time and index, so that the
+	// transactions of each attachment are adjacent.
+	Transactions []giota.Transaction `json:"transactions"`
+	// Tails are the hashes of the tail transactions of all attachments.
+	Tails     []giota.Hash `json:"tails"`
+	Confirmed bool         `json:"confirmed"`
+}
+
+// AddressResponse is the response of /address/{address}.
+type AddressResponse struct {
+	Address      giota.Trytes `json:"address"`
+	Balance      int64        `json:"balance"`
+	Transactions []giota.Hash `json:"transactions"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// requestError is an error caused by the request, as opposed to errors of
+// the node.
+type requestError struct {
+	status int
+	err    error
+}
+
+func (e *requestError) Error() string {
+	return e.err.Error()
+}
+
+// ServeHTTP implements http.Handler.
+func (e *Explorer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeJSON(w, http.StatusMethodNotAllowed, &errorResponse{Error: "method not allowed"})
+		return
+	}
+
+	var get func(string) (interface{}, error)
+	path := r.URL.Path
+	for _, rt := range []struct {
+		prefix string
+		get    func(string) (interface{}, error)
+	}{
+		{"/tx/", e.transaction},
+		{"/bundle/", e.bundle},
+		{"/address/", e.address},
+	} {
+		if strings.HasPrefix(path, rt.prefix) {
+			get = rt.get
+			path = path[len(rt.prefix):]
+			break
+		}
+	}
+	if get == nil || path == "" || strings.Contains(path, "/") {
+		writeJSON(w, http.StatusNotFound, &errorResponse{Error: "not found"})
+		return
+	}
+
+	resp, err := e.cache.get(r.URL.Path, func() (interface{}, error) {
+		return get(path)
+	})
+	if err != nil {
+		status := http.StatusBadGateway
+		if re, ok := err.(*requestError); ok {
+			status = re.status
+		}
+		writeJSON(w, status, &errorResponse{Error: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func parseHash(s string) (giota.Hash, error) {
+	h := giota.Hash(s)
+	if err := h.IsValid(); err != nil {
+		return "", &requestError{status: http.StatusBadRequest, err: err}
+	}
+	return h, nil
+}
+
+func (e *Explorer) transaction(s string) (interface{}, error) {
+	h, err := parseHash(s)
+	if err != nil {
+		return nil, err
+	}
+
+	gt, err := e.api.GetTrytes([]giota.Hash{h})
+	if err != nil {
+		return nil, err
+	}
+	if len(gt.Trytes) == 0 || gt.Trytes[0].IsNull() {
+		return nil, &requestError{status: http.StatusNotFound, err: ErrNotFound}
+	}
+	tx := gt.Trytes[0]
+
+	inc, err := e.api.GetLatestInclusion([]giota.Hash{h})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &TransactionResponse{
+		Hash:        h,
+		Transaction: tx,
+		Confirmed:   len(inc) > 0 && inc[0],
+	}
+	if d, err := decode.Decode(tx.Trytes()); err == nil {
+		resp.Message = d.Message
+	}
+	return resp, nil
+}
+
+func (e *Explorer) bundle(s string) (interface{}, error) {
+	h, err := parseHash(s)
+	if err != nil {
+		return nil, err
+	}
+	bundle := giota.BundleHash(h)
+
+	txs, err := e.api.FindTransactionObjects(&giota.FindTransactionsRequest{Bundles: []giota.BundleHash{bundle}})
+	if err != nil {
+		return nil, err
+	}
+	if len(txs) == 0 {
+		return nil, &requestError{status: http.StatusNotFound, err: ErrNotFound}
+	}
+
+	sort.SliceStable(txs, func(i, j int) bool {
+		ti, tj := txs[i].AttachmentTime(), txs[j].AttachmentTime()
+		if !ti.Equal(tj) {
+			return ti.Before(tj)
+		}
+		return txs[i].CurrentIndex < txs[j].CurrentIndex
+	})
+
+	resp := &BundleResponse{
+		Hash:         bundle,
+		Transactions: txs,
+		Tails:        []giota.Hash{},
+	}
+	for i := range txs {
+		if txs[i].CurrentIndex == 0 {
+			resp.Tails = append(resp.Tails, txs[i].Hash())
+		}
+	}
+	if len(resp.Tails) == 0 {
+		return resp, nil
+	}
+
+	inc, err := e.api.GetLatestInclusion(resp.Tails)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range inc {
+		resp.Confirmed = resp.Confirmed || c
+	}
+	return resp, nil
+}
+
+func (e *Explorer) address(s string) (interface{}, error) {
+	var (
+		adr giota.Address
+		err error
+	)
+	if len(s) == 90 {
+		adr, err = giota.ValidateChecksummedString(s)
+	} else {
+		adr, err = giota.Trytes(s).ToAddress()
+	}
+	if err != nil {
+		return nil, &requestError{status: http.StatusBadRequest, err: err}
+	}
+
+	bal, err := e.api.GetBalances([]giota.Address{adr}, 100)
+	if err != nil {
+		return nil, err
+	}
+
+	ft, err := e.api.FindTransactions(&giota.FindTransactionsRequest{Addresses: []giota.Address{adr}})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &AddressResponse{
+		Address:      adr.WithChecksum(),
+		Transactions: ft.Hashes,
+	}
+	if len(bal.Balances) > 0 {
+		resp.Balance = bal.Balances[0]
+	}
+	if resp.Transactions == nil {
+		resp.Transactions = []giota.Hash{}
+	}
+	return resp, nil
+}
@@ -0,0 +1,156 @@
+package explorerhttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/iotaledger/giota"
+)
+
+const testAddress giota.Address = "PQZKJKZAHOPZLXRZSPKFHZDLITQTQYDCHSYJYECIFGIISVJXKDGTSSMZE9XAXVUVQWIYLLJLKQDJBMMTZ"
+
+// newFakeNode returns an API of a node which knows the transactions of bs,
+// and a func returning the number of calls of a command.
+func newFakeNode(t *testing.T, bs giota.Bundle) (*giota.API, func(string) int, func()) {
+	var (
+		mu    sync.Mutex
+		calls = map[string]int{}
+		txs   = map[giota.Hash]giota.Transaction{}
+	)
+	for _, tx := range bs {
+		txs[tx.Hash()] = tx
+	}
+	null, err := giota.NewTransaction(giota.Trytes(strings.Repeat("9", giota.TransactionTrinarySize/3)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Command string
+			Hashes  []giota.Hash
+			Bundles []giota.Trytes
+			Tx      []giota.Trytes `json:"transactions"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("fake node could not decode request: %s", err)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		calls[req.Command]++
+
+		var resp interface{} = struct{}{}
+		switch req.Command {
+		case "getNodeInfo":
+			resp = &giota.GetNodeInfoResponse{LatestMilestone: giota.EmptyHash}
+		case "getInclusionStates":
+			resp = &giota.GetInclusionStatesResponse{States: make([]bool, len(req.Tx))}
+		case "getBalances":
+			resp = map[string]interface{}{"balances": []string{"42"}}
+		case "findTransactions":
+			hashes := []giota.Hash{}
+			for h, tx := range txs {
+				if len(req.Bundles) == 0 || tx.Bundle == giota.BundleHash(req.Bundles[0]) {
+					hashes = append(hashes, h)
+				}
+			}
+			resp = &giota.FindTransactionsResponse{Hashes: hashes}
+		case "getTrytes":
+			found := []giota.Transaction{}
+			for _, h := range req.Hashes {
+				tx, ok := txs[h]
+				if !ok {
+					tx = *null
+				}
+				found = append(found, tx)
+			}
+			resp = &giota.GetTrytesResponse{Trytes: found}
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	count := func(cmd string) int {
+		mu.Lock()
+		defer mu.Unlock()
+		return calls[cmd]
+	}
+	return giota.NewAPI(srv.URL, nil), count, srv.Close
+}
+
+func TestExplorer(t *testing.T) {
+	var bs giota.Bundle
+	bs.Add(2, testAddress, 0, time.Unix(1500000000, 0), "EXPLORER")
+	bs.Finalize([]giota.Trytes{giota.EncodeMessage([]byte("hi"))})
+
+	api, calls, done := newFakeNode(t, bs)
+	defer done()
+	srv := httptest.NewServer(New(api))
+	defer srv.Close()
+
+	tests := []struct {
+		path   string
+		status int
+		check  func(body *json.Decoder) bool
+	}{
+		{
+			path:   "/tx/" + string(bs[0].Hash()),
+			status: http.StatusOK,
+			check: func(d *json.Decoder) bool {
+				var r TransactionResponse
+				return d.Decode(&r) == nil && r.Hash == bs[0].Hash() && r.Transaction.Bundle == bs[0].Bundle && r.Message == "hi"
+			},
+		},
+		{
+			path:   "/bundle/" + string(bs[0].Bundle),
+			status: http.StatusOK,
+			check: func(d *json.Decoder) bool {
+				var r BundleResponse
+				return d.Decode(&r) == nil && len(r.Transactions) == 2 && r.Transactions[0].CurrentIndex == 0 &&
+					len(r.Tails) == 1 && r.Tails[0] == bs[0].Hash() && !r.Confirmed
+			},
+		},
+		{
+			path:   "/address/" + string(testAddress.WithChecksum()),
+			status: http.StatusOK,
+			check: func(d *json.Decoder) bool {
+				var r AddressResponse
+				return d.Decode(&r) == nil && r.Balance == 42 && len(r.Transactions) == 2
+			},
+		},
+		{path: "/tx/" + string(giota.EmptyHash), status: http.StatusNotFound},
+		{path: "/tx/ABC", status: http.StatusBadRequest},
+		{path: "/address/" + string(testAddress) + "999999999", status: http.StatusBadRequest},
+		{path: "/unknown/", status: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		resp, err := http.Get(srv.URL + tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		switch {
+		case resp.StatusCode != tt.status:
+			t.Errorf("%s: status %d, expected %d", tt.path, resp.StatusCode, tt.status)
+		case tt.check != nil && !tt.check(json.NewDecoder(resp.Body)):
+			t.Errorf("%s: unexpected response", tt.path)
+		}
+		resp.Body.Close()
+	}
+
+	// the transaction is served from the cache
+	n := calls("getTrytes")
+	resp, err := http.Get(srv.URL + tests[0].path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if calls("getTrytes") != n {
+		t.Error("cached transaction was fetched again")
+	}
+}
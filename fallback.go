@@ -0,0 +1,43 @@
+package giota
+
+// SetTrytesFallback sets nodes which GetTrytes asks, in order, for the
+// transactions the node of api doesn't know, e.g. because it pruned them at
+// a snapshot. Pass api.Permanode() to ask the permanode too. Transactions
+// which don't match their hash are ignored. No nodes disable it.
+// SetTrytesFallback must not be called concurrently with API calls.
+func (api *API) SetTrytesFallback(nodes ...*API) {
+	api.fallback = nodes
+}
+
+// fillMissingTrytes replaces the all-9s placeholders of unknown transactions
+// in resp, the response to getTrytes of hashes, with the transactions found
+// on the fallback nodes. Failing fallback nodes are skipped.
+func (api *API) fillMissingTrytes(hashes []Hash, resp *GetTrytesResponse) {
+	if len(api.fallback) == 0 || len(resp.Trytes) != len(hashes) {
+		return
+	}
+
+	for _, node := range api.fallback {
+		var missing []Hash
+		var index []int
+		for i := range resp.Trytes {
+			if resp.Trytes[i].IsNull() {
+				missing = append(missing, hashes[i])
+				index = append(index, i)
+			}
+		}
+		if len(missing) == 0 {
+			return
+		}
+
+		gt, err := node.GetTrytes(missing)
+		if err != nil || len(gt.Trytes) != len(missing) {
+			continue
+		}
+		for j := range gt.Trytes {
+			if !gt.Trytes[j].IsNull() && gt.Trytes[j].Hash() == missing[j] {
+				resp.Trytes[index[j]] = gt.Trytes[j]
+			}
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package giota
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// newTrytesNode returns a node answering getTrytes with txs and the all-9s
+// placeholder for unknown hashes. calls counts its getTrytes calls.
+func newTrytesNode(t *testing.T, calls *int, txs ...Transaction) (*API, func()) {
+	null, _ := NewTransaction(Trytes(strings.Repeat("9", TransactionTrinarySize/3)))
+	return newFakeNode(t, map[string]fakeNodeHandler{
+		"getTrytes": func(req map[string]json.RawMessage) interface{} {
+			*calls++
+			var hashes []Hash
+			json.Unmarshal(req["hashes"], &hashes)
+
+			found := make([]Transaction, len(hashes))
+			for i, h := range hashes {
+				found[i] = *null
+				for _, tx := range txs {
+					if tx.Hash() == h {
+						found[i] = tx
+					}
+				}
+			}
+			return &GetTrytesResponse{Trytes: found}
+		},
+	})
+}
+
+func TestTrytesFallback(t *testing.T) {
+	bs := filterTestBundle()
+	hashes := []Hash{bs[0].Hash(), bs[1].Hash(), bs[2].Hash()}
+
+	forged := bs[2]
+	forged.Tag = "FORGED"
+
+	var primaryCalls, prunedCalls, fullCalls, forgedCalls int
+	api, done := newTrytesNode(t, &primaryCalls, bs[0])
+	defer done()
+	pruned, pdone := newTrytesNode(t, &prunedCalls, bs[1])
+	defer pdone()
+	forger, fdone := newTrytesNode(t, &forgedCalls, forged)
+	defer fdone()
+	full, fulldone := newTrytesNode(t, &fullCalls, bs...)
+	defer fulldone()
+
+	gt, err := api.GetTrytes(hashes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !gt.Trytes[1].IsNull() {
+		t.Error("GetTrytes() found a transaction without fallback")
+	}
+
+	api.SetTrytesFallback(pruned, forger, full)
+	gt, err = api.GetTrytes(hashes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range gt.Trytes {
+		if gt.Trytes[i].Hash() != hashes[i] {
+			t.Errorf("GetTrytes() returned a wrong transaction %d", i)
+		}
+	}
+	if primaryCalls != 2 || prunedCalls != 1 || forgedCalls != 1 || fullCalls != 1 {
+		t.Errorf("getTrytes calls = %d, %d, %d, %d", primaryCalls, prunedCalls, forgedCalls, fullCalls)
+	}
+
+	// nothing is missing, so no fallback node is asked
+	if _, err := api.GetTrytes(hashes[:1]); err != nil || prunedCalls != 1 {
+		t.Errorf("GetTrytes() = %v, asked the fallback node %d times", err, prunedCalls)
+	}
+}
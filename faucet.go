@@ -0,0 +1,88 @@
+package giota
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// DevnetFaucetURL is the endpoint of the public devnet faucet.
+const DevnetFaucetURL = "https://faucet.devnet.iota.org/api"
+
+// ErrFaucetTimeout is returned by Fund if the tokens didn't arrive in time.
+var ErrFaucetTimeout = errors.New("faucet tokens did not arrive in time")
+
+// Faucet requests tokens from a devnet faucet, which makes value transfers
+// testable without pre-funded seeds.
+type Faucet struct {
+	// URL is the faucet endpoint. If empty, DevnetFaucetURL is used.
+	URL string
+	// Client is used for the requests. If nil, http.DefaultClient is used.
+	Client *http.Client
+	// Captcha is passed along for faucets which require solving one.
+	Captcha string
+}
+
+// Request asks the faucet to send tokens to adr.
+func (f *Faucet) Request(adr Address) error {
+	u := f.URL
+	if u == "" {
+		u = DevnetFaucetURL
+	}
+
+	c := f.Client
+	if c == nil {
+		c = http.DefaultClient
+	}
+
+	q := url.Values{}
+	q.Set("address", string(adr.WithChecksum()))
+	if f.Captcha != "" {
+		q.Set("captcha", f.Captcha)
+	}
+
+	resp, err := c.PostForm(u, q)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		b, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("faucet returned http status %d: %s", resp.StatusCode, b)
+	}
+	return nil
+}
+
+// Fund requests tokens for the first unused address of seed and polls its
+// balance every interval until they arrive or timeout passes.
+func (f *Faucet) Fund(api *API, seed Trytes, security SecurityLevel, interval, timeout time.Duration) (Address, int64, error) {
+	adr, _, err := GetUsedAddress(api, seed, security)
+	if err != nil {
+		return "", 0, err
+	}
+
+	if err := f.Request(adr); err != nil {
+		return "", 0, err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		r, err := api.GetBalances([]Address{adr}, 100)
+		if err != nil {
+			return "", 0, err
+		}
+
+		if len(r.Balances) > 0 && r.Balances[0] > 0 {
+			return adr, r.Balances[0], nil
+		}
+
+		if time.Now().Add(interval).After(deadline) {
+			return adr, 0, ErrFaucetTimeout
+		}
+		time.Sleep(interval)
+	}
+}
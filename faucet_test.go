@@ -0,0 +1,60 @@
+package giota
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFaucetFund(t *testing.T) {
+	adr, err := NewAddress(accountTestSeed, 0, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var requested string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.FormValue("address")
+	}))
+	defer srv.Close()
+
+	polls := 0
+	api, done := newFakeNode(t, map[string]fakeNodeHandler{
+		"findTransactions": func(map[string]json.RawMessage) interface{} {
+			return &FindTransactionsResponse{Hashes: []Hash{}}
+		},
+		"getBalances": func(map[string]json.RawMessage) interface{} {
+			polls++
+			if polls < 3 {
+				return map[string]interface{}{"balances": []string{"0"}}
+			}
+			return map[string]interface{}{"balances": []string{"1000"}}
+		},
+	})
+	defer done()
+
+	f := &Faucet{URL: srv.URL}
+	got, bal, err := f.Fund(api, accountTestSeed, 2, time.Millisecond, time.Second)
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case got != adr || bal != 1000:
+		t.Errorf("Fund() returned %s with %d", got, bal)
+	case requested != string(adr.WithChecksum()):
+		t.Errorf("Fund() requested tokens for %q", requested)
+	}
+}
+
+func TestFaucetRequestError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "captcha required", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	f := &Faucet{URL: srv.URL}
+	if err := f.Request(filterAddr1); err == nil {
+		t.Error("Request() should fail if the faucet refuses")
+	}
+}
@@ -0,0 +1,140 @@
+package giota
+
+import (
+	"strings"
+	"time"
+)
+
+// Filter is a predicate on transactions. Filters can be combined with And, Or
+// and Not, e.g.
+//
+//	f := AddressFilter(adr).And(ValueRangeFilter(1, Gi)).Not()
+type Filter func(*Transaction) bool
+
+// Match returns true if tx satisfies f. A nil Filter matches everything.
+func (f Filter) Match(tx *Transaction) bool {
+	return f == nil || f(tx)
+}
+
+// Apply returns the transactions in txs which satisfy f.
+func (f Filter) Apply(txs []Transaction) []Transaction {
+	out := make([]Transaction, 0, len(txs))
+	for i := range txs {
+		if f.Match(&txs[i]) {
+			out = append(out, txs[i])
+		}
+	}
+	return out
+}
+
+// And returns a Filter which matches if f and all of fs match.
+func (f Filter) And(fs ...Filter) Filter {
+	return func(tx *Transaction) bool {
+		if !f.Match(tx) {
+			return false
+		}
+		for _, g := range fs {
+			if !g.Match(tx) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or returns a Filter which matches if f or any of fs match.
+func (f Filter) Or(fs ...Filter) Filter {
+	return func(tx *Transaction) bool {
+		if f.Match(tx) {
+			return true
+		}
+		for _, g := range fs {
+			if g.Match(tx) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not returns a Filter which matches if f doesn't match.
+func (f Filter) Not() Filter {
+	return func(tx *Transaction) bool {
+		return !f.Match(tx)
+	}
+}
+
+// AddressFilter matches transactions whose address is one of adrs.
+func AddressFilter(adrs ...Address) Filter {
+	set := make(map[Address]struct{}, len(adrs))
+	for _, a := range adrs {
+		set[a] = struct{}{}
+	}
+
+	return func(tx *Transaction) bool {
+		_, ok := set[tx.Address]
+		return ok
+	}
+}
+
+// TagPrefixFilter matches transactions whose tag starts with prefix.
+func TagPrefixFilter(prefix Trytes) Filter {
+	return func(tx *Transaction) bool {
+		return strings.HasPrefix(string(tx.Tag), string(prefix))
+	}
+}
+
+// ValueRangeFilter matches transactions whose value is within [min, max].
+func ValueRangeFilter(min, max int64) Filter {
+	return func(tx *Transaction) bool {
+		return tx.Value >= min && tx.Value <= max
+	}
+}
+
+// TimestampFilter matches transactions whose timestamp is within [from, to].
+// A zero from or to leaves the window open on that side.
+func TimestampFilter(from, to time.Time) Filter {
+	return func(tx *Transaction) bool {
+		switch {
+		case !from.IsZero() && tx.Timestamp.Before(from):
+			return false
+		case !to.IsZero() && tx.Timestamp.After(to):
+			return false
+		}
+		return true
+	}
+}
+
+// ConfirmedFilter matches transactions whose hash is marked true in states,
+// e.g. as obtained from GetLatestInclusion.
+func ConfirmedFilter(states map[Hash]bool) Filter {
+	return func(tx *Transaction) bool {
+		return states[tx.Hash()]
+	}
+}
+
+// ConfirmedFilter calls GetLatestInclusion for txs and returns a Filter which
+// matches the confirmed ones.
+func (api *API) ConfirmedFilter(txs []Transaction) (Filter, error) {
+	hashes := make([]Hash, len(txs))
+	for i := range txs {
+		hashes[i] = txs[i].Hash()
+	}
+
+	states := make(map[Hash]bool, len(txs))
+	if len(hashes) == 0 {
+		return ConfirmedFilter(states), nil
+	}
+
+	inc, err := api.GetLatestInclusion(hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range inc {
+		if i < len(hashes) {
+			states[hashes[i]] = inc[i]
+		}
+	}
+	return ConfirmedFilter(states), nil
+}
@@ -0,0 +1,95 @@
+package giota
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+var (
+	filterAddr1 Address = "PQTDJXXKSNYZGRJDXEHHMNCLUVOIRZC9VXYLSITYMVCQDQERAHAUZJKRNBQEUHOLEAXRUSQBNYVJWESYR"
+	filterAddr2 Address = "KTXFP9XOVMVWIXEWMOISJHMQEXMYMZCUGEQNKGUNVRPUDPRX9IR9LBASIARWNFXXESPITSLYAQMLCLVTL"
+)
+
+func filterTestBundle() Bundle {
+	var bs Bundle
+	bs.Add(1, filterAddr1, 50, time.Unix(1500000000, 0), "FOOBAR")
+	bs.Add(1, filterAddr2, -100, time.Unix(1500000100, 0), "FOOBAZ")
+	bs.Add(1, filterAddr1, 50, time.Unix(1500000200, 0), "QUX")
+	bs.Finalize(nil)
+	return bs
+}
+
+func TestFilter(t *testing.T) {
+	bs := filterTestBundle()
+
+	tests := []struct {
+		name    string
+		filter  Filter
+		indices []int64
+	}{
+		{name: "nil filter", filter: nil, indices: []int64{0, 1, 2}},
+		{name: "address", filter: AddressFilter(filterAddr1), indices: []int64{0, 2}},
+		{name: "tag prefix", filter: TagPrefixFilter("FOO"), indices: []int64{0, 1}},
+		{name: "value range", filter: ValueRangeFilter(0, Gi), indices: []int64{0, 2}},
+		{name: "timestamp", filter: TimestampFilter(time.Unix(1500000100, 0), time.Time{}), indices: []int64{1, 2}},
+		{name: "and", filter: AddressFilter(filterAddr1).And(TagPrefixFilter("FOO")), indices: []int64{0}},
+		{name: "or", filter: TagPrefixFilter("QUX").Or(ValueRangeFilter(-Gi, -1)), indices: []int64{1, 2}},
+		{name: "not", filter: AddressFilter(filterAddr1).Not(), indices: []int64{1}},
+	}
+
+	for _, tt := range tests {
+		out := tt.filter.Apply(bs)
+		if len(out) != len(tt.indices) {
+			t.Errorf("%s: matched %d transactions, expected %d", tt.name, len(out), len(tt.indices))
+			continue
+		}
+
+		for i := range out {
+			if out[i].CurrentIndex != tt.indices[i] {
+				t.Errorf("%s: matched transaction %d, expected %d", tt.name, out[i].CurrentIndex, tt.indices[i])
+			}
+		}
+	}
+}
+
+func TestFindTransactionObjectsConfirmedFilter(t *testing.T) {
+	bs := filterTestBundle()
+	hashes := make([]Hash, len(bs))
+	for i := range bs {
+		hashes[i] = bs[i].Hash()
+	}
+
+	api, done := newFakeNode(t, map[string]fakeNodeHandler{
+		"findTransactions": func(map[string]json.RawMessage) interface{} {
+			return &FindTransactionsResponse{Hashes: hashes}
+		},
+		"getTrytes": func(map[string]json.RawMessage) interface{} {
+			return &GetTrytesResponse{Trytes: bs}
+		},
+		"getNodeInfo": func(map[string]json.RawMessage) interface{} {
+			return &GetNodeInfoResponse{LatestMilestone: EmptyHash}
+		},
+		"getInclusionStates": func(map[string]json.RawMessage) interface{} {
+			return &GetInclusionStatesResponse{States: []bool{true, false, true}}
+		},
+	})
+	defer done()
+
+	txs, err := api.FindTransactionObjects(&FindTransactionsRequest{Bundles: []BundleHash{bs[0].Bundle}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != len(bs) {
+		t.Fatalf("FindTransactionObjects() returned %d transactions, expected %d", len(txs), len(bs))
+	}
+
+	f, err := api.ConfirmedFilter(txs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if out := f.Apply(txs); len(out) != 2 || out[0].CurrentIndex != 0 || out[1].CurrentIndex != 2 {
+		t.Errorf("ConfirmedFilter() matched %d transactions, expected 0 and 2", len(out))
+	}
+}
@@ -0,0 +1,108 @@
+//go:build go1.18
+// +build go1.18
+
+package giota
+
+import (
+	"strings"
+	"testing"
+)
+
+// The fuzz targets parse untrusted data of nodes. Their seed corpus is in
+// testdata/fuzz/<target>, inputs found by
+//
+//	go test -fuzz=FuzzNewTransaction
+//
+// are added there and are run by go test like the seeds.
+
+func FuzzNewTransaction(f *testing.F) {
+	f.Add(string(filterTestBundle()[0].Trytes()))
+	f.Add(strings.Repeat("9", TransactionTrinarySize/3))
+
+	f.Fuzz(func(t *testing.T, s string) {
+		tx, err := NewTransaction(Trytes(s))
+		if err != nil {
+			return
+		}
+
+		if tx.Trytes() != Trytes(s) {
+			t.Fatalf("transaction doesn't convert back to its trytes")
+		}
+		tx.Hash()
+		tx.AttachmentTime()
+	})
+}
+
+func FuzzTrytes(f *testing.F) {
+	f.Add("ABC9")
+	f.Add(string(filterAddr1))
+	f.Add(string(filterAddr1.WithChecksum()))
+
+	f.Fuzz(func(t *testing.T, s string) {
+		trs := Trytes(s)
+		trits, err := trs.ToTrits()
+		if (err == nil) != (trs.IsValid() == nil) {
+			t.Fatalf("ToTrits() and IsValid() disagree: %v", err)
+		}
+		if err != nil {
+			return
+		}
+
+		back, err := trits.ToTrytes()
+		if err != nil || back != trs {
+			t.Fatalf("trits don't convert back to the trytes: %v", err)
+		}
+
+		trs.ToNormalized()
+		trs.ToAddress()
+		ToAddress(s)
+	})
+}
+
+func FuzzGroupTransactionsIntoBundles(f *testing.F) {
+	f.Add([]byte{0, 1, 2})
+	f.Add([]byte{2, 1, 0, 0, 1, 2})
+	f.Add([]byte{0x10, 0x21, 0x32, 0x03})
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		// validating the signatures of long inputs takes too long
+		if len(b) > 32 {
+			b = b[:32]
+		}
+
+		bs := filterTestBundle()
+		hashes := []BundleHash{bs[0].Bundle, EmptyHash}
+		tips := []Hash{bs[0].Hash(), bs[1].Hash(), EmptyHash}
+
+		// each byte selects a transaction and mixes up its bundle, trunk
+		// and indices
+		var txs []Transaction
+		for _, c := range b {
+			tx := bs[int(c)%len(bs)]
+			tx.Bundle = hashes[int(c>>2)%len(hashes)]
+			tx.TrunkTransaction = Hash(tips[int(c>>3)%len(tips)])
+			tx.CurrentIndex -= int64(c>>5) % 2
+			tx.LastIndex += int64(c>>6) % 2
+			txs = append(txs, tx)
+		}
+
+		distinct := make(map[Hash]bool)
+		for i := range txs {
+			distinct[txs[i].Hash()] = true
+		}
+
+		n := 0
+		for _, g := range GroupTransactionsIntoBundles(txs) {
+			n += len(g)
+			for i := range g {
+				if g[i].Bundle != g[0].Bundle {
+					t.Fatalf("group mixes bundles %s and %s", g[0].Bundle, g[i].Bundle)
+				}
+			}
+			g.IsValid()
+		}
+		if n != len(distinct) {
+			t.Fatalf("groups have %d transactions, expected %d", n, len(distinct))
+		}
+	})
+}
@@ -0,0 +1,81 @@
+package grpcapi
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// Client calls the Giota service.
+type Client struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewClient returns a Client using cc.
+func NewClient(cc grpc.ClientConnInterface) *Client {
+	return &Client{cc: cc}
+}
+
+func (c *Client) invoke(ctx context.Context, method string, in, out message, opts []grpc.CallOption) error {
+	opts = append(opts, grpc.ForceCodec(codec{}))
+	return c.cc.Invoke(ctx, "/"+serviceName+"/"+method, in, out, opts...)
+}
+
+// GetBalance calls GetBalance.
+func (c *Client) GetBalance(ctx context.Context, in *GetBalanceRequest, opts ...grpc.CallOption) (*GetBalanceResponse, error) {
+	out := &GetBalanceResponse{}
+	if err := c.invoke(ctx, "GetBalance", in, out, opts); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PrepareTransfer calls PrepareTransfer.
+func (c *Client) PrepareTransfer(ctx context.Context, in *PrepareTransferRequest, opts ...grpc.CallOption) (*PrepareTransferResponse, error) {
+	out := &PrepareTransferResponse{}
+	if err := c.invoke(ctx, "PrepareTransfer", in, out, opts); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Send calls Send.
+func (c *Client) Send(ctx context.Context, in *SendRequest, opts ...grpc.CallOption) (*SendResponse, error) {
+	out := &SendResponse{}
+	if err := c.invoke(ctx, "Send", in, out, opts); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ConfirmationStream receives the states sent by MonitorConfirmation.
+type ConfirmationStream struct {
+	stream grpc.ClientStream
+}
+
+// Recv returns the next state. It returns io.EOF after the state of the
+// confirmed bundle.
+func (s *ConfirmationStream) Recv() (*ConfirmationStatus, error) {
+	st := &ConfirmationStatus{}
+	if err := s.stream.RecvMsg(st); err != nil {
+		return nil, err
+	}
+	return st, nil
+}
+
+// MonitorConfirmation calls MonitorConfirmation. Canceling ctx ends the
+// stream.
+func (c *Client) MonitorConfirmation(ctx context.Context, in *MonitorConfirmationRequest, opts ...grpc.CallOption) (*ConfirmationStream, error) {
+	opts = append(opts, grpc.ForceCodec(codec{}))
+	stream, err := c.cc.NewStream(ctx, &serviceDesc.Streams[0], "/"+serviceName+"/MonitorConfirmation", opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := stream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return &ConfirmationStream{stream: stream}, nil
+}
@@ -0,0 +1,34 @@
+package grpcapi
+
+import (
+	"fmt"
+
+	"google.golang.org/grpc/encoding"
+)
+
+// codec encodes the messages of the service in the protobuf wire format.
+// It is forced on servers and clients of this package, so that the global
+// "proto" codec of grpc isn't needed.
+type codec struct{}
+
+var _ encoding.Codec = codec{}
+
+func (codec) Marshal(v interface{}) ([]byte, error) {
+	m, ok := v.(message)
+	if !ok {
+		return nil, fmt.Errorf("grpcapi: can't marshal %T", v)
+	}
+	return m.marshal(), nil
+}
+
+func (codec) Unmarshal(b []byte, v interface{}) error {
+	m, ok := v.(message)
+	if !ok {
+		return fmt.Errorf("grpcapi: can't unmarshal %T", v)
+	}
+	return m.unmarshal(b)
+}
+
+func (codec) Name() string {
+	return "proto"
+}
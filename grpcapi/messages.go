@@ -0,0 +1,445 @@
+package grpcapi
+
+import (
+	"errors"
+
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/iotaledger/giota"
+)
+
+// The messages of giota.proto. They are encoded by hand with protowire to
+// avoid depending on generated code, and must be kept in sync with the
+// .proto file.
+
+var errWireType = errors.New("unexpected wire type of field")
+
+// message is implemented by all messages of the service.
+type message interface {
+	marshal() []byte
+	unmarshal(b []byte) error
+}
+
+// Transfer is a giota.Transfer.
+type Transfer struct {
+	Address giota.Address
+	Value   int64
+	Tag     giota.Trytes
+	Message giota.Trytes
+}
+
+func (m *Transfer) marshal() []byte {
+	var b []byte
+	b = appendString(b, 1, string(m.Address))
+	b = appendInt64(b, 2, m.Value)
+	b = appendString(b, 3, string(m.Tag))
+	b = appendString(b, 4, string(m.Message))
+	return b
+}
+
+func (m *Transfer) unmarshal(b []byte) error {
+	d := decoder{b: b}
+	for {
+		num, typ, ok := d.next()
+		if !ok {
+			return d.err
+		}
+		switch num {
+		case 1:
+			m.Address = giota.Address(d.string(typ))
+		case 2:
+			m.Value = d.int64(typ)
+		case 3:
+			m.Tag = giota.Trytes(d.string(typ))
+		case 4:
+			m.Message = giota.Trytes(d.string(typ))
+		default:
+			d.skip(num, typ)
+		}
+	}
+}
+
+// GetBalanceRequest is the request of GetBalance.
+type GetBalanceRequest struct {
+	Addresses []giota.Address
+}
+
+func (m *GetBalanceRequest) marshal() []byte {
+	var b []byte
+	for _, a := range m.Addresses {
+		b = appendElem(b, 1, string(a))
+	}
+	return b
+}
+
+func (m *GetBalanceRequest) unmarshal(b []byte) error {
+	d := decoder{b: b}
+	for {
+		num, typ, ok := d.next()
+		if !ok {
+			return d.err
+		}
+		switch num {
+		case 1:
+			m.Addresses = append(m.Addresses, giota.Address(d.string(typ)))
+		default:
+			d.skip(num, typ)
+		}
+	}
+}
+
+// GetBalanceResponse is the response of GetBalance.
+type GetBalanceResponse struct {
+	Balances []int64
+	Total    int64
+}
+
+func (m *GetBalanceResponse) marshal() []byte {
+	var b []byte
+	b = appendPackedInt64s(b, 1, m.Balances)
+	b = appendInt64(b, 2, m.Total)
+	return b
+}
+
+func (m *GetBalanceResponse) unmarshal(b []byte) error {
+	d := decoder{b: b}
+	for {
+		num, typ, ok := d.next()
+		if !ok {
+			return d.err
+		}
+		switch num {
+		case 1:
+			m.Balances = d.int64s(typ, m.Balances)
+		case 2:
+			m.Total = d.int64(typ)
+		default:
+			d.skip(num, typ)
+		}
+	}
+}
+
+// PrepareTransferRequest is the request of PrepareTransfer.
+type PrepareTransferRequest struct {
+	Transfers []Transfer
+	// Remainder receives the change of the inputs. A new address of the
+	// seed is used if it is empty.
+	Remainder giota.Address
+}
+
+func (m *PrepareTransferRequest) marshal() []byte {
+	return marshalTransfers(m.Transfers, m.Remainder)
+}
+
+func (m *PrepareTransferRequest) unmarshal(b []byte) error {
+	return unmarshalTransfers(b, &m.Transfers, &m.Remainder)
+}
+
+// PrepareTransferResponse is the response of PrepareTransfer.
+type PrepareTransferResponse struct {
+	Bundle giota.Trytes
+	// Trytes are the transactions in the order of their index.
+	Trytes []giota.Trytes
+}
+
+func (m *PrepareTransferResponse) marshal() []byte {
+	var b []byte
+	b = appendString(b, 1, string(m.Bundle))
+	for _, t := range m.Trytes {
+		b = appendElem(b, 2, string(t))
+	}
+	return b
+}
+
+func (m *PrepareTransferResponse) unmarshal(b []byte) error {
+	d := decoder{b: b}
+	for {
+		num, typ, ok := d.next()
+		if !ok {
+			return d.err
+		}
+		switch num {
+		case 1:
+			m.Bundle = giota.Trytes(d.string(typ))
+		case 2:
+			m.Trytes = append(m.Trytes, giota.Trytes(d.string(typ)))
+		default:
+			d.skip(num, typ)
+		}
+	}
+}
+
+// SendRequest is the request of Send.
+type SendRequest struct {
+	Transfers []Transfer
+	Remainder giota.Address
+}
+
+func (m *SendRequest) marshal() []byte {
+	return marshalTransfers(m.Transfers, m.Remainder)
+}
+
+func (m *SendRequest) unmarshal(b []byte) error {
+	return unmarshalTransfers(b, &m.Transfers, &m.Remainder)
+}
+
+// SendResponse is the response of Send.
+type SendResponse struct {
+	Bundle giota.Trytes
+	Tail   giota.Hash
+	Trytes []giota.Trytes
+}
+
+func (m *SendResponse) marshal() []byte {
+	var b []byte
+	b = appendString(b, 1, string(m.Bundle))
+	b = appendString(b, 2, string(m.Tail))
+	for _, t := range m.Trytes {
+		b = appendElem(b, 3, string(t))
+	}
+	return b
+}
+
+func (m *SendResponse) unmarshal(b []byte) error {
+	d := decoder{b: b}
+	for {
+		num, typ, ok := d.next()
+		if !ok {
+			return d.err
+		}
+		switch num {
+		case 1:
+			m.Bundle = giota.Trytes(d.string(typ))
+		case 2:
+			m.Tail = giota.Hash(d.string(typ))
+		case 3:
+			m.Trytes = append(m.Trytes, giota.Trytes(d.string(typ)))
+		default:
+			d.skip(num, typ)
+		}
+	}
+}
+
+// MonitorConfirmationRequest is the request of MonitorConfirmation.
+type MonitorConfirmationRequest struct {
+	Bundle     giota.Trytes
+	IntervalMs int64
+}
+
+func (m *MonitorConfirmationRequest) marshal() []byte {
+	var b []byte
+	b = appendString(b, 1, string(m.Bundle))
+	b = appendInt64(b, 2, m.IntervalMs)
+	return b
+}
+
+func (m *MonitorConfirmationRequest) unmarshal(b []byte) error {
+	d := decoder{b: b}
+	for {
+		num, typ, ok := d.next()
+		if !ok {
+			return d.err
+		}
+		switch num {
+		case 1:
+			m.Bundle = giota.Trytes(d.string(typ))
+		case 2:
+			m.IntervalMs = d.int64(typ)
+		default:
+			d.skip(num, typ)
+		}
+	}
+}
+
+// ConfirmationStatus is streamed by MonitorConfirmation.
+type ConfirmationStatus struct {
+	Bundle    giota.Trytes
+	Confirmed bool
+	// Tail is the confirmed tail transaction if Confirmed is set.
+	Tail giota.Hash
+}
+
+func (m *ConfirmationStatus) marshal() []byte {
+	var b []byte
+	b = appendString(b, 1, string(m.Bundle))
+	if m.Confirmed {
+		b = protowire.AppendTag(b, 2, protowire.VarintType)
+		b = protowire.AppendVarint(b, 1)
+	}
+	b = appendString(b, 3, string(m.Tail))
+	return b
+}
+
+func (m *ConfirmationStatus) unmarshal(b []byte) error {
+	d := decoder{b: b}
+	for {
+		num, typ, ok := d.next()
+		if !ok {
+			return d.err
+		}
+		switch num {
+		case 1:
+			m.Bundle = giota.Trytes(d.string(typ))
+		case 2:
+			m.Confirmed = d.int64(typ) != 0
+		case 3:
+			m.Tail = giota.Hash(d.string(typ))
+		default:
+			d.skip(num, typ)
+		}
+	}
+}
+
+func marshalTransfers(trs []Transfer, remainder giota.Address) []byte {
+	var b []byte
+	for i := range trs {
+		b = protowire.AppendTag(b, 1, protowire.BytesType)
+		b = protowire.AppendBytes(b, trs[i].marshal())
+	}
+	b = appendString(b, 2, string(remainder))
+	return b
+}
+
+func unmarshalTransfers(b []byte, trs *[]Transfer, remainder *giota.Address) error {
+	d := decoder{b: b}
+	for {
+		num, typ, ok := d.next()
+		if !ok {
+			return d.err
+		}
+		switch num {
+		case 1:
+			var tr Transfer
+			if err := tr.unmarshal(d.bytes(typ)); err != nil {
+				return err
+			}
+			*trs = append(*trs, tr)
+		case 2:
+			*remainder = giota.Address(d.string(typ))
+		default:
+			d.skip(num, typ)
+		}
+	}
+}
+
+// appendString appends a string field unless it has the default value.
+func appendString(b []byte, num protowire.Number, s string) []byte {
+	if s == "" {
+		return b
+	}
+	return appendElem(b, num, s)
+}
+
+// appendElem appends an element of a repeated string field.
+func appendElem(b []byte, num protowire.Number, s string) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, s)
+}
+
+// appendInt64 appends an int64 field unless it has the default value.
+func appendInt64(b []byte, num protowire.Number, v int64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, uint64(v))
+}
+
+func appendPackedInt64s(b []byte, num protowire.Number, vs []int64) []byte {
+	if len(vs) == 0 {
+		return b
+	}
+	var p []byte
+	for _, v := range vs {
+		p = protowire.AppendVarint(p, uint64(v))
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, p)
+}
+
+// decoder reads the fields of a message. After the first error, it returns
+// zero values and next returns false.
+type decoder struct {
+	b   []byte
+	err error
+}
+
+func (d *decoder) fail(n int) bool {
+	if n < 0 {
+		d.err = protowire.ParseError(n)
+		d.b = nil
+		return true
+	}
+	d.b = d.b[n:]
+	return false
+}
+
+func (d *decoder) wrongType() {
+	if d.err == nil {
+		d.err = errWireType
+	}
+	d.b = nil
+}
+
+// next returns the number and wire type of the next field.
+func (d *decoder) next() (protowire.Number, protowire.Type, bool) {
+	if d.err != nil || len(d.b) == 0 {
+		return 0, 0, false
+	}
+	num, typ, n := protowire.ConsumeTag(d.b)
+	if d.fail(n) {
+		return 0, 0, false
+	}
+	return num, typ, true
+}
+
+func (d *decoder) skip(num protowire.Number, typ protowire.Type) {
+	d.fail(protowire.ConsumeFieldValue(num, typ, d.b))
+}
+
+func (d *decoder) bytes(typ protowire.Type) []byte {
+	if typ != protowire.BytesType {
+		d.wrongType()
+		return nil
+	}
+	v, n := protowire.ConsumeBytes(d.b)
+	if d.fail(n) {
+		return nil
+	}
+	return v
+}
+
+func (d *decoder) string(typ protowire.Type) string {
+	return string(d.bytes(typ))
+}
+
+func (d *decoder) int64(typ protowire.Type) int64 {
+	if typ != protowire.VarintType {
+		d.wrongType()
+		return 0
+	}
+	v, n := protowire.ConsumeVarint(d.b)
+	if d.fail(n) {
+		return 0
+	}
+	return int64(v)
+}
+
+// int64s appends a packed or unpacked repeated int64 field to vs.
+func (d *decoder) int64s(typ protowire.Type, vs []int64) []int64 {
+	if typ != protowire.BytesType {
+		return append(vs, d.int64(typ))
+	}
+
+	p := d.bytes(typ)
+	for len(p) > 0 {
+		v, n := protowire.ConsumeVarint(p)
+		if n < 0 {
+			d.fail(n)
+			return vs
+		}
+		vs = append(vs, int64(v))
+		p = p[n:]
+	}
+	return vs
+}
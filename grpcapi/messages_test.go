@@ -0,0 +1,67 @@
+package grpcapi
+
+import (
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/iotaledger/giota"
+)
+
+func TestMessages(t *testing.T) {
+	tests := []struct {
+		in  message
+		out message
+	}{
+		{
+			in:  &SendRequest{Transfers: []Transfer{{Address: testAddress, Value: -1, Tag: "TAG"}, {Message: "MSG"}}, Remainder: testAddress},
+			out: &SendRequest{},
+		},
+		{
+			in:  &GetBalanceResponse{Balances: []int64{0, 42, 1 << 40}, Total: 1<<40 + 42},
+			out: &GetBalanceResponse{},
+		},
+		{
+			in:  &ConfirmationStatus{Bundle: "BUNDLE", Confirmed: true, Tail: "TAIL"},
+			out: &ConfirmationStatus{},
+		},
+		{
+			in:  &PrepareTransferResponse{Trytes: []giota.Trytes{"", "A"}},
+			out: &PrepareTransferResponse{},
+		},
+	}
+
+	for _, tt := range tests {
+		if err := tt.out.unmarshal(tt.in.marshal()); err != nil {
+			t.Errorf("%T: %s", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(tt.in, tt.out) {
+			t.Errorf("%T: %+v, expected %+v", tt.in, tt.out, tt.in)
+		}
+	}
+}
+
+func TestUnmarshalUnpacked(t *testing.T) {
+	var b []byte
+	for _, v := range []int64{1, 2} {
+		b = protowire.AppendTag(b, 1, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(v))
+	}
+	// unknown fields are skipped
+	b = protowire.AppendTag(b, 9, protowire.BytesType)
+	b = protowire.AppendString(b, "unknown")
+
+	m := &GetBalanceResponse{}
+	if err := m.unmarshal(b); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(m.Balances, []int64{1, 2}) {
+		t.Errorf("balances %v", m.Balances)
+	}
+
+	if err := m.unmarshal(b[:len(b)-1]); err == nil {
+		t.Error("unmarshal() accepted a truncated message")
+	}
+}
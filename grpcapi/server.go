@@ -0,0 +1,264 @@
+// Package grpcapi provides the gRPC service of giota.proto, which lets
+// applications in other languages use giota as a sidecar daemon signing and
+// sending transfers with a seed that never leaves the daemon.
+//
+// The messages are encoded without generated code, so clients of this
+// package must use NewClient and servers must be created by NewServer.
+// Clients in other languages generate their stubs from giota.proto.
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotaledger/giota"
+)
+
+// DefaultMonitorInterval is the polling interval of MonitorConfirmation if
+// the request doesn't set one.
+const DefaultMonitorInterval = 10 * time.Second
+
+// MinMonitorInterval is the minimum polling interval of MonitorConfirmation.
+var MinMonitorInterval = time.Second
+
+// Server implements the Giota service with the seed of the daemon.
+type Server struct {
+	API      *giota.API
+	Seed     giota.Trytes
+	Security giota.SecurityLevel
+	Depth    int64
+	MWM      int64
+	// Pow does the PoW locally. If nil, the attachToTangle API of the node
+	// is called.
+	Pow giota.PowFunc
+}
+
+// NewServer returns a gRPC server serving srv.
+func NewServer(srv *Server, opts ...grpc.ServerOption) *grpc.Server {
+	s := grpc.NewServer(append(opts, grpc.ForceServerCodec(codec{}))...)
+	s.RegisterService(&serviceDesc, srv)
+	return s
+}
+
+// GetBalance returns the confirmed balances of the addresses of req.
+func (s *Server) GetBalance(ctx context.Context, req *GetBalanceRequest) (*GetBalanceResponse, error) {
+	adrs := make([]giota.Address, len(req.Addresses))
+	for i, a := range req.Addresses {
+		adr, err := giota.ToAddress(string(a))
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "address %d: %s", i, err)
+		}
+		adrs[i] = adr
+	}
+
+	r, err := s.API.GetBalances(adrs, 100)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &GetBalanceResponse{Balances: r.Balances}
+	for _, b := range r.Balances {
+		resp.Total += b
+	}
+	return resp, nil
+}
+
+// PrepareTransfer builds and signs the bundle of the transfers of req.
+func (s *Server) PrepareTransfer(ctx context.Context, req *PrepareTransferRequest) (*PrepareTransferResponse, error) {
+	bd, err := s.prepare(req.Transfers, req.Remainder)
+	if err != nil {
+		return nil, err
+	}
+	return &PrepareTransferResponse{
+		Bundle: giota.Trytes(bd[0].Bundle),
+		Trytes: trytesOf(bd),
+	}, nil
+}
+
+// Send prepares the bundle of the transfers of req, attaches it and
+// broadcasts it.
+func (s *Server) Send(ctx context.Context, req *SendRequest) (*SendResponse, error) {
+	bd, err := s.prepare(req.Transfers, req.Remainder)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+
+	res, err := giota.SendTrytes(s.API, s.Depth, []giota.Transaction(bd), s.MWM, s.Pow)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &SendResponse{
+		Bundle: giota.Trytes(bd[0].Bundle),
+		Tail:   res.Tail,
+		Trytes: trytesOf(res.Transactions),
+	}, nil
+}
+
+// MonitorConfirmation polls the confirmation state of the bundle of req and
+// sends it until one of its attachments is confirmed or ctx is done.
+func (s *Server) MonitorConfirmation(ctx context.Context, req *MonitorConfirmationRequest, send func(*ConfirmationStatus) error) error {
+	if err := giota.IsHash(req.Bundle); err != nil {
+		return status.Errorf(codes.InvalidArgument, "bundle: %s", err)
+	}
+
+	interval := time.Duration(req.IntervalMs) * time.Millisecond
+	switch {
+	case req.IntervalMs <= 0:
+		interval = DefaultMonitorInterval
+	case interval < MinMonitorInterval:
+		interval = MinMonitorInterval
+	}
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		tail, confirmed, err := s.API.IsBundleConfirmed(giota.BundleHash(req.Bundle))
+		if err != nil {
+			return toStatus(err)
+		}
+
+		st := &ConfirmationStatus{Bundle: req.Bundle, Confirmed: confirmed}
+		if confirmed {
+			st.Tail = tail
+		}
+		if err := send(st); err != nil {
+			return err
+		}
+		if confirmed {
+			return nil
+		}
+
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		}
+	}
+}
+
+func (s *Server) prepare(trs []Transfer, remainder giota.Address) (giota.Bundle, error) {
+	if len(trs) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no transfers")
+	}
+
+	gtrs := make([]giota.Transfer, len(trs))
+	for i, tr := range trs {
+		adr, err := giota.ToAddress(string(tr.Address))
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "transfer %d: %s", i, err)
+		}
+		gtrs[i] = giota.Transfer{
+			Address: adr,
+			Value:   tr.Value,
+			Tag:     tr.Tag,
+			Message: tr.Message,
+		}
+	}
+
+	if remainder != "" {
+		adr, err := giota.ToAddress(string(remainder))
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "remainder: %s", err)
+		}
+		remainder = adr
+	}
+
+	bd, err := giota.PrepareTransfers(s.API, s.Seed, gtrs, nil, remainder, s.Security)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return bd, nil
+}
+
+func trytesOf(txs []giota.Transaction) []giota.Trytes {
+	ts := make([]giota.Trytes, len(txs))
+	for i := range txs {
+		ts[i] = txs[i].Trytes()
+	}
+	return ts
+}
+
+// toStatus converts errors of giota to gRPC status errors.
+func toStatus(err error) error {
+	var verr *giota.ValidationError
+	if errors.As(err, &verr) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return status.Error(codes.Unknown, err.Error())
+}
+
+// service is the handler type of the service.
+type service interface {
+	GetBalance(context.Context, *GetBalanceRequest) (*GetBalanceResponse, error)
+	PrepareTransfer(context.Context, *PrepareTransferRequest) (*PrepareTransferResponse, error)
+	Send(context.Context, *SendRequest) (*SendResponse, error)
+	MonitorConfirmation(context.Context, *MonitorConfirmationRequest, func(*ConfirmationStatus) error) error
+}
+
+const serviceName = "giota.Giota"
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: serviceName,
+	HandlerType: (*service)(nil),
+	Methods: []grpc.MethodDesc{
+		unary("GetBalance", func() message { return &GetBalanceRequest{} },
+			func(s service, ctx context.Context, req message) (interface{}, error) {
+				return s.GetBalance(ctx, req.(*GetBalanceRequest))
+			}),
+		unary("PrepareTransfer", func() message { return &PrepareTransferRequest{} },
+			func(s service, ctx context.Context, req message) (interface{}, error) {
+				return s.PrepareTransfer(ctx, req.(*PrepareTransferRequest))
+			}),
+		unary("Send", func() message { return &SendRequest{} },
+			func(s service, ctx context.Context, req message) (interface{}, error) {
+				return s.Send(ctx, req.(*SendRequest))
+			}),
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "MonitorConfirmation",
+			ServerStreams: true,
+			Handler: func(srv interface{}, stream grpc.ServerStream) error {
+				req := &MonitorConfirmationRequest{}
+				if err := stream.RecvMsg(req); err != nil {
+					return err
+				}
+				return srv.(service).MonitorConfirmation(stream.Context(), req, func(st *ConfirmationStatus) error {
+					return stream.SendMsg(st)
+				})
+			},
+		},
+	},
+	Metadata: "giota.proto",
+}
+
+// unary returns the description of a unary method calling call with the
+// request made by newReq.
+func unary(name string, newReq func() message, call func(service, context.Context, message) (interface{}, error)) grpc.MethodDesc {
+	return grpc.MethodDesc{
+		MethodName: name,
+		Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, ic grpc.UnaryServerInterceptor) (interface{}, error) {
+			req := newReq()
+			if err := dec(req); err != nil {
+				return nil, err
+			}
+			if ic == nil {
+				return call(srv.(service), ctx, req)
+			}
+
+			info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + serviceName + "/" + name}
+			return ic(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				return call(srv.(service), ctx, req.(message))
+			})
+		},
+	}
+}
@@ -0,0 +1,178 @@
+package grpcapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotaledger/giota"
+)
+
+const (
+	testSeed    giota.Trytes  = "WQNZOHUT99PWKEBFSKQSYNC9XHT9GEBMOSJAQDQAXPEZPJNDIUB9TSNWVMHKWICW9WVZXSMDFGISOD9FZ"
+	testAddress giota.Address = "PQZKJKZAHOPZLXRZSPKFHZDLITQTQYDCHSYJYECIFGIISVJXKDGTSSMZE9XAXVUVQWIYLLJLKQDJBMMTZ"
+)
+
+// newFakeNode returns an API of a node which stores broadcasted
+// transactions and confirms them at the second inclusion check.
+func newFakeNode(t *testing.T) (*giota.API, func()) {
+	var (
+		mu     sync.Mutex
+		txs    = map[giota.Hash]giota.Transaction{}
+		checks int
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Command   string
+			Trytes    []giota.Transaction
+			Hashes    []giota.Hash
+			Addresses []giota.Address
+			Tx        []giota.Hash `json:"transactions"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("fake node could not decode request: %s", err)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		var resp interface{} = struct{}{}
+		switch req.Command {
+		case "getBalances":
+			bals := make([]string, len(req.Addresses))
+			for i := range bals {
+				bals[i] = "21"
+			}
+			resp = map[string]interface{}{"balances": bals}
+		case "getTransactionsToApprove":
+			resp = &giota.GetTransactionsToApproveResponse{TrunkTransaction: giota.EmptyHash, BranchTransaction: giota.EmptyHash}
+		case "broadcastTransactions", "storeTransactions":
+			for _, tx := range req.Trytes {
+				txs[tx.Hash()] = tx
+			}
+		case "findTransactions":
+			hashes := []giota.Hash{}
+			for h := range txs {
+				hashes = append(hashes, h)
+			}
+			resp = &giota.FindTransactionsResponse{Hashes: hashes}
+		case "getTrytes":
+			found := []giota.Transaction{}
+			for _, h := range req.Hashes {
+				found = append(found, txs[h])
+			}
+			resp = &giota.GetTrytesResponse{Trytes: found}
+		case "getNodeInfo":
+			resp = &giota.GetNodeInfoResponse{LatestMilestone: giota.EmptyHash}
+		case "getInclusionStates":
+			checks++
+			states := make([]bool, len(req.Tx))
+			for i := range states {
+				states[i] = checks > 1
+			}
+			resp = &giota.GetInclusionStatesResponse{States: states}
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	return giota.NewAPI(srv.URL, nil), srv.Close
+}
+
+func newTestClient(t *testing.T, api *giota.API) (*Client, func()) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewServer(&Server{
+		API:      api,
+		Seed:     testSeed,
+		Security: giota.SecurityLevelMedium,
+		Depth:    3,
+		MWM:      1,
+		Pow:      giota.PowGo,
+	})
+	go s.Serve(lis)
+
+	cc, err := grpc.NewClient("passthrough:///"+lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewClient(cc), func() {
+		cc.Close()
+		s.Stop()
+	}
+}
+
+func TestServer(t *testing.T) {
+	api, done := newFakeNode(t)
+	defer done()
+	c, stop := newTestClient(t, api)
+	defer stop()
+
+	defer func(d time.Duration) { MinMonitorInterval = d }(MinMonitorInterval)
+	MinMonitorInterval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	bal, err := c.GetBalance(ctx, &GetBalanceRequest{Addresses: []giota.Address{testAddress, testAddress}})
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case len(bal.Balances) != 2 || bal.Total != 42:
+		t.Errorf("GetBalance() returned %+v", bal)
+	}
+
+	_, err = c.GetBalance(ctx, &GetBalanceRequest{Addresses: []giota.Address{"ABC"}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("GetBalance() returned %v for an invalid address", err)
+	}
+
+	trs := []Transfer{{Address: testAddress, Tag: "GRPC", Message: giota.EncodeMessage([]byte("hi"))}}
+	prep, err := c.PrepareTransfer(ctx, &PrepareTransferRequest{Transfers: trs})
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case len(prep.Trytes) != 1 || giota.IsHash(prep.Bundle) != nil:
+		t.Errorf("PrepareTransfer() returned %+v", prep)
+	}
+
+	sent, err := c.Send(ctx, &SendRequest{Transfers: trs})
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case sent.Bundle != prep.Bundle || len(sent.Trytes) != 1 || sent.Tail.IsValid() != nil:
+		t.Errorf("Send() returned %+v", sent)
+	}
+
+	stream, err := c.MonitorConfirmation(ctx, &MonitorConfirmationRequest{Bundle: sent.Bundle, IntervalMs: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var states []*ConfirmationStatus
+	for {
+		st, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		states = append(states, st)
+	}
+	if len(states) != 2 || states[0].Confirmed || !states[1].Confirmed || states[1].Tail != sent.Tail {
+		t.Errorf("MonitorConfirmation() sent %+v", states)
+	}
+}
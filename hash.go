@@ -0,0 +1,108 @@
+package giota
+
+import (
+	"errors"
+	"strings"
+)
+
+// Hash is the hash of a transaction. It is distinct from Trytes, Address and
+// BundleHash, so that the compiler catches mix-ups; use ToHash to convert
+// and validate.
+type Hash Trytes
+
+// BundleHash is the hash of a bundle, shared by all its transactions. Use
+// ToBundleHash to convert and validate.
+type BundleHash Trytes
+
+// Tag is the tag of a transaction of 27 trytes. Use ToTag to convert, pad
+// and validate.
+type Tag Trytes
+
+// ErrInvalidTag is returned for tags longer than 27 trytes.
+var ErrInvalidTag = errors.New("tags are up to 27 trytes in length")
+
+// ToHash converts t to a Hash and checks its validity.
+func ToHash(t Trytes) (Hash, error) {
+	h := Hash(t)
+	return h, h.IsValid()
+}
+
+// ToHashes converts ts to Hashes and checks their validity. It helps callers
+// holding hashes as Trytes, which the API took before it had the Hash type.
+func ToHashes(ts []Trytes) ([]Hash, error) {
+	hs := make([]Hash, len(ts))
+	for i, t := range ts {
+		h, err := ToHash(t)
+		if err != nil {
+			return nil, err
+		}
+		hs[i] = h
+	}
+	return hs, nil
+}
+
+// IsValid returns nil if h is 81 trytes.
+func (h Hash) IsValid() error {
+	return IsHash(Trytes(h))
+}
+
+// Trytes returns h as Trytes.
+func (h Hash) Trytes() Trytes {
+	return Trytes(h)
+}
+
+// Trits returns h as Trits.
+func (h Hash) Trits() Trits {
+	return Trytes(h).Trits()
+}
+
+// ToBundleHash converts t to a BundleHash and checks its validity.
+func ToBundleHash(t Trytes) (BundleHash, error) {
+	h := BundleHash(t)
+	return h, h.IsValid()
+}
+
+// IsValid returns nil if h is 81 trytes.
+func (h BundleHash) IsValid() error {
+	return IsHash(Trytes(h))
+}
+
+// Trytes returns h as Trytes.
+func (h BundleHash) Trytes() Trytes {
+	return Trytes(h)
+}
+
+// Trits returns h as Trits.
+func (h BundleHash) Trits() Trits {
+	return Trytes(h).Trits()
+}
+
+// Normalize returns the normalized h, see Trytes.Normalize.
+func (h BundleHash) Normalize() []int8 {
+	return Trytes(h).Normalize()
+}
+
+// ToTag converts t to a Tag, padding it with 9s to 27 trytes, and checks
+// its validity.
+func ToTag(t Trytes) (Tag, error) {
+	if len(t) > TagTrinarySize/3 {
+		return "", ErrInvalidTag
+	}
+	tag := Tag(t) + Tag(strings.Repeat("9", TagTrinarySize/3-len(t)))
+	return tag, tag.IsValid()
+}
+
+// IsValid returns nil if tag is 27 trytes.
+func (tag Tag) IsValid() error {
+	return isTrytesOfLength(Trytes(tag), TagTrinarySize/3)
+}
+
+// Trytes returns tag as Trytes.
+func (tag Tag) Trytes() Trytes {
+	return Trytes(tag)
+}
+
+// Trits returns tag as Trits.
+func (tag Tag) Trits() Trits {
+	return Trytes(tag).Trits()
+}
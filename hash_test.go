@@ -0,0 +1,71 @@
+package giota
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestToHash(t *testing.T) {
+	tests := []struct {
+		name  string
+		in    Trytes
+		valid bool
+	}{
+		{name: "empty hash", in: EmptyHash, valid: true},
+		{name: "short", in: Trytes(EmptyHash[1:]), valid: false},
+		{name: "invalid tryte", in: Trytes("a" + EmptyHash[1:]), valid: false},
+	}
+
+	for _, tt := range tests {
+		h, err := ToHash(tt.in)
+		if (err == nil) != tt.valid {
+			t.Errorf("%s: ToHash() returned %v", tt.name, err)
+		}
+		if h.Trytes() != tt.in {
+			t.Errorf("%s: ToHash() returned %s", tt.name, h)
+		}
+
+		hs, err := ToHashes([]Trytes{EmptyHash, tt.in})
+		switch {
+		case (err == nil) != tt.valid:
+			t.Errorf("%s: ToHashes() returned %v", tt.name, err)
+		case tt.valid && (len(hs) != 2 || hs[1].Trytes() != tt.in):
+			t.Errorf("%s: ToHashes() returned %v", tt.name, hs)
+		}
+
+		bh, err := ToBundleHash(tt.in)
+		if (err == nil) != tt.valid {
+			t.Errorf("%s: ToBundleHash() returned %v", tt.name, err)
+		}
+		if bh.Trytes() != tt.in {
+			t.Errorf("%s: ToBundleHash() returned %s", tt.name, bh)
+		}
+	}
+}
+
+func TestToTag(t *testing.T) {
+	tests := []struct {
+		name  string
+		in    Trytes
+		tag   Tag
+		valid bool
+	}{
+		{name: "padded", in: "PROMOTESPAM", tag: "PROMOTESPAM9999999999999999", valid: true},
+		{name: "empty", in: "", tag: Tag(strings.Repeat("9", 27)), valid: true},
+		{name: "full", in: Trytes(strings.Repeat("A", 27)), tag: Tag(strings.Repeat("A", 27)), valid: true},
+		{name: "too long", in: Trytes(strings.Repeat("A", 28)), valid: false},
+		{name: "invalid tryte", in: "abc", valid: false},
+	}
+
+	for _, tt := range tests {
+		tag, err := ToTag(tt.in)
+		switch {
+		case (err == nil) != tt.valid:
+			t.Errorf("%s: ToTag() returned %v", tt.name, err)
+		case tt.valid && tag != tt.tag:
+			t.Errorf("%s: ToTag() returned %s, expected %s", tt.name, tag, tt.tag)
+		case tt.valid && len(tag.Trits()) != TagTrinarySize:
+			t.Errorf("%s: Trits() returned %d trits", tt.name, len(tag.Trits()))
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package giota
+
+// SetPermanode sets a secondary node which keeps the transactions the node
+// of api dropped at snapshots, e.g. a permanode. FindHistory and the account
+// functions merge its transactions with the ones of api. A nil p disables
+// it. SetPermanode must not be called concurrently with API calls.
+func (api *API) SetPermanode(p *API) {
+	api.permanode = p
+}
+
+// Permanode returns the node set by SetPermanode, or nil.
+func (api *API) Permanode() *API {
+	return api.permanode
+}
+
+// FindHistory is like FindTransactionObjects, but adds the transactions of
+// the permanode, if any. Transactions found on both nodes are returned once.
+func (api *API) FindHistory(ft *FindTransactionsRequest) ([]Transaction, error) {
+	txs, err := api.FindTransactionObjects(ft)
+	if err != nil || api.permanode == nil {
+		return txs, err
+	}
+
+	old, err := api.permanode.FindTransactionObjects(ft)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[Hash]bool, len(txs))
+	for i := range txs {
+		seen[txs[i].Hash()] = true
+	}
+	for i := range old {
+		if h := old[i].Hash(); !seen[h] {
+			seen[h] = true
+			txs = append(txs, old[i])
+		}
+	}
+	return txs, nil
+}
+
+// GetBundlesFromAddresses returns the bundles with transactions of adrs,
+// including the ones of the permanode. Bundles found on both nodes are
+// returned once.
+func (api *API) GetBundlesFromAddresses(adrs []Address) (Bundles, error) {
+	txs, err := api.FindHistory(&FindTransactionsRequest{Addresses: adrs})
+	if err != nil {
+		return nil, err
+	}
+
+	var bundles []BundleHash
+	seen := make(map[BundleHash]bool)
+	for _, tx := range txs {
+		if !seen[tx.Bundle] {
+			seen[tx.Bundle] = true
+			bundles = append(bundles, tx.Bundle)
+		}
+	}
+	if len(bundles) == 0 {
+		return nil, nil
+	}
+
+	txs, err = api.FindHistory(&FindTransactionsRequest{Bundles: bundles})
+	if err != nil {
+		return nil, err
+	}
+	return GroupTransactionsIntoBundles(txs), nil
+}
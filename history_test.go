@@ -0,0 +1,44 @@
+package giota
+
+import (
+	"testing"
+)
+
+func TestGetBundlesFromAddresses(t *testing.T) {
+	bs := filterTestBundle()
+
+	tests := []struct {
+		name      string
+		primary   []Transaction
+		permanode []Transaction
+		// size is the number of transactions of the found bundle, or 0
+		size int
+	}{
+		{name: "pruned without permanode", primary: bs[:1]},
+		{name: "complete without permanode", primary: bs, size: 3},
+		{name: "merged", primary: bs[:2], permanode: bs, size: 3},
+		{name: "only permanode", permanode: bs, size: 3},
+	}
+
+	for _, tt := range tests {
+		api, done := newFakeTangle(t, append([]Transaction(nil), tt.primary...))
+		if tt.permanode != nil {
+			p, pdone := newFakeTangle(t, append([]Transaction(nil), tt.permanode...))
+			defer pdone()
+			api.SetPermanode(p)
+		}
+
+		found, err := api.GetBundlesFromAddresses([]Address{filterAddr2})
+		done()
+
+		switch {
+		case err != nil:
+			t.Errorf("%s: %s", tt.name, err)
+		case tt.size == 0 && len(found) != 0:
+			t.Errorf("%s: found %d bundles, expected none", tt.name, len(found))
+		case tt.size == 0:
+		case len(found) != 1 || len(found[0]) != tt.size || found[0][2].CurrentIndex != 2:
+			t.Errorf("%s: found %d bundles", tt.name, len(found))
+		}
+	}
+}
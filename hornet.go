@@ -0,0 +1,109 @@
+package giota
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// NodeKind is the node software serving an endpoint.
+type NodeKind int
+
+// Kinds of nodes.
+const (
+	// NodeIRI is IRI, or a node of unknown software serving its command
+	// API.
+	NodeIRI NodeKind = iota
+	// NodeHornet is a Hornet node of the legacy network. Hornet serves the
+	// command API of IRI, so it is used like IRI.
+	NodeHornet
+)
+
+func (k NodeKind) String() string {
+	switch k {
+	case NodeIRI:
+		return "IRI"
+	case NodeHornet:
+		return "Hornet"
+	}
+	return "unknown"
+}
+
+// ErrChrysalisNode is returned by DetectNode for nodes of the Chrysalis
+// network, like Hornet 1.x and Bee. They replaced the IRI command API and the
+// transactions of the legacy network by a REST API of UTXO messages, which
+// giota doesn't support.
+var ErrChrysalisNode = errors.New("node serves the Chrysalis API, which giota doesn't support")
+
+// chrysalisInfoPath is the node info route of the REST API of Chrysalis
+// nodes.
+const chrysalisInfoPath = "/api/v1/info"
+
+// NewAPIAuto returns an API like NewAPI after checking with DetectNode that
+// the node at endpoint is usable.
+func NewAPIAuto(endpoint string, c *http.Client) (*API, error) {
+	api := NewAPI(endpoint, c)
+	if _, err := api.DetectNode(); err != nil {
+		return nil, err
+	}
+	return api, nil
+}
+
+// DetectNode calls getNodeInfo and returns the kind of the node by its
+// appName, which Node returns afterwards. If the node doesn't serve the IRI
+// command API, it probes the REST API of Chrysalis nodes, and returns
+// ErrChrysalisNode if the node serves it. DetectNode must not be called
+// concurrently with API calls.
+func (api *API) DetectNode() (NodeKind, error) {
+	info, err := api.GetNodeInfo()
+	if err != nil {
+		if api.isChrysalisNode() {
+			return NodeIRI, ErrChrysalisNode
+		}
+		return NodeIRI, err
+	}
+
+	api.node = NodeIRI
+	if strings.EqualFold(info.AppName, "HORNET") {
+		api.node = NodeHornet
+	}
+	return api.node, nil
+}
+
+// isChrysalisNode reports whether the node answers the info route of the
+// Chrysalis REST API with the network fields only Chrysalis nodes have.
+func (api *API) isChrysalisNode() bool {
+	req, err := api.newRequest(http.MethodGet, strings.TrimRight(api.endpoint, "/")+chrysalisInfoPath, nil)
+	if err != nil {
+		return false
+	}
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+
+	var info struct {
+		Data *struct {
+			Name      string `json:"name"`
+			NetworkID string `json:"networkId"`
+			Bech32HRP string `json:"bech32HRP"`
+		} `json:"data"`
+	}
+	if resp.StatusCode != http.StatusOK || json.NewDecoder(api.limitResponse(resp.Body)).Decode(&info) != nil {
+		return false
+	}
+	return info.Data != nil && info.Data.NetworkID != "" && info.Data.Bech32HRP != ""
+}
+
+// SetNode sets the kind of the node without probing it. It must not be
+// called concurrently with API calls.
+func (api *API) SetNode(k NodeKind) {
+	api.node = k
+}
+
+// Node returns the kind of the node.
+func (api *API) Node() NodeKind {
+	return api.node
+}
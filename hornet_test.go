@@ -0,0 +1,94 @@
+package giota
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// chrysalisInfo is a response of /api/v1/info of a Chrysalis node, in the
+// format of the REST API specification of Chrysalis.
+const chrysalisInfo = `{"data":{"name":"HORNET","version":"1.0.0","isHealthy":true,` +
+	`"networkId":"chrysalis-mainnet","bech32HRP":"iota","minPoWScore":4000,` +
+	`"messagesPerSecond":12.1,"referencedMessagesPerSecond":11.9,"referencedRate":98.3,` +
+	`"latestMilestoneTimestamp":1619000000,"latestMilestoneIndex":1000,` +
+	`"confirmedMilestoneIndex":1000,"pruningIndex":0,"features":["PoW"]}}`
+
+// newFakeNodeKind returns a server answering getNodeInfo with appName at its
+// root, or only info at the Chrysalis info route if appName is empty.
+func newFakeNodeKind(appName, info string) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc(chrysalisInfoPath, func(w http.ResponseWriter, r *http.Request) {
+		if info == "" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(info))
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if appName == "" || r.Method != http.MethodPost {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(&GetNodeInfoResponse{AppName: appName})
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestDetectNode(t *testing.T) {
+	tests := []struct {
+		name    string
+		appName string
+		info    string
+		kind    NodeKind
+		err     error
+		fail    bool
+	}{
+		{name: "IRI", appName: "IRI", kind: NodeIRI},
+		{name: "legacy Hornet", appName: "HORNET", kind: NodeHornet},
+		{name: "Chrysalis node", info: chrysalisInfo, err: ErrChrysalisNode},
+		{name: "data wrapper only", info: `{"data":{"name":"HORNET"}}`, fail: true},
+		{name: "no node", fail: true},
+	}
+
+	for _, tt := range tests {
+		srv := newFakeNodeKind(tt.appName, tt.info)
+		api := NewAPI(srv.URL+"/", nil)
+		api.SetNode(NodeHornet)
+
+		k, err := api.DetectNode()
+		switch {
+		case tt.fail && (err == nil || errors.Is(err, ErrChrysalisNode)):
+			t.Errorf("%s: DetectNode() returned %v, expected the error of getNodeInfo", tt.name, err)
+		case tt.fail:
+		case tt.err == nil && err != nil:
+			t.Errorf("%s: %s", tt.name, err)
+		case tt.err != nil && err != tt.err:
+			t.Errorf("%s: DetectNode() returned %v, expected %v", tt.name, err, tt.err)
+		case tt.err == nil && (k != tt.kind || api.Node() != tt.kind):
+			t.Errorf("%s: node is %s, expected %s", tt.name, api.Node(), tt.kind)
+		}
+		srv.Close()
+	}
+}
+
+func TestNewAPIAuto(t *testing.T) {
+	srv := newFakeNodeKind("HORNET", "")
+	defer srv.Close()
+
+	api, err := NewAPIAuto(srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if api.Node() != NodeHornet {
+		t.Errorf("node is %s, expected %s", api.Node(), NodeHornet)
+	}
+
+	srv = newFakeNodeKind("", chrysalisInfo)
+	defer srv.Close()
+	if _, err := NewAPIAuto(srv.URL, nil); err != ErrChrysalisNode {
+		t.Errorf("NewAPIAuto() returned %v, expected ErrChrysalisNode", err)
+	}
+}
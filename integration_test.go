@@ -0,0 +1,71 @@
+package giota
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iotaledger/giota/testutil"
+)
+
+func TestIntegrationValueTransfer(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping in short mode")
+	}
+
+	tangle, err := testutil.Start(5 * time.Minute)
+	if err == testutil.ErrNoTangle {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tangle.Stop()
+
+	if tangle.Seed == "" {
+		t.Skip("no funded seed, set " + testutil.EnvSeed)
+	}
+	seed, err := ToTrytes(tangle.Seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	api := NewAPI(tangle.Endpoint, nil)
+	adr, err := NewAddress(NewSeed(), 0, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the private Tangle has its own parameters, not those of the devnet
+	net := NetworkProfile{
+		Name:               "private",
+		MinWeightMagnitude: tangle.MWM,
+		Depth:              tangle.Depth,
+		Nodes:              []string{tangle.Endpoint},
+	}
+
+	_, pow := GetBestPoW()
+	bd, err := SendWithOptions(api, seed, 2, []Transfer{{Address: adr, Value: 1, Tag: "GIOTA9INTEGRATION"}}, 0, pow, &SendOptions{Network: &net})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	_, err = api.WaitForConfirmationWithOptions(ctx, bd[0].Hash(), 5*time.Second, &WaitOptions{
+		Promote: true,
+		Network: &net,
+		Pow:     pow,
+	})
+	if err != nil {
+		t.Fatalf("bundle %s is not confirmed: %s", bd.Hash(), err)
+	}
+
+	bal, err := api.GetBalances([]Address{adr}, 100)
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case len(bal.Balances) != 1 || bal.Balances[0] != 1:
+		t.Errorf("balance of %s is %v, expected 1", adr, bal.Balances)
+	}
+}
@@ -0,0 +1,323 @@
+// Package argon2 implements the Argon2 key derivation function of RFC 9106
+// in all three variants. Argon2d, which KDBX 4 databases such as Trinity
+// SeedVault files are keyed with, isn't provided by the standard library or
+// golang.org/x/crypto/argon2, which only has Argon2i and Argon2id.
+package argon2
+
+import (
+	"encoding/binary"
+	"math/bits"
+	"sync"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// Type is the variant of Argon2.
+type Type uint32
+
+// Variants of Argon2.
+const (
+	// Argon2d uses data-dependent memory access.
+	Argon2d Type = 0
+	// Argon2i uses data-independent memory access.
+	Argon2i Type = 1
+	// Argon2id uses data-independent memory access in the first half of
+	// the first pass and data-dependent access after it.
+	Argon2id Type = 2
+)
+
+const (
+	// Version is the version of Argon2 implemented, 1.3.
+	Version = 0x13
+
+	blockSize  = 1024
+	blockWords = blockSize / 8
+	syncPoints = 4
+)
+
+type block [blockWords]uint64
+
+// Key derives a key of keyLen bytes from password and salt with the variant
+// typ, time passes over memory KiB and threads lanes. secret and data are
+// the optional secret and associated data of Argon2 and may be nil.
+func Key(typ Type, password, salt, secret, data []byte, time, memory, threads, keyLen uint32) []byte {
+	if time < 1 {
+		panic("argon2: number of passes too small")
+	}
+	if threads < 1 {
+		panic("argon2: parallelism degree too small")
+	}
+
+	h0 := initHash(typ, password, salt, secret, data, time, memory, threads, keyLen)
+
+	// the memory is rounded down to a multiple of the segments of all lanes
+	blocks := memory / (syncPoints * threads) * (syncPoints * threads)
+	if blocks < 2*syncPoints*threads {
+		blocks = 2 * syncPoints * threads
+	}
+	s := &state{
+		typ:     typ,
+		b:       make([]block, blocks),
+		time:    time,
+		threads: threads,
+		laneLen: blocks / threads,
+		segLen:  blocks / threads / syncPoints,
+	}
+	s.initBlocks(&h0)
+	s.fill()
+	return s.finalize(keyLen)
+}
+
+// initHash returns the pre-hashing digest H0 followed by 8 bytes of room for
+// the block and lane counters.
+func initHash(typ Type, password, salt, secret, data []byte, time, memory, threads, keyLen uint32) [blake2b.Size + 8]byte {
+	var h0 [blake2b.Size + 8]byte
+	h, _ := blake2b.New512(nil)
+
+	var buf [4]byte
+	for _, v := range []uint32{threads, keyLen, memory, time, Version, uint32(typ)} {
+		binary.LittleEndian.PutUint32(buf[:], v)
+		h.Write(buf[:])
+	}
+	for _, p := range [][]byte{password, salt, secret, data} {
+		binary.LittleEndian.PutUint32(buf[:], uint32(len(p)))
+		h.Write(buf[:])
+		h.Write(p)
+	}
+	h.Sum(h0[:0])
+	return h0
+}
+
+// hashLong is the variable-length hash function H' of Argon2. It fills out.
+func hashLong(out, in []byte) {
+	var prefix [4]byte
+	binary.LittleEndian.PutUint32(prefix[:], uint32(len(out)))
+
+	if len(out) <= blake2b.Size {
+		h, _ := blake2b.New(len(out), nil)
+		h.Write(prefix[:])
+		h.Write(in)
+		h.Sum(out[:0])
+		return
+	}
+
+	// the first 32 bytes of a chain of 64 byte hashes, and all of the last
+	h, _ := blake2b.New512(nil)
+	h.Write(prefix[:])
+	h.Write(in)
+	var v [blake2b.Size]byte
+	h.Sum(v[:0])
+	n := copy(out, v[:32])
+	for len(out)-n > blake2b.Size {
+		v = blake2b.Sum512(v[:])
+		n += copy(out[n:], v[:32])
+	}
+	h, _ = blake2b.New(len(out)-n, nil)
+	h.Write(v[:])
+	h.Sum(out[n:n])
+}
+
+type state struct {
+	typ     Type
+	b       []block
+	time    uint32
+	threads uint32
+	laneLen uint32
+	segLen  uint32
+}
+
+// initBlocks computes the first two blocks of each lane from h0.
+func (s *state) initBlocks(h0 *[blake2b.Size + 8]byte) {
+	var buf [blockSize]byte
+	for lane := uint32(0); lane < s.threads; lane++ {
+		binary.LittleEndian.PutUint32(h0[blake2b.Size+4:], lane)
+		for i := uint32(0); i < 2; i++ {
+			binary.LittleEndian.PutUint32(h0[blake2b.Size:], i)
+			hashLong(buf[:], h0[:])
+			b := &s.b[lane*s.laneLen+i]
+			for j := range b {
+				b[j] = binary.LittleEndian.Uint64(buf[j*8:])
+			}
+		}
+	}
+}
+
+// fill runs the passes over the memory. The segments of a slice are
+// independent of each other, so the lanes are filled in parallel.
+func (s *state) fill() {
+	var wg sync.WaitGroup
+	for pass := uint32(0); pass < s.time; pass++ {
+		for slice := uint32(0); slice < syncPoints; slice++ {
+			wg.Add(int(s.threads))
+			for lane := uint32(0); lane < s.threads; lane++ {
+				go func(lane uint32) {
+					defer wg.Done()
+					s.fillSegment(pass, slice, lane)
+				}(lane)
+			}
+			wg.Wait()
+		}
+	}
+}
+
+func (s *state) fillSegment(pass, slice, lane uint32) {
+	var addresses, input, zero block
+	independent := s.typ == Argon2i || (s.typ == Argon2id && pass == 0 && slice < syncPoints/2)
+	if independent {
+		input[0] = uint64(pass)
+		input[1] = uint64(lane)
+		input[2] = uint64(slice)
+		input[3] = uint64(len(s.b))
+		input[4] = uint64(s.time)
+		input[5] = uint64(s.typ)
+	}
+	nextAddresses := func() {
+		input[6]++
+		compress(&addresses, &zero, &input, false)
+		compress(&addresses, &zero, &addresses, false)
+	}
+
+	index := uint32(0)
+	if pass == 0 && slice == 0 {
+		// the first two blocks of the lane are computed by initBlocks
+		index = 2
+		if independent {
+			nextAddresses()
+		}
+	}
+
+	offset := lane*s.laneLen + slice*s.segLen + index
+	for ; index < s.segLen; index, offset = index+1, offset+1 {
+		prev := offset - 1
+		if offset%s.laneLen == 0 {
+			prev += s.laneLen
+		}
+
+		var rand uint64
+		if independent {
+			if index%blockWords == 0 {
+				nextAddresses()
+			}
+			rand = addresses[index%blockWords]
+		} else {
+			rand = s.b[prev][0]
+		}
+
+		ref := s.refIndex(rand, pass, slice, lane, index)
+		// version 1.3 overwrites blocks of the first pass and XORs later ones
+		compress(&s.b[offset], &s.b[prev], &s.b[ref], pass > 0)
+	}
+}
+
+// refIndex maps the pseudo-random value rand to the index of the block that
+// the block index of the segment slice of lane references.
+func (s *state) refIndex(rand uint64, pass, slice, lane, index uint32) uint32 {
+	refLane := uint32(rand>>32) % s.threads
+	if pass == 0 && slice == 0 {
+		refLane = lane
+	}
+
+	// area is the number of blocks that may be referenced, starting at start
+	var area, start uint32
+	if pass == 0 {
+		area = slice * s.segLen
+	} else {
+		area = (syncPoints - 1) * s.segLen
+		start = (slice + 1) % syncPoints * s.segLen
+	}
+	if refLane == lane {
+		area += index - 1
+	} else if index == 0 {
+		area--
+	}
+
+	x := rand & 0xffffffff
+	x = x * x >> 32
+	y := uint32(uint64(area) * x >> 32)
+	return refLane*s.laneLen + (start+area-1-y)%s.laneLen
+}
+
+// finalize hashes the XOR of the last blocks of all lanes to the key.
+func (s *state) finalize(keyLen uint32) []byte {
+	c := s.b[s.laneLen-1]
+	for lane := uint32(1); lane < s.threads; lane++ {
+		last := &s.b[(lane+1)*s.laneLen-1]
+		for i := range c {
+			c[i] ^= last[i]
+		}
+	}
+
+	var buf [blockSize]byte
+	for i, w := range c {
+		binary.LittleEndian.PutUint64(buf[i*8:], w)
+	}
+	key := make([]byte, keyLen)
+	hashLong(key, buf[:])
+	return key
+}
+
+// compress is the compression function G of Argon2. It stores G(x, y) in
+// out, or XORs it into out if xor is set.
+func compress(out, x, y *block, xor bool) {
+	var r block
+	for i := range r {
+		r[i] = x[i] ^ y[i]
+	}
+
+	// the block is a matrix of 8x8 registers of 16 bytes, which is
+	// permuted row by row and then column by column
+	z := r
+	var v [16]uint64
+	for row := 0; row < 8; row++ {
+		copy(v[:], z[16*row:16*row+16])
+		permute(&v)
+		copy(z[16*row:], v[:])
+	}
+	for col := 0; col < 8; col++ {
+		for k := 0; k < 8; k++ {
+			v[2*k], v[2*k+1] = z[16*k+2*col], z[16*k+2*col+1]
+		}
+		permute(&v)
+		for k := 0; k < 8; k++ {
+			z[16*k+2*col], z[16*k+2*col+1] = v[2*k], v[2*k+1]
+		}
+	}
+
+	if xor {
+		for i := range out {
+			out[i] ^= z[i] ^ r[i]
+		}
+		return
+	}
+	for i := range out {
+		out[i] = z[i] ^ r[i]
+	}
+}
+
+// permute is the round function P of Argon2, a BLAKE2b round using
+// multiplications to strengthen it against tradeoff attacks.
+func permute(v *[16]uint64) {
+	mix(&v[0], &v[4], &v[8], &v[12])
+	mix(&v[1], &v[5], &v[9], &v[13])
+	mix(&v[2], &v[6], &v[10], &v[14])
+	mix(&v[3], &v[7], &v[11], &v[15])
+	mix(&v[0], &v[5], &v[10], &v[15])
+	mix(&v[1], &v[6], &v[11], &v[12])
+	mix(&v[2], &v[7], &v[8], &v[13])
+	mix(&v[3], &v[4], &v[9], &v[14])
+}
+
+func mix(a, b, c, d *uint64) {
+	*a = blaMka(*a, *b)
+	*d = bits.RotateLeft64(*d^*a, -32)
+	*c = blaMka(*c, *d)
+	*b = bits.RotateLeft64(*b^*c, -24)
+	*a = blaMka(*a, *b)
+	*d = bits.RotateLeft64(*d^*a, -16)
+	*c = blaMka(*c, *d)
+	*b = bits.RotateLeft64(*b^*c, -63)
+}
+
+func blaMka(x, y uint64) uint64 {
+	return x + y + 2*uint64(uint32(x))*uint64(uint32(y))
+}
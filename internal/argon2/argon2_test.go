@@ -0,0 +1,60 @@
+package argon2
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"golang.org/x/crypto/argon2"
+)
+
+func TestKey(t *testing.T) {
+	// the test vectors of RFC 9106, section 5
+	var (
+		password = bytes.Repeat([]byte{0x01}, 32)
+		salt     = bytes.Repeat([]byte{0x02}, 16)
+		secret   = bytes.Repeat([]byte{0x03}, 8)
+		data     = bytes.Repeat([]byte{0x04}, 12)
+	)
+	tests := []struct {
+		typ Type
+		tag string
+	}{
+		{Argon2d, "512b391b6f1162975371d30919734294f868e3be3984f3c1a13a4db9fabe4acb"},
+		{Argon2i, "c814d9d1dc7f37aa13f0d77f2494bda1c8de6b016dd388d29952a4c4672b6ce8"},
+		{Argon2id, "0d640df58d78766c08c037a34a8b53c9d01ef0452d75b65eb52520e96b01e659"},
+	}
+
+	for _, tt := range tests {
+		tag := Key(tt.typ, password, salt, secret, data, 3, 32, 4, 32)
+		if got := hex.EncodeToString(tag); got != tt.tag {
+			t.Errorf("type %d: tag %s, expected %s", tt.typ, got, tt.tag)
+		}
+	}
+}
+
+func TestKeyCrypto(t *testing.T) {
+	// the variants shared with golang.org/x/crypto must agree with it
+	tests := []struct {
+		name    string
+		time    uint32
+		memory  uint32
+		threads uint8
+	}{
+		{"minimal memory", 1, 8, 1},
+		{"rounded memory", 2, 1000, 3},
+		{"several passes", 4, 256, 2},
+	}
+
+	password, salt := []byte("giota"), []byte("somesaltsomesalt")
+	for _, tt := range tests {
+		if got, want := Key(Argon2i, password, salt, nil, nil, tt.time, tt.memory, uint32(tt.threads), 32),
+			argon2.Key(password, salt, tt.time, tt.memory, tt.threads, 32); !bytes.Equal(got, want) {
+			t.Errorf("%s: Argon2i key %x, expected %x", tt.name, got, want)
+		}
+		if got, want := Key(Argon2id, password, salt, nil, nil, tt.time, tt.memory, uint32(tt.threads), 72),
+			argon2.IDKey(password, salt, tt.time, tt.memory, tt.threads, 72); !bytes.Equal(got, want) {
+			t.Errorf("%s: Argon2id key %x, expected %x", tt.name, got, want)
+		}
+	}
+}
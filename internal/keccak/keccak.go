@@ -0,0 +1,134 @@
+// Package keccak implements Keccak-384 with the original padding of the
+// Keccak submission, as used by Kerl. It differs from SHA3-384 only in the
+// padding, and isn't provided by the standard library or
+// golang.org/x/crypto/sha3, which only has the legacy 256 and 512 bit
+// variants.
+package keccak
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/bits"
+)
+
+const (
+	// Size is the size of a Keccak-384 checksum in bytes.
+	Size = 48
+	// rate is the number of bytes absorbed per permutation.
+	rate = 200 - 2*Size
+)
+
+// roundConstants are the constants of the iota step of Keccak-f[1600].
+var roundConstants = [24]uint64{
+	0x0000000000000001, 0x0000000000008082, 0x800000000000808A, 0x8000000080008000,
+	0x000000000000808B, 0x0000000080000001, 0x8000000080008081, 0x8000000000008009,
+	0x000000000000008A, 0x0000000000000088, 0x0000000080008009, 0x000000008000000A,
+	0x000000008000808B, 0x800000000000008B, 0x8000000000008089, 0x8000000000008003,
+	0x8000000000008002, 0x8000000000000080, 0x000000000000800A, 0x800000008000000A,
+	0x8000000080008081, 0x8000000000008080, 0x0000000080000001, 0x8000000080008008,
+}
+
+// rotations and piLanes are the rotation offsets and the lane order of the
+// rho and pi steps.
+var (
+	rotations = [24]int{1, 3, 6, 10, 15, 21, 28, 36, 45, 55, 2, 14, 27, 41, 56, 8, 25, 43, 62, 18, 39, 61, 20, 44}
+	piLanes   = [24]int{10, 7, 11, 17, 18, 3, 5, 16, 8, 21, 24, 4, 15, 23, 19, 13, 12, 2, 20, 14, 22, 9, 6, 1}
+)
+
+// keccakF1600 applies the Keccak-f[1600] permutation to the state a.
+func keccakF1600(a *[25]uint64) {
+	var c [5]uint64
+	for r := 0; r < 24; r++ {
+		// theta
+		for i := 0; i < 5; i++ {
+			c[i] = a[i] ^ a[i+5] ^ a[i+10] ^ a[i+15] ^ a[i+20]
+		}
+		for i := 0; i < 5; i++ {
+			d := c[(i+4)%5] ^ bits.RotateLeft64(c[(i+1)%5], 1)
+			for j := 0; j < 25; j += 5 {
+				a[j+i] ^= d
+			}
+		}
+
+		// rho and pi
+		t := a[1]
+		for i := 0; i < 24; i++ {
+			j := piLanes[i]
+			t, a[j] = a[j], bits.RotateLeft64(t, rotations[i])
+		}
+
+		// chi
+		for j := 0; j < 25; j += 5 {
+			copy(c[:], a[j:j+5])
+			for i := 0; i < 5; i++ {
+				a[j+i] ^= ^c[(i+1)%5] & c[(i+2)%5]
+			}
+		}
+
+		// iota
+		a[0] ^= roundConstants[r]
+	}
+}
+
+type digest struct {
+	a   [25]uint64
+	buf [rate]byte
+	n   int
+}
+
+// New384 returns a new Keccak-384 hash.
+func New384() hash.Hash {
+	return &digest{}
+}
+
+func (d *digest) absorb(block []byte) {
+	for i := 0; i < rate/8; i++ {
+		d.a[i] ^= binary.LittleEndian.Uint64(block[i*8:])
+	}
+	keccakF1600(&d.a)
+}
+
+// Write absorbs p. It never returns an error.
+func (d *digest) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		c := copy(d.buf[d.n:], p)
+		d.n += c
+		p = p[c:]
+		if d.n == rate {
+			d.absorb(d.buf[:])
+			d.n = 0
+		}
+	}
+	return n, nil
+}
+
+// Sum appends the checksum of the data written so far to b. It doesn't
+// change the state of d.
+func (d *digest) Sum(b []byte) []byte {
+	c := *d
+	for i := c.n; i < rate; i++ {
+		c.buf[i] = 0
+	}
+	c.buf[c.n] ^= 0x01
+	c.buf[rate-1] ^= 0x80
+	c.absorb(c.buf[:])
+
+	var out [Size]byte
+	for i := 0; i < Size/8; i++ {
+		binary.LittleEndian.PutUint64(out[i*8:], c.a[i])
+	}
+	return append(b, out[:]...)
+}
+
+func (d *digest) Reset() {
+	*d = digest{}
+}
+
+func (d *digest) Size() int {
+	return Size
+}
+
+func (d *digest) BlockSize() int {
+	return rate
+}
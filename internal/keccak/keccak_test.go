@@ -0,0 +1,55 @@
+package keccak
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestKeccak384(t *testing.T) {
+	tests := []struct {
+		in  []byte
+		sum string
+	}{
+		{
+			in:  nil,
+			sum: "2c23146a63a29acf99e73b88f8c24eaa7dc60aa771780ccc006afbfa8fe2479b2dd2b21362337441ac12b515911957ff",
+		},
+		{
+			in:  []byte("abc"),
+			sum: "f7df1165f033337be098e7d288ad6a2f74409d7a60b49c36642218de161b1f99f8c681e4afaf31a34db29fb763e3c28e",
+		},
+	}
+
+	for _, tt := range tests {
+		h := New384()
+		h.Write(tt.in)
+		if sum := hex.EncodeToString(h.Sum(nil)); sum != tt.sum {
+			t.Errorf("Keccak-384(%q) = %s, expected %s", tt.in, sum, tt.sum)
+		}
+	}
+}
+
+func TestKeccak384Blocks(t *testing.T) {
+	// the checksum must not depend on how the data is split into writes
+	data := bytes.Repeat([]byte("giota"), 3*rate)
+
+	h := New384()
+	h.Write(data)
+	want := h.Sum(nil)
+
+	for _, n := range []int{1, rate - 1, rate, rate + 1} {
+		h.Reset()
+		for p := data; len(p) > 0; {
+			c := n
+			if c > len(p) {
+				c = len(p)
+			}
+			h.Write(p[:c])
+			p = p[c:]
+		}
+		if sum := h.Sum(nil); !bytes.Equal(sum, want) {
+			t.Errorf("writes of %d bytes returned %x, expected %x", n, sum, want)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package giota
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// jsonInt64 is an integer of a node response, which node versions encode as
+// JSON number or as string holding a decimal number.
+type jsonInt64 int64
+
+func (n *jsonInt64) UnmarshalJSON(b []byte) error {
+	s := string(b)
+	if s == "null" {
+		return nil
+	}
+	if len(s) > 0 && s[0] == '"' {
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+	}
+
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err == nil {
+		*n = jsonInt64(v)
+		return nil
+	}
+
+	// some nodes encode large values with exponent, e.g. 2.7E15
+	f, ferr := strconv.ParseFloat(s, 64)
+	if ferr != nil || f != math.Trunc(f) || math.Abs(f) > 1<<53 {
+		return fmt.Errorf("invalid integer %s", b)
+	}
+	*n = jsonInt64(f)
+	return nil
+}
+
+// jsonValue is a jsonInt64 which must be a valid amount of iotas.
+type jsonValue int64
+
+func (v *jsonValue) UnmarshalJSON(b []byte) error {
+	var n jsonInt64
+	if err := n.UnmarshalJSON(b); err != nil {
+		return err
+	}
+	if n > MaxSupply || n < -MaxSupply {
+		return fmt.Errorf("value %d exceeds the supply", n)
+	}
+	*v = jsonValue(n)
+	return nil
+}
+
+// maxTrits27 is the max integer of 27 trits, (3^27-1)/2.
+const maxTrits27 = 3812798742493
+
+// jsonTransaction is a transaction as JSON object, whose numbers may be
+// encoded as strings. The attachment timestamps may be numbers of
+// milliseconds or their 9 trytes.
+type jsonTransaction struct {
+	SignatureMessageFragment      Trytes
+	Address                       Address
+	Value                         jsonValue
+	ObsoleteTag                   Trytes
+	Timestamp                     jsonInt64
+	CurrentIndex                  jsonInt64
+	LastIndex                     jsonInt64
+	Bundle                        Trytes
+	TrunkTransaction              Trytes
+	BranchTransaction             Trytes
+	Tag                           Trytes
+	AttachmentTimestamp           json.RawMessage
+	AttachmentTimestampLowerBound json.RawMessage
+	AttachmentTimestampUpperBound json.RawMessage
+	Nonce                         Trytes
+}
+
+// trytes returns the trytes of the transaction, checking the sizes of all
+// fields.
+func (j *jsonTransaction) trytes() (Trytes, error) {
+	switch {
+	case j.CurrentIndex < 0 || j.LastIndex < j.CurrentIndex || j.LastIndex > maxTrits27:
+		return "", fmt.Errorf("invalid index %d of %d", j.CurrentIndex, j.LastIndex)
+	case j.Timestamp < 0 || j.Timestamp > maxTrits27:
+		return "", fmt.Errorf("invalid timestamp %d", j.Timestamp)
+	}
+
+	var fields [3]Trytes
+	for i, raw := range []json.RawMessage{j.AttachmentTimestamp, j.AttachmentTimestampLowerBound, j.AttachmentTimestampUpperBound} {
+		if len(raw) > 0 && raw[0] == '"' {
+			if err := json.Unmarshal(raw, &fields[i]); err != nil {
+				return "", err
+			}
+			if len(fields[i]) == AttachmentTimestampTrinarySize/3 && fields[i].IsValid() == nil {
+				continue
+			}
+		}
+
+		var ms jsonInt64
+		if len(raw) > 0 {
+			if err := ms.UnmarshalJSON(raw); err != nil {
+				return "", err
+			}
+		}
+		if ms < 0 || ms > maxTrits27 {
+			return "", fmt.Errorf("invalid attachment timestamp %d", ms)
+		}
+		fields[i] = Int2Trits(int64(ms), AttachmentTimestampTrinarySize).Trytes()
+	}
+
+	sizes := []struct {
+		name string
+		t    Trytes
+		size int
+	}{
+		{"signatureMessageFragment", j.SignatureMessageFragment, SignatureMessageFragmentTrinarySize},
+		{"address", Trytes(j.Address), AddressTrinarySize},
+		{"obsoleteTag", j.ObsoleteTag, ObsoleteTagTrinarySize},
+		{"bundle", j.Bundle, BundleTrinarySize},
+		{"trunkTransaction", j.TrunkTransaction, TrunkTransactionTrinarySize},
+		{"branchTransaction", j.BranchTransaction, BranchTransactionTrinarySize},
+		{"tag", j.Tag, TagTrinarySize},
+		{"attachmentTimestamp", fields[0], AttachmentTimestampTrinarySize},
+		{"attachmentTimestampLowerBound", fields[1], AttachmentTimestampLowerBoundTrinarySize},
+		{"attachmentTimestampUpperBound", fields[2], AttachmentTimestampUpperBoundTrinarySize},
+		{"nonce", j.Nonce, NonceTrinarySize},
+	}
+	for _, s := range sizes {
+		if len(s.t) != s.size/3 || s.t.IsValid() != nil {
+			return "", fmt.Errorf("invalid %s of transaction", s.name)
+		}
+	}
+
+	tx := Transaction{
+		SignatureMessageFragment:      j.SignatureMessageFragment,
+		Address:                       j.Address,
+		Value:                         int64(j.Value),
+		ObsoleteTag:                   Tag(j.ObsoleteTag),
+		CurrentIndex:                  int64(j.CurrentIndex),
+		LastIndex:                     int64(j.LastIndex),
+		Bundle:                        BundleHash(j.Bundle),
+		TrunkTransaction:              Hash(j.TrunkTransaction),
+		BranchTransaction:             Hash(j.BranchTransaction),
+		Tag:                           Tag(j.Tag),
+		AttachmentTimestamp:           fields[0],
+		AttachmentTimestampLowerBound: fields[1],
+		AttachmentTimestampUpperBound: fields[2],
+		Nonce:                         j.Nonce,
+	}
+	tx.Timestamp = time.Unix(int64(j.Timestamp), 0)
+	return tx.Trytes(), nil
+}
@@ -0,0 +1,116 @@
+package giota
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestGetBalancesNumbers(t *testing.T) {
+	tests := []struct {
+		name     string
+		balances []interface{}
+		index    interface{}
+		want     []int64
+		valid    bool
+	}{
+		{name: "strings", balances: []interface{}{"100", "0"}, index: 42, want: []int64{100, 0}, valid: true},
+		{name: "numbers", balances: []interface{}{100, 2779530283277761}, index: "42", want: []int64{100, MaxSupply}, valid: true},
+		{name: "exponent", balances: []interface{}{"2.5E3", 1e6}, index: 42, want: []int64{2500, Mi}, valid: true},
+		{name: "negative", balances: []interface{}{"-1"}, index: 42},
+		{name: "beyond supply", balances: []interface{}{"2779530283277762"}, index: 42},
+		{name: "fraction", balances: []interface{}{1.5}, index: 42},
+		{name: "no number", balances: []interface{}{"abc"}, index: 42},
+		{name: "bad milestone index", balances: []interface{}{"1"}, index: "x"},
+	}
+
+	for _, tt := range tests {
+		api, done := newFakeNode(t, map[string]fakeNodeHandler{
+			"getBalances": func(map[string]json.RawMessage) interface{} {
+				return map[string]interface{}{"balances": tt.balances, "milestoneIndex": tt.index}
+			},
+		})
+		resp, err := api.GetBalances([]Address{filterAddr1, filterAddr2}, 100)
+		done()
+
+		switch {
+		case !tt.valid && err == nil:
+			t.Errorf("%s: GetBalances() accepted %v", tt.name, tt.balances)
+		case !tt.valid:
+		case err != nil:
+			t.Errorf("%s: %s", tt.name, err)
+		case resp.MilestoneIndex != 42 || len(resp.Balances) != len(tt.want):
+			t.Errorf("%s: GetBalances() returned %+v", tt.name, resp)
+		default:
+			for i := range tt.want {
+				if resp.Balances[i] != tt.want[i] {
+					t.Errorf("%s: GetBalances() returned balance %d, expected %d", tt.name, resp.Balances[i], tt.want[i])
+				}
+			}
+		}
+	}
+}
+
+func TestTransactionUnmarshalObject(t *testing.T) {
+	bs := filterTestBundle()
+	bs[1].AttachmentTimestamp = Int2Trits(1500000100123, AttachmentTimestampTrinarySize).Trytes()
+	tx, err := NewTransaction(bs[1].Trytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fields := func(change map[string]interface{}) []byte {
+		m := map[string]interface{}{
+			"signatureMessageFragment":      tx.SignatureMessageFragment,
+			"address":                       tx.Address,
+			"value":                         "-100",
+			"obsoleteTag":                   tx.ObsoleteTag,
+			"timestamp":                     tx.Timestamp.Unix(),
+			"currentIndex":                  "1",
+			"lastIndex":                     2,
+			"bundle":                        tx.Bundle,
+			"trunkTransaction":              tx.TrunkTransaction,
+			"branchTransaction":             tx.BranchTransaction,
+			"tag":                           tx.Tag,
+			"attachmentTimestamp":           1500000100123,
+			"attachmentTimestampLowerBound": tx.AttachmentTimestampLowerBound,
+			"attachmentTimestampUpperBound": "0",
+			"nonce":                         tx.Nonce,
+		}
+		for k, v := range change {
+			m[k] = v
+		}
+		b, err := json.Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+
+	tests := []struct {
+		name  string
+		json  []byte
+		valid bool
+	}{
+		{name: "trytes", json: []byte(`"` + tx.Trytes() + `"`), valid: true},
+		{name: "object", json: fields(nil), valid: true},
+		{name: "numeric value", json: fields(map[string]interface{}{"value": -100, "timestamp": "1500000100"}), valid: true},
+		{name: "short address", json: fields(map[string]interface{}{"address": "ABC"})},
+		{name: "value beyond supply", json: fields(map[string]interface{}{"value": "-2779530283277762"})},
+		{name: "index beyond last", json: fields(map[string]interface{}{"currentIndex": 3})},
+		{name: "negative timestamp", json: fields(map[string]interface{}{"attachmentTimestamp": -1})},
+	}
+
+	for _, tt := range tests {
+		var got Transaction
+		err := json.Unmarshal(tt.json, &got)
+		switch {
+		case !tt.valid && err == nil:
+			t.Errorf("%s: Unmarshal() accepted %s", tt.name, tt.json)
+		case !tt.valid:
+		case err != nil:
+			t.Errorf("%s: %s", tt.name, err)
+		case got.Hash() != tx.Hash():
+			t.Errorf("%s: Unmarshal() returned %+v", tt.name, got)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/iotaledger/giota"
+)
+
+// maxAddresses is the max total of getNewAddress and the max range of
+// getAccountData.
+const maxAddresses = 500
+
+type getNewAddressParams struct {
+	// Index is the index of the first address. If it is nil, the first
+	// unused address is returned.
+	Index    *uint64             `json:"index"`
+	Total    int                 `json:"total"`
+	Security giota.SecurityLevel `json:"security"`
+	Checksum bool                `json:"checksum"`
+}
+
+func (s *Server) getNewAddress(params json.RawMessage) (interface{}, error) {
+	p := getNewAddressParams{Total: 1, Security: s.Security}
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	if p.Total < 1 || p.Total > maxAddresses {
+		return nil, invalidParams(errors.New("total must be between 1 and 500"))
+	}
+
+	var (
+		adrs []giota.Address
+		err  error
+	)
+	switch {
+	case p.Index != nil:
+		adrs, err = giota.NewAddresses(s.Seed, *p.Index, p.Total, p.Security)
+	default:
+		var adr giota.Address
+		adr, _, err = giota.GetUsedAddress(s.API, s.Seed, p.Security)
+		adrs = []giota.Address{adr}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]giota.Trytes, len(adrs))
+	for i, a := range adrs {
+		res[i] = giota.Trytes(a)
+		if p.Checksum {
+			res[i] = a.WithChecksum()
+		}
+	}
+	return res, nil
+}
+
+// Input is an address of the seed with balance.
+type Input struct {
+	Address  giota.Address       `json:"address"`
+	Balance  int64               `json:"balance"`
+	KeyIndex uint64              `json:"keyIndex"`
+	Security giota.SecurityLevel `json:"security"`
+}
+
+// AccountData is the result of getAccountData.
+type AccountData struct {
+	// LatestAddress is the first unused address.
+	LatestAddress giota.Address `json:"latestAddress"`
+	// Addresses are the used addresses.
+	Addresses []giota.Address `json:"addresses"`
+	Inputs    []Input         `json:"inputs"`
+	Balance   int64           `json:"balance"`
+}
+
+type getAccountDataParams struct {
+	Start uint64 `json:"start"`
+	// End is the index after the last address to check the balance of. If
+	// it is 0, all used addresses are checked and Start is ignored.
+	End uint64 `json:"end"`
+}
+
+func (s *Server) getAccountData(params json.RawMessage) (interface{}, error) {
+	var p getAccountDataParams
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	if p.End != 0 && (p.End < p.Start || p.End > p.Start+maxAddresses) {
+		return nil, invalidParams(errors.New("invalid start/end"))
+	}
+
+	latest, used, err := giota.GetUsedAddress(s.API, s.Seed, s.Security)
+	if err != nil {
+		return nil, err
+	}
+
+	// the index of a balance is its position in the checked addresses
+	var (
+		bals  giota.Balances
+		first uint64
+	)
+	switch {
+	case p.End > 0:
+		first = p.Start
+		bals, err = giota.GetInputs(s.API, s.Seed, p.Start, p.End, 0, s.Security)
+	case len(used) > 0:
+		bals, err = s.API.Balances(used)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data := &AccountData{
+		LatestAddress: latest,
+		Addresses:     used,
+		Inputs:        []Input{},
+		Balance:       bals.Total(),
+	}
+	if data.Addresses == nil {
+		data.Addresses = []giota.Address{}
+	}
+	for _, b := range bals {
+		if b.Value <= 0 {
+			continue
+		}
+		data.Inputs = append(data.Inputs, Input{
+			Address:  b.Address,
+			Balance:  b.Value,
+			KeyIndex: first + b.Index,
+			Security: s.Security,
+		})
+	}
+	return data, nil
+}
+
+// Transfer is a transfer of sendTransfer. The address may have a checksum.
+type Transfer struct {
+	Address giota.Trytes `json:"address"`
+	Value   int64        `json:"value"`
+	Message giota.Trytes `json:"message"`
+	Tag     giota.Trytes `json:"tag"`
+}
+
+type sendTransferParams struct {
+	Transfers []Transfer   `json:"transfers"`
+	Remainder giota.Trytes `json:"remainder"`
+}
+
+// SendTransferResult is the result of sendTransfer.
+type SendTransferResult struct {
+	Bundle giota.Trytes `json:"bundle"`
+	Tail   giota.Hash   `json:"tail"`
+	// Trytes are the attached transactions in the order of their index.
+	Trytes []giota.Trytes `json:"trytes"`
+}
+
+func (s *Server) sendTransfer(params json.RawMessage) (interface{}, error) {
+	var p sendTransferParams
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	if len(p.Transfers) == 0 {
+		return nil, invalidParams(errors.New("no transfers"))
+	}
+
+	trs := make([]giota.Transfer, len(p.Transfers))
+	for i, tr := range p.Transfers {
+		adr, err := parseAddress(tr.Address)
+		if err != nil {
+			return nil, invalidParams(err)
+		}
+		trs[i] = giota.Transfer{Address: adr, Value: tr.Value, Message: tr.Message, Tag: tr.Tag}
+	}
+
+	var remainder giota.Address
+	if p.Remainder != "" {
+		adr, err := parseAddress(p.Remainder)
+		if err != nil {
+			return nil, invalidParams(err)
+		}
+		remainder = adr
+	}
+
+	bd, err := giota.PrepareTransfers(s.API, s.Seed, trs, nil, remainder, s.Security)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := giota.SendTrytes(s.API, s.Depth, []giota.Transaction(bd), s.MWM, s.Pow)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &SendTransferResult{
+		Bundle: giota.Trytes(bd[0].Bundle),
+		Tail:   res.Tail,
+		Trytes: make([]giota.Trytes, len(res.Transactions)),
+	}
+	for i := range res.Transactions {
+		out.Trytes[i] = res.Transactions[i].Trytes()
+	}
+	return out, nil
+}
+
+// parseAddress converts t to an address, verifying the checksum if t has
+// one.
+func parseAddress(t giota.Trytes) (giota.Address, error) {
+	if len(t) == 90 {
+		return giota.ValidateChecksummedString(string(t))
+	}
+	return t.ToAddress()
+}
@@ -0,0 +1,255 @@
+// Package jsonrpc serves the wallet functions of giota over JSON-RPC 2.0 on
+// HTTP, so that a Go process can replace the node middleware of wallet
+// backends. Like the wallet libraries, the methods are named
+//
+//	getNewAddress    {"index", "total", "security", "checksum"} -> [address]
+//	getAccountData   {"start", "end"} -> AccountData
+//	sendTransfer     {"transfers", "remainder"} -> SendTransferResult
+//
+// but the seed is kept by the server instead of being sent with each call.
+// Batch requests and notifications are supported.
+//
+// As anyone able to reach the server can spend the funds of the seed, it
+// rejects the requests of web pages: requests need the Content-Type
+// application/json, which browsers only send cross-origin after a CORS
+// preflight the server doesn't answer, and must not have the Origin header
+// browsers add. Set Server.Token or listen on a unix socket to keep out
+// other local processes.
+package jsonrpc
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+
+	"github.com/iotaledger/giota"
+)
+
+// Error codes of JSON-RPC 2.0.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+	// CodeServerError is used for errors returned by giota or the node.
+	CodeServerError = -32000
+)
+
+// MaxRequestSize is the max size of a request body in bytes.
+var MaxRequestSize int64 = 1 << 20
+
+// Error is a JSON-RPC error object.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func invalidParams(err error) *Error {
+	return &Error{Code: CodeInvalidParams, Message: err.Error()}
+}
+
+// Request is a JSON-RPC request. Requests without ID are notifications.
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	ID      json.RawMessage `json:"id,omitempty"`
+}
+
+// Response is a JSON-RPC response.
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
+}
+
+// Server is an http.Handler serving the wallet methods with the seed of the
+// server.
+type Server struct {
+	API      *giota.API
+	Seed     giota.Trytes
+	Security giota.SecurityLevel
+	Depth    int64
+	MWM      int64
+	// Pow does the PoW locally. If nil, the attachToTangle API of the node
+	// is called.
+	Pow giota.PowFunc
+	// Token, if not empty, must be sent by clients in the Authorization
+	// header as "Bearer <Token>".
+	Token string
+}
+
+type method func(s *Server, params json.RawMessage) (interface{}, error)
+
+var methods = map[string]method{
+	"getNewAddress":  (*Server).getNewAddress,
+	"getAccountData": (*Server).getAccountData,
+	"sendTransfer":   (*Server).sendTransfer,
+}
+
+// ListenAndServe serves s on address of network, e.g. "tcp" or "unix".
+func (s *Server) ListenAndServe(network, address string) error {
+	l, err := net.Listen(network, address)
+	if err != nil {
+		return err
+	}
+	return http.Serve(l, s)
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if r.Header.Get("Origin") != "" {
+		http.Error(w, "cross-origin requests are not allowed", http.StatusForbidden)
+		return
+	}
+	if mt, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mt != "application/json" {
+		http.Error(w, "Content-Type must be application/json", http.StatusUnsupportedMediaType)
+		return
+	}
+	if !s.authorized(r) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, MaxRequestSize+1))
+	switch {
+	case err != nil:
+		writeJSON(w, &Response{JSONRPC: "2.0", Error: &Error{Code: CodeParseError, Message: err.Error()}})
+		return
+	case int64(len(body)) > MaxRequestSize:
+		writeJSON(w, &Response{JSONRPC: "2.0", Error: &Error{Code: CodeInvalidRequest, Message: "request too large"}})
+		return
+	}
+
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 || body[0] != '[' {
+		if resp := s.handleRaw(body); resp != nil {
+			writeJSON(w, resp)
+		} else {
+			w.WriteHeader(http.StatusNoContent)
+		}
+		return
+	}
+
+	var batch []json.RawMessage
+	if err := json.Unmarshal(body, &batch); err != nil {
+		writeJSON(w, &Response{JSONRPC: "2.0", Error: &Error{Code: CodeParseError, Message: err.Error()}})
+		return
+	}
+	if len(batch) == 0 {
+		writeJSON(w, &Response{JSONRPC: "2.0", Error: &Error{Code: CodeInvalidRequest, Message: "empty batch"}})
+		return
+	}
+
+	resps := []*Response{}
+	for _, raw := range batch {
+		if resp := s.handleRaw(raw); resp != nil {
+			resps = append(resps, resp)
+		}
+	}
+	if len(resps) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, resps)
+}
+
+// authorized reports whether r carries the token of s, if any.
+func (s *Server) authorized(r *http.Request) bool {
+	if s.Token == "" {
+		return true
+	}
+	const prefix = "Bearer "
+	auth := r.Header.Get("Authorization")
+	if len(auth) < len(prefix) || auth[:len(prefix)] != prefix {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(auth[len(prefix):]), []byte(s.Token)) == 1
+}
+
+// handleRaw handles a single request and returns its response, or nil for
+// notifications.
+func (s *Server) handleRaw(raw json.RawMessage) *Response {
+	var req Request
+	if err := json.Unmarshal(raw, &req); err != nil {
+		return &Response{JSONRPC: "2.0", Error: &Error{Code: CodeParseError, Message: err.Error()}}
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return &Response{JSONRPC: "2.0", Error: &Error{Code: CodeInvalidRequest, Message: "invalid request"}, ID: req.ID}
+	}
+
+	result, err := s.Call(req.Method, req.Params)
+	if req.ID == nil {
+		return nil
+	}
+
+	resp := &Response{JSONRPC: "2.0", ID: req.ID}
+	if err != nil {
+		resp.Error = toError(err)
+	} else {
+		resp.Result = result
+	}
+	return resp
+}
+
+// Call calls the method named name with the JSON object params.
+func (s *Server) Call(name string, params json.RawMessage) (interface{}, error) {
+	m, ok := methods[name]
+	if !ok {
+		return nil, &Error{Code: CodeMethodNotFound, Message: "method not found: " + name}
+	}
+	if p := bytes.TrimSpace(params); len(p) > 0 && p[0] != '{' && !bytes.Equal(p, []byte("null")) {
+		return nil, invalidParams(errors.New("params must be an object"))
+	}
+	return m(s, params)
+}
+
+func toError(err error) *Error {
+	var rerr *Error
+	if errors.As(err, &rerr) {
+		return rerr
+	}
+
+	var verr *giota.ValidationError
+	if errors.As(err, &verr) {
+		return invalidParams(err)
+	}
+	return &Error{Code: CodeServerError, Message: err.Error()}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// decodeParams decodes params into v, which keeps its values if params are
+// empty.
+func decodeParams(params json.RawMessage, v interface{}) error {
+	if len(bytes.TrimSpace(params)) == 0 {
+		return nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(params))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return invalidParams(err)
+	}
+	return nil
+}
@@ -0,0 +1,283 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/iotaledger/giota"
+)
+
+const (
+	testSeed giota.Trytes  = "WQNZOHUT99PWKEBFSKQSYNC9XHT9GEBMOSJAQDQAXPEZPJNDIUB9TSNWVMHKWICW9WVZXSMDFGISOD9FZ"
+	usedAdr  giota.Address = "AYYNHWWNZQOFYXNQSLVULU9ARZCSXNWWAFYEWEL9LIXYDFS9KDSRZF9ZID9AQWSLAEUAJSTQKGPGXNWCD"
+	newAdr   giota.Address = "9CTFIAYOFLOKXVNDFKNERQQEFR9FCIXQQHNRDKHIVVGFZQKTBWPCOIHCCQIU9ASJQECGPHDBAREDXIRCX"
+)
+
+// newFakeNode returns an API of a node on which only usedAdr has
+// transactions and every address has a balance of 10.
+func newFakeNode(t *testing.T) (*giota.API, func()) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Command   string
+			Addresses []giota.Address
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("fake node could not decode request: %s", err)
+		}
+
+		var resp interface{} = struct{}{}
+		switch req.Command {
+		case "findTransactions":
+			hashes := []giota.Hash{}
+			if len(req.Addresses) > 0 && req.Addresses[0] == usedAdr {
+				hashes = append(hashes, giota.EmptyHash)
+			}
+			resp = &giota.FindTransactionsResponse{Hashes: hashes}
+		case "getBalances":
+			bals := make([]string, len(req.Addresses))
+			for i := range bals {
+				bals[i] = "10"
+			}
+			resp = map[string]interface{}{"balances": bals}
+		case "getTransactionsToApprove":
+			resp = &giota.GetTransactionsToApproveResponse{TrunkTransaction: giota.EmptyHash, BranchTransaction: giota.EmptyHash}
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	return giota.NewAPI(srv.URL, nil), srv.Close
+}
+
+type testResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *Error          `json:"error"`
+	ID     int             `json:"id"`
+}
+
+func post(t *testing.T, url, body string, out interface{}) {
+	resp, err := http.Post(url, "application/json", bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		t.Fatalf("%s: %s", body, err)
+	}
+}
+
+func TestServer(t *testing.T) {
+	api, done := newFakeNode(t)
+	defer done()
+
+	srv := httptest.NewServer(&Server{
+		API:      api,
+		Seed:     testSeed,
+		Security: giota.SecurityLevelMedium,
+		Depth:    3,
+		MWM:      1,
+		Pow:      giota.PowGo,
+	})
+	defer srv.Close()
+
+	tests := []struct {
+		name   string
+		req    string
+		code   int
+		result interface{}
+	}{
+		{
+			name:   "addresses by index",
+			req:    `{"jsonrpc":"2.0","id":1,"method":"getNewAddress","params":{"index":0,"total":2,"checksum":true}}`,
+			result: &[]giota.Trytes{},
+		},
+		{
+			name:   "unused address",
+			req:    `{"jsonrpc":"2.0","id":1,"method":"getNewAddress"}`,
+			result: &[]giota.Trytes{},
+		},
+		{
+			name:   "account data",
+			req:    `{"jsonrpc":"2.0","id":1,"method":"getAccountData","params":{}}`,
+			result: &AccountData{},
+		},
+		{
+			name:   "account data by range",
+			req:    `{"jsonrpc":"2.0","id":1,"method":"getAccountData","params":{"start":2,"end":4}}`,
+			result: &AccountData{},
+		},
+		{
+			name:   "send",
+			req:    `{"jsonrpc":"2.0","id":1,"method":"sendTransfer","params":{"transfers":[{"address":"` + string(newAdr.WithChecksum()) + `","tag":"JSONRPC"}]}}`,
+			result: &SendTransferResult{},
+		},
+		{name: "unknown method", req: `{"jsonrpc":"2.0","id":1,"method":"foo"}`, code: CodeMethodNotFound},
+		{name: "positional params", req: `{"jsonrpc":"2.0","id":1,"method":"getNewAddress","params":[0]}`, code: CodeInvalidParams},
+		{name: "unknown param", req: `{"jsonrpc":"2.0","id":1,"method":"getNewAddress","params":{"seed":"A"}}`, code: CodeInvalidParams},
+		{name: "bad checksum", req: `{"jsonrpc":"2.0","id":1,"method":"sendTransfer","params":{"transfers":[{"address":"` + string(newAdr) + `999999999"}]}}`, code: CodeInvalidParams},
+		{name: "no version", req: `{"id":1,"method":"getNewAddress"}`, code: CodeInvalidRequest},
+		{name: "parse error", req: `{"jsonrpc"`, code: CodeParseError},
+	}
+
+	results := make(map[string]interface{})
+	for _, tt := range tests {
+		var resp testResponse
+		post(t, srv.URL, tt.req, &resp)
+
+		switch {
+		case tt.code != 0 && (resp.Error == nil || resp.Error.Code != tt.code):
+			t.Errorf("%s: error %+v, expected code %d", tt.name, resp.Error, tt.code)
+		case tt.code == 0 && resp.Error != nil:
+			t.Errorf("%s: %s", tt.name, resp.Error.Message)
+		case tt.code == 0:
+			if err := json.Unmarshal(resp.Result, tt.result); err != nil {
+				t.Errorf("%s: %s", tt.name, err)
+			}
+			results[tt.name] = tt.result
+		}
+	}
+
+	adrs := *results["addresses by index"].(*[]giota.Trytes)
+	if len(adrs) != 2 || adrs[0] != usedAdr.WithChecksum() || adrs[1] != newAdr.WithChecksum() {
+		t.Errorf("getNewAddress() returned %v", adrs)
+	}
+	if adrs := *results["unused address"].(*[]giota.Trytes); len(adrs) != 1 || adrs[0] != giota.Trytes(newAdr) {
+		t.Errorf("getNewAddress() returned %v", adrs)
+	}
+
+	data := results["account data"].(*AccountData)
+	if data.LatestAddress != newAdr || len(data.Addresses) != 1 || data.Balance != 10 ||
+		len(data.Inputs) != 1 || data.Inputs[0].KeyIndex != 0 {
+		t.Errorf("getAccountData() returned %+v", data)
+	}
+	data = results["account data by range"].(*AccountData)
+	if data.Balance != 20 || len(data.Inputs) != 2 || data.Inputs[0].KeyIndex != 2 || data.Inputs[1].KeyIndex != 3 {
+		t.Errorf("getAccountData() returned %+v", data)
+	}
+
+	if res := results["send"].(*SendTransferResult); len(res.Trytes) != 1 || res.Tail.IsValid() != nil {
+		t.Errorf("sendTransfer() returned %+v", res)
+	}
+}
+
+func TestServerBatch(t *testing.T) {
+	api, done := newFakeNode(t)
+	defer done()
+
+	srv := httptest.NewServer(&Server{API: api, Seed: testSeed, Security: giota.SecurityLevelMedium})
+	defer srv.Close()
+
+	var resps []testResponse
+	post(t, srv.URL, `[
+		{"jsonrpc":"2.0","id":1,"method":"getNewAddress","params":{"index":1}},
+		{"jsonrpc":"2.0","method":"getNewAddress"},
+		{"jsonrpc":"2.0","id":2,"method":"foo"}
+	]`, &resps)
+
+	if len(resps) != 2 || resps[0].ID != 1 || resps[0].Error != nil || resps[1].ID != 2 || resps[1].Error == nil {
+		t.Errorf("batch returned %+v", resps)
+	}
+}
+
+func TestServerRejectsCrossOrigin(t *testing.T) {
+	api, done := newFakeNode(t)
+	defer done()
+
+	srv := httptest.NewServer(&Server{API: api, Seed: testSeed, Security: giota.SecurityLevelMedium, Token: "secret"})
+	defer srv.Close()
+
+	const body = `{"jsonrpc":"2.0","id":1,"method":"getNewAddress","params":{"index":1}}`
+	tests := []struct {
+		name   string
+		header map[string]string
+		status int
+	}{
+		{
+			name:   "authorized",
+			header: map[string]string{"Content-Type": "application/json; charset=utf-8", "Authorization": "Bearer secret"},
+			status: http.StatusOK,
+		},
+		{
+			name:   "simple cross-origin request",
+			header: map[string]string{"Content-Type": "text/plain", "Authorization": "Bearer secret"},
+			status: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:   "no Content-Type",
+			header: map[string]string{"Authorization": "Bearer secret"},
+			status: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:   "browser origin",
+			header: map[string]string{"Content-Type": "application/json", "Authorization": "Bearer secret", "Origin": "https://example.com"},
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "no token",
+			header: map[string]string{"Content-Type": "application/json"},
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   "wrong token",
+			header: map[string]string{"Content-Type": "application/json", "Authorization": "Bearer secreT"},
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		req, err := http.NewRequest(http.MethodPost, srv.URL, bytes.NewBufferString(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for k, v := range tt.header {
+			req.Header.Set(k, v)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tt.status {
+			t.Errorf("%s: status %d, expected %d", tt.name, resp.StatusCode, tt.status)
+		}
+	}
+}
+
+func TestServerUnixSocket(t *testing.T) {
+	api, done := newFakeNode(t)
+	defer done()
+
+	path := filepath.Join(t.TempDir(), "jsonrpc.sock")
+	srv := &Server{API: api, Seed: testSeed, Security: giota.SecurityLevelMedium}
+	go srv.ListenAndServe("unix", path)
+
+	c := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			for i := 0; ; i++ {
+				conn, err := d.DialContext(ctx, "unix", path)
+				if err == nil || i == 50 {
+					return conn, err
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+		},
+	}}
+	resp, err := c.Post("http://unix", "application/json",
+		bytes.NewBufferString(`{"jsonrpc":"2.0","id":1,"method":"getNewAddress","params":{"index":1}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var tr testResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tr); err != nil || tr.Error != nil {
+		t.Errorf("getNewAddress() over the unix socket returned %+v, %v", tr.Error, err)
+	}
+}
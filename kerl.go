@@ -27,8 +27,9 @@ package giota
 import (
 	"fmt"
 	"hash"
+	"sync"
 
-	keccak "github.com/tildeleb/hashland/keccakpg"
+	"github.com/iotaledger/giota/internal/keccak"
 )
 
 // Kerl ...
@@ -45,6 +46,32 @@ func NewKerl() *Kerl {
 	return k
 }
 
+// kerlPool reuses Kerl instances for the many short hashes of signing.
+var kerlPool = sync.Pool{
+	New: func() interface{} { return NewKerl() },
+}
+
+// getKerl returns a reset Kerl from the pool. Return it with putKerl.
+func getKerl() *Kerl {
+	k := kerlPool.Get().(*Kerl)
+	k.Reset()
+	return k
+}
+
+func putKerl(k *Kerl) {
+	kerlPool.Put(k)
+}
+
+// hashChain hashes t n times with k and returns the result.
+func hashChain(k *Kerl, t Trits, n int) Trits {
+	for ; n > 0; n-- {
+		k.Reset()
+		k.Absorb(t)
+		t, _ = k.Squeeze(HashSize)
+	}
+	return t
+}
+
 // Squeeze out `length` trits. Length has to be a multiple of TritHashLength.
 func (k *Kerl) Squeeze(length int) (Trits, error) {
 	if length%HashSize != 0 {
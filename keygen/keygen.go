@@ -0,0 +1,194 @@
+// Package keygen generates large numbers of addresses of a seed in parallel,
+// e.g. the deposit addresses of an exchange. A Job checkpoints its progress
+// to a Store, so that it resumes where it stopped after an interruption:
+//
+//	store, err := keygen.NewFileStore("addresses")
+//	job := &keygen.Job{ID: "deposits", Seed: seed, Security: giota.SecurityLevelMedium, Count: 100000, Store: store}
+//	err = job.Run(ctx)
+package keygen
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+
+	"github.com/iotaledger/giota"
+)
+
+// DefaultCheckpointEvery is the number of addresses between checkpoints if
+// Job.CheckpointEvery is zero.
+const DefaultCheckpointEvery = 100
+
+// chunkSize is the number of addresses a worker generates at once.
+const chunkSize = 16
+
+// Store keeps the addresses generated by jobs and their progress. It must be
+// safe for concurrent use by different jobs.
+type Store interface {
+	// Checkpoint returns the index of the next address of job id to
+	// generate, or false if the job has not saved addresses yet.
+	Checkpoint(id string) (next uint64, ok bool, err error)
+	// Save stores adrs of job id, starting at index start, and moves the
+	// checkpoint of the job to the index after them. Saves of a job are
+	// contiguous.
+	Save(id string, start uint64, adrs []giota.Address) error
+}
+
+// Job generates the addresses of Seed from index Start to Start+Count-1.
+type Job struct {
+	// ID identifies the job in the Store.
+	ID       string
+	Seed     giota.Trytes
+	Security giota.SecurityLevel
+	Start    uint64
+	Count    int
+	// Workers is the number of goroutines generating addresses. If zero,
+	// runtime.NumCPU() is used.
+	Workers int
+	// CheckpointEvery is the number of addresses saved at once.
+	CheckpointEvery int
+	Store           Store
+	// Progress, if set, is called after each checkpoint with the number
+	// of addresses generated so far, including the ones of previous runs.
+	Progress func(done, total int)
+}
+
+type chunk struct {
+	start uint64
+	adrs  []giota.Address
+	err   error
+}
+
+// Run generates the addresses of j which are not saved yet. If ctx is
+// canceled, Run saves the addresses received in order so far and returns
+// ctx.Err(); running j again resumes it.
+func (j *Job) Run(ctx context.Context) error {
+	switch {
+	case j.Store == nil:
+		return errors.New("keygen: no store")
+	case j.Count < 0:
+		return errors.New("keygen: negative count")
+	}
+	if err := j.Security.IsValid(); err != nil {
+		return err
+	}
+
+	next, ok, err := j.Store.Checkpoint(j.ID)
+	if err != nil {
+		return err
+	}
+	if !ok || next < j.Start {
+		next = j.Start
+	}
+	end := j.Start + uint64(j.Count)
+	if next >= end {
+		return nil
+	}
+
+	workers := j.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	every := j.CheckpointEvery
+	if every <= 0 {
+		every = DefaultCheckpointEvery
+	}
+
+	wctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	starts := make(chan uint64)
+	go func() {
+		defer close(starts)
+		for s := next; s < end; s += chunkSize {
+			select {
+			case starts <- s:
+			case <-wctx.Done():
+				return
+			}
+		}
+	}()
+
+	results := make(chan chunk)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for s := range starts {
+				if wctx.Err() != nil {
+					return
+				}
+				n := chunkSize
+				if end-s < uint64(n) {
+					n = int(end - s)
+				}
+				adrs, err := giota.NewAddresses(j.Seed, s, n, j.Security)
+				select {
+				case results <- chunk{start: s, adrs: adrs, err: err}:
+				case <-wctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// chunks arrive out of order, so they wait in pending until the ones
+	// before them are done
+	pending := make(map[uint64][]giota.Address)
+	var done []giota.Address
+	var runErr error
+	save := func() {
+		if err := j.Store.Save(j.ID, next, done); err != nil {
+			runErr = err
+			cancel()
+			return
+		}
+		next += uint64(len(done))
+		done = nil
+		if j.Progress != nil {
+			j.Progress(int(next-j.Start), j.Count)
+		}
+	}
+
+	for c := range results {
+		// drain the workers once canceled
+		if runErr != nil || wctx.Err() != nil {
+			continue
+		}
+		if c.err != nil {
+			runErr = c.err
+			cancel()
+			continue
+		}
+
+		pending[c.start] = c.adrs
+		for {
+			adrs, ok := pending[next+uint64(len(done))]
+			if !ok {
+				break
+			}
+			delete(pending, next+uint64(len(done)))
+			done = append(done, adrs...)
+		}
+		if len(done) >= every {
+			save()
+		}
+	}
+
+	if runErr == nil && len(done) > 0 {
+		save()
+	}
+	if runErr != nil {
+		return runErr
+	}
+	if next < end {
+		return ctx.Err()
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package keygen
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/iotaledger/giota"
+)
+
+var testSeed = giota.Trytes(strings.Repeat("KEYGEN9", 12)[:81])
+
+// cancelStore cancels a job after its first save.
+type cancelStore struct {
+	Store
+	cancel context.CancelFunc
+	saves  int
+}
+
+func (s *cancelStore) Save(id string, start uint64, adrs []giota.Address) error {
+	s.saves++
+	if s.saves == 1 {
+		s.cancel()
+	}
+	return s.Store.Save(id, start, adrs)
+}
+
+func TestJob(t *testing.T) {
+	want, err := giota.NewAddresses(testSeed, 3, 40, giota.SecurityLevelLow)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fs, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ms := NewMemoryStore()
+	stores := []struct {
+		name      string
+		store     Store
+		addresses func() ([]giota.Address, error)
+	}{
+		{name: "memory", store: ms, addresses: func() ([]giota.Address, error) { return ms.Addresses("job"), nil }},
+		{name: "file", store: fs, addresses: func() ([]giota.Address, error) { return fs.Addresses("job") }},
+	}
+
+	for _, s := range stores {
+		ctx, cancel := context.WithCancel(context.Background())
+		var progress int
+		job := &Job{
+			ID:              "job",
+			Seed:            testSeed,
+			Security:        giota.SecurityLevelLow,
+			Start:           3,
+			Count:           40,
+			Workers:         1,
+			CheckpointEvery: 10,
+			Store:           &cancelStore{Store: s.store, cancel: cancel},
+			Progress:        func(done, total int) { progress = done },
+		}
+
+		// the job is interrupted after its first checkpoint, one worker
+		// keeps the chunks in order
+		if err := job.Run(ctx); err != context.Canceled {
+			t.Errorf("%s: Run() = %v, expected context.Canceled", s.name, err)
+		}
+		next, ok, err := s.store.Checkpoint("job")
+		if err != nil || !ok || next != 19 || uint64(progress) != next-3 {
+			t.Errorf("%s: Checkpoint() = %d, %v, %v after progress %d", s.name, next, ok, err, progress)
+		}
+
+		job.Workers = 3
+		if err := job.Run(context.Background()); err != nil {
+			t.Errorf("%s: Run() = %v", s.name, err)
+		}
+		got, err := s.addresses()
+		if err != nil || !reflect.DeepEqual(got, want) || progress != 40 {
+			t.Errorf("%s: generated %d addresses, %v, progress %d", s.name, len(got), err, progress)
+		}
+
+		// a done job is not run again
+		if err := job.Run(context.Background()); err != nil {
+			t.Errorf("%s: Run() = %v", s.name, err)
+		}
+	}
+}
+
+func TestJobInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		job  Job
+	}{
+		{name: "no store", job: Job{Seed: testSeed, Security: giota.SecurityLevelLow, Count: 1}},
+		{name: "security", job: Job{Seed: testSeed, Count: 1, Store: NewMemoryStore()}},
+		{name: "seed", job: Job{Seed: "SEED", Security: giota.SecurityLevelLow, Count: 1, Store: NewMemoryStore()}},
+		{name: "count", job: Job{Seed: testSeed, Security: giota.SecurityLevelLow, Count: -1, Store: NewMemoryStore()}},
+	}
+	for _, tt := range tests {
+		if err := tt.job.Run(context.Background()); err == nil {
+			t.Errorf("%s: Run() should fail", tt.name)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package keygen
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/iotaledger/giota"
+)
+
+// MemoryStore is a Store in memory, e.g. for tests.
+type MemoryStore struct {
+	mu   sync.Mutex
+	jobs map[string]*memoryJob
+}
+
+type memoryJob struct {
+	start uint64
+	adrs  []giota.Address
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{jobs: make(map[string]*memoryJob)}
+}
+
+// Checkpoint implements Store.
+func (s *MemoryStore) Checkpoint(id string) (uint64, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return 0, false, nil
+	}
+	return j.start + uint64(len(j.adrs)), true, nil
+}
+
+// Save implements Store.
+func (s *MemoryStore) Save(id string, start uint64, adrs []giota.Address) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		j = &memoryJob{start: start}
+		s.jobs[id] = j
+	}
+	if next := j.start + uint64(len(j.adrs)); start != next {
+		return fmt.Errorf("keygen: addresses of %s saved at %d, expected %d", id, start, next)
+	}
+	j.adrs = append(j.adrs, adrs...)
+	return nil
+}
+
+// Addresses returns the addresses of job id saved so far.
+func (s *MemoryStore) Addresses(id string) []giota.Address {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if j, ok := s.jobs[id]; ok {
+		return append([]giota.Address(nil), j.adrs...)
+	}
+	return nil
+}
+
+// lineSize is the size of an address and its newline in the files of a
+// FileStore.
+const lineSize = 82
+
+// FileStore is a Store in a directory. The addresses of a job are in the file
+// <id>.addresses, one per line, and its checkpoint in <id>.checkpoint. The
+// checkpoint is written after the addresses, so a crash in between only
+// leaves addresses which are generated again.
+type FileStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+type fileCheckpoint struct {
+	Start uint64 `json:"start"`
+	Next  uint64 `json:"next"`
+}
+
+// NewFileStore returns a store in dir, which is created if needed.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+func (s *FileStore) path(id, ext string) string {
+	return filepath.Join(s.dir, id+ext)
+}
+
+func (s *FileStore) checkpoint(id string) (*fileCheckpoint, error) {
+	b, err := ioutil.ReadFile(s.path(id, ".checkpoint"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	cp := &fileCheckpoint{}
+	if err := json.Unmarshal(b, cp); err != nil {
+		return nil, fmt.Errorf("keygen: checkpoint of %s: %w", id, err)
+	}
+	return cp, nil
+}
+
+// Checkpoint implements Store.
+func (s *FileStore) Checkpoint(id string) (uint64, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cp, err := s.checkpoint(id)
+	if err != nil || cp == nil {
+		return 0, false, err
+	}
+	return cp.Next, true, nil
+}
+
+// Save implements Store.
+func (s *FileStore) Save(id string, start uint64, adrs []giota.Address) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cp, err := s.checkpoint(id)
+	if err != nil {
+		return err
+	}
+	if cp == nil {
+		cp = &fileCheckpoint{Start: start, Next: start}
+	}
+	if start != cp.Next {
+		return fmt.Errorf("keygen: addresses of %s saved at %d, expected %d", id, start, cp.Next)
+	}
+
+	f, err := os.OpenFile(s.path(id, ".addresses"), os.O_WRONLY|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	b := make([]byte, 0, len(adrs)*lineSize)
+	for _, adr := range adrs {
+		b = append(b, adr...)
+		b = append(b, '\n')
+	}
+	// addresses after the checkpoint left by a crash are overwritten
+	_, err = f.WriteAt(b, int64(start-cp.Start)*lineSize)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+
+	cp.Next = start + uint64(len(adrs))
+	b, err = json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	tmp := s.path(id, ".checkpoint.tmp")
+	if err := ioutil.WriteFile(tmp, b, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path(id, ".checkpoint"))
+}
+
+// Addresses returns the addresses of job id saved up to its checkpoint.
+func (s *FileStore) Addresses(id string) ([]giota.Address, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cp, err := s.checkpoint(id)
+	if err != nil || cp == nil {
+		return nil, err
+	}
+	b, err := ioutil.ReadFile(s.path(id, ".addresses"))
+	if err != nil {
+		return nil, err
+	}
+	n := int(cp.Next - cp.Start)
+	if len(b) < n*lineSize {
+		return nil, fmt.Errorf("keygen: addresses of %s are truncated", id)
+	}
+	adrs := make([]giota.Address, n)
+	for i := range adrs {
+		adrs[i] = giota.Address(b[i*lineSize : i*lineSize+81])
+	}
+	return adrs, nil
+}
@@ -0,0 +1,204 @@
+package giota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultBreakerCooldown is the time the circuit breaker of an API stays open
+// if Limits.Cooldown is zero.
+const DefaultBreakerCooldown = 30 * time.Second
+
+var (
+	// ErrNodeUnhealthy is returned by API calls without contacting the node
+	// while the circuit breaker is open, see Limits.
+	ErrNodeUnhealthy = errors.New("node is unhealthy")
+	// ErrRequestTooLarge is returned by API calls if the request exceeds
+	// Limits.MaxRequestSize.
+	ErrRequestTooLarge = errors.New("request is too large")
+)
+
+// Limits are guardrails for the calls of an API. Zero values disable a limit.
+type Limits struct {
+	// MaxRequestSize is the max size of a request in bytes.
+	MaxRequestSize int64
+	// MaxResponseSize is the max size of a response in bytes, see
+	// SetMaxResponseSize.
+	MaxResponseSize int64
+	// Timeout is the max duration of a call, including reading the
+	// response.
+	Timeout time.Duration
+	// MaxFailures is the number of consecutive failures of the node after
+	// which the circuit breaker opens: calls fail with ErrNodeUnhealthy
+	// for Cooldown, then the node is tried again. Failures are network
+	// errors, timeouts and 5xx responses, but not errors the node reports
+	// for invalid requests.
+	MaxFailures int
+	Cooldown    time.Duration
+}
+
+// SetLimits sets the limits of the calls of api and closes its circuit
+// breaker. It must not be called concurrently with API calls.
+func (api *API) SetLimits(l Limits) {
+	api.limits = l
+	api.maxResponseSize = l.MaxResponseSize
+	api.breaker.reset()
+}
+
+// Limits returns the limits of the calls of api.
+func (api *API) Limits() Limits {
+	l := api.limits
+	l.MaxResponseSize = api.maxResponseSize
+	return l
+}
+
+// breaker counts the consecutive failures of a node.
+type breaker struct {
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+}
+
+func (b *breaker) reset() {
+	b.mu.Lock()
+	b.failures = 0
+	b.openUntil = time.Time{}
+	b.mu.Unlock()
+}
+
+func (b *breaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if time.Now().Before(b.openUntil) {
+		return ErrNodeUnhealthy
+	}
+	return nil
+}
+
+// record counts a failed or successful call. The breaker opens once max
+// calls in a row failed, and again after each failure while it is half-open.
+func (b *breaker) record(failed bool, max int, cooldown time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !failed {
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if max > 0 && b.failures >= max {
+		if cooldown <= 0 {
+			cooldown = DefaultBreakerCooldown
+		}
+		b.openUntil = time.Now().Add(cooldown)
+	}
+}
+
+// cancelBody cancels the context of a request when the response is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// roundTrip sends req of cmd to the node within the limits of api, retrying
+// it as configured by WithRetry.
+func (api *API) roundTrip(req *http.Request, cmd interface{}) (*http.Response, error) {
+	l := api.limits
+	if l.MaxRequestSize > 0 && req.ContentLength > l.MaxRequestSize {
+		return nil, fmt.Errorf("%d bytes: %w", req.ContentLength, ErrRequestTooLarge)
+	}
+
+	cancel := context.CancelFunc(func() {})
+	if l.Timeout > 0 {
+		var ctx context.Context
+		ctx, cancel = context.WithTimeout(req.Context(), l.Timeout)
+		req = req.WithContext(ctx)
+	}
+
+	backoff := api.retry.Backoff
+	for attempt := 1; ; attempt++ {
+		resp, err := api.tryRoundTrip(req, cmd)
+		if attempt >= api.retry.Attempts || !retryable(resp, err) || req.GetBody == nil {
+			if err != nil {
+				cancel()
+				return nil, err
+			}
+			resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+			return resp, nil
+		}
+
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if api.logger != nil {
+			api.logger.Printf("giota: retrying %s in %s", commandName(cmd), backoff)
+		}
+		select {
+		case <-time.After(backoff):
+		case <-req.Context().Done():
+			cancel()
+			return nil, req.Context().Err()
+		}
+		backoff *= 2
+
+		if req.Body, err = req.GetBody(); err != nil {
+			cancel()
+			return nil, err
+		}
+	}
+}
+
+// tryRoundTrip sends req once.
+func (api *API) tryRoundTrip(req *http.Request, cmd interface{}) (*http.Response, error) {
+	l := api.limits
+	if err := api.breaker.allow(); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	resp, err := api.client.Do(req)
+	if err != nil {
+		api.breaker.record(true, l.MaxFailures, l.Cooldown)
+		if api.logger != nil {
+			api.logger.Printf("giota: %s failed: %s", commandName(cmd), err)
+		}
+		return nil, err
+	}
+
+	d := time.Since(start)
+	api.breaker.record(resp.StatusCode >= 500, l.MaxFailures, l.Cooldown)
+	api.reportCall(cmd, resp, d)
+	if api.logger != nil {
+		api.logger.Printf("giota: %s: status %d in %s", commandName(cmd), resp.StatusCode, d)
+	}
+	return resp, nil
+}
+
+// retryable returns true if a call failed with a network error or a
+// response telling to try again later.
+func retryable(resp *http.Response, err error) bool {
+	switch {
+	case err == ErrNodeUnhealthy || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+		return false
+	case err != nil:
+		return true
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package giota
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLimits(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits Limits
+		status int
+		delay  time.Duration
+		// errs are the expected errors of consecutive calls, nil for any
+		// error other than the listed ones
+		errs []error
+		hits int
+	}{
+		{
+			name:   "request too large",
+			limits: Limits{MaxRequestSize: 10},
+			status: http.StatusOK,
+			errs:   []error{ErrRequestTooLarge},
+		},
+		{
+			name:   "timeout",
+			limits: Limits{Timeout: 10 * time.Millisecond},
+			status: http.StatusOK,
+			delay:  time.Second,
+			errs:   []error{nil},
+			hits:   1,
+		},
+		{
+			name:   "breaker opens",
+			limits: Limits{MaxFailures: 2, Cooldown: time.Hour},
+			status: http.StatusInternalServerError,
+			errs:   []error{nil, nil, ErrNodeUnhealthy, ErrNodeUnhealthy},
+			hits:   2,
+		},
+		{
+			name:   "invalid requests don't open the breaker",
+			limits: Limits{MaxFailures: 1, Cooldown: time.Hour},
+			status: http.StatusBadRequest,
+			errs:   []error{nil, nil, nil},
+			hits:   3,
+		},
+	}
+
+	for _, tt := range tests {
+		var hits int
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hits++
+			select {
+			case <-time.After(tt.delay):
+			case <-r.Context().Done():
+			}
+			w.WriteHeader(tt.status)
+			json.NewEncoder(w).Encode(&ErrorResponse{Error: "failed"})
+		}))
+
+		api := NewAPI(srv.URL, nil)
+		api.SetLimits(tt.limits)
+		for i, want := range tt.errs {
+			_, err := api.GetNodeInfo()
+			switch {
+			case err == nil:
+				t.Errorf("%s: call %d succeeded", tt.name, i)
+			case want != nil && !errors.Is(err, want):
+				t.Errorf("%s: call %d returned %v, expected %v", tt.name, i, err, want)
+			case want == nil && (errors.Is(err, ErrNodeUnhealthy) || errors.Is(err, ErrRequestTooLarge)):
+				t.Errorf("%s: call %d returned %v", tt.name, i, err)
+			}
+		}
+		srv.Close()
+
+		if hits != tt.hits {
+			t.Errorf("%s: node was called %d times, expected %d", tt.name, hits, tt.hits)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package giota
+
+import (
+	"fmt"
+	"time"
+)
+
+// lintMaxFutureSkew is the tolerance for timestamps ahead of the local clock.
+const lintMaxFutureSkew = 10 * time.Minute
+
+// LintWarning is a likely mistake in a bundle found by Lint. Unlike the
+// errors of IsValid, it doesn't necessarily make the bundle invalid.
+type LintWarning struct {
+	// Index is the index of the offending transaction, or -1 if the warning
+	// concerns the whole bundle.
+	Index   int
+	Message string
+}
+
+func (w LintWarning) String() string {
+	if w.Index < 0 {
+		return w.Message
+	}
+	return fmt.Sprintf("tx %d: %s", w.Index, w.Message)
+}
+
+// Lint checks bs for common mistakes before it is attached. It returns the
+// warnings found and, separately, the error of IsValid if bs is invalid.
+// nolint: gocyclo
+func (bs Bundle) Lint() ([]LintWarning, error) {
+	var ws []LintWarning
+	warn := func(i int, format string, args ...interface{}) {
+		ws = append(ws, LintWarning{Index: i, Message: fmt.Sprintf(format, args...)})
+	}
+
+	h := bs.Hash()
+	now := time.Now()
+	inputs := make(map[Address]bool)
+	var total int64
+
+	for i := range bs {
+		tx := &bs[i]
+		total += tx.Value
+
+		if i > 0 && tx.ObsoleteTag != tx.Tag {
+			warn(i, "obsolete tag %s doesn't match tag %s", tx.ObsoleteTag, tx.Tag)
+		}
+		if tx.Timestamp.After(now.Add(lintMaxFutureSkew)) {
+			warn(i, "timestamp %s is in the future", tx.Timestamp)
+		}
+
+		if tx.Value >= 0 {
+			continue
+		}
+		inputs[tx.Address] = true
+
+		frags := []Trytes{tx.SignatureMessageFragment}
+		for j := i + 1; j < len(bs) && bs[j].Address == tx.Address && bs[j].Value == 0; j++ {
+			frags = append(frags, bs[j].SignatureMessageFragment)
+		}
+		if len(frags) > 3 {
+			warn(i, "input has %d signature fragments, security levels are 1 to 3", len(frags))
+		}
+
+		switch {
+		case isEmptyFragment(frags):
+			warn(i, "input is not signed")
+		case !IsValidSig(tx.Address, frags, Trytes(h)):
+			warn(i, "input fragments contain a message or a wrong signature")
+		}
+	}
+
+	for i := range bs {
+		if bs[i].Value > 0 && inputs[bs[i].Address] {
+			warn(i, "output sends to the spent input address %s", bs[i].Address)
+		}
+	}
+	if total < 0 {
+		warn(-1, "inputs exceed outputs by %d, remainder is missing", -total)
+	}
+
+	return ws, bs.IsValid()
+}
+
+func isEmptyFragment(frags []Trytes) bool {
+	for _, f := range frags {
+		for _, c := range f {
+			if c != '9' {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,91 @@
+package giota
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func lintTestBundle(t *testing.T, remainder bool, prepare func(Bundle)) Bundle {
+	in := AddressInfo{Seed: accountTestSeed, Index: 0, Security: 2}
+	adr, err := in.Address()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	var bs Bundle
+	bs.Add(1, filterAddr1, 60, now, "LINT")
+	bs.Add(2, adr, -100, now, "")
+	if remainder {
+		bs.Add(1, filterAddr2, 40, now, "")
+	}
+	if prepare != nil {
+		prepare(bs)
+	}
+	bs.Finalize(nil)
+
+	keys, err := NewKeys([]AddressInfo{in})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bs.SignInputs(keys); err != nil {
+		t.Fatal(err)
+	}
+	return bs
+}
+
+func TestBundleLint(t *testing.T) {
+	tests := []struct {
+		name     string
+		prepare  func(bs Bundle)
+		mutate   func(bs Bundle)
+		noRemain bool
+		warning  string
+		invalid  bool
+	}{
+		{name: "clean", mutate: func(Bundle) {}},
+		{name: "missing remainder", noRemain: true, warning: "remainder is missing", invalid: true},
+		{
+			name:    "unsigned",
+			mutate:  func(bs Bundle) { bs[1].SignatureMessageFragment, bs[2].SignatureMessageFragment = emptySig, emptySig },
+			warning: "not signed",
+			invalid: true,
+		},
+		{
+			name:    "message in input",
+			mutate:  func(bs Bundle) { bs[1].SignatureMessageFragment = pad("HELLO", SignatureMessageFragmentTrinarySize/3) },
+			warning: "contain a message",
+			invalid: true,
+		},
+		{
+			name:    "obsolete tag",
+			prepare: func(bs Bundle) { bs[3].ObsoleteTag = Tag(pad("OTHER", TagTrinarySize/3)) },
+			warning: "obsolete tag",
+		},
+		{
+			name:    "future",
+			prepare: func(bs Bundle) { bs[3].Timestamp = time.Now().Add(time.Hour) },
+			warning: "in the future",
+		},
+	}
+
+	for _, tt := range tests {
+		bs := lintTestBundle(t, !tt.noRemain, tt.prepare)
+		if tt.mutate != nil {
+			tt.mutate(bs)
+		}
+
+		ws, err := bs.Lint()
+		if (err != nil) != tt.invalid {
+			t.Errorf("%s: Lint() returned error %v", tt.name, err)
+		}
+
+		switch {
+		case tt.warning == "" && len(ws) != 0:
+			t.Errorf("%s: Lint() returned warnings %v", tt.name, ws)
+		case tt.warning != "" && (len(ws) == 0 || !strings.Contains(ws[0].String(), tt.warning)):
+			t.Errorf("%s: Lint() returned warnings %v, expected %q", tt.name, ws, tt.warning)
+		}
+	}
+}
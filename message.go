@@ -0,0 +1,50 @@
+package giota
+
+import (
+	"errors"
+)
+
+// ErrInvalidMessage is returned by DecodeMessage if the trytes are not an
+// encoded message.
+var ErrInvalidMessage = errors.New("trytes are not an encoded message")
+
+// EncodeMessage encodes b into trytes to be used as message, two trytes per
+// byte like the ASCII encoding of the IOTA libraries of other languages.
+func EncodeMessage(b []byte) Trytes {
+	t := make([]byte, len(b)*2)
+	for i, c := range b {
+		t[i*2] = TryteAlphabet[c%27]
+		t[i*2+1] = TryteAlphabet[c/27]
+	}
+	return Trytes(t)
+}
+
+// DecodeMessage decodes trytes encoded by EncodeMessage. Note that padding a
+// message with 9s appends zero bytes, so the length of the message must be
+// known or the zero bytes must be trimmed.
+func DecodeMessage(t Trytes) ([]byte, error) {
+	if len(t)%2 != 0 {
+		return nil, ErrInvalidMessage
+	}
+
+	b := make([]byte, len(t)/2)
+	for i := range b {
+		lo, hi := tryteValue(t[i*2]), tryteValue(t[i*2+1])
+		if lo < 0 || hi < 0 || lo+hi*27 > 255 {
+			return nil, ErrInvalidMessage
+		}
+		b[i] = byte(lo + hi*27)
+	}
+	return b, nil
+}
+
+// tryteValue returns the index of c in TryteAlphabet, or -1.
+func tryteValue(c byte) int {
+	switch {
+	case c == '9':
+		return 0
+	case 'A' <= c && c <= 'Z':
+		return int(c-'A') + 1
+	}
+	return -1
+}
@@ -0,0 +1,68 @@
+package giota
+
+// messageDomain is prepended to signed messages, so their hashes can't be
+// mistaken for bundle hashes.
+var messageDomain = EncodeMessage([]byte("IOTA Signed Message:"))
+
+// MessageHash returns the hash signed by SignMessage. Like a bundle hash, it
+// is chosen so that its normalized form doesn't contain 13 ('M'), which
+// would reveal a part of the key.
+func MessageHash(message []byte) Trytes {
+	in := messageDomain + EncodeMessage(message)
+	in = pad(in, (len(in)+HashSize/3-1)/(HashSize/3)*(HashSize/3))
+	trits := append(in.Trits(), make(Trits, HashSize)...)
+	counter := trits[len(trits)-HashSize:]
+
+	k := getKerl()
+	defer putKerl(k)
+
+	for {
+		k.Reset()
+		k.Absorb(trits)
+		h, _ := k.Squeeze(HashSize)
+
+		t := h.Trytes()
+		if !HasM(t.Normalize()) {
+			return t
+		}
+		IncTrits(counter)
+	}
+}
+
+// SignMessage signs message with the key of seed at index, which proves that
+// the signer owns the address at index.
+//
+// Like signing a bundle, signing a message reveals a part of the key. An
+// address which signed a message must be treated as spent: no funds should be
+// sent from it, and it should not sign anything else, especially no bundle.
+// Use addresses dedicated to identification for SignMessage.
+func SignMessage(seed Trytes, index uint64, security SecurityLevel, message []byte) ([]Trytes, error) {
+	key, err := NewKey(seed, index, security)
+	if err != nil {
+		return nil, err
+	}
+
+	normalized, err := MessageHash(message).ToNormalized()
+	if err != nil {
+		return nil, err
+	}
+	sig := make([]Trytes, security)
+	for i := range sig {
+		sig[i] = Sign(NormalizedFragment(normalized, i), key[i*keyFragmentSize:(i+1)*keyFragmentSize])
+	}
+	return sig, nil
+}
+
+// VerifyMessage returns true if sig is a signature of message by the key of
+// adr, as created by SignMessage.
+func VerifyMessage(adr Address, sig []Trytes, message []byte) bool {
+	if len(sig) == 0 || SecurityLevel(len(sig)).IsValid() != nil {
+		return false
+	}
+	for _, s := range sig {
+		if len(s) != keyFragmentSize || s.IsValid() != nil {
+			return false
+		}
+	}
+	return IsValidSig(adr, sig, MessageHash(message))
+}
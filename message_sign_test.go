@@ -0,0 +1,53 @@
+package giota
+
+import (
+	"testing"
+)
+
+func TestSignMessage(t *testing.T) {
+	msg := []byte("I own this address")
+
+	adr1, err := NewAddress(accountTestSeed, 0, SecurityLevelLow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	adr2, err := NewAddress(accountTestSeed, 1, SecurityLevelMedium)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sig1, err := SignMessage(accountTestSeed, 0, SecurityLevelLow, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig2, err := SignMessage(accountTestSeed, 1, SecurityLevelMedium, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		adr   Address
+		sig   []Trytes
+		msg   []byte
+		valid bool
+	}{
+		{name: "security 1", adr: adr1, sig: sig1, msg: msg, valid: true},
+		{name: "security 2", adr: adr2, sig: sig2, msg: msg, valid: true},
+		{name: "other message", adr: adr2, sig: sig2, msg: []byte("I own that address")},
+		{name: "other address", adr: adr1, sig: sig2, msg: msg},
+		{name: "truncated signature", adr: adr2, sig: sig2[:1], msg: msg},
+		{name: "malformed signature", adr: adr2, sig: []Trytes{sig2[0][1:], sig2[1]}, msg: msg},
+		{name: "no signature", adr: adr2, msg: msg},
+	}
+
+	for _, tt := range tests {
+		if valid := VerifyMessage(tt.adr, tt.sig, tt.msg); valid != tt.valid {
+			t.Errorf("%s: VerifyMessage() = %v, expected %v", tt.name, valid, tt.valid)
+		}
+	}
+
+	if h := MessageHash(msg); HasM(h.Normalize()) {
+		t.Errorf("MessageHash() returned %s with 13 in its normalized form", h)
+	}
+}
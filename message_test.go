@@ -0,0 +1,37 @@
+package giota
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEncodeMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      []byte
+		trytes  Trytes
+		wantErr bool
+	}{
+		{name: "empty", in: []byte{}, trytes: ""},
+		{name: "ascii", in: []byte("IOTA"), trytes: "SBYBCCKB"},
+		{name: "binary", in: []byte{0, 26, 27, 255}, trytes: "99Z99ALI"},
+	}
+
+	for _, tt := range tests {
+		tr := EncodeMessage(tt.in)
+		if tr != tt.trytes {
+			t.Errorf("%s: EncodeMessage() = %s, expected %s", tt.name, tr, tt.trytes)
+		}
+
+		b, err := DecodeMessage(tr)
+		if err != nil || !bytes.Equal(b, tt.in) {
+			t.Errorf("%s: DecodeMessage() = %v, %v", tt.name, b, err)
+		}
+	}
+
+	for _, tr := range []Trytes{"A", "ZZ", "a9"} {
+		if _, err := DecodeMessage(tr); err != ErrInvalidMessage {
+			t.Errorf("DecodeMessage(%s) returned %v", tr, err)
+		}
+	}
+}
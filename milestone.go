@@ -0,0 +1,86 @@
+package giota
+
+import (
+	"errors"
+	"fmt"
+)
+
+// milestoneIndexTrinarySize is the number of trits at the beginning of the
+// obsolete tag of a milestone which hold the milestone index.
+const milestoneIndexTrinarySize = 15
+
+var (
+	// ErrNoCoordinator is returned if a milestone must be looked up but the
+	// coordinator of the API is not set.
+	ErrNoCoordinator = errors.New("coordinator address is not set")
+	// ErrMilestoneNotFound is returned if the node doesn't know the milestone.
+	ErrMilestoneNotFound = errors.New("milestone is not found")
+)
+
+// SetCoordinator sets the address of the coordinator used to look up
+// milestones, e.g. Mainnet.Coordinator. It must not be called concurrently with
+// API calls.
+func (api *API) SetCoordinator(adr Address) {
+	api.coordinator = adr
+}
+
+// GetMilestone returns the hash of the milestone with index. The latest and
+// the latest solid milestone are taken from getNodeInfo, older milestones are
+// searched among the transactions of the coordinator.
+func (api *API) GetMilestone(index int64) (Hash, error) {
+	ni, err := api.GetNodeInfo()
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case index <= 0 || index > ni.LatestMilestoneIndex:
+		return "", fmt.Errorf("milestone %d is not issued yet", index)
+	case index == ni.LatestMilestoneIndex:
+		return ni.LatestMilestone, nil
+	case index == ni.LatestSolidSubtangleMilestoneIndex:
+		return ni.LatestSolidSubtangleMilestone, nil
+	case api.coordinator == "":
+		return "", ErrNoCoordinator
+	}
+
+	// milestones carry their index in the obsolete tag
+	tag := Int2Trits(index, TagTrinarySize).Trytes()
+	ft, err := api.FindTransactions(&FindTransactionsRequest{
+		Addresses: []Address{api.coordinator},
+		Tags:      []Trytes{tag},
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(ft.Hashes) == 0 {
+		return "", ErrMilestoneNotFound
+	}
+
+	gt, err := api.GetTrytes(ft.Hashes)
+	if err != nil {
+		return "", err
+	}
+	for i := range gt.Trytes {
+		tx := &gt.Trytes[i]
+		if tx.Address == api.coordinator && tx.CurrentIndex == 0 && milestoneIndexOf(tx) == index {
+			return tx.Hash(), nil
+		}
+	}
+	return "", ErrMilestoneNotFound
+}
+
+func milestoneIndexOf(tx *Transaction) int64 {
+	return tx.ObsoleteTag.Trits()[:milestoneIndexTrinarySize].Int()
+}
+
+// GetInclusionStatesSince returns whether txs are confirmed by the milestone
+// with milestoneIndex, i.e. whether they were confirmed at the latest by that
+// milestone.
+func (api *API) GetInclusionStatesSince(txs []Hash, milestoneIndex int64) (*GetInclusionStatesResponse, error) {
+	ms, err := api.GetMilestone(milestoneIndex)
+	if err != nil {
+		return nil, err
+	}
+	return api.GetInclusionStates(txs, []Hash{ms})
+}
@@ -0,0 +1,66 @@
+package giota
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestGetInclusionStatesSince(t *testing.T) {
+	ms := filterTestBundle()[0]
+	ms.Address = Mainnet.Coordinator
+	ms.ObsoleteTag = Tag(Int2Trits(50, ObsoleteTagTrinarySize).Trytes())
+
+	var tips []Hash
+	api, done := newFakeNode(t, map[string]fakeNodeHandler{
+		"getNodeInfo": func(map[string]json.RawMessage) interface{} {
+			return &GetNodeInfoResponse{
+				LatestMilestone:                    EmptyHash,
+				LatestMilestoneIndex:               100,
+				LatestSolidSubtangleMilestone:      filterTestBundle()[1].Hash(),
+				LatestSolidSubtangleMilestoneIndex: 99,
+			}
+		},
+		"findTransactions": func(map[string]json.RawMessage) interface{} {
+			return &FindTransactionsResponse{Hashes: []Hash{filterTestBundle()[2].Hash(), ms.Hash()}}
+		},
+		"getTrytes": func(map[string]json.RawMessage) interface{} {
+			return &GetTrytesResponse{Trytes: []Transaction{filterTestBundle()[2], ms}}
+		},
+		"getInclusionStates": func(req map[string]json.RawMessage) interface{} {
+			json.Unmarshal(req["tips"], &tips)
+			return &GetInclusionStatesResponse{States: []bool{true}}
+		},
+	})
+	defer done()
+
+	tests := []struct {
+		name    string
+		index   int64
+		coo     Address
+		tip     Hash
+		wantErr error
+	}{
+		{name: "latest", index: 100, tip: EmptyHash},
+		{name: "latest solid", index: 99, tip: filterTestBundle()[1].Hash()},
+		{name: "old", index: 50, coo: Mainnet.Coordinator, tip: ms.Hash()},
+		{name: "no coordinator", index: 50, wantErr: ErrNoCoordinator},
+		{name: "unknown", index: 51, coo: Mainnet.Coordinator, wantErr: ErrMilestoneNotFound},
+	}
+
+	for _, tt := range tests {
+		tips = nil
+		api.SetCoordinator(tt.coo)
+
+		_, err := api.GetInclusionStatesSince([]Hash{EmptyHash}, tt.index)
+		switch {
+		case err != tt.wantErr:
+			t.Errorf("%s: GetInclusionStatesSince() returned error %v, expected %v", tt.name, err, tt.wantErr)
+		case err == nil && (len(tips) != 1 || tips[0] != tt.tip):
+			t.Errorf("%s: GetInclusionStatesSince() used tips %v", tt.name, tips)
+		}
+	}
+
+	if _, err := api.GetInclusionStatesSince([]Hash{EmptyHash}, 101); err == nil {
+		t.Error("GetInclusionStatesSince() should fail for a future milestone")
+	}
+}
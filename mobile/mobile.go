@@ -0,0 +1,211 @@
+// Package mobile wraps address generation, transfer preparation and sending
+// of giota for gomobile bindings, so that Android and iOS wallets can embed
+// giota:
+//
+//	gomobile bind -target android github.com/iotaledger/giota/mobile
+//
+// Its funcs only take and return strings, byte slices, numbers and pointers
+// to the types of this package, which gomobile can bind. Trytes, addresses
+// and hashes are strings; addresses may have checksums.
+package mobile
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/iotaledger/giota"
+)
+
+// NewAddress returns the address of seed with index and security, with its
+// checksum if checksum is true. index is signed as gomobile can't bind
+// unsigned integers; it must not be negative.
+func NewAddress(seed string, index int64, security int, checksum bool) (string, error) {
+	s, err := giota.ToTrytes(seed)
+	if err != nil {
+		return "", err
+	}
+	if index < 0 {
+		return "", errors.New("index must not be negative")
+	}
+	adr, err := giota.NewAddress(s, uint64(index), giota.SecurityLevel(security))
+	if err != nil {
+		return "", err
+	}
+	if checksum {
+		return string(adr.WithChecksum()), nil
+	}
+	return string(adr), nil
+}
+
+// NewSeed returns a random seed.
+func NewSeed() string {
+	return string(giota.NewSeed())
+}
+
+// Transfers is a list of transfers built by Add, since gomobile can't bind
+// slices of structs.
+type Transfers struct {
+	trs []giota.Transfer
+}
+
+// NewTransfers returns an empty list of transfers.
+func NewTransfers() *Transfers {
+	return &Transfers{}
+}
+
+// Add adds a transfer of value to address with the text message and tag,
+// which may be empty.
+func (t *Transfers) Add(address string, value int64, message string, tag string) error {
+	adr, err := parseAddress(address)
+	if err != nil {
+		return err
+	}
+	tg, err := giota.ToTrytes(tag)
+	if tag != "" && err != nil {
+		return err
+	}
+	t.trs = append(t.trs, giota.Transfer{
+		Address: adr,
+		Value:   value,
+		Message: giota.EncodeMessage([]byte(message)),
+		Tag:     tg,
+	})
+	return nil
+}
+
+// Len returns the number of transfers.
+func (t *Transfers) Len() int {
+	return len(t.trs)
+}
+
+// Bundle is a bundle of transactions.
+type Bundle struct {
+	bdl giota.Bundle
+}
+
+// Len returns the number of transactions of b.
+func (b *Bundle) Len() int {
+	return len(b.bdl)
+}
+
+// Trytes returns the trytes of the i-th transaction of b.
+func (b *Bundle) Trytes(i int) string {
+	return string(b.bdl[i].Trytes())
+}
+
+// Hash returns the bundle hash of b.
+func (b *Bundle) Hash() string {
+	if len(b.bdl) == 0 {
+		return ""
+	}
+	return string(b.bdl[0].Bundle)
+}
+
+// Tail returns the hash of the tail transaction of b.
+func (b *Bundle) Tail() string {
+	if len(b.bdl) == 0 {
+		return ""
+	}
+	return string(b.bdl[0].Hash())
+}
+
+// Client calls a node.
+type Client struct {
+	api *giota.API
+	// MWM is the min weight magnitude of the PoW. If zero,
+	// giota.DefaultMinWeightMagnitude is used.
+	MWM int64
+	// Depth is the depth of the tip selection. If zero, giota.DefaultDepth
+	// is used.
+	Depth int64
+	// LocalPoW does the PoW on the device instead of the node.
+	LocalPoW bool
+}
+
+// NewClient returns a client of the node at endpoint.
+func NewClient(endpoint string) *Client {
+	return &Client{api: giota.NewAPI(endpoint, nil)}
+}
+
+// NextAddress returns the first address of seed without transactions, with
+// its checksum.
+func (c *Client) NextAddress(seed string, security int) (string, error) {
+	s, err := giota.ToTrytes(seed)
+	if err != nil {
+		return "", err
+	}
+	adr, _, err := giota.GetUsedAddress(c.api, s, giota.SecurityLevel(security))
+	if err != nil {
+		return "", err
+	}
+	return string(adr.WithChecksum()), nil
+}
+
+// Balance returns the confirmed balance of address.
+func (c *Client) Balance(address string) (int64, error) {
+	adr, err := parseAddress(address)
+	if err != nil {
+		return 0, err
+	}
+	r, err := c.api.GetBalances([]giota.Address{adr}, 100)
+	if err != nil {
+		return 0, err
+	}
+	if len(r.Balances) != 1 {
+		return 0, fmt.Errorf("node returned %d balances", len(r.Balances))
+	}
+	return r.Balances[0], nil
+}
+
+// PrepareTransfers prepares and signs the bundle of trs, spending the
+// balances of seed. The change goes to remainder or, if it is empty, to the
+// next address of seed.
+func (c *Client) PrepareTransfers(seed string, security int, trs *Transfers, remainder string) (*Bundle, error) {
+	s, err := giota.ToTrytes(seed)
+	if err != nil {
+		return nil, err
+	}
+	var rem giota.Address
+	if remainder != "" {
+		if rem, err = parseAddress(remainder); err != nil {
+			return nil, err
+		}
+	}
+	bdl, err := giota.PrepareTransfers(c.api, s, trs.trs, nil, rem, giota.SecurityLevel(security))
+	if err != nil {
+		return nil, err
+	}
+	return &Bundle{bdl: bdl}, nil
+}
+
+// SendBundle attaches b to the Tangle and broadcasts it. It returns the
+// attached bundle.
+func (c *Client) SendBundle(b *Bundle) (*Bundle, error) {
+	var pow giota.PowFunc
+	if c.LocalPoW {
+		_, pow = giota.GetBestPoW()
+	}
+	res, err := giota.SendTrytes(c.api, c.Depth, b.bdl, c.MWM, pow)
+	if err != nil {
+		return nil, err
+	}
+	return &Bundle{bdl: res.Transactions}, nil
+}
+
+// Send prepares, attaches and broadcasts the bundle of trs, like
+// PrepareTransfers and SendBundle.
+func (c *Client) Send(seed string, security int, trs *Transfers) (*Bundle, error) {
+	b, err := c.PrepareTransfers(seed, security, trs, "")
+	if err != nil {
+		return nil, err
+	}
+	return c.SendBundle(b)
+}
+
+// parseAddress parses an address with or without checksum.
+func parseAddress(s string) (giota.Address, error) {
+	if len(s) == 90 {
+		return giota.ValidateChecksummedString(s)
+	}
+	return giota.ToAddress(s)
+}
@@ -0,0 +1,106 @@
+package mobile
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/iotaledger/giota"
+)
+
+const testSeed = "WQNZOHUT99PWKEBFSKQSYNC9XHT9GEBMOSJAQDQAXPEZPJNDIUB9TSNWVMHKWICW9WVZXSMDFGISOD9FZ"
+
+// newFakeNode returns a client of a node without transactions, which
+// stores the broadcasted ones in broadcasted.
+func newFakeNode(t *testing.T, broadcasted *[]giota.Transaction) (*Client, func()) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Command string
+			Trytes  []giota.Transaction
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("fake node could not decode request: %s", err)
+		}
+
+		var resp interface{} = struct{}{}
+		switch req.Command {
+		case "getTransactionsToApprove":
+			resp = &giota.GetTransactionsToApproveResponse{TrunkTransaction: giota.EmptyHash, BranchTransaction: giota.EmptyHash}
+		case "findTransactions":
+			resp = &giota.FindTransactionsResponse{Hashes: []giota.Hash{}}
+		case "getBalances":
+			resp = &giota.GetBalancesResponse{Balances: []int64{42}}
+		case "broadcastTransactions":
+			*broadcasted = req.Trytes
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	return NewClient(srv.URL), srv.Close
+}
+
+func TestNewAddress(t *testing.T) {
+	want, err := giota.NewAddress(testSeed, 3, giota.SecurityLevelMedium)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		seed     string
+		security int
+		checksum bool
+		want     string
+		wantErr  bool
+	}{
+		{seed: testSeed, security: 2, want: string(want)},
+		{seed: testSeed, security: 2, checksum: true, want: string(want.WithChecksum())},
+		{seed: "seed", security: 2, wantErr: true},
+		{seed: testSeed, security: 4, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		adr, err := NewAddress(tt.seed, 3, tt.security, tt.checksum)
+		if (err != nil) != tt.wantErr || adr != tt.want {
+			t.Errorf("NewAddress(%s, 3, %d, %v) = %s, %v, expected %s", tt.seed, tt.security, tt.checksum, adr, err, tt.want)
+		}
+	}
+}
+
+func TestClientSend(t *testing.T) {
+	var broadcasted []giota.Transaction
+	c, done := newFakeNode(t, &broadcasted)
+	defer done()
+	c.MWM = 1
+	c.LocalPoW = true
+
+	adr, err := c.NextAddress(testSeed, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := NewAddress(testSeed, 0, 2, true)
+	if adr != want {
+		t.Errorf("NextAddress() = %s, expected %s", adr, want)
+	}
+	if b, err := c.Balance(adr); err != nil || b != 42 {
+		t.Errorf("Balance() = %d, %v", b, err)
+	}
+
+	trs := NewTransfers()
+	if err := trs.Add("NOADDRESS", 0, "", ""); err == nil {
+		t.Error("Add() accepted an invalid address")
+	}
+	if err := trs.Add(adr, 0, "hello", "MOBILE"); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := c.Send(testSeed, 2, trs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Len() != 1 || len(broadcasted) != 1 || b.Trytes(0) != string(broadcasted[0].Trytes()) || b.Tail() != string(broadcasted[0].Hash()) {
+		t.Errorf("Send() returned %d transactions, broadcasted %d", b.Len(), len(broadcasted))
+	}
+	if b.Hash() != string(broadcasted[0].Bundle) {
+		t.Errorf("Hash() = %s", b.Hash())
+	}
+}
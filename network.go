@@ -0,0 +1,76 @@
+package giota
+
+import (
+	"crypto/rand"
+)
+
+// NetworkProfile carries the parameters which differ between IOTA networks.
+// Custom networks, e.g. private Tangles, can be described with a literal.
+// SendOptions.Network sends with the depth and MWM of a profile, and
+// WaitOptions.Network waits and promotes with them and its DepthPolicy.
+type NetworkProfile struct {
+	Name               string
+	MinWeightMagnitude int64
+	Depth              int64
+	// Coordinator is the address of the milestones, if it is known.
+	Coordinator Address
+	// Nodes are known public endpoints of the network.
+	Nodes []string
+	// DepthPolicy tells for how long transactions can be promoted. If zero,
+	// DefaultDepthPolicy is used.
+	DepthPolicy AttachmentDepthPolicy
+}
+
+// Known networks.
+var (
+	Mainnet = NetworkProfile{
+		Name:               "mainnet",
+		MinWeightMagnitude: MainnetMWM,
+		Depth:              DefaultDepth,
+		Coordinator:        "KPWCHICGJZXKE9GSUDXZYUAPLHAKAHYHDXNPHENTERYMMBQOPSQIDENXKLKCEYCPVTZQLEEJVYJZV9BWU",
+		Nodes:              PublicNodes,
+		DepthPolicy:        DefaultDepthPolicy,
+	}
+
+	Devnet = NetworkProfile{
+		Name:               "devnet",
+		MinWeightMagnitude: DevnetMWM,
+		Depth:              DefaultDepth,
+		Nodes: []string{
+			"https://nodes.devnet.iota.org:443",
+		},
+	}
+)
+
+// RandomNode returns a random node from Nodes, or "" if there is none.
+func (n *NetworkProfile) RandomNode() string {
+	if len(n.Nodes) == 0 {
+		return ""
+	}
+
+	b := make([]byte, 1)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return n.Nodes[int(b[0])%len(n.Nodes)]
+}
+
+// depthPolicy returns the DepthPolicy of n, or DefaultDepthPolicy if n is
+// nil or has none.
+func (n *NetworkProfile) depthPolicy() AttachmentDepthPolicy {
+	if n == nil || n.DepthPolicy == (AttachmentDepthPolicy{}) {
+		return DefaultDepthPolicy
+	}
+	return n.DepthPolicy
+}
+
+// resendParams returns DefaultResendParams with the depth policy and MWM
+// of n.
+func (n *NetworkProfile) resendParams() *ResendParams {
+	p := DefaultResendParams
+	p.Depth = n.depthPolicy()
+	if n != nil && n.MinWeightMagnitude != 0 {
+		p.MinWeightMagnitude = n.MinWeightMagnitude
+	}
+	return &p
+}
@@ -0,0 +1,95 @@
+package giota
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestNetworkProfileSendTrytes(t *testing.T) {
+	var (
+		depth int64
+		mwm   int64
+	)
+
+	api, done := newFakeNode(t, map[string]fakeNodeHandler{
+		"getTransactionsToApprove": func(req map[string]json.RawMessage) interface{} {
+			json.Unmarshal(req["depth"], &depth)
+			return &GetTransactionsToApproveResponse{TrunkTransaction: EmptyHash, BranchTransaction: EmptyHash}
+		},
+		"attachToTangle": func(req map[string]json.RawMessage) interface{} {
+			json.Unmarshal(req["minWeightMagnitude"], &mwm)
+			var txs []Transaction
+			json.Unmarshal(req["trytes"], &txs)
+			return &AttachToTangleResponse{Trytes: txs}
+		},
+		"broadcastTransactions": func(map[string]json.RawMessage) interface{} {
+			return struct{}{}
+		},
+		"storeTransactions": func(map[string]json.RawMessage) interface{} {
+			return struct{}{}
+		},
+	})
+	defer done()
+
+	n := Devnet
+	n.Depth = 4
+	tests := []struct {
+		name      string
+		depth     int64
+		mwm       int64
+		opts      *SendOptions
+		wantDepth int64
+		wantMWM   int64
+	}{
+		{"defaults", 0, 0, nil, DefaultDepth, DefaultMinWeightMagnitude},
+		{"network", 0, 0, &SendOptions{Network: &n}, 4, Devnet.MinWeightMagnitude},
+		{"arguments override network", 5, 10, &SendOptions{Network: &n}, 5, 10},
+	}
+
+	for _, tt := range tests {
+		if _, err := SendTrytesWithOptions(api, tt.depth, filterTestBundle(), tt.mwm, nil, tt.opts); err != nil {
+			t.Fatalf("%s: %s", tt.name, err)
+		}
+		if depth != tt.wantDepth || mwm != tt.wantMWM {
+			t.Errorf("%s: SendTrytesWithOptions() used depth %d and MWM %d, expected %d and %d", tt.name, depth, mwm, tt.wantDepth, tt.wantMWM)
+		}
+	}
+}
+
+func TestNetworkProfileRandomNode(t *testing.T) {
+	if n := (&NetworkProfile{}).RandomNode(); n != "" {
+		t.Errorf("RandomNode() of empty profile returned %q", n)
+	}
+
+	node := Mainnet.RandomNode()
+	for _, n := range PublicNodes {
+		if n == node {
+			return
+		}
+	}
+	t.Errorf("RandomNode() returned unknown node %q", node)
+}
+
+func TestNetworkProfileResendParams(t *testing.T) {
+	n := Devnet
+	n.DepthPolicy = AttachmentDepthPolicy{MilestoneInterval: 10 * time.Second, MaxDepth: 3}
+	tests := []struct {
+		name      string
+		n         *NetworkProfile
+		wantDepth AttachmentDepthPolicy
+		wantMWM   int64
+	}{
+		{"nil", nil, DefaultDepthPolicy, DefaultMinWeightMagnitude},
+		{"mainnet", &Mainnet, DefaultDepthPolicy, Mainnet.MinWeightMagnitude},
+		{"no policy", &Devnet, DefaultDepthPolicy, Devnet.MinWeightMagnitude},
+		{"policy", &n, n.DepthPolicy, Devnet.MinWeightMagnitude},
+	}
+
+	for _, tt := range tests {
+		p := tt.n.resendParams()
+		if p.Depth != tt.wantDepth || p.MinWeightMagnitude != tt.wantMWM || p.PromoteAfter != DefaultResendParams.PromoteAfter {
+			t.Errorf("%s: resendParams() returned %+v", tt.name, p)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package giota
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// Node features used in node lists.
+const (
+	// FeatureRemotePoW marks nodes which allow attachToTangle.
+	FeatureRemotePoW = "RemotePOW"
+	// FeatureZMQ marks nodes which publish ZMQ events.
+	FeatureZMQ = "zeroMessageQueue"
+)
+
+// HealthCheckTimeout is the max duration of the getNodeInfo call of
+// IsNodeHealthy, so that a hanging node doesn't block RandomNode.
+var HealthCheckTimeout = 10 * time.Second
+
+// ErrNoHealthyNode is returned by RandomNode if no node matches or none of
+// the matching nodes is healthy.
+var ErrNoHealthyNode = errors.New("no healthy node found")
+
+// Node is an entry of a node list.
+type Node struct {
+	URL      string   `json:"url"`
+	Network  string   `json:"network"`
+	Features []string `json:"features,omitempty"`
+}
+
+// HasFeatures returns true if n has all of features.
+func (n *Node) HasFeatures(features ...string) bool {
+	for _, f := range features {
+		found := false
+		for _, nf := range n.Features {
+			if nf == f {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Nodes is a registry of known nodes. It is safe for concurrent use.
+type Nodes struct {
+	// Healthy reports whether the node at endpoint can be used. If nil,
+	// IsNodeHealthy is used.
+	Healthy func(endpoint string) bool
+
+	mu    sync.RWMutex
+	nodes []Node
+}
+
+// NewNodes returns a registry containing nodes.
+func NewNodes(nodes ...Node) *Nodes {
+	n := &Nodes{}
+	n.Add(nodes...)
+	return n
+}
+
+// DefaultNodes contains the nodes of the Mainnet and Devnet profiles.
+var DefaultNodes = NewNodes(append(profileNodes(&Mainnet), profileNodes(&Devnet)...)...)
+
+func profileNodes(p *NetworkProfile) []Node {
+	nodes := make([]Node, len(p.Nodes))
+	for i, u := range p.Nodes {
+		nodes[i] = Node{URL: u, Network: p.Name}
+	}
+	return nodes
+}
+
+// Add adds nodes to the registry. Nodes with an already known URL replace
+// the old entry.
+func (n *Nodes) Add(nodes ...Node) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for _, nd := range nodes {
+		replaced := false
+		for i := range n.nodes {
+			if n.nodes[i].URL == nd.URL {
+				n.nodes[i] = nd
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			n.nodes = append(n.nodes, nd)
+		}
+	}
+}
+
+// Load adds the nodes of a JSON array of Node read from r.
+func (n *Nodes) Load(r io.Reader) error {
+	var nodes []Node
+	if err := json.NewDecoder(r).Decode(&nodes); err != nil {
+		return err
+	}
+
+	for i := range nodes {
+		if nodes[i].URL == "" {
+			return fmt.Errorf("node %d has no url", i)
+		}
+	}
+	n.Add(nodes...)
+	return nil
+}
+
+// LoadFile adds the nodes listed in the JSON file at path.
+func (n *Nodes) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return n.Load(f)
+}
+
+// LoadURL adds the nodes listed in the JSON document at u. If c is nil,
+// http.DefaultClient is used.
+func (n *Nodes) LoadURL(u string, c *http.Client) error {
+	if c == nil {
+		c = http.DefaultClient
+	}
+
+	resp, err := c.Get(u)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("node list returned http status %d", resp.StatusCode)
+	}
+	return n.Load(resp.Body)
+}
+
+// Filter returns the nodes of network which have all of features. An empty
+// network matches all networks.
+func (n *Nodes) Filter(network string, features ...string) []Node {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	var nodes []Node
+	for i := range n.nodes {
+		nd := &n.nodes[i]
+		if (network == "" || nd.Network == network) && nd.HasFeatures(features...) {
+			nodes = append(nodes, *nd)
+		}
+	}
+	return nodes
+}
+
+// RandomNode returns the URL of a random healthy node of network which has
+// all of features.
+func (n *Nodes) RandomNode(network string, features ...string) (string, error) {
+	healthy := n.Healthy
+	if healthy == nil {
+		healthy = IsNodeHealthy
+	}
+
+	nodes := n.Filter(network, features...)
+	for len(nodes) > 0 {
+		r, err := rand.Int(rand.Reader, big.NewInt(int64(len(nodes))))
+		if err != nil {
+			return "", err
+		}
+		i := int(r.Int64())
+
+		if healthy(nodes[i].URL) {
+			return nodes[i].URL, nil
+		}
+		nodes = append(nodes[:i], nodes[i+1:]...)
+	}
+	return "", ErrNoHealthyNode
+}
+
+// IsNodeHealthy returns true if the node at endpoint answers getNodeInfo
+// within HealthCheckTimeout and is synced, i.e. its latest solid milestone
+// is the latest milestone.
+func IsNodeHealthy(endpoint string) bool {
+	ni, err := NewAPIWithOptions(endpoint, WithTimeout(HealthCheckTimeout)).GetNodeInfo()
+	if err != nil {
+		return false
+	}
+	return ni.LatestMilestoneIndex > 0 && ni.LatestSolidSubtangleMilestoneIndex >= ni.LatestMilestoneIndex
+}
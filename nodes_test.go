@@ -0,0 +1,124 @@
+package giota
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNodesLoadAndFilter(t *testing.T) {
+	n := NewNodes(Node{URL: "http://a", Network: "mainnet"})
+	err := n.Load(strings.NewReader(`[
+		{"url": "http://a", "network": "mainnet", "features": ["RemotePOW"]},
+		{"url": "http://b", "network": "devnet"},
+		{"url": "http://c", "network": "mainnet"}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		network  string
+		features []string
+		urls     string
+	}{
+		{network: "", urls: "http://a,http://b,http://c"},
+		{network: "mainnet", urls: "http://a,http://c"},
+		{network: "mainnet", features: []string{FeatureRemotePoW}, urls: "http://a"},
+		{network: "devnet", features: []string{FeatureZMQ}, urls: ""},
+	}
+
+	for _, tt := range tests {
+		var urls []string
+		for _, nd := range n.Filter(tt.network, tt.features...) {
+			urls = append(urls, nd.URL)
+		}
+		if got := strings.Join(urls, ","); got != tt.urls {
+			t.Errorf("Filter(%q, %v) returned %s, expected %s", tt.network, tt.features, got, tt.urls)
+		}
+	}
+
+	if err := n.Load(strings.NewReader(`[{"network": "mainnet"}]`)); err == nil {
+		t.Error("Load() should fail for a node without url")
+	}
+}
+
+func TestNodesRandomNode(t *testing.T) {
+	n := NewNodes(
+		Node{URL: "http://down", Network: "mainnet"},
+		Node{URL: "http://up", Network: "mainnet"},
+		Node{URL: "http://dev", Network: "devnet"},
+	)
+	n.Healthy = func(endpoint string) bool { return endpoint != "http://down" }
+
+	for i := 0; i < 10; i++ {
+		u, err := n.RandomNode("mainnet")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u != "http://up" {
+			t.Fatalf("RandomNode() returned %s", u)
+		}
+	}
+
+	n.Healthy = func(string) bool { return false }
+	if _, err := n.RandomNode("mainnet"); err != ErrNoHealthyNode {
+		t.Errorf("RandomNode() returned %v, expected ErrNoHealthyNode", err)
+	}
+}
+
+func TestIsNodeHealthy(t *testing.T) {
+	solid := int64(100)
+	api, done := newFakeNode(t, map[string]fakeNodeHandler{
+		"getNodeInfo": func(map[string]json.RawMessage) interface{} {
+			return &GetNodeInfoResponse{LatestMilestoneIndex: 100, LatestSolidSubtangleMilestoneIndex: solid}
+		},
+	})
+	defer done()
+
+	if !IsNodeHealthy(api.endpoint) {
+		t.Error("IsNodeHealthy() returned false for a synced node")
+	}
+	solid = 90
+	if IsNodeHealthy(api.endpoint) {
+		t.Error("IsNodeHealthy() returned true for an unsynced node")
+	}
+}
+
+func TestIsNodeHealthyTimeout(t *testing.T) {
+	hang := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-hang
+	}))
+	defer srv.Close()
+	defer close(hang)
+
+	defer func(d time.Duration) { HealthCheckTimeout = d }(HealthCheckTimeout)
+	HealthCheckTimeout = 50 * time.Millisecond
+
+	start := time.Now()
+	if IsNodeHealthy(srv.URL) {
+		t.Error("IsNodeHealthy() returned true for a hanging node")
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("IsNodeHealthy() took %s for a timeout of %s", d, HealthCheckTimeout)
+	}
+}
+
+func TestNodesLoadURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"url": "http://x", "network": "devnet"}]`))
+	}))
+	defer srv.Close()
+
+	n := NewNodes()
+	if err := n.LoadURL(srv.URL, nil); err != nil {
+		t.Fatal(err)
+	}
+	if nodes := n.Filter("devnet"); len(nodes) != 1 || nodes[0].URL != "http://x" {
+		t.Errorf("LoadURL() loaded %v", nodes)
+	}
+}
@@ -0,0 +1,188 @@
+// Package notary publishes proofs of existence of documents to the Tangle and
+// verifies them.
+//
+// The SHA-256 digest of a document is published as message of a zero-value
+// transaction with Tag to an address derived from the digest, so the proof
+// can be found by the document alone. The confirmation of the transaction by
+// a milestone proves that the document existed at that time.
+package notary
+
+import (
+	"crypto/sha256"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/iotaledger/giota"
+)
+
+// Tag is the tag of the transactions published by Notarize.
+const Tag giota.Trytes = "GIOTANOTARY9999999999999999"
+
+// ErrNotFound is returned by Verify if no proof of the document is found.
+var ErrNotFound = errors.New("no proof of existence is found")
+
+// Proof is the evidence of the existence of a document.
+type Proof struct {
+	Digest      giota.Trytes
+	Hash        giota.Hash
+	Transaction giota.Transaction
+	// Timestamp is the attachment time of the transaction.
+	Timestamp time.Time
+	// Confirmed is true if the transaction is confirmed by Milestone, the
+	// first milestone confirming it.
+	Confirmed      bool
+	Milestone      giota.Hash
+	MilestoneIndex int64
+}
+
+// Digest returns the SHA-256 digest of the document read from r as trytes.
+func Digest(r io.Reader) (giota.Trytes, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return giota.EncodeMessage(h.Sum(nil)), nil
+}
+
+// Address returns the address the proofs of digest are published to.
+func Address(digest giota.Trytes) giota.Address {
+	return giota.Address(digest + giota.Trytes(strings.Repeat("9", giota.HashSize/3-len(digest))))
+}
+
+// Notary publishes and verifies proofs. Milestones are looked up with the
+// coordinator set by API.SetCoordinator.
+type Notary struct {
+	API *giota.API
+	// Depth, MWM and Pow are passed to giota.SendTrytes.
+	Depth int64
+	MWM   int64
+	Pow   giota.PowFunc
+}
+
+// Notarize publishes the digest of the document read from r. The returned
+// proof is not confirmed yet.
+func (n *Notary) Notarize(r io.Reader) (*Proof, error) {
+	digest, err := Digest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	trs := []giota.Transfer{{Address: Address(digest), Message: digest, Tag: Tag}}
+	bd, err := giota.PrepareTransfers(n.API, "", trs, nil, "", giota.SecurityLevelMedium)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := giota.SendTrytes(n.API, n.Depth, bd, n.MWM, n.Pow)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := res.Transactions[0]
+	return &Proof{
+		Digest:      digest,
+		Hash:        res.Tail,
+		Transaction: tx,
+		Timestamp:   tx.AttachmentTime(),
+	}, nil
+}
+
+// Verify searches the proofs of the document read from r and returns the one
+// confirmed by the earliest milestone, or the earliest unconfirmed one if none
+// is confirmed.
+func (n *Notary) Verify(r io.Reader) (*Proof, error) {
+	digest, err := Digest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	txs, err := n.API.FindTransactionObjects(&giota.FindTransactionsRequest{
+		Addresses: []giota.Address{Address(digest)},
+		Tags:      []giota.Trytes{Tag},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var best *Proof
+	for _, tx := range txs {
+		if !strings.HasPrefix(string(tx.SignatureMessageFragment), string(digest)) {
+			continue
+		}
+
+		p := &Proof{
+			Digest:      digest,
+			Hash:        tx.Hash(),
+			Transaction: tx,
+			Timestamp:   tx.AttachmentTime(),
+		}
+		if err := n.confirmation(p); err != nil {
+			return nil, err
+		}
+
+		switch {
+		case best == nil,
+			p.Confirmed && (!best.Confirmed || p.MilestoneIndex < best.MilestoneIndex),
+			!p.Confirmed && !best.Confirmed && p.Timestamp.Before(best.Timestamp):
+			best = p
+		}
+	}
+
+	if best == nil {
+		return nil, ErrNotFound
+	}
+	return best, nil
+}
+
+// confirmation searches the first milestone confirming p. As a transaction
+// confirmed by a milestone is confirmed by all later ones, it is a binary
+// search over the milestone indexes.
+func (n *Notary) confirmation(p *Proof) error {
+	ni, err := n.API.GetNodeInfo()
+	if err != nil {
+		return err
+	}
+
+	confirmedBy := func(index int64) (bool, error) {
+		inc, err := n.API.GetInclusionStatesSince([]giota.Hash{p.Hash}, index)
+		switch {
+		case errors.Is(err, giota.ErrMilestoneNotFound):
+			// pruned by the node, so it is older than the transaction
+			return false, nil
+		case err != nil:
+			return false, err
+		}
+		return len(inc.States) > 0 && inc.States[0], nil
+	}
+
+	hi := ni.LatestSolidSubtangleMilestoneIndex
+	ok, err := confirmedBy(hi)
+	if err != nil || !ok {
+		return err
+	}
+
+	lo := int64(0)
+	for hi-lo > 1 {
+		mid := lo + (hi-lo)/2
+		ok, err := confirmedBy(mid)
+		switch {
+		case err != nil:
+			return err
+		case ok:
+			hi = mid
+		default:
+			lo = mid
+		}
+	}
+
+	ms, err := n.API.GetMilestone(hi)
+	if err != nil {
+		return err
+	}
+	p.Confirmed = true
+	p.Milestone = ms
+	p.MilestoneIndex = hi
+	return nil
+}
@@ -0,0 +1,151 @@
+package notary
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/iotaledger/giota"
+)
+
+const (
+	testLatestMilestone    = 100
+	testConfirmedMilestone = 42
+)
+
+// newFakeNode returns an API of a node with milestones up to
+// testLatestMilestone, which confirm all broadcasted transactions from
+// testConfirmedMilestone on if confirm is true.
+func newFakeNode(t *testing.T, confirm bool) (*giota.API, func()) {
+	var (
+		mu         sync.Mutex
+		txs        = map[giota.Hash]giota.Transaction{}
+		milestones = map[giota.Hash]int64{}
+		byIndex    = map[int64]giota.Hash{}
+	)
+
+	for i := int64(1); i <= testLatestMilestone; i++ {
+		tx := giota.Transaction{
+			SignatureMessageFragment:      giota.Trytes(strings.Repeat("9", 2187)),
+			Address:                       giota.Mainnet.Coordinator,
+			ObsoleteTag:                   giota.Tag(giota.Int2Trits(i, giota.ObsoleteTagTrinarySize).Trytes()),
+			Timestamp:                     time.Unix(i, 0),
+			Bundle:                        giota.EmptyHash,
+			TrunkTransaction:              giota.EmptyHash,
+			BranchTransaction:             giota.EmptyHash,
+			Tag:                           giota.Tag(giota.Int2Trits(i, giota.TagTrinarySize).Trytes()),
+			AttachmentTimestamp:           "999999999",
+			AttachmentTimestampLowerBound: "999999999",
+			AttachmentTimestampUpperBound: "999999999",
+			Nonce:                         giota.Trytes(strings.Repeat("9", 27)),
+		}
+		h := tx.Hash()
+		txs[h] = tx
+		milestones[h] = i
+		byIndex[i] = h
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Command   string
+			Trytes    []giota.Transaction
+			Hashes    []giota.Hash
+			Addresses []giota.Address
+			Tags      []giota.Trytes
+			Tips      []giota.Hash
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("fake node could not decode request: %s", err)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		var resp interface{} = struct{}{}
+		switch req.Command {
+		case "getNodeInfo":
+			resp = &giota.GetNodeInfoResponse{
+				LatestMilestone:                    byIndex[testLatestMilestone],
+				LatestMilestoneIndex:               testLatestMilestone,
+				LatestSolidSubtangleMilestone:      byIndex[testLatestMilestone],
+				LatestSolidSubtangleMilestoneIndex: testLatestMilestone,
+			}
+		case "getTransactionsToApprove":
+			resp = &giota.GetTransactionsToApproveResponse{TrunkTransaction: giota.EmptyHash, BranchTransaction: giota.EmptyHash}
+		case "broadcastTransactions":
+			for _, tx := range req.Trytes {
+				txs[tx.Hash()] = tx
+			}
+		case "findTransactions":
+			hashes := []giota.Hash{}
+			for h, tx := range txs {
+				if tx.Address == req.Addresses[0] && (tx.Tag == giota.Tag(req.Tags[0]) || tx.ObsoleteTag == giota.Tag(req.Tags[0])) {
+					hashes = append(hashes, h)
+				}
+			}
+			resp = &giota.FindTransactionsResponse{Hashes: hashes}
+		case "getTrytes":
+			found := []giota.Transaction{}
+			for _, h := range req.Hashes {
+				found = append(found, txs[h])
+			}
+			resp = &giota.GetTrytesResponse{Trytes: found}
+		case "getInclusionStates":
+			resp = &giota.GetInclusionStatesResponse{States: []bool{confirm && milestones[req.Tips[0]] >= testConfirmedMilestone}}
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+
+	api := giota.NewAPI(srv.URL, nil)
+	api.SetCoordinator(giota.Mainnet.Coordinator)
+	return api, srv.Close
+}
+
+func TestNotary(t *testing.T) {
+	tests := []struct {
+		name      string
+		notarize  bool
+		confirm   bool
+		wantErr   error
+		confirmed bool
+	}{
+		{name: "confirmed", notarize: true, confirm: true, confirmed: true},
+		{name: "pending", notarize: true},
+		{name: "unknown", wantErr: ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		api, done := newFakeNode(t, tt.confirm)
+		n := &Notary{
+			API:   api,
+			Depth: 3,
+			MWM:   1,
+			Pow:   func(giota.Trytes, int) (giota.Trytes, error) { return "NONCE", nil },
+		}
+
+		if tt.notarize {
+			if _, err := n.Notarize(strings.NewReader("contract")); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		p, err := n.Verify(strings.NewReader("contract"))
+		done()
+
+		switch {
+		case err != tt.wantErr:
+			t.Errorf("%s: Verify() returned %v, expected %v", tt.name, err, tt.wantErr)
+		case err != nil:
+		case p.Confirmed != tt.confirmed:
+			t.Errorf("%s: Verify() returned confirmed %v", tt.name, p.Confirmed)
+		case p.Confirmed && p.MilestoneIndex != testConfirmedMilestone:
+			t.Errorf("%s: Verify() returned milestone %d, expected %d", tt.name, p.MilestoneIndex, testConfirmedMilestone)
+		case p.Timestamp.IsZero():
+			t.Errorf("%s: Verify() returned no timestamp", tt.name)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package giota
+
+import (
+	"container/list"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Option configures an API made by NewAPIWithOptions.
+type Option func(*API)
+
+// NewAPIWithOptions returns an API of the node at endpoint configured by
+// opts. If endpoint is empty, "http://localhost:14265" is used, and if no
+// http.Client is given, http.DefaultClient.
+func NewAPIWithOptions(endpoint string, opts ...Option) *API {
+	if endpoint == "" {
+		endpoint = "http://localhost:14265/"
+	}
+
+	api := &API{client: http.DefaultClient, endpoint: endpoint}
+	for _, o := range opts {
+		o(api)
+	}
+	return api
+}
+
+// WithHTTPClient makes the API send its requests with c.
+func WithHTTPClient(c *http.Client) Option {
+	return func(api *API) {
+		if c != nil {
+			api.client = c
+		}
+	}
+}
+
+// WithTimeout limits the duration of each call, including its retries, to
+// d. It sets Limits.Timeout.
+func WithTimeout(d time.Duration) Option {
+	return func(api *API) {
+		api.limits.Timeout = d
+	}
+}
+
+// Retry tells how often calls are retried after network errors and
+// responses with status 429, 502, 503 or 504.
+type Retry struct {
+	// Attempts is the max number of attempts of a call, including the
+	// first one.
+	Attempts int
+	// Backoff is the wait before the first retry. It doubles with each
+	// retry.
+	Backoff time.Duration
+}
+
+// WithRetry makes the API try calls up to attempts times, waiting backoff
+// before the first retry.
+func WithRetry(attempts int, backoff time.Duration) Option {
+	return func(api *API) {
+		api.retry = Retry{Attempts: attempts, Backoff: backoff}
+	}
+}
+
+// WithHeaders adds h to the headers of all requests, e.g. for the
+// authentication at a node behind a proxy. They override the User-Agent.
+func WithHeaders(h http.Header) Option {
+	return func(api *API) {
+		api.headers = h.Clone()
+	}
+}
+
+// Logger logs the calls of an API. *log.Logger implements it.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// WithLogger makes the API log its calls, their duration and failures to l.
+func WithLogger(l Logger) Option {
+	return func(api *API) {
+		api.logger = l
+	}
+}
+
+// TransactionCache keeps transactions by their hash. As transactions never
+// change, GetTrytes only asks the node for the ones not in the cache. It
+// must be safe for concurrent use.
+type TransactionCache interface {
+	Get(hash Hash) (Transaction, bool)
+	Add(hash Hash, tx Transaction)
+}
+
+// WithCache makes GetTrytes look up transactions in c before calling the
+// node, and add the ones found to c.
+func WithCache(c TransactionCache) Option {
+	return func(api *API) {
+		api.cache = c
+	}
+}
+
+// LRUTransactionCache is a TransactionCache of a fixed size, which drops the
+// least recently used transactions.
+type LRUTransactionCache struct {
+	size int
+
+	mu    sync.Mutex
+	order *list.List
+	txs   map[Hash]*list.Element
+}
+
+type lruEntry struct {
+	hash Hash
+	tx   Transaction
+}
+
+// NewLRUTransactionCache returns a cache keeping up to size transactions.
+func NewLRUTransactionCache(size int) *LRUTransactionCache {
+	return &LRUTransactionCache{
+		size:  size,
+		order: list.New(),
+		txs:   make(map[Hash]*list.Element),
+	}
+}
+
+// Get returns the transaction of hash, if it is cached.
+func (c *LRUTransactionCache) Get(hash Hash) (Transaction, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.txs[hash]
+	if !ok {
+		return Transaction{}, false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*lruEntry).tx, true
+}
+
+// Add caches tx, dropping the least recently used transaction if the cache
+// is full.
+func (c *LRUTransactionCache) Add(hash Hash, tx Transaction) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.txs[hash]; ok {
+		c.order.MoveToFront(e)
+		return
+	}
+	c.txs[hash] = c.order.PushFront(&lruEntry{hash: hash, tx: tx})
+	for c.order.Len() > c.size {
+		e := c.order.Back()
+		c.order.Remove(e)
+		delete(c.txs, e.Value.(*lruEntry).hash)
+	}
+}
+
+// Len returns the number of cached transactions.
+func (c *LRUTransactionCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
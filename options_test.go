@@ -0,0 +1,110 @@
+package giota
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type testLogger struct {
+	lines []string
+}
+
+func (l *testLogger) Printf(format string, v ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprintf(format, v...))
+}
+
+func TestNewAPIWithOptions(t *testing.T) {
+	bs := filterTestBundle()
+	var calls, failures int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("request without the Authorization header")
+		}
+		if atomic.AddInt32(&failures, -1) >= 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		var req GetTrytesRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		resp := &GetTrytesResponse{}
+		for _, h := range req.Hashes {
+			for _, tx := range bs {
+				if tx.Hash() == h {
+					resp.Trytes = append(resp.Trytes, tx)
+				}
+			}
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+
+	log := &testLogger{}
+	cache := NewLRUTransactionCache(2)
+	api := NewAPIWithOptions(srv.URL,
+		WithHTTPClient(srv.Client()),
+		WithTimeout(5*time.Second),
+		WithRetry(3, time.Millisecond),
+		WithHeaders(http.Header{"Authorization": {"Bearer token"}}),
+		WithLogger(log),
+		WithCache(cache),
+	)
+	if api.Limits().Timeout != 5*time.Second {
+		t.Errorf("Limits().Timeout = %s", api.Limits().Timeout)
+	}
+
+	// two failures are retried
+	failures = 2
+	hashes := []Hash{bs[0].Hash(), bs[1].Hash()}
+	gt, err := api.GetTrytes(hashes)
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case calls != 3 || len(gt.Trytes) != 2 || gt.Trytes[1].Hash() != hashes[1]:
+		t.Errorf("GetTrytes() called the node %d times", calls)
+	case len(log.lines) != 5 || !strings.Contains(log.lines[1], "retrying getTrytes"):
+		t.Errorf("logged %q", log.lines)
+	}
+
+	// bs[0] is dropped from the cache for bs[2], bs[1] is cached
+	calls = 0
+	hashes = []Hash{bs[1].Hash(), bs[2].Hash(), bs[0].Hash()}
+	if gt, err = api.GetTrytes(hashes); err != nil || calls != 1 || gt.Trytes[0].Hash() != hashes[0] || gt.Trytes[2].Hash() != hashes[2] {
+		t.Errorf("GetTrytes() = %v, called the node %d times", err, calls)
+	}
+	if _, err = api.GetTrytes(hashes[1:]); err != nil || calls != 1 || cache.Len() != 2 {
+		t.Errorf("GetTrytes() = %v, called the node %d times", err, calls)
+	}
+
+	// too many failures
+	calls, failures = 0, 3
+	if _, err = api.GetTrytes([]Hash{bs[1].Hash(), bs[0].Hash()}); err == nil || calls != 3 {
+		t.Errorf("GetTrytes() = %v after %d calls, expected an error", err, calls)
+	}
+}
+
+func TestLRUTransactionCache(t *testing.T) {
+	bs := filterTestBundle()
+	c := NewLRUTransactionCache(2)
+	c.Add("A", bs[0])
+	c.Add("B", bs[1])
+	c.Get("A")
+	c.Add("C", bs[2])
+
+	if _, ok := c.Get("B"); ok {
+		t.Error("least recently used transaction is cached")
+	}
+	if tx, ok := c.Get("A"); !ok || tx.Hash() != bs[0].Hash() {
+		t.Error("recently used transaction is not cached")
+	}
+	if c.Len() != 2 {
+		t.Errorf("Len() = %d", c.Len())
+	}
+}
@@ -0,0 +1,75 @@
+// Package otelgiota exports the spans of giota with OpenTelemetry.
+//
+//	api := giota.NewAPI(endpoint, nil)
+//	api.SetTracer(otelgiota.New(otel.Tracer("giota")))
+package otelgiota
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/iotaledger/giota"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracer is a giota.Tracer starting OpenTelemetry spans.
+type Tracer struct {
+	tracer trace.Tracer
+	parent func() context.Context
+}
+
+// New returns a Tracer starting root spans with t.
+func New(t trace.Tracer) *Tracer {
+	return &Tracer{tracer: t, parent: context.Background}
+}
+
+// NewWithParent returns a Tracer starting the spans with t as children of
+// the span of the context returned by parent, e.g. the one of the request a
+// service is handling.
+func NewWithParent(t trace.Tracer, parent func() context.Context) *Tracer {
+	return &Tracer{tracer: t, parent: parent}
+}
+
+// StartSpan implements giota.Tracer.
+func (t *Tracer) StartSpan(name string, attrs ...giota.Attribute) giota.Span {
+	_, s := t.tracer.Start(t.parent(), name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(convert(attrs)...),
+	)
+	return &span{s}
+}
+
+type span struct {
+	s trace.Span
+}
+
+func (s *span) SetAttributes(attrs ...giota.Attribute) {
+	s.s.SetAttributes(convert(attrs)...)
+}
+
+func (s *span) End(err error) {
+	if err != nil {
+		s.s.RecordError(err)
+		s.s.SetStatus(codes.Error, err.Error())
+	}
+	s.s.End()
+}
+
+func convert(attrs []giota.Attribute) []attribute.KeyValue {
+	kvs := make([]attribute.KeyValue, len(attrs))
+	for i, a := range attrs {
+		switch v := a.Value.(type) {
+		case string:
+			kvs[i] = attribute.String(a.Key, v)
+		case int64:
+			kvs[i] = attribute.Int64(a.Key, v)
+		case bool:
+			kvs[i] = attribute.Bool(a.Key, v)
+		default:
+			kvs[i] = attribute.String(a.Key, fmt.Sprint(v))
+		}
+	}
+	return kvs
+}
@@ -0,0 +1,71 @@
+package otelgiota
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/iotaledger/giota"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracer(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Command string `json:"command"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Command != "getNodeInfo" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(&giota.ErrorResponse{Error: "unknown command"})
+			return
+		}
+		json.NewEncoder(w).Encode(&giota.GetNodeInfoResponse{AppName: "IRI"})
+	}))
+	defer srv.Close()
+
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	api := giota.NewAPI(srv.URL, nil)
+	api.SetTracer(New(tp.Tracer("giota")))
+	if _, err := api.GetNodeInfo(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := api.GetTips(); err == nil {
+		t.Fatal("expected error of the node")
+	}
+
+	spans := sr.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("recorded %d spans, expected 2", len(spans))
+	}
+
+	tests := []struct {
+		name    string
+		command string
+		status  codes.Code
+	}{
+		{name: "giota.getNodeInfo", command: "getNodeInfo", status: codes.Unset},
+		{name: "giota.getTips", command: "getTips", status: codes.Error},
+	}
+	for i, tt := range tests {
+		s := spans[i]
+		attrs := attribute.NewSet(s.Attributes()...)
+		cmd, _ := attrs.Value(giota.AttrCommand)
+		_, hasDuration := attrs.Value(giota.AttrDurationMS)
+
+		switch {
+		case s.Name() != tt.name:
+			t.Errorf("span %d is named %s, expected %s", i, s.Name(), tt.name)
+		case cmd.AsString() != tt.command || !hasDuration:
+			t.Errorf("span %s has attributes %v", s.Name(), s.Attributes())
+		case s.Status().Code != tt.status:
+			t.Errorf("span %s has status %v, expected %v", s.Name(), s.Status().Code, tt.status)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package giota
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+// HistoryRange restricts the transactions found by a permanode to a time
+// range of their timestamps and a range of the milestones confirming them.
+// Zero bounds are open, the bounds are inclusive.
+type HistoryRange struct {
+	From, To                   time.Time
+	FromMilestone, ToMilestone int64
+}
+
+func (r *HistoryRange) isValid() error {
+	switch {
+	case !r.From.IsZero() && !r.To.IsZero() && r.To.Before(r.From):
+		return errors.New("time range ends before it starts")
+	case r.FromMilestone < 0 || r.ToMilestone < 0:
+		return errors.New("milestone index must not be negative")
+	case r.ToMilestone > 0 && r.ToMilestone < r.FromMilestone:
+		return errors.New("milestone range ends before it starts")
+	}
+	return nil
+}
+
+// PermanodeAPI is an API of a permanode like Chronicle, which keeps all
+// transactions beyond the pruning horizon of nodes. Its findTransactions
+// accepts ranges, see FindTransactionsInRange. Its API can be used with
+// SetPermanode.
+type PermanodeAPI struct {
+	*API
+}
+
+// NewPermanodeAPI returns the API of the permanode at endpoint like NewAPI.
+func NewPermanodeAPI(endpoint string, c *http.Client) *PermanodeAPI {
+	return &PermanodeAPI{API: NewAPI(endpoint, c)}
+}
+
+// findTransactionsInRangeRequest holds the range parameters of
+// findTransactions of permanodes, timestamps in Unix seconds.
+type findTransactionsInRangeRequest struct {
+	Command string `json:"command"`
+	*FindTransactionsRequest
+	TimestampFrom int64 `json:"timestampFrom,omitempty"`
+	TimestampTo   int64 `json:"timestampTo,omitempty"`
+	MilestoneFrom int64 `json:"milestoneFrom,omitempty"`
+	MilestoneTo   int64 `json:"milestoneTo,omitempty"`
+}
+
+// FindTransactionsInRange calls findTransactions with the range r.
+func (p *PermanodeAPI) FindTransactionsInRange(ft *FindTransactionsRequest, r HistoryRange) (*FindTransactionsResponse, error) {
+	if err := r.isValid(); err != nil {
+		return nil, &ValidationError{Func: "FindTransactionsInRange", Arg: "r", Index: -1, Err: err}
+	}
+
+	req := &findTransactionsInRangeRequest{
+		Command:                 "findTransactions",
+		FindTransactionsRequest: ft,
+		MilestoneFrom:           r.FromMilestone,
+		MilestoneTo:             r.ToMilestone,
+	}
+	if !r.From.IsZero() {
+		req.TimestampFrom = r.From.Unix()
+	}
+	if !r.To.IsZero() {
+		req.TimestampTo = r.To.Unix()
+	}
+
+	resp := &FindTransactionsResponse{}
+	err := p.do(req, resp)
+	return resp, err
+}
+
+// FindTransactionObjectsInRange is like FindTransactionObjects, but finds
+// the transactions with FindTransactionsInRange.
+func (p *PermanodeAPI) FindTransactionObjectsInRange(ft *FindTransactionsRequest, r HistoryRange) ([]Transaction, error) {
+	found, err := p.FindTransactionsInRange(ft, r)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(found.Hashes) == 0 {
+		return []Transaction{}, nil
+	}
+
+	resp, err := p.GetTrytes(found.Hashes)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Trytes, nil
+}
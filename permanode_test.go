@@ -0,0 +1,71 @@
+package giota
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPermanodeFindTransactionsInRange(t *testing.T) {
+	tests := []struct {
+		name  string
+		r     HistoryRange
+		query map[string]int64
+		valid bool
+	}{
+		{name: "open", valid: true, query: map[string]int64{}},
+		{
+			name:  "time range",
+			r:     HistoryRange{From: time.Unix(1500000000, 0), To: time.Unix(1500000100, 0)},
+			query: map[string]int64{"timestampFrom": 1500000000, "timestampTo": 1500000100},
+			valid: true,
+		},
+		{
+			name:  "milestone range",
+			r:     HistoryRange{FromMilestone: 10, ToMilestone: 20},
+			query: map[string]int64{"milestoneFrom": 10, "milestoneTo": 20},
+			valid: true,
+		},
+		{name: "reversed time", r: HistoryRange{From: time.Unix(2, 0), To: time.Unix(1, 0)}},
+		{name: "reversed milestones", r: HistoryRange{FromMilestone: 20, ToMilestone: 10}},
+		{name: "negative milestone", r: HistoryRange{FromMilestone: -1}},
+	}
+
+	for _, tt := range tests {
+		var got map[string]int64
+		api, done := newFakeNode(t, map[string]fakeNodeHandler{
+			"findTransactions": func(req map[string]json.RawMessage) interface{} {
+				got = make(map[string]int64)
+				for k, v := range req {
+					if strings.HasPrefix(k, "timestamp") || strings.HasPrefix(k, "milestone") {
+						var n int64
+						json.Unmarshal(v, &n)
+						got[k] = n
+					}
+				}
+				return &FindTransactionsResponse{Hashes: []Hash{}}
+			},
+		})
+		p := &PermanodeAPI{API: api}
+
+		txs, err := p.FindTransactionObjectsInRange(&FindTransactionsRequest{Addresses: []Address{filterAddr1}}, tt.r)
+		done()
+
+		switch {
+		case !tt.valid && err == nil:
+			t.Errorf("%s: invalid range accepted", tt.name)
+		case !tt.valid:
+		case err != nil:
+			t.Errorf("%s: %s", tt.name, err)
+		case len(txs) != 0 || len(got) != len(tt.query):
+			t.Errorf("%s: sent range %v, expected %v", tt.name, got, tt.query)
+		default:
+			for k, v := range tt.query {
+				if got[k] != v {
+					t.Errorf("%s: sent %s=%d, expected %d", tt.name, k, got[k], v)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package giota
+
+import "fmt"
+
+// TransferPolicy decides whether transfers spending inputs may be signed,
+// e.g. to limit the value sent by a hot wallet. The policy package has
+// composable rules.
+type TransferPolicy interface {
+	// Allow returns an error if trs must not be signed.
+	Allow(trs []Transfer) error
+}
+
+// SetTransferPolicy sets the policy which PrepareTransfers, and so Send and
+// SendToAddress, evaluate before signing inputs with api. A nil p allows all
+// transfers. SetTransferPolicy must not be called concurrently with API
+// calls.
+func (api *API) SetTransferPolicy(p TransferPolicy) {
+	api.policy = p
+}
+
+// allowTransfers evaluates the policy of api for trs.
+func (api *API) allowTransfers(trs []Transfer) error {
+	if api == nil || api.policy == nil {
+		return nil
+	}
+	if err := api.policy.Allow(trs); err != nil {
+		return fmt.Errorf("transfers denied: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+// Package policy is a spending policy engine for giota. An Engine evaluates
+// composable rules, e.g. a max value per transfer, a daily limit or allowed
+// destinations, and logs its decisions for auditing. It is a
+// giota.TransferPolicy, which PrepareTransfers evaluates before signing:
+//
+//	e := policy.New(policy.MaxPerTransfer(10*giota.Mi), policy.DailyLimit(50*giota.Mi))
+//	e.Log = policy.NewJSONLog(f)
+//	api.SetTransferPolicy(e)
+package policy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/iotaledger/giota"
+)
+
+// ErrDenied is wrapped by the errors of transfers denied by a rule.
+var ErrDenied = errors.New("denied by spending policy")
+
+// Rule is a rule of an Engine.
+type Rule interface {
+	// Name names the rule in the audit log.
+	Name() string
+	// Check returns an error if trs violate the rule at now.
+	Check(trs []giota.Transfer, now time.Time) error
+}
+
+// Tracker is a Rule with state, which is updated with the transfers allowed
+// by all rules of the Engine.
+type Tracker interface {
+	Rule
+	Track(trs []giota.Transfer, now time.Time)
+}
+
+// Decision is an entry of the audit log.
+type Decision struct {
+	Time      time.Time        `json:"time"`
+	Transfers []giota.Transfer `json:"transfers"`
+	// Value is the total value of Transfers.
+	Value   int64 `json:"value"`
+	Allowed bool  `json:"allowed"`
+	// Rule and Reason tell why transfers were denied.
+	Rule   string `json:"rule,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// AuditLog records the decisions of an Engine.
+type AuditLog interface {
+	Record(d *Decision) error
+}
+
+// LogFunc is an AuditLog calling itself.
+type LogFunc func(d *Decision) error
+
+// Record implements AuditLog.
+func (f LogFunc) Record(d *Decision) error {
+	return f(d)
+}
+
+type jsonLog struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewJSONLog returns an AuditLog writing decisions as JSON lines to w.
+func NewJSONLog(w io.Writer) AuditLog {
+	return &jsonLog{enc: json.NewEncoder(w)}
+}
+
+func (l *jsonLog) Record(d *Decision) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.enc.Encode(d)
+}
+
+// Engine allows transfers which satisfy all of its rules. It is safe for
+// concurrent use.
+type Engine struct {
+	Rules []Rule
+	// Log records all decisions. If it fails, the transfers are denied.
+	// It may be nil.
+	Log AuditLog
+	// Clock tells the time of decisions. If nil, giota.SystemClock is used.
+	Clock giota.Clock
+
+	mu sync.Mutex
+}
+
+// New returns an Engine with rules.
+func New(rules ...Rule) *Engine {
+	return &Engine{Rules: rules}
+}
+
+// Allow implements giota.TransferPolicy. The state of trackers is updated
+// when transfers are allowed, i.e. before they are signed and sent, so a
+// failed send still counts.
+func (e *Engine) Allow(trs []giota.Transfer) error {
+	clock := e.Clock
+	if clock == nil {
+		clock = giota.SystemClock
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	d := &Decision{Time: clock.Now(), Transfers: trs, Allowed: true}
+	for _, tr := range trs {
+		d.Value += tr.Value
+	}
+
+	var err error
+	for _, r := range e.Rules {
+		if rerr := r.Check(trs, d.Time); rerr != nil {
+			d.Allowed, d.Rule, d.Reason = false, r.Name(), rerr.Error()
+			err = fmt.Errorf("%w: %s: %s", ErrDenied, d.Rule, d.Reason)
+			break
+		}
+	}
+
+	if e.Log != nil {
+		if lerr := e.Log.Record(d); lerr != nil && err == nil {
+			return fmt.Errorf("audit log: %w", lerr)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, r := range e.Rules {
+		if t, ok := r.(Tracker); ok {
+			t.Track(trs, d.Time)
+		}
+	}
+	return nil
+}
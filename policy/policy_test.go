@@ -0,0 +1,81 @@
+package policy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/iotaledger/giota"
+)
+
+const (
+	adr1 giota.Address = "PQZKJKZAHOPZLXRZSPKFHZDLITQTQYDCHSYJYECIFGIISVJXKDGTSSMZE9XAXVUVQWIYLLJLKQDJBMMTZ"
+	adr2 giota.Address = "RVORZ9SIIP9RCYMREUIXXVPQIPHVCNPQ9HZWYKFWYWZRE9JQKG9REPKIASHUUECPSQO9JT9XNMVKWYGVA"
+)
+
+// testClock is a clock which can be advanced.
+type testClock struct {
+	t time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.t
+}
+
+func TestEngine(t *testing.T) {
+	clock := &testClock{t: time.Date(2018, 1, 1, 12, 0, 0, 0, time.UTC)}
+	var log bytes.Buffer
+	e := New(
+		MaxPerTransfer(60),
+		DailyLimit(100),
+		AllowDestinations(adr1.WithChecksum(), giota.Trytes(adr2)),
+		DenyDestinations(giota.Trytes(adr2)),
+		RequireTag("PAYROLL"),
+	)
+	e.Log = NewJSONLog(&log)
+	e.Clock = clock
+
+	tests := []struct {
+		name    string
+		trs     []giota.Transfer
+		advance time.Duration
+		rule    string
+	}{
+		{name: "allowed", trs: []giota.Transfer{{Address: adr1, Value: 60, Tag: "PAYROLL"}, {Address: adr1, Value: 30, Tag: "PAYROLL99"}}},
+		{name: "max per transfer", trs: []giota.Transfer{{Address: adr1, Value: 61, Tag: "PAYROLL"}}, rule: "MaxPerTransfer"},
+		{name: "daily limit", trs: []giota.Transfer{{Address: adr1, Value: 11, Tag: "PAYROLL"}}, rule: "DailyLimit"},
+		{name: "next day", trs: []giota.Transfer{{Address: adr1, Value: 11, Tag: "PAYROLL"}}, advance: 24 * time.Hour},
+		{name: "not allowed", trs: []giota.Transfer{{Address: giota.Address(giota.EmptyHash), Value: 1, Tag: "PAYROLL"}}, rule: "AllowDestinations"},
+		{name: "denied", trs: []giota.Transfer{{Address: adr2, Tag: "PAYROLL"}}, rule: "DenyDestinations"},
+		{name: "tag", trs: []giota.Transfer{{Address: adr1, Value: 1, Tag: "OTHER"}}, rule: "RequireTag"},
+	}
+
+	for _, tt := range tests {
+		clock.t = clock.t.Add(tt.advance)
+		err := e.Allow(tt.trs)
+
+		var d Decision
+		if err := json.NewDecoder(&log).Decode(&d); err != nil {
+			t.Fatalf("%s: no decision logged: %s", tt.name, err)
+		}
+
+		switch {
+		case tt.rule == "" && err != nil:
+			t.Errorf("%s: %s", tt.name, err)
+		case tt.rule != "" && !errors.Is(err, ErrDenied):
+			t.Errorf("%s: Allow() returned %v, expected ErrDenied", tt.name, err)
+		case d.Allowed != (tt.rule == "") || d.Rule != tt.rule || !d.Time.Equal(clock.t):
+			t.Errorf("%s: logged %+v", tt.name, d)
+		}
+	}
+}
+
+func TestEngineLogFailure(t *testing.T) {
+	e := New(MaxPerTransfer(10))
+	e.Log = LogFunc(func(*Decision) error { return errors.New("disk full") })
+	if err := e.Allow([]giota.Transfer{{Address: adr1, Value: 1}}); err == nil {
+		t.Error("Allow() allowed transfers which could not be logged")
+	}
+}
@@ -0,0 +1,152 @@
+package policy
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iotaledger/giota"
+)
+
+type maxPerTransfer int64
+
+// MaxPerTransfer limits the value of each transfer.
+func MaxPerTransfer(max int64) Rule {
+	return maxPerTransfer(max)
+}
+
+func (m maxPerTransfer) Name() string {
+	return "MaxPerTransfer"
+}
+
+func (m maxPerTransfer) Check(trs []giota.Transfer, _ time.Time) error {
+	for _, tr := range trs {
+		if tr.Value > int64(m) {
+			return fmt.Errorf("transfer of %di to %s exceeds %di", tr.Value, tr.Address, int64(m))
+		}
+	}
+	return nil
+}
+
+type dailyLimit struct {
+	max int64
+
+	mu    sync.Mutex
+	day   string
+	spent int64
+}
+
+// DailyLimit limits the total value of the transfers allowed per UTC day.
+func DailyLimit(max int64) Tracker {
+	return &dailyLimit{max: max}
+}
+
+func (l *dailyLimit) Name() string {
+	return "DailyLimit"
+}
+
+// spentOn returns the value spent on the day of now.
+func (l *dailyLimit) spentOn(now time.Time) int64 {
+	if now.UTC().Format("2006-01-02") != l.day {
+		return 0
+	}
+	return l.spent
+}
+
+func (l *dailyLimit) Check(trs []giota.Transfer, now time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	spent := l.spentOn(now)
+	for _, tr := range trs {
+		spent += tr.Value
+	}
+	if spent > l.max {
+		return fmt.Errorf("%di spent today would exceed %di", spent, l.max)
+	}
+	return nil
+}
+
+func (l *dailyLimit) Track(trs []giota.Transfer, now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	spent := l.spentOn(now)
+	for _, tr := range trs {
+		spent += tr.Value
+	}
+	l.day, l.spent = now.UTC().Format("2006-01-02"), spent
+}
+
+type destinations struct {
+	allow bool
+	adrs  map[giota.Address]bool
+}
+
+// AllowDestinations denies value transfers to addresses other than adrs.
+// Checksums of adrs are ignored.
+func AllowDestinations(adrs ...giota.Trytes) Rule {
+	return newDestinations(true, adrs)
+}
+
+// DenyDestinations denies transfers to adrs. Checksums of adrs are ignored.
+func DenyDestinations(adrs ...giota.Trytes) Rule {
+	return newDestinations(false, adrs)
+}
+
+func newDestinations(allow bool, adrs []giota.Trytes) *destinations {
+	d := &destinations{allow: allow, adrs: make(map[giota.Address]bool, len(adrs))}
+	for _, a := range adrs {
+		d.adrs[address(a)] = true
+	}
+	return d
+}
+
+// address returns t without checksum.
+func address(t giota.Trytes) giota.Address {
+	if len(t) > 81 {
+		t = t[:81]
+	}
+	return giota.Address(t)
+}
+
+func (d *destinations) Name() string {
+	if d.allow {
+		return "AllowDestinations"
+	}
+	return "DenyDestinations"
+}
+
+func (d *destinations) Check(trs []giota.Transfer, _ time.Time) error {
+	for _, tr := range trs {
+		listed := d.adrs[address(giota.Trytes(tr.Address))]
+		switch {
+		case d.allow && !listed && tr.Value > 0:
+			return fmt.Errorf("%s is not an allowed destination", tr.Address)
+		case !d.allow && listed:
+			return fmt.Errorf("%s is a denied destination", tr.Address)
+		}
+	}
+	return nil
+}
+
+type requiredTag giota.Trytes
+
+// RequireTag denies transfers without tag.
+func RequireTag(tag giota.Trytes) Rule {
+	return requiredTag(strings.TrimRight(string(tag), "9"))
+}
+
+func (r requiredTag) Name() string {
+	return "RequireTag"
+}
+
+func (r requiredTag) Check(trs []giota.Transfer, _ time.Time) error {
+	for _, tr := range trs {
+		if strings.TrimRight(string(tr.Tag), "9") != string(r) {
+			return fmt.Errorf("transfer to %s has tag %q instead of %s", tr.Address, tr.Tag, string(r))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package giota
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// denyValue is a TransferPolicy denying transfers beyond max.
+type denyValue int64
+
+func (d denyValue) Allow(trs []Transfer) error {
+	for _, tr := range trs {
+		if tr.Value > int64(d) {
+			return errors.New("too much")
+		}
+	}
+	return nil
+}
+
+func TestSetTransferPolicy(t *testing.T) {
+	api, done := newFakeNode(t, map[string]fakeNodeHandler{
+		"getBalances": func(map[string]json.RawMessage) interface{} {
+			return map[string]interface{}{"balances": []string{"100"}}
+		},
+	})
+	defer done()
+	api.SetTransferPolicy(denyValue(50))
+
+	inputs := []AddressInfo{{Seed: accountTestSeed, Index: 0, Security: SecurityLevelLow}}
+	tests := []struct {
+		trs   []Transfer
+		valid bool
+	}{
+		{trs: []Transfer{{Address: filterAddr1, Value: 50}}, valid: true},
+		{trs: []Transfer{{Address: filterAddr1, Value: 51}}},
+		// transfers without value are not signed
+		{trs: []Transfer{{Address: filterAddr1}}, valid: true},
+	}
+	for _, tt := range tests {
+		_, err := PrepareTransfers(api, accountTestSeed, tt.trs, inputs, filterAddr2, SecurityLevelLow)
+		switch {
+		case tt.valid && err != nil:
+			t.Errorf("PrepareTransfers(%+v): %s", tt.trs, err)
+		case !tt.valid && err == nil:
+			t.Errorf("PrepareTransfers(%+v) ignored the policy", tt.trs)
+		}
+	}
+}
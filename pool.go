@@ -0,0 +1,99 @@
+package giota
+
+import "sync"
+
+// DefaultMaxConcurrency is the max number of parallel requests of an API to
+// its node unless set by SetMaxConcurrency.
+const DefaultMaxConcurrency = 8
+
+// workerPool caps the parallel requests of an API. Its slots are created on
+// first use, so that the zero value is usable.
+type workerPool struct {
+	max  int
+	once sync.Once
+	sem  chan struct{}
+}
+
+func (p *workerPool) size() int {
+	if p.max <= 0 {
+		return DefaultMaxConcurrency
+	}
+	return p.max
+}
+
+func (p *workerPool) acquire() {
+	p.once.Do(func() { p.sem = make(chan struct{}, p.size()) })
+	p.sem <- struct{}{}
+}
+
+func (p *workerPool) release() {
+	<-p.sem
+}
+
+// SetMaxConcurrency sets the max number of parallel requests of api to its
+// node, including the ones of funcs fanning out like GetAccountData. If n is
+// zero or negative, DefaultMaxConcurrency is used. Further requests wait for
+// a free slot. SetMaxConcurrency must not be called concurrently with API
+// calls.
+func (api *API) SetMaxConcurrency(n int) {
+	api.pool = workerPool{max: n}
+}
+
+// MaxConcurrency returns the max number of parallel requests of api.
+func (api *API) MaxConcurrency() int {
+	if api == nil {
+		return DefaultMaxConcurrency
+	}
+	return api.pool.size()
+}
+
+// WithMaxConcurrency limits the parallel requests of the API to n, see
+// SetMaxConcurrency.
+func WithMaxConcurrency(n int) Option {
+	return func(api *API) {
+		api.SetMaxConcurrency(n)
+	}
+}
+
+// fanOut runs the tasks on up to MaxConcurrency goroutines and returns the
+// error of the first failed task. Once a task failed, the ones not started
+// yet are skipped. The node requests of the tasks share the slots of api.
+func (api *API) fanOut(tasks ...func() error) error {
+	workers := api.MaxConcurrency()
+	if workers > len(tasks) {
+		workers = len(tasks)
+	}
+
+	var (
+		mu   sync.Mutex
+		next int
+		ferr error
+		wg   sync.WaitGroup
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				mu.Lock()
+				if ferr != nil || next == len(tasks) {
+					mu.Unlock()
+					return
+				}
+				task := tasks[next]
+				next++
+				mu.Unlock()
+
+				if err := task(); err != nil {
+					mu.Lock()
+					if ferr == nil {
+						ferr = err
+					}
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return ferr
+}
@@ -0,0 +1,92 @@
+package giota
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMaxConcurrency(t *testing.T) {
+	var inFlight, peak int64
+	api, done := newFakeNode(t, map[string]fakeNodeHandler{
+		"getNodeInfo": func(map[string]json.RawMessage) interface{} {
+			n := atomic.AddInt64(&inFlight, 1)
+			for {
+				p := atomic.LoadInt64(&peak)
+				if n <= p || atomic.CompareAndSwapInt64(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt64(&inFlight, -1)
+			return &GetNodeInfoResponse{}
+		},
+	})
+	defer done()
+
+	for _, max := range []int{1, 3} {
+		api.SetMaxConcurrency(max)
+		atomic.StoreInt64(&peak, 0)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 12; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := api.GetNodeInfo(); err != nil {
+					t.Error(err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		if p := atomic.LoadInt64(&peak); p > int64(max) {
+			t.Errorf("%d parallel requests with a max of %d", p, max)
+		}
+	}
+}
+
+func TestFanOut(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	tests := []struct {
+		name    string
+		max     int
+		tasks   int
+		failing int
+	}{
+		{name: "all tasks", max: 2, tasks: 10, failing: -1},
+		{name: "more workers than tasks", max: 8, tasks: 3, failing: -1},
+		{name: "failing task", max: 1, tasks: 10, failing: 4},
+	}
+
+	for _, tt := range tests {
+		api := NewAPIWithOptions("", WithMaxConcurrency(tt.max))
+
+		var ran int64
+		tasks := make([]func() error, tt.tasks)
+		for i := range tasks {
+			i := i
+			tasks[i] = func() error {
+				atomic.AddInt64(&ran, 1)
+				if i == tt.failing {
+					return errFailed
+				}
+				return nil
+			}
+		}
+
+		err := api.fanOut(tasks...)
+		switch {
+		case tt.failing >= 0 && err != errFailed:
+			t.Errorf("%s: expected the error of the task, got %v", tt.name, err)
+		case tt.failing >= 0 && ran != int64(tt.failing+1):
+			t.Errorf("%s: %d tasks ran after the failed one", tt.name, ran-int64(tt.failing+1))
+		case tt.failing < 0 && (err != nil || ran != int64(tt.tasks)):
+			t.Errorf("%s: %d of %d tasks ran, error %v", tt.name, ran, tt.tasks, err)
+		}
+	}
+}
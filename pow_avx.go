@@ -238,7 +238,7 @@ int loop256(__m256d *lmid, __m256d *hmid, int m, char *nonce,int *stop)
   int i = 0, n = 0, j = 0;
 
   __m256d lcpy[STATE_LENGTH * 2], hcpy[STATE_LENGTH * 2];
-  for (i = 0; !incr256(lmid, hmid) && !*stop; i++)
+  for (i = 0; !incr256(lmid, hmid) && !__atomic_load_n(stop, __ATOMIC_RELAXED); i++)
   {
     for (j = 0; j < STATE_LENGTH; j++)
     {
@@ -321,32 +321,53 @@ int pwork256(char mid[], int mwm, char nonce[],int n,int *stop)
 import "C"
 import (
 	"sync"
+	"sync/atomic"
 	"unsafe"
 )
 
 func init() {
 	powFuncs["PowAVX"] = PowAVX
+	powOptionsFuncs["PowAVX"] = powAVX
 }
 
-var countAVX int64
+var (
+	countAVX int64
+	// serializes searches, which share countAVX
+	powAVXMu sync.Mutex
+)
 
 // PowAVX is proof of work of iota for amd64 using AVX.
 func PowAVX(trytes Trytes, mwm int) (Trytes, error) {
+	return powAVX(trytes, mwm, nil)
+}
+
+func powAVX(trytes Trytes, mwm int, opts *PowOptions) (Trytes, error) {
+	if err := IsTransactionTrytes(trytes); err != nil {
+		return "", err
+	}
+
+	powAVXMu.Lock()
+	defer powAVXMu.Unlock()
+
 	countAVX = 0
 	c := NewCurl()
 	c.Absorb(trytes[:(TransactionTrinarySize-HashSize)/3])
-	tr := trytes.Trits()
+	tr, _ := trytes.ToTrits()
 	copy(c.state, tr[TransactionTrinarySize-HashSize:])
 	var (
-		stop   int64
+		stop   int32
 		result Trytes
 		wg     sync.WaitGroup
 		mutex  sync.Mutex
 	)
 
-	for n := 0; n < PowProcs; n++ {
+	canceled := opts.watchCancel(func() { atomic.StoreInt32(&stop, 1) })
+
+	for n := 0; n < opts.procs(); n++ {
 		wg.Add(1)
 		go func(n int) {
+			opts.lowerPriority()
+
 			nonce := make(Trits, NonceTrinarySize)
 
 			// nolint: gas
@@ -359,7 +380,7 @@ func PowAVX(trytes Trytes, mwm int) (Trytes, error) {
 			switch {
 			case r >= 0:
 				result = nonce.Trytes()
-				stop = 1
+				atomic.StoreInt32(&stop, 1)
 				countAVX += int64(r)
 			default:
 				countAVX += int64(-r + 1)
@@ -370,5 +391,8 @@ func PowAVX(trytes Trytes, mwm int) (Trytes, error) {
 		}(n)
 	}
 	wg.Wait()
+	if canceled() && result == "" {
+		return "", ErrPowCanceled
+	}
 	return result, nil
 }
@@ -32,10 +32,10 @@ import (
 	"time"
 )
 
-func testPowAVX(t *testing.T) float64 {
+func testPowAVX(t *testing.T, opts *PowOptions) float64 {
 	var tx Trytes = "999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999A9RGRKVGWMWMKOLVMDFWJUHNUNYWZTJADGGPZGXNLERLXYWJE9WQHWWBMCPZMVVMJUMWWBLZLNMLDCGDJ999999999999999999999999999999999999999999999999999999YGYQIVD99999999999999999999TXEFLKNPJRBYZPORHZU9CEMFIFVVQBUSTDGSJCZMBTZCDTTJVUFPTCCVHHORPMGCURKTH9VGJIXUQJVHK999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999"
 	s := time.Now()
-	nonce, err := PowAVX(tx, 15)
+	nonce, err := powAVX(tx, 15, opts)
 	ti := time.Now().Sub(s)
 	sp := float64(countAVX) / 1000 / ti.Seconds()
 	if err != nil {
@@ -50,30 +50,21 @@ func testPowAVX(t *testing.T) float64 {
 }
 
 func TestPowAVX(t *testing.T) {
-	sp := testPowAVX(t)
+	sp := testPowAVX(t, nil)
 	t.Logf("%d kH/sec on AVX PoW", int(sp))
 }
 
 func TestPowAVX1(t *testing.T) {
-	proc := PowProcs
-	PowProcs = 1
-	sp := testPowAVX(t)
+	sp := testPowAVX(t, &PowOptions{Procs: 1})
 	t.Logf("%d kH/sec on AVX PoW", int(sp))
-	PowProcs = proc
 }
 
 func TestPowAVX32(t *testing.T) {
-	proc := PowProcs
-	PowProcs = 32
-	sp := testPowAVX(t)
+	sp := testPowAVX(t, &PowOptions{Procs: 32})
 	t.Logf("%d kH/sec on AVX PoW", int(sp))
-	PowProcs = proc
 }
 
 func TestPowAVX64(t *testing.T) {
-	proc := PowProcs
-	PowProcs = 64
-	sp := testPowAVX(t)
+	sp := testPowAVX(t, &PowOptions{Procs: 64})
 	t.Logf("%d kH/sec on AVX PoW", int(sp))
-	PowProcs = proc
 }
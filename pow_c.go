@@ -154,13 +154,18 @@ int check(unsigned long *l, unsigned long *h, int m)
 
 int stopC=1;
 
+static void setStopC(int v)
+{
+  __atomic_store_n(&stopC, v, __ATOMIC_RELAXED);
+}
+
 long long int loop_cpu(unsigned long *lmid, unsigned long *hmid, int m, signed char *nonce)
 {
   int n = 0;
   long long int i = 0;
   unsigned long lcpy[STATE_LENGTH * 2], hcpy[STATE_LENGTH * 2];
 
-  for (i = 0; !incr(lmid, hmid) && !stopC; i++)
+  for (i = 0; !incr(lmid, hmid) && !__atomic_load_n(&stopC, __ATOMIC_RELAXED); i++)
   {
     memcpy(lcpy, lmid, STATE_LENGTH * sizeof(long));
     memcpy(hcpy, hmid, STATE_LENGTH * sizeof(long));
@@ -171,7 +176,7 @@ long long int loop_cpu(unsigned long *lmid, unsigned long *hmid, int m, signed c
       return i * 64;
     }
   }
-  return -i*64+1;
+  return -i*64-1;
 }
 
 // 01:-1 11:0 10:1
@@ -235,33 +240,39 @@ long long int pwork(signed char mid[], int mwm, signed char nonce[],int n)
 */
 import "C"
 import (
-	"errors"
 	"sync"
 	"unsafe"
 )
 
 func init() {
 	powFuncs["PowC"] = PowC
+	powOptionsFuncs["PowC"] = powC
 }
 
-var countC int64
+var (
+	countC int64
+	powCMu sync.Mutex
+)
 
 // PowC is proof of work of iota using pure C.
 func PowC(trytes Trytes, mwm int) (Trytes, error) {
-	if C.stopC == 0 {
-		C.stopC = 1
-		return "", errors.New("pow is already running, stopped")
-	}
+	return powC(trytes, mwm, nil)
+}
+
+func powC(trytes Trytes, mwm int, opts *PowOptions) (Trytes, error) {
+	// the C search uses a global stop flag, so only one search can run at a time
+	powCMu.Lock()
+	defer powCMu.Unlock()
 
-	if trytes == "" {
-		return "", errors.New("invalid trytes")
+	if err := IsTransactionTrytes(trytes); err != nil {
+		return "", err
 	}
-	C.stopC = 0
+	C.setStopC(0)
 	countC = 0
 
 	c := NewCurl()
 	c.Absorb(trytes[:(TransactionTrinarySize-HashSize)/3])
-	tr := trytes.Trits()
+	tr, _ := trytes.ToTrits()
 	copy(c.state, tr[TransactionTrinarySize-HashSize:])
 
 	var (
@@ -270,9 +281,13 @@ func PowC(trytes Trytes, mwm int) (Trytes, error) {
 		mutex  sync.Mutex
 	)
 
-	for n := 0; n < PowProcs; n++ {
+	canceled := opts.watchCancel(func() { C.setStopC(1) })
+
+	for n := 0; n < opts.procs(); n++ {
 		wg.Add(1)
 		go func(n int) {
+			opts.lowerPriority()
+
 			nonce := make(Trits, NonceTrinarySize)
 
 			// nolint: gas
@@ -284,7 +299,7 @@ func PowC(trytes Trytes, mwm int) (Trytes, error) {
 			switch {
 			case r >= 0:
 				result = nonce.Trytes()
-				C.stopC = 1
+				C.setStopC(1)
 				countC += int64(r)
 			default:
 				countC += int64(-r + 1)
@@ -296,6 +311,11 @@ func PowC(trytes Trytes, mwm int) (Trytes, error) {
 	}
 
 	wg.Wait()
-	C.stopC = 1
+	// wait for the cancel watcher before touching the stop flag again
+	stopped := canceled()
+	C.setStopC(1)
+	if stopped && result == "" {
+		return "", ErrPowCanceled
+	}
 	return result, nil
 }
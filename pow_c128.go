@@ -214,13 +214,18 @@ int checkC128(unsigned __int128 *l, unsigned __int128 *h, int m)
 
 int stopC128 = 1;
 
+static void setStopC128(int v)
+{
+  __atomic_store_n(&stopC128, v, __ATOMIC_RELAXED);
+}
+
 long long int loopC128(unsigned __int128 *lmid, unsigned __int128 *hmid, int m, signed char *nonce)
 {
   int n = 0, j = 0;
   long long int i = 0;
   unsigned __int128 lcpy[STATE_LENGTH * 2], hcpy[STATE_LENGTH * 2];
 
-  for (i = 0; !incrC128(lmid, hmid) && !stopC128; i++)
+  for (i = 0; !incrC128(lmid, hmid) && !__atomic_load_n(&stopC128, __ATOMIC_RELAXED); i++)
   {
     for (j = 0; j < STATE_LENGTH; j++)
     {
@@ -300,34 +305,40 @@ long long int pworkC128(signed char mid[], int mwm, signed char nonce[], int n)
 */
 import "C"
 import (
-	"errors"
 	"sync"
 	"unsafe"
 )
 
 func init() {
 	powFuncs["PowC128"] = PowC128
+	powOptionsFuncs["PowC128"] = powC128
 }
 
-var countC128 int64
+var (
+	countC128 int64
+	powC128Mu sync.Mutex
+)
 
 // PowC128 is a proof of work library for Iota that uses the standard __int128 C type that is available in 64 bit processors (AMD64 and ARM64).
 // This PoW calculator follows common C standards and does not rely on SSE which is AMD64 specific.
 func PowC128(trytes Trytes, mwm int) (Trytes, error) {
-	if C.stopC128 == 0 {
-		C.stopC128 = 1
-		return "", errors.New("pow is already running, stopped")
-	}
+	return powC128(trytes, mwm, nil)
+}
+
+func powC128(trytes Trytes, mwm int, opts *PowOptions) (Trytes, error) {
+	// the C search uses a global stop flag, so only one search can run at a time
+	powC128Mu.Lock()
+	defer powC128Mu.Unlock()
 
-	if trytes == "" {
-		return "", errors.New("invalid trytes")
+	if err := IsTransactionTrytes(trytes); err != nil {
+		return "", err
 	}
 
-	C.stopC128 = 0
+	C.setStopC128(0)
 	countC128 = 0
 	c := NewCurl()
 	c.Absorb(trytes[:(TransactionTrinarySize-HashSize)/3])
-	tr := trytes.Trits()
+	tr, _ := trytes.ToTrits()
 	copy(c.state, tr[TransactionTrinarySize-HashSize:])
 
 	var (
@@ -336,9 +347,13 @@ func PowC128(trytes Trytes, mwm int) (Trytes, error) {
 		mutex  sync.Mutex
 	)
 
-	for n := 0; n < PowProcs; n++ {
+	canceled := opts.watchCancel(func() { C.setStopC128(1) })
+
+	for n := 0; n < opts.procs(); n++ {
 		wg.Add(1)
 		go func(n int) {
+			opts.lowerPriority()
+
 			nonce := make(Trits, NonceTrinarySize)
 
 			// nolint: gas
@@ -348,7 +363,7 @@ func PowC128(trytes Trytes, mwm int) (Trytes, error) {
 			switch {
 			case r >= 0:
 				result = nonce.Trytes()
-				C.stopC128 = 1
+				C.setStopC128(1)
 				countC128 += int64(r)
 			default:
 				countC128 += int64(-r + 1)
@@ -360,6 +375,11 @@ func PowC128(trytes Trytes, mwm int) (Trytes, error) {
 	}
 
 	wg.Wait()
-	C.stopC128 = 1
+	// wait for the cancel watcher before touching the stop flag again
+	stopped := canceled()
+	C.setStopC128(1)
+	if stopped && result == "" {
+		return "", ErrPowCanceled
+	}
 	return result, nil
 }
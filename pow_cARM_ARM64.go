@@ -181,13 +181,18 @@ int checkARM64(uint64x2_t *l, uint64x2_t *h, int m)
 
 int stopCARM64=1;
 
+static void setStopCARM64(int v)
+{
+  __atomic_store_n(&stopCARM64, v, __ATOMIC_RELAXED);
+}
+
 long long int loopARM64(uint64x2_t *lmid, uint64x2_t *hmid, int m, signed char *nonce)
 {
   int n = 0, j = 0;
   long long int i = 0;
 
   uint64x2_t lcpy[STATE_LENGTH * 2], hcpy[STATE_LENGTH * 2];
-  for (i = 0; !incrARM64(lmid, hmid) && !stopCARM64; i++)
+  for (i = 0; !incrARM64(lmid, hmid) && !__atomic_load_n(&stopCARM64, __ATOMIC_RELAXED); i++)
   {
     for (j = 0; j < STATE_LENGTH; j++)
     {
@@ -288,34 +293,40 @@ long long int pworkARM64(signed char mid[], int mwm, signed char nonce[], int n)
 */
 import "C"
 import (
-	"errors"
 	"sync"
 	"unsafe"
 )
 
 func init() {
 	powFuncs["PowCARM64"] = PowCARM64
+	powOptionsFuncs["PowCARM64"] = powCARM64
 }
 
-var countCARM64 int64
+var (
+	countCARM64 int64
+	powCARM64Mu sync.Mutex
+)
 
 // PowCARM64 is a proof of work library for Iota that uses the standard __int128 C type that is available in 64 bit processors (AMD64 and ARM64).
 // This PoW calculator follows common C standards and does not rely on SSE which is AMD64 specific.
 func PowCARM64(trytes Trytes, mwm int) (Trytes, error) {
-	if C.stopCARM64 == 0 {
-		C.stopCARM64 = 1
-		return "", errors.New("pow is already running, stopped")
-	}
+	return powCARM64(trytes, mwm, nil)
+}
+
+func powCARM64(trytes Trytes, mwm int, opts *PowOptions) (Trytes, error) {
+	// the C search uses a global stop flag, so only one search can run at a time
+	powCARM64Mu.Lock()
+	defer powCARM64Mu.Unlock()
 
-	if trytes == "" {
-		return "", errors.New("invalid trytes")
+	if err := IsTransactionTrytes(trytes); err != nil {
+		return "", err
 	}
 
-	C.stopCARM64 = 0
+	C.setStopCARM64(0)
 	countCARM64 = 0
 	c := NewCurl()
 	c.Absorb(trytes[:(TransactionTrinarySize-HashSize)/3])
-	tr := trytes.Trits()
+	tr, _ := trytes.ToTrits()
 	copy(c.state, tr[TransactionTrinarySize-HashSize:])
 
 	var (
@@ -324,9 +335,13 @@ func PowCARM64(trytes Trytes, mwm int) (Trytes, error) {
 		mutex  sync.Mutex
 	)
 
-	for n := 0; n < PowProcs; n++ {
+	canceled := opts.watchCancel(func() { C.setStopCARM64(1) })
+
+	for n := 0; n < opts.procs(); n++ {
 		wg.Add(1)
 		go func(n int) {
+			opts.lowerPriority()
+
 			nonce := make(Trits, NonceTrinarySize)
 
 			// nolint: gas
@@ -339,7 +354,7 @@ func PowCARM64(trytes Trytes, mwm int) (Trytes, error) {
 			switch {
 			case r >= 0:
 				result = nonce.Trytes()
-				C.stopCARM64 = 1
+				C.setStopCARM64(1)
 				countCARM64 += int64(r)
 			default:
 				countCARM64 += int64(-r + 1)
@@ -351,6 +366,11 @@ func PowCARM64(trytes Trytes, mwm int) (Trytes, error) {
 	}
 
 	wg.Wait()
-	C.stopCARM64 = 1
+	// wait for the cancel watcher before touching the stop flag again
+	stopped := canceled()
+	C.setStopCARM64(1)
+	if stopped && result == "" {
+		return "", ErrPowCanceled
+	}
 	return result, nil
 }
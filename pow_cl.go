@@ -29,6 +29,7 @@ package giota
 import (
 	"encoding/binary"
 	"errors"
+	"sync"
 	"sync/atomic"
 
 	"github.com/iotaledger/giota/cl"
@@ -50,7 +51,11 @@ func init() {
 	// pows["PowCL"] = PowCL
 }
 
-var stopCL = true
+var (
+	stopCL = true
+	// the search uses the global stopCL, so only one search can run at a time
+	powCLMu sync.Mutex
+)
 
 // nolint: gocyclo
 func exec(
@@ -280,19 +285,18 @@ func loopCL(binfo []bufferInfo) (Trytes, error) {
 
 // PowCL is proof of work of iota in OpenCL.
 func PowCL(trytes Trytes, mwm int) (Trytes, error) {
-	switch {
-	case !stopCL:
-		stopCL = true
-		return "", errors.New("pow is already running, stopped")
-	case trytes == "":
-		return "", errors.New("invalid trytes")
+	powCLMu.Lock()
+	defer powCLMu.Unlock()
+
+	if err := IsTransactionTrytes(trytes); err != nil {
+		return "", err
 	}
 
 	stopCL = false
 	countCL = 0
 	c := NewCurl()
 	c.Absorb(trytes[:(TransactionTrinarySize-HashSize)/3])
-	tr := trytes.Trits()
+	tr, _ := trytes.ToTrits()
 	copy(c.state, tr[TransactionTrinarySize-HashSize:])
 
 	lmid, hmid := para(c.state)
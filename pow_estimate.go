@@ -0,0 +1,73 @@
+package giota
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// PowHashRates are the hash rates of the builtin PoW funcs in hashes per
+// second used by EstimatePowDuration. The defaults are the rates of the
+// benchmark in the README, measured on a desktop CPU; slow devices like ARM
+// boards are much slower, so calibrate the rates with MeasurePowHashRate on
+// the device. It must not be changed concurrently with EstimatePowDuration.
+var PowHashRates = map[string]float64{
+	"PowGo":     1164e3,
+	"PowC":      1550e3,
+	"PowC128":   1550e3,
+	"PowCARM64": 1550e3,
+	"PowSSE":    2292e3,
+	"PowAVX":    2292e3,
+	"PowCL":     332e3,
+}
+
+// PowHashRater is implemented by PowBackends which know their hash rate in
+// hashes per second. EstimatePowDuration prefers it over PowHashRates.
+type PowHashRater interface {
+	HashRate() float64
+}
+
+// measureMWM is the mwm of the searches of MeasurePowHashRate. It is high
+// enough for the hashing to outweigh the setup of a search.
+const measureMWM = 11
+
+// EstimatePowDuration returns the expected duration of the PoW of a bundle
+// of txs transactions with mwm by the PoW func named pow, so that UIs can
+// warn before Send if it takes long. A nonce needs 3^mwm hashes on average;
+// the duration of single searches varies widely around it.
+func EstimatePowDuration(mwm int64, txs int, pow string) (time.Duration, error) {
+	if mwm < 0 || mwm > HashSize {
+		return 0, &ValidationError{Func: "EstimatePowDuration", Arg: "mwm", Index: -1, Err: fmt.Errorf("invalid mwm %d", mwm)}
+	}
+
+	rate := PowHashRates[pow]
+	if b, ok := DefaultPowRegistry.Get(pow); ok {
+		if r, ok := b.(PowHashRater); ok {
+			rate = r.HashRate()
+		}
+	}
+	if rate <= 0 {
+		return 0, fmt.Errorf("hash rate of %s is unknown", pow)
+	}
+
+	secs := float64(txs) * math.Pow(3, float64(mwm)) / rate
+	return time.Duration(secs * float64(time.Second)), nil
+}
+
+// MeasurePowHashRate returns the hash rate of pow in hashes per second,
+// estimated from the nonces found with a low mwm for at least d.
+func MeasurePowHashRate(pow PowFunc, d time.Duration) (float64, error) {
+	tx := []byte(strings.Repeat("9", TransactionTrinarySize/3))
+	start := time.Now()
+	var n int
+	for n == 0 || time.Since(start) < d {
+		// vary the transaction to search a different nonce each time
+		copy(tx, Int2Trits(int64(n), 27).Trytes())
+		if _, err := pow(Trytes(tx), measureMWM); err != nil {
+			return 0, err
+		}
+		n++
+	}
+	return float64(n) * math.Pow(3, measureMWM) / time.Since(start).Seconds(), nil
+}
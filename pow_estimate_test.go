@@ -0,0 +1,60 @@
+package giota
+
+import (
+	"testing"
+	"time"
+)
+
+type ratedPowBackend struct {
+	fakePowBackend
+	rate float64
+}
+
+func (b *ratedPowBackend) HashRate() float64 { return b.rate }
+
+func TestEstimatePowDuration(t *testing.T) {
+	defer func(r *PowRegistry) { DefaultPowRegistry = r }(DefaultPowRegistry)
+	DefaultPowRegistry = &PowRegistry{}
+	if err := RegisterPowBackend(&ratedPowBackend{fakePowBackend{name: "PowRated"}, 3}); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterPowBackend(&fakePowBackend{name: "PowUnrated"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		mwm     int64
+		txs     int
+		pow     string
+		want    time.Duration
+		wantErr bool
+	}{
+		{mwm: 14, txs: 4, pow: "PowRated", want: 4 * 1594323 * time.Second},
+		{mwm: 2, txs: 2, pow: "PowRated", want: 6 * time.Second},
+		{mwm: 14, txs: 0, pow: "PowGo"},
+		{mwm: 14, txs: 1, pow: "PowUnrated", wantErr: true},
+		{mwm: 14, txs: 1, pow: "PowUnknown", wantErr: true},
+		{mwm: -1, txs: 1, pow: "PowGo", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		d, err := EstimatePowDuration(tt.mwm, tt.txs, tt.pow)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("EstimatePowDuration(%d, %d, %s) error = %v, wantErr %v", tt.mwm, tt.txs, tt.pow, err, tt.wantErr)
+		}
+		if d != tt.want {
+			t.Errorf("EstimatePowDuration(%d, %d, %s) = %s, expected %s", tt.mwm, tt.txs, tt.pow, d, tt.want)
+		}
+	}
+}
+
+func TestMeasurePowHashRate(t *testing.T) {
+	if testing.Short() {
+		t.Skip("measuring takes some seconds")
+	}
+	rate, err := MeasurePowHashRate(PowGo, 500*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("%d kH/sec on Go PoW", int(rate/1000))
+}
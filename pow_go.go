@@ -25,10 +25,10 @@ SOFTWARE.
 package giota
 
 import (
-	"errors"
 	"fmt"
 	"runtime"
 	"sync"
+	"sync/atomic"
 )
 
 // trytes
@@ -54,13 +54,17 @@ const (
 type PowFunc func(Trytes, int) (Trytes, error)
 
 var (
+	// powFuncs is only written by init funcs and read-only afterwards.
 	powFuncs = make(map[string]PowFunc)
-	// PowProcs is number of concurrent processes (default is NumCPU()-1)
+	// PowProcs is number of concurrent processes (default is NumCPU()-1).
+	// It must not be changed while a PoW is running, use PowOptions to set it
+	// per call.
 	PowProcs int
 )
 
 func init() {
 	powFuncs["PowGo"] = PowGo
+	powOptionsFuncs["PowGo"] = powGo
 	PowProcs = runtime.NumCPU()
 	if PowProcs != 1 {
 		PowProcs--
@@ -72,6 +76,9 @@ func GetPowFunc(pow string) (PowFunc, error) {
 	if p, exist := powFuncs[pow]; exist {
 		return p, nil
 	}
+	if b, exist := DefaultPowRegistry.Get(pow); exist {
+		return PowOptionsFunc(b.Pow).WithOptions(nil), nil
+	}
 
 	return nil, fmt.Errorf("PowFunc %v does not exist", pow)
 }
@@ -85,23 +92,37 @@ func GetPowFuncNames() (powFuncNames []string) {
 		powFuncNames[i] = k
 		i++
 	}
+	for _, b := range DefaultPowRegistry.Backends() {
+		powFuncNames = append(powFuncNames, b.Name())
+	}
 
 	return powFuncNames
 }
 
-// GetBestPoW returns most preferable PoW func.
+// GetBestPoW returns most preferable PoW func. Usable backends of
+// DefaultPowRegistry are preferred over the builtin ones.
 func GetBestPoW() (string, PowFunc) {
+	if b, ok := DefaultPowRegistry.Best(); ok {
+		return b.Name(), PowOptionsFunc(b.Pow).WithOptions(nil)
+	}
 
+	name := bestBuiltinPoW()
+	return name, powFuncs[name]
+}
+
+// bestBuiltinPoW returns the name of the most preferable PoW func of this
+// package.
+func bestBuiltinPoW() string {
 	// PowGo is the last and default return value
 	powOrderPreference := []string{"PowCL", "PowSSE", "PowCARM64", "PowC128", "PowC"}
 
 	for _, pow := range powOrderPreference {
-		if p, exist := powFuncs[pow]; exist {
-			return pow, p
+		if _, exist := powFuncs[pow]; exist {
+			return pow
 		}
 	}
 
-	return "PowGo", PowGo // default return PowGo if no others
+	return "PowGo" // default return PowGo if no others
 }
 
 func transform64(lmid *[stateSize]uint64, hmid *[stateSize]uint64) {
@@ -195,12 +216,10 @@ func check(l *[stateSize]uint64, h *[stateSize]uint64, m int) int {
 	return -1
 }
 
-var stopGO = true
-
-func loop(lmid *[stateSize]uint64, hmid *[stateSize]uint64, m int) (Trits, int64) {
+func loop(lmid *[stateSize]uint64, hmid *[stateSize]uint64, m int, stop *int32) (Trits, int64) {
 	var lcpy, hcpy [stateSize]uint64
 	var i int64
-	for i = 0; !incr(lmid, hmid) && !stopGO; i++ {
+	for i = 0; !incr(lmid, hmid) && atomic.LoadInt32(stop) == 0; i++ {
 		copy(lcpy[:], lmid[:])
 		copy(hcpy[:], hmid[:])
 		transform64(&lcpy, &hcpy)
@@ -248,25 +267,27 @@ func incrN(n int, lmid *[stateSize]uint64, hmid *[stateSize]uint64) {
 	}
 }
 
+// countGo is the number of hashes tried by the last PowGo call. It is only
+// accessed atomically.
 var countGo int64 = 1
 
-// PowGo is proof of work for iota in pure Go
+// PowGo is proof of work for iota in pure Go. Concurrent calls run
+// independently of each other.
 func PowGo(trytes Trytes, mwm int) (Trytes, error) {
-	if !stopGO {
-		stopGO = true
-		return "", errors.New("pow is already running, stopped")
-	}
+	return powGo(trytes, mwm, nil)
+}
 
-	if trytes == "" {
-		return "", errors.New("invalid trytes")
+func powGo(trytes Trytes, mwm int, opts *PowOptions) (Trytes, error) {
+	if err := IsTransactionTrytes(trytes); err != nil {
+		return "", err
 	}
 
-	countGo = 0
-	stopGO = false
+	atomic.StoreInt64(&countGo, 0)
+	var stop int32
 
 	c := NewCurl()
 	c.Absorb(trytes[:(TransactionTrinarySize-HashSize)/3])
-	tr := trytes.Trits()
+	tr, _ := trytes.ToTrits()
 	copy(c.state, tr[TransactionTrinarySize-HashSize:])
 
 	var (
@@ -275,9 +296,13 @@ func PowGo(trytes Trytes, mwm int) (Trytes, error) {
 		mutex  sync.Mutex
 	)
 
-	for i := 0; i < PowProcs; i++ {
+	canceled := opts.watchCancel(func() { atomic.StoreInt32(&stop, 1) })
+
+	for i := 0; i < opts.procs(); i++ {
 		wg.Add(1)
 		go func(i int) {
+			opts.lowerPriority()
+
 			lmid, hmid := para(c.state)
 			lmid[nonceOffset] = low0
 			hmid[nonceOffset] = high0
@@ -289,21 +314,23 @@ func PowGo(trytes Trytes, mwm int) (Trytes, error) {
 			hmid[nonceOffset+3] = high3
 
 			incrN(i, lmid, hmid)
-			nonce, cnt := loop(lmid, hmid, mwm)
+			nonce, cnt := loop(lmid, hmid, mwm, &stop)
 
 			mutex.Lock()
-			if nonce != nil {
+			if nonce != nil && result == "" {
 				result = nonce.Trytes()
-				stopGO = true
+				atomic.StoreInt32(&stop, 1)
 			}
-
-			countGo += cnt
 			mutex.Unlock()
+
+			atomic.AddInt64(&countGo, cnt)
 			wg.Done()
 		}(i)
 	}
 
 	wg.Wait()
-	stopGO = true
+	if canceled() && result == "" {
+		return "", ErrPowCanceled
+	}
 	return result, nil
 }
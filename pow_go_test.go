@@ -25,14 +25,15 @@ SOFTWARE.
 package giota
 
 import (
+	"sync/atomic"
 	"testing"
 	"time"
 )
 
-func testPowGo(t *testing.T) {
+func testPowGo(t *testing.T, opts *PowOptions) {
 	var tx Trytes = "999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999A9RGRKVGWMWMKOLVMDFWJUHNUNYWZTJADGGPZGXNLERLXYWJE9WQHWWBMCPZMVVMJUMWWBLZLNMLDCGDJ999999999999999999999999999999999999999999999999999999YGYQIVD99999999999999999999TXEFLKNPJRBYZPORHZU9CEMFIFVVQBUSTDGSJCZMBTZCDTTJVUFPTCCVHHORPMGCURKTH9VGJIXUQJVHK999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999"
 
-	nonce, err := PowGo(tx, 14)
+	nonce, err := powGo(tx, 14, opts)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -46,17 +47,13 @@ func testPowGo(t *testing.T) {
 
 func TestPowGo(t *testing.T) {
 	s := time.Now()
-	testPowGo(t)
+	testPowGo(t, nil)
 	ti := time.Now().Sub(s)
 
-	sp := float64(countGo) / 1000 / ti.Seconds()
+	sp := float64(atomic.LoadInt64(&countGo)) / 1000 / ti.Seconds()
 	t.Logf("%d kH/sec on Go PoW", int(sp))
 }
 
 func TestPowGo1(t *testing.T) {
-	t.Logf("PowProcs:%d", PowProcs)
-	proc := PowProcs
-	PowProcs = 1
-	testPowGo(t)
-	PowProcs = proc
+	testPowGo(t, &PowOptions{Procs: 1})
 }
@@ -0,0 +1,7 @@
+package giota
+
+import "syscall"
+
+func setThreadNice(n int) {
+	syscall.Setpriority(syscall.PRIO_PROCESS, syscall.Gettid(), n)
+}
@@ -0,0 +1,6 @@
+//go:build !linux
+// +build !linux
+
+package giota
+
+func setThreadNice(n int) {}
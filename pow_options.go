@@ -0,0 +1,116 @@
+package giota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+)
+
+// ErrPowCanceled is returned by a PoW func if PowOptions.Cancel was closed or
+// PowOptions.Context was done before a nonce was found.
+var ErrPowCanceled = errors.New("pow was canceled")
+
+// PowOptions configures a single PoW call. A nil *PowOptions uses the
+// defaults.
+type PowOptions struct {
+	// Procs is the number of concurrent searches. If zero, PowProcs is used.
+	Procs int
+	// Cancel stops the search when it is closed.
+	Cancel <-chan struct{}
+	// Context stops the search when it is done, e.g. at its deadline. The
+	// C searches check it between batches of nonces, so they stop within
+	// microseconds.
+	Context context.Context
+	// Nice lowers the OS scheduling priority of the search threads, like
+	// nice(1). It is only supported on Linux and ignored elsewhere.
+	Nice int
+}
+
+// PowOptionsFunc is a PoW func which takes PowOptions.
+type PowOptionsFunc func(trytes Trytes, mwm int, opts *PowOptions) (Trytes, error)
+
+// powOptionsFuncs holds the PowOptionsFunc of each entry of powFuncs.
+var powOptionsFuncs = make(map[string]PowOptionsFunc)
+
+// WithOptions returns a PowFunc calling f with opts.
+func (f PowOptionsFunc) WithOptions(opts *PowOptions) PowFunc {
+	return func(trytes Trytes, mwm int) (Trytes, error) {
+		return f(trytes, mwm, opts)
+	}
+}
+
+// GetPowFuncWithOptions returns the PoW func named pow which uses opts.
+func GetPowFuncWithOptions(pow string, opts *PowOptions) (PowFunc, error) {
+	if p, exist := powOptionsFuncs[pow]; exist {
+		return p.WithOptions(opts), nil
+	}
+	if b, exist := DefaultPowRegistry.Get(pow); exist {
+		return PowOptionsFunc(b.Pow).WithOptions(opts), nil
+	}
+	return nil, fmt.Errorf("PowFunc %v does not exist", pow)
+}
+
+// GetBestPoWWithOptions is like GetBestPoW, but the returned func uses opts.
+// PowCL doesn't support options and ignores them.
+func GetBestPoWWithOptions(opts *PowOptions) (string, PowFunc) {
+	if b, ok := DefaultPowRegistry.Best(); ok {
+		return b.Name(), PowOptionsFunc(b.Pow).WithOptions(opts)
+	}
+
+	name := bestBuiltinPoW()
+	if p, exist := powOptionsFuncs[name]; exist {
+		return name, p.WithOptions(opts)
+	}
+	return name, powFuncs[name]
+}
+
+func (o *PowOptions) procs() int {
+	if o == nil || o.Procs <= 0 {
+		return PowProcs
+	}
+	return o.Procs
+}
+
+// lowerPriority applies Nice to the thread of the calling goroutine. The
+// thread stays locked, so it is discarded when the goroutine exits instead of
+// running other goroutines with the lowered priority.
+func (o *PowOptions) lowerPriority() {
+	if o == nil || o.Nice <= 0 {
+		return
+	}
+	runtime.LockOSThread()
+	setThreadNice(o.Nice)
+}
+
+// watchCancel calls stop if Cancel is closed or Context is done before the
+// returned func is called. The returned func reports whether stop was called.
+func (o *PowOptions) watchCancel(stop func()) func() bool {
+	if o == nil || (o.Cancel == nil && o.Context == nil) {
+		return func() bool { return false }
+	}
+	var ctxDone <-chan struct{}
+	if o.Context != nil {
+		ctxDone = o.Context.Done()
+	}
+
+	done := make(chan struct{})
+	canceled := make(chan bool, 1)
+	go func() {
+		select {
+		case <-o.Cancel:
+			stop()
+			canceled <- true
+		case <-ctxDone:
+			stop()
+			canceled <- true
+		case <-done:
+			canceled <- false
+		}
+	}()
+
+	return func() bool {
+		close(done)
+		return <-canceled
+	}
+}
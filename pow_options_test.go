@@ -0,0 +1,61 @@
+package giota
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPowOptionsCancel(t *testing.T) {
+	tx := Trytes(strings.Repeat("9", TransactionTrinarySize/3))
+
+	cancel := make(chan struct{})
+	close(cancel)
+
+	for name := range powOptionsFuncs {
+		p, err := GetPowFuncWithOptions(name, &PowOptions{Procs: 2, Cancel: cancel, Nice: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// no nonce can be found for the whole hash in time
+		if _, err := p(tx, HashSize); err != ErrPowCanceled {
+			t.Errorf("%s returned %v, expected ErrPowCanceled", name, err)
+		}
+	}
+}
+
+func TestPowOptionsContext(t *testing.T) {
+	tx := Trytes(strings.Repeat("9", TransactionTrinarySize/3))
+
+	for name := range powOptionsFuncs {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		p, err := GetPowFuncWithOptions(name, &PowOptions{Procs: 2, Context: ctx})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		start := time.Now()
+		_, err = p(tx, HashSize)
+		cancel()
+		if err != ErrPowCanceled {
+			t.Errorf("%s returned %v, expected ErrPowCanceled", name, err)
+		}
+		if d := time.Since(start); d > 5*time.Second {
+			t.Errorf("%s stopped after %s", name, d)
+		}
+	}
+}
+
+func TestGetPowFuncWithOptions(t *testing.T) {
+	if _, err := GetPowFuncWithOptions("PowUnknown", nil); err == nil {
+		t.Error("GetPowFuncWithOptions() should fail for unknown PoW")
+	}
+
+	name, _ := GetBestPoW()
+	oname, p := GetBestPoWWithOptions(&PowOptions{Procs: 1})
+	if oname != name || p == nil {
+		t.Errorf("GetBestPoWWithOptions() returned %s, expected %s", oname, name)
+	}
+}
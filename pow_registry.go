@@ -0,0 +1,87 @@
+package giota
+
+import (
+	"fmt"
+	"sync"
+)
+
+// PowBackend is a PoW implementation provided outside of this package, e.g.
+// a driver for an FPGA board attached via USB or serial port. Backends are
+// made available by registering them with a PowRegistry, usually in the init
+// func of the module providing the driver.
+type PowBackend interface {
+	// Name returns a unique name of the backend, like "PowPiDiver".
+	Name() string
+	// Probe checks if the backend can be used right now, e.g. that the
+	// device is connected and responds. It returns nil if it is usable.
+	// Probe is called whenever the best PoW is selected and should be cheap.
+	Probe() error
+	// Pow does the PoW for trytes with mwm. opts may be nil.
+	Pow(trytes Trytes, mwm int, opts *PowOptions) (Trytes, error)
+}
+
+// PowRegistry holds PowBackends in the order of their registration. It is
+// safe for concurrent use.
+type PowRegistry struct {
+	mu       sync.RWMutex
+	backends []PowBackend
+}
+
+// DefaultPowRegistry is the registry used by GetPowFunc and GetBestPoW.
+var DefaultPowRegistry = &PowRegistry{}
+
+// RegisterPowBackend registers b with DefaultPowRegistry.
+func RegisterPowBackend(b PowBackend) error {
+	return DefaultPowRegistry.Register(b)
+}
+
+// Register adds b to the registry. It fails if a backend or a builtin PoW
+// func with the same name exists.
+func (r *PowRegistry) Register(b PowBackend) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	name := b.Name()
+	if _, exist := powFuncs[name]; exist {
+		return fmt.Errorf("PowFunc %v already exists", name)
+	}
+	for _, o := range r.backends {
+		if o.Name() == name {
+			return fmt.Errorf("PowFunc %v already exists", name)
+		}
+	}
+
+	r.backends = append(r.backends, b)
+	return nil
+}
+
+// Backends returns the registered backends.
+func (r *PowRegistry) Backends() []PowBackend {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]PowBackend(nil), r.backends...)
+}
+
+// Get returns the backend named name.
+func (r *PowRegistry) Get(name string) (PowBackend, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, b := range r.backends {
+		if b.Name() == name {
+			return b, true
+		}
+	}
+	return nil, false
+}
+
+// Best returns the first registered backend whose Probe succeeds.
+func (r *PowRegistry) Best() (PowBackend, bool) {
+	for _, b := range r.Backends() {
+		if b.Probe() == nil {
+			return b, true
+		}
+	}
+	return nil, false
+}
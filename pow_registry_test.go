@@ -0,0 +1,71 @@
+package giota
+
+import (
+	"errors"
+	"testing"
+)
+
+type fakePowBackend struct {
+	name  string
+	err   error
+	opts  *PowOptions
+	calls int
+}
+
+func (b *fakePowBackend) Name() string { return b.name }
+
+func (b *fakePowBackend) Probe() error { return b.err }
+
+func (b *fakePowBackend) Pow(trytes Trytes, mwm int, opts *PowOptions) (Trytes, error) {
+	b.calls++
+	b.opts = opts
+	return Trytes(EmptyHash[:NonceTrinarySize/3]), nil
+}
+
+func TestRegistryBackends(t *testing.T) {
+	defer func(r *PowRegistry) { DefaultPowRegistry = r }(DefaultPowRegistry)
+	DefaultPowRegistry = &PowRegistry{}
+
+	builtin, _ := GetBestPoW()
+
+	offline := &fakePowBackend{name: "PowOffline", err: errors.New("no device")}
+	online := &fakePowBackend{name: "PowOnline"}
+
+	tests := []struct {
+		name    string
+		backend PowBackend
+		best    string
+		wantErr bool
+	}{
+		{name: "unusable backend", backend: offline, best: builtin},
+		{name: "usable backend", backend: online, best: "PowOnline"},
+		{name: "duplicate backend", backend: &fakePowBackend{name: "PowOnline"}, best: "PowOnline", wantErr: true},
+		{name: "builtin name", backend: &fakePowBackend{name: "PowGo"}, best: "PowOnline", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		err := RegisterPowBackend(tt.backend)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: RegisterPowBackend() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if best, _ := GetBestPoW(); best != tt.best {
+			t.Errorf("%s: GetBestPoW() = %s, expected %s", tt.name, best, tt.best)
+		}
+	}
+
+	opts := &PowOptions{Procs: 1}
+	_, p := GetBestPoWWithOptions(opts)
+	if _, err := p(EmptyHash, 1); err != nil {
+		t.Fatal(err)
+	}
+	if online.calls != 1 || online.opts != opts {
+		t.Errorf("backend was called %d times with %v", online.calls, online.opts)
+	}
+
+	if _, err := GetPowFunc("PowOffline"); err != nil {
+		t.Error(err)
+	}
+	if len(GetPowFuncNames()) != len(powFuncs)+2 {
+		t.Errorf("GetPowFuncNames() returned %v", GetPowFuncNames())
+	}
+}
@@ -183,13 +183,18 @@ int check128(__m128i *l, __m128i *h, int m)
 
 int stopSSE=1;
 
+static void setStopSSE(int v)
+{
+  __atomic_store_n(&stopSSE, v, __ATOMIC_RELAXED);
+}
+
 long long int loop128(__m128i *lmid, __m128i *hmid, int m, char *nonce)
 {
   int n = 0, j = 0;
   long long int i = 0;
 
   __m128i lcpy[STATE_LENGTH * 2], hcpy[STATE_LENGTH * 2];
-  for (i = 0; !incr128(lmid, hmid) && !stopSSE; i++)
+  for (i = 0; !incr128(lmid, hmid) && !__atomic_load_n(&stopSSE, __ATOMIC_RELAXED); i++)
   {
     for (j = 0; j < STATE_LENGTH; j++)
     {
@@ -269,33 +274,39 @@ long long int pwork128(char mid[], int mwm, char nonce[],int n)
 */
 import "C"
 import (
-	"errors"
 	"sync"
 	"unsafe"
 )
 
 func init() {
 	powFuncs["PowSSE"] = PowSSE
+	powOptionsFuncs["PowSSE"] = powSSE
 }
 
-var countSSE int64
+var (
+	countSSE int64
+	powSSEMu sync.Mutex
+)
 
 // PowSSE is proof of work for iota for amd64 using SSE2(or AMD64).
 func PowSSE(trytes Trytes, mwm int) (Trytes, error) {
-	if C.stopSSE == 0 {
-		C.stopSSE = 1
-		return "", errors.New("pow is already running, stopped")
-	}
+	return powSSE(trytes, mwm, nil)
+}
+
+func powSSE(trytes Trytes, mwm int, opts *PowOptions) (Trytes, error) {
+	// the C search uses a global stop flag, so only one search can run at a time
+	powSSEMu.Lock()
+	defer powSSEMu.Unlock()
 
-	if trytes == "" {
-		return "", errors.New("invalid trytes")
+	if err := IsTransactionTrytes(trytes); err != nil {
+		return "", err
 	}
 
-	C.stopSSE = 0
+	C.setStopSSE(0)
 	countSSE = 0
 	c := NewCurl()
 	c.Absorb(trytes[:(TransactionTrinarySize-HashSize)/3])
-	tr := trytes.Trits()
+	tr, _ := trytes.ToTrits()
 	copy(c.state, tr[TransactionTrinarySize-HashSize:])
 
 	var (
@@ -304,9 +315,13 @@ func PowSSE(trytes Trytes, mwm int) (Trytes, error) {
 		mutex  sync.Mutex
 	)
 
-	for n := 0; n < PowProcs; n++ {
+	canceled := opts.watchCancel(func() { C.setStopSSE(1) })
+
+	for n := 0; n < opts.procs(); n++ {
 		wg.Add(1)
 		go func(n int) {
+			opts.lowerPriority()
+
 			nonce := make(Trits, NonceTrinarySize)
 
 			// nolint: gas
@@ -318,7 +333,7 @@ func PowSSE(trytes Trytes, mwm int) (Trytes, error) {
 			switch {
 			case r >= 0:
 				result = nonce.Trytes()
-				C.stopSSE = 1
+				C.setStopSSE(1)
 				countSSE += int64(r)
 			default:
 				countSSE += int64(-r + 1)
@@ -330,6 +345,11 @@ func PowSSE(trytes Trytes, mwm int) (Trytes, error) {
 	}
 
 	wg.Wait()
-	C.stopSSE = 1
+	// wait for the cancel watcher before touching the stop flag again
+	stopped := canceled()
+	C.setStopSSE(1)
+	if stopped && result == "" {
+		return "", ErrPowCanceled
+	}
 	return result, nil
 }
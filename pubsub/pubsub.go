@@ -0,0 +1,182 @@
+// Package pubsub is a message bus on the Tangle for IoT applications. Topics
+// are transaction tags: Publish posts a payload as zero-value transactions
+// with the tag of the topic to the address of the publisher, and Subscribe
+// polls the transactions of the tag and yields the payloads with the address
+// they were posted to as sender.
+//
+// Payloads are encoded as by package stream, so they are not authenticated:
+// anybody can post to any address.
+package pubsub
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iotaledger/giota"
+	"github.com/iotaledger/giota/stream"
+)
+
+// DefaultInterval is the poll interval of Subscribe if Bus.Interval is zero.
+const DefaultInterval = 10 * time.Second
+
+// ErrInvalidTopic is returned for topics which are no tags.
+var ErrInvalidTopic = errors.New("topic must be 1 to 27 trytes")
+
+// Bus publishes and subscribes to topics.
+type Bus struct {
+	API *giota.API
+	// Address is the sender address of published messages.
+	Address giota.Address
+	// Depth, MWM and Pow are passed to giota.SendTrytes.
+	Depth int64
+	MWM   int64
+	Pow   giota.PowFunc
+	// Interval is the poll interval of Subscribe.
+	Interval time.Duration
+}
+
+// Message is a payload received by Subscribe.
+type Message struct {
+	Topic  giota.Trytes
+	Sender giota.Address
+	// ID is the ID of the payload, see stream.Publisher.Publish.
+	ID        giota.Trytes
+	Data      []byte
+	Timestamp time.Time
+	// Err is set instead of the other fields if polling the node failed.
+	Err error
+}
+
+// tag returns topic padded to a tag.
+func tag(topic giota.Trytes) (giota.Trytes, error) {
+	topic = giota.Trytes(strings.TrimRight(string(topic), "9"))
+	if len(topic) == 0 || len(topic) > giota.TagTrinarySize/3 || giota.IsTrytes(topic) != nil {
+		return "", ErrInvalidTopic
+	}
+	return topic + giota.Trytes(strings.Repeat("9", giota.TagTrinarySize/3-len(topic))), nil
+}
+
+// Publish posts payload to topic and returns the ID of the payload.
+func (b *Bus) Publish(topic giota.Trytes, payload []byte) (giota.Trytes, error) {
+	t, err := tag(topic)
+	if err != nil {
+		return "", err
+	}
+	p := &stream.Publisher{
+		API:     b.API,
+		Address: b.Address,
+		Tag:     t,
+		Depth:   b.Depth,
+		MWM:     b.MWM,
+		Pow:     b.Pow,
+	}
+	return p.Publish(payload)
+}
+
+// Subscribe polls topic every Interval and sends the messages posted to it
+// since the previous poll, ordered by their timestamp. The first poll only
+// records the messages already posted. Calling the returned func stops
+// polling and closes the channel.
+func (b *Bus) Subscribe(topic giota.Trytes) (<-chan Message, func(), error) {
+	t, err := tag(topic)
+	if err != nil {
+		return nil, nil, err
+	}
+	interval := b.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
+	ch := make(chan Message)
+	stop := make(chan struct{})
+
+	go func() {
+		defer close(ch)
+
+		var known map[string]bool
+		tk := time.NewTicker(interval)
+		defer tk.Stop()
+
+		for {
+			msgs, err := b.poll(t, known)
+			switch {
+			case err != nil:
+				msgs = []Message{{Err: err}}
+			case known == nil:
+				// the first poll only records the state
+				known = make(map[string]bool, len(msgs))
+				for _, m := range msgs {
+					known[key(&m)] = true
+				}
+				msgs = nil
+			default:
+				for _, m := range msgs {
+					known[key(&m)] = true
+				}
+			}
+
+			for _, m := range msgs {
+				select {
+				case ch <- m:
+				case <-stop:
+					return
+				}
+			}
+
+			select {
+			case <-tk.C:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() { close(stop) })
+	}, nil
+}
+
+// key identifies the payload of a sender.
+func key(m *Message) string {
+	return string(m.Sender) + string(m.ID)
+}
+
+// poll returns the messages of tag t which are not known.
+func (b *Bus) poll(t giota.Trytes, known map[string]bool) ([]Message, error) {
+	txs, err := b.API.FindTransactionObjects(&giota.FindTransactionsRequest{Tags: []giota.Trytes{t}})
+	if err != nil {
+		return nil, err
+	}
+
+	// payloads are decoded per sender, so that nobody can complete the
+	// payload of someone else
+	var senders []giota.Address
+	bySender := make(map[giota.Address][]giota.Transaction)
+	for _, tx := range txs {
+		if tx.Tag != giota.Tag(t) {
+			continue
+		}
+		if _, ok := bySender[tx.Address]; !ok {
+			senders = append(senders, tx.Address)
+		}
+		bySender[tx.Address] = append(bySender[tx.Address], tx)
+	}
+
+	var msgs []Message
+	for _, s := range senders {
+		for _, p := range stream.Decode(bySender[s]) {
+			m := Message{Topic: t, Sender: s, ID: p.ID, Data: p.Data, Timestamp: p.Timestamp}
+			if !known[key(&m)] {
+				msgs = append(msgs, m)
+			}
+		}
+	}
+	sort.SliceStable(msgs, func(i, j int) bool {
+		return msgs[i].Timestamp.Before(msgs[j].Timestamp)
+	})
+	return msgs, nil
+}
@@ -0,0 +1,127 @@
+package pubsub
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/iotaledger/giota"
+)
+
+const (
+	sender1 giota.Address = "PQZKJKZAHOPZLXRZSPKFHZDLITQTQYDCHSYJYECIFGIISVJXKDGTSSMZE9XAXVUVQWIYLLJLKQDJBMMTZ"
+	sender2 giota.Address = "RVORZ9SIIP9RCYMREUIXXVPQIPHVCNPQ9HZWYKFWYWZRE9JQKG9REPKIASHUUECPSQO9JT9XNMVKWYGVA"
+)
+
+// newFakeNode returns an API of a node which stores broadcasted transactions
+// and finds them by tag.
+func newFakeNode(t *testing.T) (*giota.API, func()) {
+	var (
+		mu  sync.Mutex
+		txs = map[giota.Hash]giota.Transaction{}
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Command string
+			Trytes  []giota.Transaction
+			Hashes  []giota.Hash
+			Tags    []giota.Trytes
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("fake node could not decode request: %s", err)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		var resp interface{} = struct{}{}
+		switch req.Command {
+		case "getTransactionsToApprove":
+			resp = &giota.GetTransactionsToApproveResponse{TrunkTransaction: giota.EmptyHash, BranchTransaction: giota.EmptyHash}
+		case "broadcastTransactions":
+			for _, tx := range req.Trytes {
+				txs[tx.Hash()] = tx
+			}
+		case "findTransactions":
+			hashes := []giota.Hash{}
+			for h, tx := range txs {
+				for _, tag := range req.Tags {
+					if tx.Tag == giota.Tag(tag) {
+						hashes = append(hashes, h)
+					}
+				}
+			}
+			resp = &giota.FindTransactionsResponse{Hashes: hashes}
+		case "getTrytes":
+			found := []giota.Transaction{}
+			for _, h := range req.Hashes {
+				found = append(found, txs[h])
+			}
+			resp = &giota.GetTrytesResponse{Trytes: found}
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	return giota.NewAPI(srv.URL, nil), srv.Close
+}
+
+func TestBus(t *testing.T) {
+	api, done := newFakeNode(t)
+	defer done()
+
+	pow := func(giota.Trytes, int) (giota.Trytes, error) { return "NONCE", nil }
+	bus1 := &Bus{API: api, Address: sender1, MWM: 1, Pow: pow, Interval: 10 * time.Millisecond}
+	bus2 := &Bus{API: api, Address: sender2, MWM: 1, Pow: pow}
+
+	if _, err := bus1.Publish("SENSORS", []byte("before subscribing")); err != nil {
+		t.Fatal(err)
+	}
+
+	msgs, stop, err := bus1.Subscribe("SENSORS")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+	// let the first poll record the existing message
+	time.Sleep(50 * time.Millisecond)
+
+	if _, err := bus2.Publish("OTHER", []byte("other topic")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bus2.Publish("SENSORS", []byte("temperature=21.5")); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case m := <-msgs:
+		switch {
+		case m.Err != nil:
+			t.Fatal(m.Err)
+		case string(m.Data) != "temperature=21.5" || m.Sender != sender2 || m.Topic != "SENSORS99999999999999999999":
+			t.Errorf("Subscribe() sent %q of %s on %s", m.Data, m.Sender, m.Topic)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Subscribe() sent no message")
+	}
+
+	select {
+	case m := <-msgs:
+		t.Errorf("Subscribe() sent %q again", m.Data)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestInvalidTopic(t *testing.T) {
+	bus := &Bus{}
+	for _, topic := range []giota.Trytes{"", "999", "abc", "AAAAAAAAAAAAAAAAAAAAAAAAAAAA"} {
+		if _, err := bus.Publish(topic, []byte("x")); err != ErrInvalidTopic {
+			t.Errorf("Publish() returned %v for topic %q", err, topic)
+		}
+		if _, _, err := bus.Subscribe(topic); err != ErrInvalidTopic {
+			t.Errorf("Subscribe() returned %v for topic %q", err, topic)
+		}
+	}
+}
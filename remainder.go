@@ -0,0 +1,146 @@
+package giota
+
+import (
+	"errors"
+	"fmt"
+)
+
+// RemainderPolicy tells where PrepareTransfersWithOptions sends the change
+// of the inputs.
+type RemainderPolicy int
+
+const (
+	// RemainderNewAddress sends the change to the remainder address given to
+	// PrepareTransfers, or to the first address of the seed without
+	// transactions.
+	RemainderNewAddress RemainderPolicy = iota
+	// RemainderHighestInput sends the change back to the spent input with
+	// the highest index. The bundle reveals a part of the key of the input,
+	// so the change is at risk until it is moved; it is meant for wallets
+	// which track their balance on a fixed address.
+	RemainderHighestInput
+	// RemainderSplit splits the change evenly across the first Split
+	// addresses of the seed without transactions.
+	RemainderSplit
+	// RemainderShares sends percentages of the change to the addresses of
+	// Shares.
+	RemainderShares
+)
+
+// RemainderShare is an address receiving Percent percent of the change.
+type RemainderShare struct {
+	Address Address
+	Percent int
+}
+
+// RemainderOptions are the options of the change of PrepareOptions.
+type RemainderOptions struct {
+	Policy RemainderPolicy
+	// Split is the number of addresses of RemainderSplit.
+	Split int
+	// Shares are the addresses of RemainderShares, whose percentages must
+	// sum up to 100. Rounding leftovers go to the first address.
+	Shares []RemainderShare
+}
+
+// validate checks the options, remainder being the remainder address given
+// to PrepareTransfers.
+func (o *RemainderOptions) validate(remainder Address) error {
+	var err error
+	switch {
+	case remainder != "" && o.Policy != RemainderNewAddress:
+		err = errors.New("remainder address given with a remainder policy without it")
+	case o.Policy == RemainderSplit && (o.Split < 1 || o.Split > MaxBundleSize):
+		err = fmt.Errorf("invalid split into %d addresses", o.Split)
+	case o.Policy == RemainderShares:
+		sum := 0
+		for i, s := range o.Shares {
+			if s.Percent < 0 {
+				return &ValidationError{Func: "PrepareTransfers", Arg: "Shares", Index: i, Err: errors.New("negative percentage")}
+			}
+			if _, err := parseAddress(string(s.Address)); err != nil {
+				return &ValidationError{Func: "PrepareTransfers", Arg: "Shares", Index: i, Err: err}
+			}
+			sum += s.Percent
+		}
+		if sum != 100 {
+			err = fmt.Errorf("percentages of shares sum up to %d", sum)
+		}
+	case o.Policy < RemainderNewAddress || o.Policy > RemainderShares:
+		err = fmt.Errorf("unknown remainder policy %d", o.Policy)
+	}
+	if err != nil {
+		return &ValidationError{Func: "PrepareTransfers", Arg: "Remainder", Index: -1, Err: err}
+	}
+	return nil
+}
+
+// outputs returns the outputs of the change of inputs spent.
+func (o *RemainderOptions) outputs(api APIClient, seed Trytes, security SecurityLevel, remainder Address, spent []AddressInfo, change int64) (Balances, error) {
+	switch o.Policy {
+	case RemainderHighestInput:
+		highest := spent[0]
+		for _, ai := range spent[1:] {
+			if ai.Index > highest.Index {
+				highest = ai
+			}
+		}
+		adr, err := highest.Address()
+		if err != nil {
+			return nil, err
+		}
+		return Balances{{Address: adr, Value: change, Index: highest.Index}}, nil
+
+	case RemainderSplit:
+		_, used, err := GetUsedAddress(api, seed, security)
+		if err != nil {
+			return nil, err
+		}
+		adrs, err := NewAddresses(seed, uint64(len(used)), o.Split, security)
+		if err != nil {
+			return nil, err
+		}
+		outs := make(Balances, 0, len(adrs))
+		for i, adr := range adrs {
+			v := change / int64(len(adrs))
+			if i == 0 {
+				v += change % int64(len(adrs))
+			}
+			if v > 0 {
+				outs = append(outs, Balance{Address: adr, Value: v, Index: uint64(len(used) + i)})
+			}
+		}
+		return outs, nil
+
+	case RemainderShares:
+		var outs Balances
+		rest := change
+		for _, s := range o.Shares {
+			v := change * int64(s.Percent) / 100
+			rest -= v
+			adr, _ := parseAddress(string(s.Address))
+			outs = append(outs, Balance{Address: adr, Value: v})
+		}
+		outs[0].Value += rest
+		nonzero := outs[:0]
+		for _, out := range outs {
+			if out.Value > 0 {
+				nonzero = append(nonzero, out)
+			}
+		}
+		return nonzero, nil
+	}
+
+	adr := withoutChecksum(Trytes(remainder))
+	var index uint64
+	if adr == "" {
+		var used []Address
+		var err error
+		adr, used, err = GetUsedAddress(api, seed, security)
+		if err != nil {
+			return nil, err
+		}
+		index = uint64(len(used))
+	}
+	return Balances{{Address: adr, Value: change, Index: index}}, nil
+}
@@ -0,0 +1,95 @@
+package giota
+
+import (
+	"testing"
+)
+
+func TestRemainderPolicies(t *testing.T) {
+	addr := func(index uint64) Address {
+		adr, err := NewAddress(accountTestSeed, index, SecurityLevelLow)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return adr
+	}
+	inputs := []AddressInfo{
+		{Seed: accountTestSeed, Index: 5, Security: SecurityLevelLow},
+		{Seed: accountTestSeed, Index: 3, Security: SecurityLevelLow},
+	}
+
+	tests := []struct {
+		name      string
+		opts      *RemainderOptions
+		remainder Address
+		want      Balances
+	}{
+		{
+			name: "default",
+			want: Balances{{Address: addr(0), Value: 99, Index: 0}},
+		},
+		{
+			name:      "remainder address",
+			opts:      &RemainderOptions{},
+			remainder: filterAddr1,
+			want:      Balances{{Address: filterAddr1, Value: 99}},
+		},
+		{
+			name: "highest input",
+			opts: &RemainderOptions{Policy: RemainderHighestInput},
+			want: Balances{{Address: addr(5), Value: 99, Index: 5}},
+		},
+		{
+			name: "split",
+			opts: &RemainderOptions{Policy: RemainderSplit, Split: 2},
+			want: Balances{{Address: addr(0), Value: 50, Index: 0}, {Address: addr(1), Value: 49, Index: 1}},
+		},
+		{
+			name: "shares",
+			opts: &RemainderOptions{Policy: RemainderShares, Shares: []RemainderShare{{filterAddr1, 1}, {Address(filterAddr2.WithChecksum()), 99}}},
+			want: Balances{{Address: filterAddr1, Value: 1}, {Address: filterAddr2, Value: 98}},
+		},
+		{
+			name: "shares beyond 100",
+			opts: &RemainderOptions{Policy: RemainderShares, Shares: []RemainderShare{{filterAddr1, 50}, {filterAddr2, 60}}},
+		},
+		{
+			name:      "split with remainder address",
+			opts:      &RemainderOptions{Policy: RemainderSplit, Split: 2},
+			remainder: filterAddr1,
+		},
+		{
+			name: "no split",
+			opts: &RemainderOptions{Policy: RemainderSplit},
+		},
+		{
+			name: "unknown policy",
+			opts: &RemainderOptions{Policy: 7},
+		},
+	}
+
+	for _, tt := range tests {
+		api, done := newFakeTangle(t, filterTestBundle())
+		trs := []Transfer{{Address: filterAddr1, Value: 301}}
+		bdl, report, err := PrepareTransfersWithReport(api, accountTestSeed, trs, inputs, tt.remainder, SecurityLevelLow, &PrepareOptions{Remainder: tt.opts})
+		done()
+
+		switch {
+		case tt.want == nil && err == nil:
+			t.Errorf("%s: PrepareTransfersWithReport() accepted %+v", tt.name, tt.opts)
+		case tt.want == nil:
+		case err != nil:
+			t.Errorf("%s: %s", tt.name, err)
+		case report.RemainderValue != 99 || report.Remainder != tt.want[0].Address || len(report.Remainders) != len(tt.want):
+			t.Errorf("%s: reported %+v", tt.name, report)
+		case len(bdl) != 3+len(tt.want):
+			t.Errorf("%s: PrepareTransfersWithReport() returned %d transactions", tt.name, len(bdl))
+		default:
+			for i, w := range tt.want {
+				tx := bdl[3+i]
+				if report.Remainders[i] != w || tx.Address != w.Address || tx.Value != w.Value {
+					t.Errorf("%s: remainder %d is %+v with transaction of %di to %s, expected %+v", tt.name, i, report.Remainders[i], tx.Value, tx.Address, w)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package giota
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// DefaultMaxResolveIndex is the number of indices searched by an
+// AddressIndexResolver if its MaxIndex is zero.
+const DefaultMaxResolveIndex = 1000
+
+// ErrAddressNotFound is returned by AddressIndexResolver if an address is
+// not among the searched addresses of the seed.
+var ErrAddressNotFound = errors.New("address not found for seed")
+
+// AddressIndexResolver recovers the index and security level of addresses of
+// a seed, e.g. to spend inputs given by address with PrepareTransfers. It
+// derives the addresses of indices 0 to MaxIndex-1 in parallel.
+type AddressIndexResolver struct {
+	Seed Trytes
+	// Securities are the searched security levels. If empty, all are.
+	Securities []SecurityLevel
+	// MaxIndex is the index after the last searched one. If zero,
+	// DefaultMaxResolveIndex is used.
+	MaxIndex uint64
+	// Workers is the number of goroutines deriving addresses. If zero,
+	// runtime.NumCPU is used.
+	Workers int
+}
+
+// NewAddressIndexResolver returns a resolver of the addresses of seed with the
+// default bounds.
+func NewAddressIndexResolver(seed Trytes) *AddressIndexResolver {
+	return &AddressIndexResolver{Seed: seed}
+}
+
+// Resolve returns the index and security level of adr, which may have a
+// checksum.
+func (r *AddressIndexResolver) Resolve(adr Trytes) (AddressInfo, error) {
+	infos, err := r.ResolveAll([]Trytes{adr})
+	if err != nil {
+		return AddressInfo{}, err
+	}
+	return infos[0], nil
+}
+
+// ResolveAll returns the index and security level of each address of adrs,
+// in the order of adrs, searching the indices once for all addresses. The
+// error of an unknown address wraps ErrAddressNotFound.
+func (r *AddressIndexResolver) ResolveAll(adrs []Trytes) ([]AddressInfo, error) {
+	parsed := make([]Address, len(adrs))
+	want := make(map[Address]bool, len(adrs))
+	for i, t := range adrs {
+		adr, err := parseAddress(string(t))
+		if err != nil {
+			return nil, &ValidationError{Func: "ResolveAll", Arg: "adrs", Index: i, Err: err}
+		}
+		parsed[i] = adr
+		want[adr] = true
+	}
+
+	secs := r.Securities
+	if len(secs) == 0 {
+		secs = []SecurityLevel{SecurityLevelLow, SecurityLevelMedium, SecurityLevelHigh}
+	}
+	var maxSec SecurityLevel
+	for _, s := range secs {
+		if err := s.IsValid(); err != nil {
+			return nil, &ValidationError{Func: "ResolveAll", Arg: "Securities", Index: -1, Err: err}
+		}
+		if s > maxSec {
+			maxSec = s
+		}
+	}
+
+	maxIndex := r.MaxIndex
+	if maxIndex == 0 {
+		maxIndex = DefaultMaxResolveIndex
+	}
+	workers := r.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	var (
+		mu      sync.Mutex
+		found   = make(map[Address]AddressInfo, len(want))
+		derr    error
+		indices = make(chan uint64)
+		done    = make(chan struct{})
+		stop    sync.Once
+		wg      sync.WaitGroup
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indices {
+				derived, err := r.addresses(index, secs, maxSec)
+				for i, adr := range derived {
+					if want[adr] {
+						mu.Lock()
+						found[adr] = AddressInfo{Seed: r.Seed, Index: index, Security: secs[i]}
+						if len(found) == len(want) {
+							stop.Do(func() { close(done) })
+						}
+						mu.Unlock()
+					}
+				}
+				if err != nil {
+					mu.Lock()
+					if derr == nil {
+						derr = err
+					}
+					mu.Unlock()
+					stop.Do(func() { close(done) })
+				}
+			}
+		}()
+	}
+
+feed:
+	for index := uint64(0); index < maxIndex && len(want) > 0; index++ {
+		select {
+		case indices <- index:
+		case <-done:
+			break feed
+		}
+	}
+	close(indices)
+	wg.Wait()
+
+	if derr != nil {
+		return nil, derr
+	}
+	infos := make([]AddressInfo, len(adrs))
+	for i, adr := range parsed {
+		info, ok := found[adr]
+		if !ok {
+			return nil, fmt.Errorf("%s: %w", adr, ErrAddressNotFound)
+		}
+		infos[i] = info
+	}
+	return infos, nil
+}
+
+// addresses returns the addresses of the seed at index with secs, taking
+// them from the address cache if all are cached. Otherwise they are derived
+// from the key of maxSec: the keys of lower security levels are prefixes of
+// it, so one key yields all addresses.
+func (r *AddressIndexResolver) addresses(index uint64, secs []SecurityLevel, maxSec SecurityLevel) ([]Address, error) {
+	adrs := make([]Address, len(secs))
+	keys := make([]AddressKey, len(secs))
+	cached := true
+	for i, sec := range secs {
+		keys[i] = NewAddressKey(r.Seed, index, sec)
+		if cached {
+			adrs[i], cached = cachedAddress(keys[i])
+		}
+	}
+	if cached {
+		return adrs, nil
+	}
+
+	key, err := newKeyTrits(r.Seed, index, maxSec)
+	if err != nil {
+		return nil, err
+	}
+	dg, err := Digests(key)
+	if err != nil {
+		return nil, err
+	}
+	for i, sec := range secs {
+		trits, err := calcAddress(dg[:int(sec)*HashSize])
+		if err != nil {
+			return nil, err
+		}
+		adrs[i] = Address(trits.Trytes())
+		cacheAddress(keys[i], adrs[i])
+	}
+	return adrs, nil
+}
@@ -0,0 +1,84 @@
+package giota
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAddressIndexResolver(t *testing.T) {
+	addr := func(index uint64, sec SecurityLevel) Address {
+		adr, err := NewAddress(accountTestSeed, index, sec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return adr
+	}
+
+	tests := []struct {
+		name     string
+		adrs     []Trytes
+		maxIndex uint64
+		secs     []SecurityLevel
+		want     []AddressInfo
+		notFound bool
+	}{
+		{
+			name: "several",
+			adrs: []Trytes{Trytes(addr(7, SecurityLevelHigh)), Trytes(addr(0, SecurityLevelLow)), Trytes(addr(3, SecurityLevelMedium))},
+			want: []AddressInfo{{accountTestSeed, 7, SecurityLevelHigh}, {accountTestSeed, 0, SecurityLevelLow}, {accountTestSeed, 3, SecurityLevelMedium}},
+		},
+		{
+			name: "checksum",
+			adrs: []Trytes{addr(2, SecurityLevelMedium).WithChecksum()},
+			want: []AddressInfo{{accountTestSeed, 2, SecurityLevelMedium}},
+		},
+		{
+			name: "security",
+			adrs: []Trytes{Trytes(addr(1, SecurityLevelLow))},
+			secs: []SecurityLevel{SecurityLevelLow},
+			want: []AddressInfo{{accountTestSeed, 1, SecurityLevelLow}},
+		},
+		{
+			name:     "beyond max index",
+			adrs:     []Trytes{Trytes(addr(5, SecurityLevelMedium))},
+			maxIndex: 5,
+			notFound: true,
+		},
+		{
+			name:     "other security",
+			adrs:     []Trytes{Trytes(addr(1, SecurityLevelHigh))},
+			maxIndex: 3,
+			secs:     []SecurityLevel{SecurityLevelLow, SecurityLevelMedium},
+			notFound: true,
+		},
+	}
+
+	for _, tt := range tests {
+		r := NewAddressIndexResolver(accountTestSeed)
+		r.MaxIndex = tt.maxIndex
+		if r.MaxIndex == 0 {
+			r.MaxIndex = 10
+		}
+		r.Securities = tt.secs
+		got, err := r.ResolveAll(tt.adrs)
+		switch {
+		case tt.notFound && !errors.Is(err, ErrAddressNotFound):
+			t.Errorf("%s: ResolveAll() returned error %v, expected ErrAddressNotFound", tt.name, err)
+		case tt.notFound:
+		case err != nil:
+			t.Errorf("%s: %s", tt.name, err)
+		case len(got) != len(tt.want):
+			t.Errorf("%s: ResolveAll() returned %v", tt.name, got)
+		default:
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("%s: ResolveAll() returned %v for address %d, expected %v", tt.name, got[i], i, tt.want[i])
+				}
+			}
+		}
+	}
+
+	if _, err := NewAddressIndexResolver(accountTestSeed).Resolve("ABC"); err == nil {
+		t.Error("Resolve() accepted an invalid address")
+	}
+}
@@ -0,0 +1,103 @@
+package giota
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DefaultMaxResponseSize is the max size of a response of a node, which is
+// enough for getTrytes of several thousand transactions.
+const DefaultMaxResponseSize = 64 << 20
+
+// ErrResponseTooLarge is returned by API calls if the response of the node
+// exceeds the max response size.
+var ErrResponseTooLarge = errors.New("response of the node is too large")
+
+// SetMaxResponseSize sets the max size of responses of the node in bytes.
+// If n is zero or negative, DefaultMaxResponseSize is used. It must not be
+// called concurrently with API calls.
+func (api *API) SetMaxResponseSize(n int64) {
+	api.maxResponseSize = n
+}
+
+// MaxResponseSize returns the max size of responses of the node.
+func (api *API) MaxResponseSize() int64 {
+	if api.maxResponseSize <= 0 {
+		return DefaultMaxResponseSize
+	}
+	return api.maxResponseSize
+}
+
+// limitReader returns ErrResponseTooLarge once more than max bytes are read.
+type limitReader struct {
+	r    io.Reader
+	left int64
+}
+
+func (api *API) limitResponse(r io.Reader) io.Reader {
+	return &limitReader{r: r, left: api.MaxResponseSize()}
+}
+
+func (l *limitReader) Read(p []byte) (int, error) {
+	// read one byte more than allowed to detect the excess
+	if int64(len(p)) > l.left+1 {
+		p = p[:l.left+1]
+	}
+	n, err := l.r.Read(p)
+	l.left -= int64(n)
+	if l.left < 0 {
+		return n, ErrResponseTooLarge
+	}
+	return n, err
+}
+
+// checkResponseLen returns an error wrapping ErrNodeResponse unless the node
+// returned as many results as it was asked for, so that the results can be
+// indexed like the arguments.
+func checkResponseLen(results string, got int, args string, want int) error {
+	if got != want {
+		return fmt.Errorf("%w: %d %s for %d %s", ErrNodeResponse, got, results, want, args)
+	}
+	return nil
+}
+
+// errorPeekSize is the number of bytes of a response searched for the key
+// of an error object.
+const errorPeekSize = 128
+
+// decodeResponse decodes the JSON response of the node from r into out,
+// without buffering it entirely. Responses whose first key is "error" or
+// "exception" are returned as error.
+func decodeResponse(r io.Reader, out interface{}) error {
+	br := bufio.NewReader(r)
+	head, err := br.Peek(errorPeekSize)
+	if err != nil && err != io.EOF {
+		return err
+	}
+
+	if isErrorObject(head) {
+		errResp := &ErrorResponse{}
+		err := json.NewDecoder(br).Decode(errResp)
+		return handleError(errResp, err, fmt.Errorf("%w: unknown error", ErrNodeResponse))
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(br).Decode(out)
+}
+
+// isErrorObject returns true if the first key of the JSON object starting
+// with head is "error" or "exception".
+func isErrorObject(head []byte) bool {
+	dec := json.NewDecoder(bytes.NewReader(head))
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return false
+	}
+	t, err := dec.Token()
+	return err == nil && (t == "error" || t == "exception")
+}
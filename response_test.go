@@ -0,0 +1,107 @@
+package giota
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDecodeResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		max     int64
+		hashes  int
+		err     string
+		tooLong bool
+	}{
+		{name: "hashes", status: 200, body: `{"hashes":["A","B"],"duration":1}`, hashes: 2},
+		{name: "error", status: 200, body: ` {"error":"invalid hash","duration":1}`, err: "invalid hash"},
+		{name: "exception", status: 200, body: `{"exception":"boom"}`, err: "boom"},
+		{name: "error status", status: 400, body: `{"error":"bad request"}`, err: "bad request"},
+		{name: "hash named error", status: 200, body: `{"hashes":["error"]}`, hashes: 1},
+		{name: "within limit", status: 200, body: `{"hashes":["A"]}`, max: 16, hashes: 1},
+		{name: "too large", status: 200, body: `{"hashes":["` + strings.Repeat("A", 200) + `"]}`, max: 100, tooLong: true},
+		{name: "too large error", status: 500, body: `{"error":"` + strings.Repeat("A", 200) + `"}`, max: 100, tooLong: true},
+	}
+
+	for _, tt := range tests {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+			io.WriteString(w, tt.body)
+		}))
+		api := NewAPI(srv.URL, nil)
+		api.SetMaxResponseSize(tt.max)
+
+		resp, err := api.FindTransactions(&FindTransactionsRequest{Bundles: []BundleHash{EmptyHash}})
+		srv.Close()
+
+		switch {
+		case tt.tooLong && !errors.Is(err, ErrResponseTooLarge):
+			t.Errorf("%s: expected ErrResponseTooLarge, got %v", tt.name, err)
+		case tt.tooLong:
+		case tt.err != "" && (!errors.Is(err, ErrNodeResponse) || !strings.HasSuffix(err.Error(), ": "+tt.err)):
+			t.Errorf("%s: expected error %q, got %v", tt.name, tt.err, err)
+		case tt.err != "":
+		case err != nil:
+			t.Errorf("%s: %s", tt.name, err)
+		case len(resp.Hashes) != tt.hashes:
+			t.Errorf("%s: decoded %d hashes, expected %d", tt.name, len(resp.Hashes), tt.hashes)
+		}
+	}
+}
+
+func TestResponseLen(t *testing.T) {
+	api, done := newFakeNode(t, map[string]fakeNodeHandler{
+		"getBalances": func(req map[string]json.RawMessage) interface{} {
+			return map[string]interface{}{"balances": []string{"1", "2"}}
+		},
+		"getInclusionStates": func(req map[string]json.RawMessage) interface{} {
+			return map[string]interface{}{"states": []bool{}}
+		},
+		"getTrytes": func(req map[string]json.RawMessage) interface{} {
+			return map[string]interface{}{"trytes": []Transaction{}}
+		},
+		"getTransactionsToApprove": func(req map[string]json.RawMessage) interface{} {
+			return map[string]interface{}{"trunkTransaction": "A", "branchTransaction": EmptyHash}
+		},
+	})
+	defer done()
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"Balances", func() error {
+			_, err := api.Balances([]Address{filterAddr1})
+			return err
+		}},
+		{"GetInclusionStates", func() error {
+			_, err := api.GetInclusionStates([]Hash{EmptyHash}, []Hash{EmptyHash})
+			return err
+		}},
+		{"GetTrytes", func() error {
+			_, err := api.GetTrytes([]Hash{EmptyHash})
+			return err
+		}},
+		{"GetLatestInclusion", func() error {
+			_, err := api.GetLatestInclusion([]Hash{EmptyHash})
+			return err
+		}},
+		{"GetTransactionsToApprove", func() error {
+			_, err := api.GetTransactionsToApprove(3, 0, "")
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		if err := tt.call(); !errors.Is(err, ErrNodeResponse) {
+			t.Errorf("%s returned %v, expected ErrNodeResponse", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,542 @@
+package seedvault
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/iotaledger/giota/internal/argon2"
+	"golang.org/x/crypto/chacha20"
+)
+
+// signatures and the major version of KDBX 4 files.
+const (
+	signature1   = 0x9aa2d903
+	signature2   = 0xb54bfb67
+	versionMajor = 4
+)
+
+// ids of the fields of the outer header.
+const (
+	headerEnd           = 0
+	headerCipherID      = 2
+	headerCompression   = 3
+	headerMasterSeed    = 4
+	headerEncryptionIV  = 7
+	headerKdfParameters = 11
+)
+
+// ids of the fields of the inner header.
+const (
+	innerEnd       = 0
+	innerStreamID  = 1
+	innerStreamKey = 2
+)
+
+const (
+	compressionGzip = 1
+	streamChaCha20  = 3
+
+	// blockSize is the size of the HMAC blocks written.
+	blockSize = 1 << 20
+	// maxBlockSize limits the HMAC blocks read.
+	maxBlockSize = 64 << 20
+	// maxKdfMemory limits the memory of Argon2 read from a header.
+	maxKdfMemory = 1 << 30
+)
+
+// UUIDs of the ciphers and key derivation functions.
+var (
+	cipherAES256   = []byte{0x31, 0xc1, 0xf2, 0xe6, 0xbf, 0x71, 0x43, 0x50, 0xbe, 0x58, 0x05, 0x21, 0x6a, 0xfc, 0x5a, 0xff}
+	cipherChaCha20 = []byte{0xd6, 0x03, 0x8a, 0x2b, 0x8b, 0x6f, 0x4c, 0xb5, 0xa5, 0x24, 0x33, 0x9a, 0x31, 0xdb, 0xb5, 0x9a}
+	kdfAES         = []byte{0xc9, 0xd9, 0xf3, 0x9a, 0x62, 0x8a, 0x44, 0x60, 0xbf, 0x74, 0x0d, 0x08, 0xc1, 0x8a, 0x4f, 0xea}
+	kdfArgon2d     = []byte{0xef, 0x63, 0x6d, 0xdf, 0x8c, 0x29, 0x44, 0x4b, 0x91, 0xf7, 0xa9, 0xa4, 0x03, 0xe3, 0x0a, 0x0c}
+	kdfArgon2id    = []byte{0x9e, 0x29, 0x8b, 0x19, 0x56, 0xdb, 0x47, 0x73, 0xb2, 0x3d, 0xfc, 0x3e, 0xc6, 0xf0, 0xa1, 0xe6}
+)
+
+// header is the outer header of a KDBX 4 file.
+type header struct {
+	cipherID    []byte
+	compression uint32
+	masterSeed  []byte
+	iv          []byte
+	kdf         variantDict
+	// raw are the bytes of the header, which are authenticated.
+	raw []byte
+}
+
+func readHeader(r io.Reader) (*header, error) {
+	var raw bytes.Buffer
+	r = io.TeeReader(r, &raw)
+
+	var start [12]byte
+	if _, err := io.ReadFull(r, start[:]); err != nil {
+		return nil, ErrFormat
+	}
+	if binary.LittleEndian.Uint32(start[0:]) != signature1 || binary.LittleEndian.Uint32(start[4:]) != signature2 {
+		return nil, ErrFormat
+	}
+	if major := binary.LittleEndian.Uint16(start[10:]); major != versionMajor {
+		return nil, fmt.Errorf("%w: %d", ErrVersion, major)
+	}
+
+	h := &header{}
+	for {
+		id, data, err := readField(r)
+		if err != nil {
+			return nil, err
+		}
+		switch id {
+		case headerEnd:
+			h.raw = raw.Bytes()
+			return h, h.check()
+		case headerCipherID:
+			h.cipherID = data
+		case headerCompression:
+			if len(data) != 4 {
+				return nil, ErrCorrupt
+			}
+			h.compression = binary.LittleEndian.Uint32(data)
+		case headerMasterSeed:
+			h.masterSeed = data
+		case headerEncryptionIV:
+			h.iv = data
+		case headerKdfParameters:
+			if h.kdf, err = parseVariantDict(data); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// newHeader returns the header of a new database, which is encrypted with
+// AES-256 and keyed with Argon2d.
+func newHeader(o Options) (*header, error) {
+	h := &header{
+		cipherID:    cipherAES256,
+		compression: compressionGzip,
+		masterSeed:  make([]byte, 32),
+		iv:          make([]byte, aes.BlockSize),
+	}
+	salt := make([]byte, 32)
+	for _, b := range [][]byte{h.masterSeed, h.iv, salt} {
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+	}
+	h.kdf.set("$UUID", variantByteArray, kdfArgon2d)
+	h.kdf.set("S", variantByteArray, salt)
+	h.kdf.setUint32("P", o.Parallelism)
+	h.kdf.setUint64("M", o.Memory)
+	h.kdf.setUint64("I", o.Iterations)
+	h.kdf.setUint32("V", argon2.Version)
+	h.marshal()
+	return h, nil
+}
+
+// marshal sets the raw bytes of h from its fields.
+func (h *header) marshal() {
+	b := binary.LittleEndian.AppendUint32(nil, signature1)
+	b = binary.LittleEndian.AppendUint32(b, signature2)
+	b = binary.LittleEndian.AppendUint32(b, versionMajor<<16)
+	b = appendField(b, headerCipherID, h.cipherID)
+	b = appendField(b, headerCompression, binary.LittleEndian.AppendUint32(nil, h.compression))
+	b = appendField(b, headerMasterSeed, h.masterSeed)
+	b = appendField(b, headerEncryptionIV, h.iv)
+	b = appendField(b, headerKdfParameters, h.kdf.bytes())
+	h.raw = appendField(b, headerEnd, []byte("\r\n\r\n"))
+}
+
+func (h *header) check() error {
+	switch {
+	case len(h.masterSeed) != 32:
+		return ErrCorrupt
+	case bytes.Equal(h.cipherID, cipherAES256) && len(h.iv) != aes.BlockSize:
+		return ErrCorrupt
+	case bytes.Equal(h.cipherID, cipherChaCha20) && len(h.iv) != chacha20.NonceSize:
+		return ErrCorrupt
+	case !bytes.Equal(h.cipherID, cipherAES256) && !bytes.Equal(h.cipherID, cipherChaCha20):
+		return fmt.Errorf("%w: cipher %x", ErrUnsupported, h.cipherID)
+	case h.compression > compressionGzip:
+		return fmt.Errorf("%w: compression %d", ErrUnsupported, h.compression)
+	}
+	return nil
+}
+
+// readField reads a field of a header with a 4 byte size.
+func readField(r io.Reader) (byte, []byte, error) {
+	var prefix [5]byte
+	if _, err := io.ReadFull(r, prefix[:]); err != nil {
+		return 0, nil, ErrCorrupt
+	}
+	size := binary.LittleEndian.Uint32(prefix[1:])
+	if size > maxBlockSize {
+		return 0, nil, ErrCorrupt
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return 0, nil, ErrCorrupt
+	}
+	return prefix[0], data, nil
+}
+
+func appendField(b []byte, id byte, data []byte) []byte {
+	b = append(b, id)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(data)))
+	return append(b, data...)
+}
+
+// keys are the keys derived from the password and the master seed.
+type keys struct {
+	cipher []byte
+	hmac   []byte
+}
+
+func deriveKeys(h *header, password string) (*keys, error) {
+	pw := sha256.Sum256([]byte(password))
+	composite := sha256.Sum256(pw[:])
+	transformed, err := h.kdf.transform(composite[:])
+	if err != nil {
+		return nil, err
+	}
+
+	seeded := append(append([]byte{}, h.masterSeed...), transformed...)
+	c := sha256.Sum256(seeded)
+	m := sha512.Sum512(append(seeded, 0x01))
+	return &keys{cipher: c[:], hmac: m[:]}, nil
+}
+
+// blockKey returns the HMAC key of block index. The header is authenticated
+// with the index 2^64-1.
+func (k *keys) blockKey(index uint64) []byte {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], index)
+	sum := sha512.Sum512(append(b[:], k.hmac...))
+	return sum[:]
+}
+
+func (k *keys) headerHMAC(raw []byte) []byte {
+	mac := hmac.New(sha256.New, k.blockKey(^uint64(0)))
+	mac.Write(raw)
+	return mac.Sum(nil)
+}
+
+func (k *keys) blockHMAC(index uint64, data []byte) []byte {
+	var b [12]byte
+	binary.LittleEndian.PutUint64(b[:], index)
+	binary.LittleEndian.PutUint32(b[8:], uint32(len(data)))
+	mac := hmac.New(sha256.New, k.blockKey(index))
+	mac.Write(b[:])
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// readBlocks reads the HMAC blocks of the payload up to the empty block.
+func (k *keys) readBlocks(r io.Reader) ([]byte, error) {
+	var payload []byte
+	br := bufio.NewReader(r)
+	for index := uint64(0); ; index++ {
+		var prefix [36]byte
+		if _, err := io.ReadFull(br, prefix[:]); err != nil {
+			return nil, ErrCorrupt
+		}
+		size := binary.LittleEndian.Uint32(prefix[32:])
+		if size > maxBlockSize {
+			return nil, ErrCorrupt
+		}
+		data := make([]byte, size)
+		if _, err := io.ReadFull(br, data); err != nil {
+			return nil, ErrCorrupt
+		}
+		if !hmac.Equal(prefix[:32], k.blockHMAC(index, data)) {
+			return nil, ErrCorrupt
+		}
+		if size == 0 {
+			return payload, nil
+		}
+		payload = append(payload, data...)
+	}
+}
+
+func (k *keys) writeBlocks(w io.Writer, payload []byte) error {
+	for index := uint64(0); ; index++ {
+		n := len(payload)
+		if n > blockSize {
+			n = blockSize
+		}
+		data := payload[:n]
+		payload = payload[n:]
+
+		var size [4]byte
+		binary.LittleEndian.PutUint32(size[:], uint32(n))
+		for _, b := range [][]byte{k.blockHMAC(index, data), size[:], data} {
+			if _, err := w.Write(b); err != nil {
+				return err
+			}
+		}
+		if n == 0 {
+			return nil
+		}
+	}
+}
+
+func (k *keys) decrypt(h *header, data []byte) ([]byte, error) {
+	if bytes.Equal(h.cipherID, cipherChaCha20) {
+		return k.chacha20(h, data)
+	}
+
+	if len(data) == 0 || len(data)%aes.BlockSize != 0 {
+		return nil, ErrCorrupt
+	}
+	b, err := aes.NewCipher(k.cipher)
+	if err != nil {
+		return nil, err
+	}
+	cipher.NewCBCDecrypter(b, h.iv).CryptBlocks(data, data)
+
+	// PKCS #7 padding
+	pad := int(data[len(data)-1])
+	if pad == 0 || pad > aes.BlockSize {
+		return nil, ErrCorrupt
+	}
+	for _, p := range data[len(data)-pad:] {
+		if int(p) != pad {
+			return nil, ErrCorrupt
+		}
+	}
+	return data[:len(data)-pad], nil
+}
+
+func (k *keys) encrypt(h *header, data []byte) ([]byte, error) {
+	if bytes.Equal(h.cipherID, cipherChaCha20) {
+		return k.chacha20(h, data)
+	}
+
+	pad := aes.BlockSize - len(data)%aes.BlockSize
+	data = append(data, bytes.Repeat([]byte{byte(pad)}, pad)...)
+	b, err := aes.NewCipher(k.cipher)
+	if err != nil {
+		return nil, err
+	}
+	cipher.NewCBCEncrypter(b, h.iv).CryptBlocks(data, data)
+	return data, nil
+}
+
+func (k *keys) chacha20(h *header, data []byte) ([]byte, error) {
+	c, err := chacha20.NewUnauthenticatedCipher(k.cipher, h.iv)
+	if err != nil {
+		return nil, err
+	}
+	c.XORKeyStream(data, data)
+	return data, nil
+}
+
+// readInnerHeader reads the inner header from the start of the decrypted
+// payload and returns the stream that protected values are encrypted with,
+// and the rest of the payload.
+func readInnerHeader(payload []byte) (cipher.Stream, []byte, error) {
+	r := bytes.NewReader(payload)
+	var (
+		id  uint32
+		key []byte
+	)
+	for {
+		field, data, err := readField(r)
+		if err != nil {
+			return nil, nil, err
+		}
+		switch field {
+		case innerEnd:
+			if id != streamChaCha20 {
+				return nil, nil, fmt.Errorf("%w: inner stream %d", ErrUnsupported, id)
+			}
+			stream, err := newInnerStream(key)
+			return stream, payload[len(payload)-r.Len():], err
+		case innerStreamID:
+			if len(data) != 4 {
+				return nil, nil, ErrCorrupt
+			}
+			id = binary.LittleEndian.Uint32(data)
+		case innerStreamKey:
+			key = data
+		}
+	}
+}
+
+func appendInnerHeader(b []byte, key []byte) []byte {
+	var id [4]byte
+	binary.LittleEndian.PutUint32(id[:], streamChaCha20)
+	b = appendField(b, innerStreamID, id[:])
+	b = appendField(b, innerStreamKey, key)
+	return appendField(b, innerEnd, nil)
+}
+
+// newInnerStream returns the ChaCha20 stream of the inner random stream key.
+func newInnerStream(key []byte) (cipher.Stream, error) {
+	if len(key) == 0 {
+		return nil, ErrCorrupt
+	}
+	k := sha512.Sum512(key)
+	return chacha20.NewUnauthenticatedCipher(k[:chacha20.KeySize], k[chacha20.KeySize:chacha20.KeySize+chacha20.NonceSize])
+}
+
+// types of the values of a variant dictionary.
+const (
+	variantUint32    = 0x04
+	variantUint64    = 0x05
+	variantByteArray = 0x42
+	variantVersion   = 0x0100
+)
+
+// variantDict is a variant dictionary of KDBX 4, which holds the parameters
+// of the key derivation function. Values keep their encoding.
+type variantDict struct {
+	keys   []string
+	types  map[string]byte
+	values map[string][]byte
+}
+
+func parseVariantDict(b []byte) (variantDict, error) {
+	d := variantDict{types: make(map[string]byte), values: make(map[string][]byte)}
+	if len(b) < 2 || binary.LittleEndian.Uint16(b)&0xff00 != variantVersion&0xff00 {
+		return d, ErrCorrupt
+	}
+	b = b[2:]
+	next := func() ([]byte, bool) {
+		if len(b) < 4 {
+			return nil, false
+		}
+		n := binary.LittleEndian.Uint32(b)
+		if uint64(n) > uint64(len(b)-4) {
+			return nil, false
+		}
+		v := b[4 : 4+n]
+		b = b[4+n:]
+		return v, true
+	}
+	for {
+		if len(b) == 0 {
+			return d, ErrCorrupt
+		}
+		typ := b[0]
+		b = b[1:]
+		if typ == 0 {
+			return d, nil
+		}
+		k, ok := next()
+		if !ok {
+			return d, ErrCorrupt
+		}
+		v, ok := next()
+		if !ok {
+			return d, ErrCorrupt
+		}
+		d.set(string(k), typ, v)
+	}
+}
+
+func (d *variantDict) set(key string, typ byte, value []byte) {
+	if d.types == nil {
+		d.types = make(map[string]byte)
+		d.values = make(map[string][]byte)
+	}
+	if _, ok := d.values[key]; !ok {
+		d.keys = append(d.keys, key)
+	}
+	d.types[key] = typ
+	d.values[key] = value
+}
+
+func (d *variantDict) setUint32(key string, v uint32) {
+	d.set(key, variantUint32, binary.LittleEndian.AppendUint32(nil, v))
+}
+
+func (d *variantDict) setUint64(key string, v uint64) {
+	d.set(key, variantUint64, binary.LittleEndian.AppendUint64(nil, v))
+}
+
+func (d *variantDict) bytes() []byte {
+	b := binary.LittleEndian.AppendUint16(nil, variantVersion)
+	for _, k := range d.keys {
+		b = append(b, d.types[k])
+		b = binary.LittleEndian.AppendUint32(b, uint32(len(k)))
+		b = append(b, k...)
+		b = binary.LittleEndian.AppendUint32(b, uint32(len(d.values[k])))
+		b = append(b, d.values[k]...)
+	}
+	return append(b, 0)
+}
+
+func (d *variantDict) uint32(key string) (uint32, error) {
+	if v := d.values[key]; len(v) == 4 && d.types[key] == variantUint32 {
+		return binary.LittleEndian.Uint32(v), nil
+	}
+	return 0, fmt.Errorf("%w: KDF parameter %s", ErrCorrupt, key)
+}
+
+func (d *variantDict) uint64(key string) (uint64, error) {
+	if v := d.values[key]; len(v) == 8 && d.types[key] == variantUint64 {
+		return binary.LittleEndian.Uint64(v), nil
+	}
+	return 0, fmt.Errorf("%w: KDF parameter %s", ErrCorrupt, key)
+}
+
+// transform derives the transformed key from the composite key with the
+// key derivation function of d.
+func (d *variantDict) transform(composite []byte) ([]byte, error) {
+	uuid := d.values["$UUID"]
+	salt := d.values["S"]
+	switch {
+	case bytes.Equal(uuid, kdfArgon2d), bytes.Equal(uuid, kdfArgon2id):
+		typ := argon2.Argon2d
+		if bytes.Equal(uuid, kdfArgon2id) {
+			typ = argon2.Argon2id
+		}
+		var (
+			errs                 [4]error
+			parallelism, version uint32
+			memory, iterations   uint64
+		)
+		parallelism, errs[0] = d.uint32("P")
+		version, errs[1] = d.uint32("V")
+		memory, errs[2] = d.uint64("M")
+		iterations, errs[3] = d.uint64("I")
+		if err := errors.Join(errs[:]...); err != nil {
+			return nil, err
+		}
+		switch {
+		case version != argon2.Version:
+			return nil, fmt.Errorf("%w: Argon2 version %#x", ErrUnsupported, version)
+		case parallelism < 1 || iterations < 1 || iterations > 1<<32-1 || memory > maxKdfMemory:
+			return nil, fmt.Errorf("%w: Argon2 parameters", ErrUnsupported)
+		}
+		return argon2.Key(typ, composite, salt, d.values["K"], d.values["A"], uint32(iterations), uint32(memory/1024), parallelism, 32), nil
+
+	case bytes.Equal(uuid, kdfAES):
+		rounds, err := d.uint64("R")
+		if err != nil {
+			return nil, err
+		}
+		b, err := aes.NewCipher(salt)
+		if err != nil {
+			return nil, fmt.Errorf("%w: AES-KDF seed", ErrCorrupt)
+		}
+		key := append([]byte{}, composite...)
+		for i := uint64(0); i < rounds; i++ {
+			b.Encrypt(key[:16], key[:16])
+			b.Encrypt(key[16:], key[16:])
+		}
+		sum := sha256.Sum256(key)
+		return sum[:], nil
+	}
+	return nil, fmt.Errorf("%w: KDF %x", ErrUnsupported, uuid)
+}
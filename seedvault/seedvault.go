@@ -0,0 +1,348 @@
+// Package seedvault reads and writes Trinity SeedVault files, so that seeds
+// can be moved between the Trinity wallet and applications built on giota.
+//
+// A SeedVault is a KeePass database in the KDBX 4 format, protected by a
+// password. Each entry of its default group holds a seed in its Seed field
+// and the name of the account in its Title:
+//
+//	seeds, err := seedvault.Read(f, password)
+//	err = seedvault.Write(w, password, []seedvault.Seed{{Title: "savings", Seed: seed}})
+package seedvault
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/iotaledger/giota"
+)
+
+var (
+	// ErrFormat is returned for files that aren't KDBX databases.
+	ErrFormat = errors.New("file is not a KDBX database")
+	// ErrVersion is returned for KDBX databases of another major version
+	// than 4.
+	ErrVersion = errors.New("unsupported KDBX version")
+	// ErrUnsupported is returned for databases using a cipher or key
+	// derivation function that isn't supported.
+	ErrUnsupported = errors.New("unsupported KDBX database")
+	// ErrPassword is returned if the password doesn't open the database.
+	ErrPassword = errors.New("wrong password")
+	// ErrCorrupt is returned for databases that are damaged or were
+	// tampered with.
+	ErrCorrupt = errors.New("KDBX database is corrupted")
+	// ErrInvalidSeed is returned for entries whose seed isn't 81 trytes.
+	ErrInvalidSeed = errors.New("invalid seed")
+)
+
+// Seed is an entry of a SeedVault.
+type Seed struct {
+	// Title is the name of the account in Trinity.
+	Title string
+	Seed  giota.Trytes
+}
+
+// Options are the parameters of the Argon2d key derivation of
+// WriteWithOptions. Zero fields take the defaults of KeePass.
+type Options struct {
+	// Iterations is the number of passes, 2 by default.
+	Iterations uint64
+	// Memory is the memory in bytes, 64 MiB by default. It is rounded down
+	// to a multiple of 1 KiB.
+	Memory uint64
+	// Parallelism is the number of lanes, 2 by default.
+	Parallelism uint32
+}
+
+// Read reads the seeds of the SeedVault of r opened with password. Entries
+// without a Seed field are skipped like Trinity does.
+func Read(r io.Reader, password string) ([]Seed, error) {
+	h, err := readHeader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var sums [64]byte
+	if _, err := io.ReadFull(r, sums[:]); err != nil {
+		return nil, ErrCorrupt
+	}
+	if sum := sha256.Sum256(h.raw); !bytes.Equal(sum[:], sums[:32]) {
+		return nil, ErrCorrupt
+	}
+	k, err := deriveKeys(h, password)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(k.headerHMAC(h.raw), sums[32:]) {
+		return nil, ErrPassword
+	}
+
+	payload, err := k.readBlocks(r)
+	if err != nil {
+		return nil, err
+	}
+	if payload, err = k.decrypt(h, payload); err != nil {
+		return nil, err
+	}
+	if h.compression == compressionGzip {
+		zr, err := gzip.NewReader(bytes.NewReader(payload))
+		if err != nil {
+			return nil, ErrCorrupt
+		}
+		if payload, err = io.ReadAll(zr); err != nil {
+			return nil, ErrCorrupt
+		}
+	}
+
+	stream, doc, err := readInnerHeader(payload)
+	if err != nil {
+		return nil, err
+	}
+	return readSeeds(doc, func(b []byte) { stream.XORKeyStream(b, b) })
+}
+
+// readSeeds returns the seeds of the entries of the default group of the
+// XML document doc. unprotect decrypts protected values; it must see all of
+// them in the order of the document.
+func readSeeds(doc []byte, unprotect func([]byte)) ([]Seed, error) {
+	var (
+		seeds  []Seed
+		path   []string
+		groups int
+		fields map[string]string
+		key    string
+	)
+	dec := xml.NewDecoder(bytes.NewReader(doc))
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return seeds, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrCorrupt, err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Local != "Key" && t.Name.Local != "Value" {
+				path = append(path, t.Name.Local)
+				if strings.Join(path, "/") == "KeePassFile/Root/Group" {
+					groups++
+				}
+				if inDefaultEntry(path, groups) && len(path) == 4 {
+					fields = make(map[string]string)
+				}
+				continue
+			}
+
+			var text string
+			if err := dec.DecodeElement(&text, &t); err != nil {
+				return nil, fmt.Errorf("%w: %s", ErrCorrupt, err)
+			}
+			if t.Name.Local == "Value" && isProtected(t) {
+				b, err := base64.StdEncoding.DecodeString(text)
+				if err != nil {
+					return nil, fmt.Errorf("%w: %s", ErrCorrupt, err)
+				}
+				unprotect(b)
+				text = string(b)
+			}
+			if !inDefaultEntry(path, groups) || len(path) != 5 || path[4] != "String" {
+				continue
+			}
+			if t.Name.Local == "Key" {
+				key = text
+			} else {
+				fields[key] = text
+			}
+
+		case xml.EndElement:
+			if inDefaultEntry(path, groups) && len(path) == 4 {
+				if s, ok := fields["Seed"]; ok {
+					seed := giota.Trytes(strings.ToUpper(strings.TrimSpace(s)))
+					if err := giota.IsHash(seed); err != nil {
+						return nil, fmt.Errorf("%w: entry %q: %s", ErrInvalidSeed, fields["Title"], err)
+					}
+					seeds = append(seeds, Seed{Title: fields["Title"], Seed: seed})
+				}
+			}
+			if len(path) > 0 {
+				path = path[:len(path)-1]
+			}
+		}
+	}
+}
+
+// inDefaultEntry returns whether path is in an entry of the first group of
+// the root, the default group.
+func inDefaultEntry(path []string, groups int) bool {
+	return groups == 1 && len(path) >= 4 && path[0] == "KeePassFile" && path[1] == "Root" && path[2] == "Group" && path[3] == "Entry"
+}
+
+func isProtected(t xml.StartElement) bool {
+	for _, a := range t.Attr {
+		if a.Name.Local == "Protected" {
+			return strings.EqualFold(a.Value, "True")
+		}
+	}
+	return false
+}
+
+// Write writes seeds to w as a SeedVault protected by password, with the
+// default Options.
+func Write(w io.Writer, password string, seeds []Seed) error {
+	return WriteWithOptions(w, password, seeds, nil)
+}
+
+// WriteWithOptions writes seeds to w as a SeedVault protected by password.
+// Seeds without a title are named like Trinity names them.
+func WriteWithOptions(w io.Writer, password string, seeds []Seed, opts *Options) error {
+	for i, s := range seeds {
+		if err := giota.IsHash(s.Seed); err != nil {
+			return fmt.Errorf("%w: seed %d: %s", ErrInvalidSeed, i, err)
+		}
+	}
+
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+	if o.Iterations == 0 {
+		o.Iterations = 2
+	}
+	if o.Memory == 0 {
+		o.Memory = 64 << 20
+	}
+	if o.Parallelism == 0 {
+		o.Parallelism = 2
+	}
+
+	if o.Memory > maxKdfMemory {
+		return fmt.Errorf("%w: Argon2 memory of %d bytes", ErrUnsupported, o.Memory)
+	}
+
+	h, err := newHeader(o)
+	if err != nil {
+		return err
+	}
+	return write(w, h, password, seeds)
+}
+
+// write writes seeds to w as a database with the header h.
+func write(w io.Writer, h *header, password string, seeds []Seed) error {
+	k, err := deriveKeys(h, password)
+	if err != nil {
+		return err
+	}
+
+	streamKey := make([]byte, 64)
+	if _, err := rand.Read(streamKey); err != nil {
+		return err
+	}
+	stream, err := newInnerStream(streamKey)
+	if err != nil {
+		return err
+	}
+	doc, err := writeSeeds(seeds, func(b []byte) { stream.XORKeyStream(b, b) })
+	if err != nil {
+		return err
+	}
+
+	var payload bytes.Buffer
+	zw := gzip.NewWriter(&payload)
+	zw.Write(appendInnerHeader(nil, streamKey))
+	zw.Write(doc)
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	encrypted, err := k.encrypt(h, payload.Bytes())
+	if err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(h.raw)
+	for _, b := range [][]byte{h.raw, sum[:], k.headerHMAC(h.raw)} {
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+	return k.writeBlocks(w, encrypted)
+}
+
+type xmlFile struct {
+	XMLName xml.Name  `xml:"KeePassFile"`
+	Meta    xmlMeta   `xml:"Meta"`
+	Group   *xmlGroup `xml:"Root>Group"`
+}
+
+type xmlMeta struct {
+	Generator    string `xml:"Generator"`
+	DatabaseName string `xml:"DatabaseName"`
+}
+
+type xmlGroup struct {
+	UUID    string     `xml:"UUID"`
+	Name    string     `xml:"Name"`
+	Entries []xmlEntry `xml:"Entry"`
+}
+
+type xmlEntry struct {
+	UUID    string      `xml:"UUID"`
+	Strings []xmlString `xml:"String"`
+}
+
+type xmlString struct {
+	Key   string   `xml:"Key"`
+	Value xmlValue `xml:"Value"`
+}
+
+type xmlValue struct {
+	Protected string `xml:"Protected,attr,omitempty"`
+	Text      string `xml:",chardata"`
+}
+
+// writeSeeds returns the XML document of a database with an entry for each
+// seed in its default group. protect encrypts protected values in the order
+// of the document.
+func writeSeeds(seeds []Seed, protect func([]byte)) ([]byte, error) {
+	uuid := func() (string, error) {
+		b := make([]byte, 16)
+		_, err := rand.Read(b)
+		return base64.StdEncoding.EncodeToString(b), err
+	}
+
+	g := &xmlGroup{Name: "Trinity"}
+	var err error
+	if g.UUID, err = uuid(); err != nil {
+		return nil, err
+	}
+	for i, s := range seeds {
+		title := s.Title
+		if title == "" {
+			title = fmt.Sprintf("IOTA Seed #%d", i+1)
+		}
+		seed := []byte(s.Seed)
+		protect(seed)
+
+		e := xmlEntry{Strings: []xmlString{
+			{Key: "Title", Value: xmlValue{Text: title}},
+			{Key: "Seed", Value: xmlValue{Protected: "True", Text: base64.StdEncoding.EncodeToString(seed)}},
+		}}
+		if e.UUID, err = uuid(); err != nil {
+			return nil, err
+		}
+		g.Entries = append(g.Entries, e)
+	}
+
+	doc, err := xml.MarshalIndent(&xmlFile{Meta: xmlMeta{Generator: "giota", DatabaseName: "Trinity"}, Group: g}, "", "\t")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), doc...), nil
+}
@@ -0,0 +1,174 @@
+package seedvault
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/iotaledger/giota"
+)
+
+// testOptions keep the key derivation of the tests fast.
+var testOptions = &Options{Iterations: 1, Memory: 64 << 10, Parallelism: 1}
+
+var testSeeds = []Seed{
+	{Title: "savings", Seed: giota.Trytes(strings.Repeat("SEEDVAULT", 9))},
+	{Seed: giota.Trytes(strings.Repeat("TRINITY99", 9))},
+}
+
+func TestReadWrite(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteWithOptions(&buf, "correct horse", testSeeds, testOptions); err != nil {
+		t.Fatal(err)
+	}
+	vault := buf.Bytes()
+
+	seeds, err := Read(bytes.NewReader(vault), "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Seed{testSeeds[0], {Title: "IOTA Seed #2", Seed: testSeeds[1].Seed}}
+	if !reflect.DeepEqual(seeds, want) {
+		t.Errorf("Read() returned %v, expected %v", seeds, want)
+	}
+
+	tampered := append([]byte{}, vault...)
+	tampered[len(tampered)-50] ^= 1
+
+	tests := []struct {
+		name     string
+		vault    []byte
+		password string
+		err      error
+	}{
+		{"wrong password", vault, "wrong horse", ErrPassword},
+		{"tampered payload", tampered, "correct horse", ErrCorrupt},
+		{"truncated", vault[:len(vault)-10], "correct horse", ErrCorrupt},
+		{"not a database", []byte("giota"), "correct horse", ErrFormat},
+		{"KDBX 3", append(append([]byte{}, vault[:8]...), 1, 0, 3, 0), "correct horse", ErrVersion},
+	}
+
+	for _, tt := range tests {
+		if _, err := Read(bytes.NewReader(tt.vault), tt.password); !errors.Is(err, tt.err) {
+			t.Errorf("%s: Read() returned %v, expected %v", tt.name, err, tt.err)
+		}
+	}
+
+	if err := WriteWithOptions(&buf, "correct horse", []Seed{{Seed: "SHORT"}}, testOptions); !errors.Is(err, ErrInvalidSeed) {
+		t.Errorf("WriteWithOptions() returned %v for an invalid seed", err)
+	}
+}
+
+func TestReadCiphers(t *testing.T) {
+	// KeePass may encrypt with ChaCha20 and key with AES-KDF or Argon2id
+	// instead of the AES-256 and Argon2d written by Write
+	tests := []struct {
+		name   string
+		cipher []byte
+		ivLen  int
+		kdf    func(*variantDict)
+	}{
+		{
+			name:   "ChaCha20 and AES-KDF",
+			cipher: cipherChaCha20,
+			ivLen:  12,
+			kdf: func(d *variantDict) {
+				d.set("$UUID", variantByteArray, kdfAES)
+				d.set("S", variantByteArray, bytes.Repeat([]byte{7}, 32))
+				d.setUint64("R", 1000)
+			},
+		},
+		{
+			name:   "AES-256 and Argon2id",
+			cipher: cipherAES256,
+			ivLen:  16,
+			kdf: func(d *variantDict) {
+				d.set("$UUID", variantByteArray, kdfArgon2id)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		h, err := newHeader(*testOptions)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h.cipherID = tt.cipher
+		h.iv = h.iv[:tt.ivLen]
+		tt.kdf(&h.kdf)
+		h.marshal()
+
+		var buf bytes.Buffer
+		if err := write(&buf, h, "pw", testSeeds[:1]); err != nil {
+			t.Fatalf("%s: %s", tt.name, err)
+		}
+		seeds, err := Read(&buf, "pw")
+		if err != nil || !reflect.DeepEqual(seeds, testSeeds[:1]) {
+			t.Errorf("%s: Read() returned %v, %v", tt.name, seeds, err)
+		}
+	}
+}
+
+func TestReadSeeds(t *testing.T) {
+	seed := strings.Repeat("SEEDVAULT", 9)
+	// protected values are XORed with 0x01 by the test stream
+	protect := func(s string) string {
+		b := []byte(s)
+		for i := range b {
+			b[i] ^= 1
+		}
+		return base64.StdEncoding.EncodeToString(b)
+	}
+	unprotect := func(b []byte) {
+		for i := range b {
+			b[i] ^= 1
+		}
+	}
+
+	doc := `<?xml version="1.0" encoding="utf-8"?>
+<KeePassFile>
+	<Meta><CustomData><Item><Key>k</Key><Value>v</Value></Item></CustomData></Meta>
+	<Root>
+		<Group>
+			<Name>Trinity</Name>
+			<Entry>
+				<String><Key>Title</Key><Value>lower case</Value></String>
+				<String><Key>Seed</Key><Value Protected="True">` + protect(strings.ToLower(seed)) + `</Value></String>
+				<History>
+					<Entry>
+						<String><Key>Seed</Key><Value Protected="True">` + protect("OLD") + `</Value></String>
+					</Entry>
+				</History>
+			</Entry>
+			<Entry>
+				<String><Key>Title</Key><Value>no seed</Value></String>
+				<String><Key>Password</Key><Value Protected="True">` + protect("secret") + `</Value></String>
+			</Entry>
+			<Group>
+				<Entry>
+					<String><Key>Seed</Key><Value>` + seed + `</Value></String>
+				</Entry>
+			</Group>
+		</Group>
+		<Group>
+			<Entry>
+				<String><Key>Seed</Key><Value>` + seed + `</Value></String>
+			</Entry>
+		</Group>
+	</Root>
+</KeePassFile>`
+
+	seeds, err := readSeeds([]byte(doc), unprotect)
+	want := []Seed{{Title: "lower case", Seed: giota.Trytes(seed)}}
+	if err != nil || !reflect.DeepEqual(seeds, want) {
+		t.Errorf("readSeeds() returned %v, %v, expected %v", seeds, err, want)
+	}
+
+	invalid := strings.Replace(doc, protect(strings.ToLower(seed)), protect("SHORT"), 1)
+	if _, err := readSeeds([]byte(invalid), unprotect); !errors.Is(err, ErrInvalidSeed) {
+		t.Errorf("readSeeds() returned %v for an invalid seed", err)
+	}
+}
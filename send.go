@@ -0,0 +1,235 @@
+package giota
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrSpentAddress is returned by SendToAddress if the destination or the
+// remainder address was spent from. Funds on such an address are at risk,
+// because its key is partially revealed.
+var ErrSpentAddress = errors.New("address was spent from")
+
+// DefaultConfirmationPoll is the interval of SendToAddress checking whether
+// the bundle is confirmed.
+const DefaultConfirmationPoll = 10 * time.Second
+
+// SendToAddressOptions are options of SendToAddress. The zero value sends an
+// amount of iotas with SecurityLevelMedium, selects the inputs and the
+// remainder address at the node, does the PoW locally with GetBestPoW and
+// doesn't wait for the confirmation.
+type SendToAddressOptions struct {
+	// Unit is the unit of the amount, e.g. Mi. If zero, the amount is in
+	// iotas.
+	Unit int64
+	// Security is the security level of the inputs and the remainder
+	// address. If zero, SecurityLevelMedium is used.
+	Security SecurityLevel
+	// Message and Tag are added to the output.
+	Message string
+	Tag     string
+	// Inputs are the addresses to spend. If nil, inputs with enough
+	// balance are looked up.
+	Inputs []AddressInfo
+	// Remainder is the address, which may have a checksum, receiving the
+	// remainder of the inputs. If empty, the first unused address of the
+	// seed is used.
+	Remainder string
+	// Depth and MWM are passed to SendTrytes.
+	Depth int64
+	MWM   int64
+	// Pow is the PoW func. If nil, the PoW is done by GetBestPoW unless
+	// RemotePoW is set, which lets the node do it.
+	Pow       PowFunc
+	RemotePoW bool
+	// WaitConfirmation is the max time to wait for the confirmation of the
+	// bundle, which is checked every ConfirmationPoll. If zero,
+	// SendToAddress returns after broadcasting.
+	WaitConfirmation time.Duration
+	// ConfirmationPoll defaults to DefaultConfirmationPoll.
+	ConfirmationPoll time.Duration
+}
+
+// SendToAddressResult is the result of SendToAddress.
+type SendToAddressResult struct {
+	// Bundle are the attached transactions.
+	Bundle Bundle
+	// Tail is the hash of the attached tail transaction.
+	Tail Hash
+	// Value is the sent amount in iotas.
+	Value int64
+	// Confirmed is true if the bundle was confirmed while waiting.
+	Confirmed bool
+}
+
+// SendToAddress sends amount, in units of opts.Unit, from the seed to the
+// address dest, which may have a checksum. It rejects addresses which were
+// spent from with ErrSpentAddress. opts may be nil. If the bundle isn't
+// confirmed within opts.WaitConfirmation, the result is returned without
+// error and Confirmed false.
+func (api *API) SendToAddress(seed Trytes, dest string, amount int64, opts *SendToAddressOptions) (*SendToAddressResult, error) {
+	if opts == nil {
+		opts = &SendToAddressOptions{}
+	}
+
+	unit := opts.Unit
+	if unit == 0 {
+		unit = 1
+	}
+	security := opts.Security
+	if security == 0 {
+		security = SecurityLevelMedium
+	}
+	if unit < 0 || amount < 0 || amount > MaxSupply/unit {
+		return nil, &ValidationError{Func: "SendToAddress", Arg: "amount", Index: -1, Err: errors.New("amount must be between 0 and the supply")}
+	}
+	value := amount * unit
+
+	b := NewTransferBuilder(seed).To(dest, value)
+	if opts.Message != "" {
+		b.WithMessage(opts.Message)
+	}
+	if opts.Tag != "" {
+		b.WithTag(opts.Tag)
+	}
+	if opts.Remainder != "" {
+		b.WithRemainder(opts.Remainder)
+	}
+	trs, _, remainder, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	check := []Address{trs[0].Address}
+	if remainder != "" {
+		check = append(check, remainder)
+	}
+	for _, adr := range check {
+		spent, err := api.isSpent(adr)
+		switch {
+		case err != nil:
+			return nil, err
+		case spent:
+			return nil, fmt.Errorf("%s: %w", adr, ErrSpentAddress)
+		}
+	}
+
+	bd, err := PrepareTransfers(api, seed, trs, opts.Inputs, remainder, security)
+	if err != nil {
+		return nil, err
+	}
+
+	pow := opts.Pow
+	if pow == nil && !opts.RemotePoW {
+		_, pow = GetBestPoW()
+	}
+	sent, err := SendTrytes(api, opts.Depth, []Transaction(bd), opts.MWM, pow)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &SendToAddressResult{
+		Bundle: Bundle(sent.Transactions),
+		Tail:   sent.Tail,
+		Value:  value,
+	}
+	if opts.WaitConfirmation > 0 {
+		res.Confirmed, err = api.waitConfirmed(bd[0].Bundle, opts.WaitConfirmation, opts.ConfirmationPoll)
+		if err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// isSpent returns true if adr was spent from, see spentFrom.
+func (api *API) isSpent(adr Address) (bool, error) {
+	spent, err := api.spentFrom([]Address{adr}, nil)
+	if err != nil {
+		return false, err
+	}
+	return spent[0], nil
+}
+
+// spentFrom returns whether each of adrs was spent from. It calls
+// wereAddressesSpentFrom, which also knows the spends pruned by snapshots of
+// the node. Only if the node rejects the command, e.g. because it is
+// disabled, the outgoing transactions of adrs tell, which are taken from txs
+// or found with findTransactions if txs is nil.
+func (api *API) spentFrom(adrs []Address, txs []Transaction) ([]bool, error) {
+	resp, err := api.WereAddressesSpentFrom(adrs)
+	switch {
+	case err == nil:
+		return resp.States, nil
+	case !errors.Is(err, ErrNodeResponse):
+		return nil, err
+	}
+
+	if txs == nil {
+		txs, err = api.FindTransactionObjects(&FindTransactionsRequest{Addresses: adrs})
+		if err != nil {
+			return nil, err
+		}
+	}
+	out := make(map[Address]bool)
+	for i := range txs {
+		if txs[i].Value < 0 {
+			out[txs[i].Address] = true
+		}
+	}
+
+	spent := make([]bool, len(adrs))
+	for i, adr := range adrs {
+		spent[i] = out[adr]
+	}
+	return spent, nil
+}
+
+// waitConfirmed polls IsBundleConfirmed until bundle is confirmed or timeout
+// is over.
+func (api *API) waitConfirmed(bundle BundleHash, timeout, poll time.Duration) (bool, error) {
+	if poll <= 0 {
+		poll = DefaultConfirmationPoll
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		_, confirmed, err := api.IsBundleConfirmed(bundle)
+		if err != nil || confirmed {
+			return confirmed, err
+		}
+
+		left := time.Until(deadline)
+		if left <= 0 {
+			return false, nil
+		}
+		if left < poll {
+			poll = left
+		}
+		time.Sleep(poll)
+	}
+}
+
+// AttachAddressToTangle publishes a zero-value transaction to the address
+// adr, which may have a checksum, so that the address is known to the
+// Tangle, e.g. before it is handed out. tag and message may be empty. The
+// tips are selected with DefaultDepth and the PoW is done by GetBestPoW with
+// DefaultMinWeightMagnitude.
+func (api *API) AttachAddressToTangle(adr, tag, message string) (*SendResult, error) {
+	b := NewTransferBuilder("").To(adr, 0)
+	if tag != "" {
+		b.WithTag(tag)
+	}
+	if message != "" {
+		b.WithMessage(message)
+	}
+
+	bd, err := b.Prepare(api, SecurityLevelMedium)
+	if err != nil {
+		return nil, err
+	}
+
+	_, pow := GetBestPoW()
+	return SendTrytes(api, DefaultDepth, []Transaction(bd), DefaultMinWeightMagnitude, pow)
+}
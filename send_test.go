@@ -0,0 +1,213 @@
+package giota
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+// newFakeTangle returns a node which stores broadcast transactions and finds
+// them by address and bundle. Its balances are 200 and all transactions are
+// confirmed.
+func newFakeTangle(t *testing.T, txs []Transaction) (*API, func()) {
+	return newFakeNode(t, map[string]fakeNodeHandler{
+		"findTransactions": func(req map[string]json.RawMessage) interface{} {
+			var ft FindTransactionsRequest
+			json.Unmarshal(req["addresses"], &ft.Addresses)
+			json.Unmarshal(req["bundles"], &ft.Bundles)
+
+			hashes := []Hash{}
+			for i := range txs {
+				for _, adr := range ft.Addresses {
+					if txs[i].Address == adr {
+						hashes = append(hashes, txs[i].Hash())
+					}
+				}
+				for _, b := range ft.Bundles {
+					if txs[i].Bundle == BundleHash(b) {
+						hashes = append(hashes, txs[i].Hash())
+					}
+				}
+			}
+			return &FindTransactionsResponse{Hashes: hashes}
+		},
+		"getTrytes": func(req map[string]json.RawMessage) interface{} {
+			var hashes []Hash
+			json.Unmarshal(req["hashes"], &hashes)
+
+			found := []Transaction{}
+			for _, h := range hashes {
+				for i := range txs {
+					if txs[i].Hash() == h {
+						found = append(found, txs[i])
+					}
+				}
+			}
+			return &GetTrytesResponse{Trytes: found}
+		},
+		"getBalances": func(req map[string]json.RawMessage) interface{} {
+			var adrs []Address
+			json.Unmarshal(req["addresses"], &adrs)
+			bals := make([]string, len(adrs))
+			for i := range bals {
+				bals[i] = "200"
+			}
+			return map[string]interface{}{"balances": bals}
+		},
+		"getTransactionsToApprove": func(map[string]json.RawMessage) interface{} {
+			return &GetTransactionsToApproveResponse{TrunkTransaction: EmptyHash, BranchTransaction: EmptyHash}
+		},
+		"broadcastTransactions": func(req map[string]json.RawMessage) interface{} {
+			var sent []Transaction
+			json.Unmarshal(req["trytes"], &sent)
+			txs = append(txs, sent...)
+			return struct{}{}
+		},
+		"storeTransactions": func(map[string]json.RawMessage) interface{} {
+			return struct{}{}
+		},
+		"getNodeInfo": func(map[string]json.RawMessage) interface{} {
+			return &GetNodeInfoResponse{LatestMilestone: EmptyHash}
+		},
+		"getInclusionStates": func(req map[string]json.RawMessage) interface{} {
+			var hashes []Hash
+			json.Unmarshal(req["transactions"], &hashes)
+			states := make([]bool, len(hashes))
+			for i := range states {
+				states[i] = true
+			}
+			return &GetInclusionStatesResponse{States: states}
+		},
+	})
+}
+
+func TestSendToAddress(t *testing.T) {
+	pow := func(Trytes, int) (Trytes, error) { return "NONCE", nil }
+	inputs := []AddressInfo{{Seed: accountTestSeed, Index: 0, Security: SecurityLevelLow}}
+
+	tests := []struct {
+		name   string
+		dest   string
+		amount int64
+		opts   *SendToAddressOptions
+		err    error
+		value  int64
+	}{
+		{
+			name:   "checksum and unit",
+			dest:   string(filterAddr1.WithChecksum()),
+			amount: 1,
+			opts: &SendToAddressOptions{
+				Unit: 100, Inputs: inputs, Remainder: string(filterAddr1), Pow: pow,
+				Message: "hi", WaitConfirmation: time.Second, ConfirmationPoll: time.Millisecond,
+			},
+			value: 100,
+		},
+		{
+			name:   "spent destination",
+			dest:   string(filterAddr2),
+			amount: 1,
+			opts:   &SendToAddressOptions{Inputs: inputs, Pow: pow},
+			err:    ErrSpentAddress,
+		},
+		{
+			name:   "spent remainder",
+			dest:   string(filterAddr1),
+			amount: 1,
+			opts:   &SendToAddressOptions{Inputs: inputs, Remainder: string(filterAddr2), Pow: pow},
+			err:    ErrSpentAddress,
+		},
+		{
+			name:   "amount exceeds supply",
+			dest:   string(filterAddr1),
+			amount: MaxSupply/Mi + 1,
+			opts:   &SendToAddressOptions{Unit: Mi},
+		},
+		{
+			name:   "bad checksum",
+			dest:   string(filterAddr1) + "999999999",
+			amount: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		api, done := newFakeTangle(t, filterTestBundle())
+		res, err := api.SendToAddress(accountTestSeed, tt.dest, tt.amount, tt.opts)
+		done()
+
+		var verr *ValidationError
+		switch {
+		case tt.err != nil && !errors.Is(err, tt.err):
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.err, err)
+		case tt.err == nil && tt.value == 0 && !errors.As(err, &verr):
+			t.Errorf("%s: expected validation error, got %v", tt.name, err)
+		case tt.value == 0:
+		case err != nil:
+			t.Errorf("%s: unexpected error %s", tt.name, err)
+		case res.Value != tt.value || res.Bundle[0].Value != tt.value || res.Bundle[0].Address != filterAddr1:
+			t.Errorf("%s: sent %d to %s", tt.name, res.Bundle[0].Value, res.Bundle[0].Address)
+		case res.Tail != res.Bundle[0].Hash() || !res.Confirmed:
+			t.Errorf("%s: unexpected tail %s, confirmed %v", tt.name, res.Tail, res.Confirmed)
+		}
+	}
+}
+
+func TestSpentFrom(t *testing.T) {
+	adrs := []Address{filterAddr1, filterAddr2}
+
+	// the node knows that filterAddr1 was spent from before its snapshot,
+	// which pruned the transactions
+	api, done := newFakeNode(t, map[string]fakeNodeHandler{
+		"wereAddressesSpentFrom": func(req map[string]json.RawMessage) interface{} {
+			var as []Address
+			json.Unmarshal(req["addresses"], &as)
+			states := make([]bool, len(as))
+			for i := range as {
+				states[i] = as[i] == filterAddr1
+			}
+			return &WereAddressesSpentFromResponse{States: states}
+		},
+		"findTransactions": func(map[string]json.RawMessage) interface{} {
+			t.Error("findTransactions called although wereAddressesSpentFrom is served")
+			return &FindTransactionsResponse{Hashes: []Hash{}}
+		},
+	})
+	spent, err := api.spentFrom(adrs, nil)
+	done()
+	if err != nil || len(spent) != 2 || !spent[0] || spent[1] {
+		t.Errorf("spentFrom() returned %v, %v with wereAddressesSpentFrom", spent, err)
+	}
+
+	// without wereAddressesSpentFrom, the outgoing transaction of
+	// filterAddr2 tells
+	api, done = newFakeTangle(t, filterTestBundle())
+	spent, err = api.spentFrom(adrs, nil)
+	done()
+	if err != nil || len(spent) != 2 || spent[0] || !spent[1] {
+		t.Errorf("spentFrom() returned %v, %v without wereAddressesSpentFrom", spent, err)
+	}
+}
+
+func TestAttachAddressToTangleInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		adr, tag string
+		arg      string
+	}{
+		{name: "bad address", adr: "ABC", arg: "adr"},
+		{name: "bad checksum", adr: string(filterAddr1) + "999999999", arg: "adr"},
+		{name: "long tag", adr: string(filterAddr1), tag: "A234567890123456789012345678", arg: "tag"},
+	}
+
+	for _, tt := range tests {
+		api, done := newFakeTangle(t, nil)
+		_, err := api.AttachAddressToTangle(tt.adr, tt.tag, "")
+		done()
+
+		var verr *ValidationError
+		if !errors.As(err, &verr) || verr.Arg != tt.arg {
+			t.Errorf("%s: expected error of %s, got %v", tt.name, tt.arg, err)
+		}
+	}
+}
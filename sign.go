@@ -28,7 +28,9 @@ package giota
 import (
 	"crypto/rand"
 	"errors"
+	"fmt"
 	"math/big"
+	"strings"
 )
 
 // errors used in sign
@@ -36,8 +38,28 @@ var (
 	ErrSeedTritsLength  = errors.New("seed trit slice should be HashSize entries long")
 	ErrSeedTrytesLength = errors.New("seed string needs to be HashSize / 3 characters long")
 	ErrKeyTritsLength   = errors.New("key trit slice should be a multiple of HashSize*27 entries long")
+	ErrSecurityLevel    = errors.New("security level must be 1, 2 or 3")
 )
 
+// SecurityLevel is the number of key fragments of an address. Each level
+// adds a signature fragment of 2187 trytes to every input of a bundle.
+type SecurityLevel int
+
+// Security levels.
+const (
+	SecurityLevelLow    SecurityLevel = 1
+	SecurityLevelMedium SecurityLevel = 2
+	SecurityLevelHigh   SecurityLevel = 3
+)
+
+// IsValid returns ErrSecurityLevel if s is not between 1 and 3.
+func (s SecurityLevel) IsValid() error {
+	if s < SecurityLevelLow || s > SecurityLevelHigh {
+		return ErrSecurityLevel
+	}
+	return nil
+}
+
 // NewSeed generate a random Trytes
 func NewSeed() Trytes {
 	b := make([]byte, 49)
@@ -65,9 +87,17 @@ func NewSeed() Trytes {
 	return Trytes(t)
 }
 
-// newKeyTrits takes a seed encoded as Trytes, an index and a security
-// level to derive a private key returned as Trits
-func newKeyTrits(seed Trytes, index, securityLevel int) (Trits, error) {
+// Subseed returns the subseed of seed at index, i.e. the Kerl hash of seed
+// plus index, from which the key of the index is derived.
+func Subseed(seed Trytes, index uint64) (Trytes, error) {
+	ts, err := subseedTrits(seed, index)
+	if err != nil {
+		return "", err
+	}
+	return ts.Trytes(), nil
+}
+
+func subseedTrits(seed Trytes, index uint64) (Trits, error) {
 	if err := seed.IsValid(); err != nil {
 		return nil, err
 	} else if len(seed) != TritHashLength/Radix {
@@ -75,27 +105,36 @@ func newKeyTrits(seed Trytes, index, securityLevel int) (Trits, error) {
 	}
 
 	seedTrits := seed.Trits()
-	// Utils.increment
-	for i := 0; i < index; i++ {
-		incTrits(seedTrits)
-	}
+	addTrits(seedTrits, index)
 
 	k := NewKerl()
-	err := k.Absorb(seedTrits)
+	if err := k.Absorb(seedTrits); err != nil {
+		return nil, err
+	}
+	return k.Squeeze(HashSize)
+}
+
+// newKeyTrits takes a seed encoded as Trytes, an index and a security
+// level to derive a private key returned as Trits
+func newKeyTrits(seed Trytes, index uint64, securityLevel SecurityLevel) (Trits, error) {
+	if err := securityLevel.IsValid(); err != nil {
+		return nil, err
+	}
+
+	hashedTrits, err := subseedTrits(seed, index)
 	if err != nil {
 		return nil, err
 	}
 
-	hashedTrits, _ := k.Squeeze(HashSize)
-	k.Reset()
+	k := NewKerl()
 	err = k.Absorb(hashedTrits)
 	if err != nil {
 		return nil, err
 	}
 
-	key := make(Trits, (HashSize * 27 * securityLevel))
+	key := make(Trits, (HashSize * 27 * int(securityLevel)))
 
-	for l := 0; l < securityLevel; l++ {
+	for l := 0; l < int(securityLevel); l++ {
 		for i := 0; i < 27; i++ {
 			b, _ := k.Squeeze(HashSize)
 			copy(key[(l*27+i)*HashSize:], b)
@@ -107,7 +146,7 @@ func newKeyTrits(seed Trytes, index, securityLevel int) (Trits, error) {
 
 // NewKey takes a seed encoded as Trytes, an index and a security
 // level to derive a private key returned as Trytes
-func NewKey(seed Trytes, index, securityLevel int) (Trytes, error) {
+func NewKey(seed Trytes, index uint64, securityLevel SecurityLevel) (Trytes, error) {
 	ts, err := newKeyTrits(seed, index, securityLevel)
 	return ts.Trytes(), err
 }
@@ -179,16 +218,14 @@ func Digests(key Trits) (Trits, error) {
 	digests := make(Trits, HashSize*numKeys)
 	buffer := make(Trits, HashSize)
 
+	k := getKerl()
+	defer putKerl(k)
+
 	for i := 0; i < numKeys; i++ {
 		k2 := NewKerl()
 		for j := 0; j < 27; j++ {
 			copy(buffer, key[i*SignatureSize+j*HashSize:i*SignatureSize+(j+1)*HashSize])
-
-			for k := 0; k < 26; k++ {
-				k := NewKerl()
-				k.Absorb(buffer)
-				buffer, _ = k.Squeeze(HashSize)
-			}
+			buffer = hashChain(k, buffer, 26)
 			k2.Absorb(buffer)
 		}
 		buffer, _ = k2.Squeeze(HashSize)
@@ -199,14 +236,13 @@ func Digests(key Trits) (Trits, error) {
 
 // digest calculates hash x normalizedBundleFragment[i] for each segment in keyTrits.
 func digest(normalizedBundleFragment []int8, signatureFragment Trytes) Trits {
+	kerl := getKerl()
+	defer putKerl(kerl)
+
 	k := NewKerl()
 	for i := 0; i < 27; i++ {
 		bb := signatureFragment[i*HashSize/3 : (i+1)*HashSize/3].Trits()
-		for j := normalizedBundleFragment[i] + 13; j > 0; j-- {
-			kerl := NewKerl()
-			kerl.Absorb(bb)
-			bb, _ = kerl.Squeeze(HashSize)
-		}
+		bb = hashChain(kerl, bb, int(normalizedBundleFragment[i])+13)
 		k.Absorb(bb)
 	}
 	tr, _ := k.Squeeze(HashSize)
@@ -216,15 +252,13 @@ func digest(normalizedBundleFragment []int8, signatureFragment Trytes) Trits {
 // Sign calculates signature from bundle hash and key
 // by hashing x 13-normalizedBundleFragment[i] for each segments in keyTrits.
 func Sign(normalizedBundleFragment []int8, keyFragment Trytes) Trytes {
+	kerl := getKerl()
+	defer putKerl(kerl)
+
 	signatureFragment := make(Trits, len(keyFragment)*3)
 	for i := 0; i < 27; i++ {
 		bb := keyFragment[i*HashSize/3 : (i+1)*HashSize/3].Trits()
-		for j := 0; j < 13-int(normalizedBundleFragment[i]); j++ {
-			kerl := NewKerl()
-			kerl.Absorb(bb)
-			// TODO: why is the error ignored here?
-			bb, _ = kerl.Squeeze(HashSize)
-		}
+		bb = hashChain(kerl, bb, 13-int(normalizedBundleFragment[i]))
 		copy(signatureFragment[i*HashSize:], bb)
 	}
 	return signatureFragment.Trytes()
@@ -232,13 +266,20 @@ func Sign(normalizedBundleFragment []int8, keyFragment Trytes) Trytes {
 
 // IsValidSig validates signatureFragment.
 func IsValidSig(expectedAddress Address, signatureFragments []Trytes, bundleHash Trytes) bool {
-	normalizedBundleHash := bundleHash.Normalize()
+	normalizedBundleHash, err := bundleHash.ToNormalized()
+	if err != nil {
+		return false
+	}
+	for _, f := range signatureFragments {
+		if len(f) != keyFragmentSize || f.IsValid() != nil {
+			return false
+		}
+	}
 
 	// Get digests
 	digests := make(Trits, HashSize*len(signatureFragments))
 	for i := range signatureFragments {
-		start := 27 * (i % 3)
-		digestBuffer := digest(normalizedBundleHash[start:start+27], signatureFragments[i])
+		digestBuffer := digest(NormalizedFragment(normalizedBundleHash, i), signatureFragments[i])
 		copy(digests[i*HashSize:], digestBuffer)
 	}
 
@@ -273,7 +314,12 @@ func calcAddress(digests Trits) (Trits, error) {
 }
 
 // NewAddress generates a new address from seed without checksum
-func NewAddress(seed Trytes, index, security int) (Address, error) {
+func NewAddress(seed Trytes, index uint64, security SecurityLevel) (Address, error) {
+	ck := NewAddressKey(seed, index, security)
+	if adr, ok := cachedAddress(ck); ok {
+		return adr, nil
+	}
+
 	k, err := newKeyTrits(seed, index, security)
 	if err != nil {
 		return "", err
@@ -289,17 +335,21 @@ func NewAddress(seed Trytes, index, security int) (Address, error) {
 		return "", err
 	}
 
-	tryt := addr.Trytes()
-	return tryt.ToAddress()
+	adr, err := addr.Trytes().ToAddress()
+	if err != nil {
+		return "", err
+	}
+	cacheAddress(ck, adr)
+	return adr, nil
 }
 
 // NewAddresses generates new count addresses from seed without a checksum
-func NewAddresses(seed Trytes, start, count, security int) ([]Address, error) {
+func NewAddresses(seed Trytes, start uint64, count int, security SecurityLevel) ([]Address, error) {
 	as := make([]Address, count)
 
 	var err error
-	for i := 0; i < count; i++ {
-		as[i], err = NewAddress(seed, start+i, security)
+	for i := range as {
+		as[i], err = NewAddress(seed, start+uint64(i), security)
 		if err != nil {
 			return nil, err
 		}
@@ -350,7 +400,70 @@ func (a Address) Checksum() Trytes {
 		panic("len(address) must be 81")
 	}
 
-	return a.Hash()[81-9 : 81]
+	cs, _ := Checksum(Trytes(a), 9)
+	return cs
+}
+
+// Checksum returns the last length trytes of the Kerl hash of t, which is
+// padded with 9s to a multiple of 81 trytes beforehand. Address checksums
+// use a length of 9, seed checksums (as shown by Trinity) a length of 3.
+func Checksum(t Trytes, length int) (Trytes, error) {
+	if length < 1 || length > HashSize/3 {
+		return "", fmt.Errorf("checksum length must be between 1 and %d", HashSize/3)
+	}
+
+	if err := t.IsValid(); err != nil {
+		return "", err
+	}
+
+	if r := len(t) % (HashSize / 3); r != 0 || len(t) == 0 {
+		t = pad(t, len(t)+HashSize/3-r)
+	}
+
+	k := NewKerl()
+	if err := k.Absorb(t.Trits()); err != nil {
+		return "", err
+	}
+
+	h, err := k.Squeeze(HashSize)
+	if err != nil {
+		return "", err
+	}
+	return h.Trytes()[HashSize/3-length:], nil
+}
+
+// ValidateChecksummedString converts user input to an Address. Unlike
+// ToAddress, it requires the 9 trytes checksum to be present and valid.
+func ValidateChecksummedString(s string) (Address, error) {
+	t := Trytes(strings.TrimSpace(s))
+	if len(t) != 90 {
+		return "", errors.New("addresses with checksum are 90 trytes in length")
+	}
+
+	a := Address(t[:81])
+	if err := a.ValidateChecksummedString(s); err != nil {
+		return "", err
+	}
+	return a, nil
+}
+
+// ValidateChecksummedString checks that user input s is the address with
+// its 9 trytes checksum, e.g. to confirm an address typed in again.
+func (a Address) ValidateChecksummedString(s string) error {
+	if err := a.IsValid(); err != nil {
+		return err
+	}
+
+	t := Trytes(strings.TrimSpace(s))
+	switch {
+	case len(t) != 90:
+		return errors.New("addresses with checksum are 90 trytes in length")
+	case Address(t[:81]) != a:
+		return errors.New("input is another address")
+	case a.Checksum() != t[81:]:
+		return errors.New("checksum is illegal")
+	}
+	return nil
 }
 
 // Hash hashes the address and returns trytes
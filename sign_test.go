@@ -134,8 +134,8 @@ func TestAddress(t *testing.T) {
 	tests := []struct {
 		name         Trytes
 		seed         Trytes
-		seedIndex    int
-		seedSecurity int
+		seedIndex    uint64
+		seedSecurity SecurityLevel
 		address      Trytes
 		addressValid bool
 	}{
@@ -227,3 +227,164 @@ func TestSign(t *testing.T) {
 	}
 
 }
+
+func TestChecksum(t *testing.T) {
+	var adr Address = "RGVOWCDJAGSO9TNLBBPUVYE9KHBOAZNVFRVKVYYCHRKQRKRNKGGWBF9WCRJVROKLVKWZUMBABVJGAALWU"
+
+	cs, err := Checksum(Trytes(adr), 9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cs != "NPJ9QIHFW" {
+		t.Errorf("Checksum() of address is %s, expected NPJ9QIHFW", cs)
+	}
+
+	short, err := Checksum("ABC", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	padded, err := Checksum(pad("ABC", 81), 9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if short != padded[6:] {
+		t.Errorf("Checksum() of short trytes is %s, expected %s", short, padded[6:])
+	}
+
+	if _, err := Checksum("ABC", 0); err == nil {
+		t.Error("Checksum() should fail for a length of 0")
+	}
+	if _, err := Checksum("abc", 3); err == nil {
+		t.Error("Checksum() should fail for invalid trytes")
+	}
+}
+
+func TestValidateChecksummedString(t *testing.T) {
+	tests := []struct {
+		in    string
+		valid bool
+	}{
+		{in: "RGVOWCDJAGSO9TNLBBPUVYE9KHBOAZNVFRVKVYYCHRKQRKRNKGGWBF9WCRJVROKLVKWZUMBABVJGAALWUNPJ9QIHFW", valid: true},
+		{in: " RGVOWCDJAGSO9TNLBBPUVYE9KHBOAZNVFRVKVYYCHRKQRKRNKGGWBF9WCRJVROKLVKWZUMBABVJGAALWUNPJ9QIHFW\n", valid: true},
+		{in: "RGVOWCDJAGSO9TNLBBPUVYE9KHBOAZNVFRVKVYYCHRKQRKRNKGGWBF9WCRJVROKLVKWZUMBABVJGAALWUA9BEONKZW", valid: false},
+		{in: "RGVOWCDJAGSO9TNLBBPUVYE9KHBOAZNVFRVKVYYCHRKQRKRNKGGWBF9WCRJVROKLVKWZUMBABVJGAALWU", valid: false},
+	}
+
+	for _, tt := range tests {
+		adr, err := ValidateChecksummedString(tt.in)
+		if (err == nil) != tt.valid {
+			t.Errorf("ValidateChecksummedString(%q) should be %v but got %v", tt.in, tt.valid, err)
+		}
+		if err == nil && len(adr) != 81 {
+			t.Errorf("ValidateChecksummedString(%q) returned %q", tt.in, adr)
+		}
+	}
+}
+
+func TestAddressValidateChecksummedString(t *testing.T) {
+	adr := Address("RGVOWCDJAGSO9TNLBBPUVYE9KHBOAZNVFRVKVYYCHRKQRKRNKGGWBF9WCRJVROKLVKWZUMBABVJGAALWU")
+	tests := []struct {
+		name  string
+		adr   Address
+		in    string
+		valid bool
+	}{
+		{name: "same address", adr: adr, in: "RGVOWCDJAGSO9TNLBBPUVYE9KHBOAZNVFRVKVYYCHRKQRKRNKGGWBF9WCRJVROKLVKWZUMBABVJGAALWUNPJ9QIHFW", valid: true},
+		{name: "surrounding space", adr: adr, in: " RGVOWCDJAGSO9TNLBBPUVYE9KHBOAZNVFRVKVYYCHRKQRKRNKGGWBF9WCRJVROKLVKWZUMBABVJGAALWUNPJ9QIHFW\n", valid: true},
+		{name: "illegal checksum", adr: adr, in: "RGVOWCDJAGSO9TNLBBPUVYE9KHBOAZNVFRVKVYYCHRKQRKRNKGGWBF9WCRJVROKLVKWZUMBABVJGAALWUA9BEONKZW"},
+		{name: "no checksum", adr: adr, in: string(adr)},
+		{name: "other address", adr: adr, in: string(filterAddr1.WithChecksum())},
+		{name: "invalid address", adr: "ABC", in: "RGVOWCDJAGSO9TNLBBPUVYE9KHBOAZNVFRVKVYYCHRKQRKRNKGGWBF9WCRJVROKLVKWZUMBABVJGAALWUNPJ9QIHFW"},
+	}
+
+	for _, tt := range tests {
+		if err := tt.adr.ValidateChecksummedString(tt.in); (err == nil) != tt.valid {
+			t.Errorf("%s: ValidateChecksummedString() should be %v but got %v", tt.name, tt.valid, err)
+		}
+	}
+}
+
+func TestSecurityLevel(t *testing.T) {
+	s := Trytes("A99999999999999999999999999999999999999999999999999999999999999999999999999999999")
+
+	for sec := SecurityLevel(0); sec <= 4; sec++ {
+		valid := sec >= SecurityLevelLow && sec <= SecurityLevelHigh
+		if err := sec.IsValid(); (err == nil) != valid {
+			t.Errorf("IsValid() of level %d returned %v", sec, err)
+		}
+
+		key, err := NewKey(s, 0, sec)
+		switch {
+		case !valid && err != ErrSecurityLevel:
+			t.Errorf("NewKey() with level %d returned %v", sec, err)
+		case valid && err != nil:
+			t.Errorf("NewKey() with level %d returned %v", sec, err)
+		case valid && len(key) != int(sec)*2187:
+			t.Errorf("NewKey() with level %d returned a key of %d trytes", sec, len(key))
+		}
+	}
+}
+
+func TestSignSecurityLevel3(t *testing.T) {
+	s := Trytes("A99999999999999999999999999999999999999999999999999999999999999999999999999999999")
+	h := Trytes("RGVOWCDJAGSO9TNLBBPUVYE9KHBOAZNVFRVKVYYCHRKQRKRNKGGWBF9WCRJVROKLVKWZUMBABVJGAALWU")
+
+	adr, err := NewAddress(s, 0, SecurityLevelHigh)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frags, err := Keys{adr: mustNewKey(t, s, 0, SecurityLevelHigh)}.Sign(adr, h.Normalize())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(frags) != 3 {
+		t.Fatalf("Sign() returned %d fragments, expected 3", len(frags))
+	}
+
+	if !IsValidSig(adr, frags, h) {
+		t.Error("level 3 signature is invalid")
+	}
+	if IsValidSig(adr, frags[:2], h) {
+		t.Error("truncated level 3 signature is valid")
+	}
+}
+
+func mustNewKey(t *testing.T, s Trytes, index uint64, sec SecurityLevel) Trytes {
+	key, err := NewKey(s, index, sec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestSubseed(t *testing.T) {
+	s := Trytes("WQNZOHUT99PWKEBFSKQSYNC9XHT9GEBMOSJAQDQAXPEZPJNDIUB9TSNWVMHKWICW9WVZXSMDFGISOD9FZ")
+
+	// the subseed of index 1 is the subseed of index 0 of the incremented seed
+	ts := s.Trits()
+	IncTrits(ts)
+	a, err := Subseed(s, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Subseed(ts.Trytes(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a != b {
+		t.Errorf("Subseed(1) = %s, expected %s", a, b)
+	}
+
+	large, err := Subseed(s, 1<<40)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(large) != HashSize/3 || large == a {
+		t.Errorf("Subseed(1<<40) = %s", large)
+	}
+
+	if _, err := Subseed("ABC", 0); err != ErrSeedTrytesLength {
+		t.Errorf("Subseed() of a short seed returned %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package giota
+
+import (
+	"errors"
+	"fmt"
+)
+
+// keyFragmentSize is the number of trytes of a key per security level.
+const keyFragmentSize = 6561 / 3
+
+// Signer produces the signatures of bundle inputs. It allows keys to be
+// derived once and cached, or to be kept in external devices like HSMs.
+type Signer interface {
+	// Sign returns the signature fragments of adr for the normalized bundle
+	// hash, one per security level of adr.
+	Sign(adr Address, normalizedHash []int8) ([]Trytes, error)
+}
+
+// Keys is a Signer using precomputed private keys by address.
+type Keys map[Address]Trytes
+
+// NewKeys derives the private keys of inputs.
+func NewKeys(inputs []AddressInfo) (Keys, error) {
+	ks := make(Keys, len(inputs))
+	for i := range inputs {
+		key, err := newKeyTrits(inputs[i].Seed, inputs[i].Index, inputs[i].Security)
+		if err != nil {
+			return nil, err
+		}
+
+		dg, err := Digests(key)
+		if err != nil {
+			return nil, err
+		}
+
+		adr, err := calcAddress(dg)
+		if err != nil {
+			return nil, err
+		}
+		ks[Address(adr.Trytes())] = key.Trytes()
+	}
+	return ks, nil
+}
+
+// Sign signs normalizedHash with the key of adr.
+func (ks Keys) Sign(adr Address, normalizedHash []int8) ([]Trytes, error) {
+	key, ok := ks[adr]
+	if !ok {
+		return nil, fmt.Errorf("no key for address %s", adr)
+	}
+	if len(key) == 0 || len(key)%keyFragmentSize != 0 {
+		return nil, fmt.Errorf("key for address %s has invalid length %d", adr, len(key))
+	}
+
+	frags := make([]Trytes, len(key)/keyFragmentSize)
+	for j := range frags {
+		frags[j] = Sign(NormalizedFragment(normalizedHash, j), key[j*keyFragmentSize:(j+1)*keyFragmentSize])
+	}
+	return frags, nil
+}
+
+// NormalizedHash returns the normalized hash of bs, which SignInputs passes
+// to the Signer. Use NormalizedFragment to get the part signed by each key
+// fragment. It fails if the hash has an M, see HasM.
+func (bs Bundle) NormalizedHash() ([]int8, error) {
+	nh, err := Trytes(bs.Hash()).ToNormalized()
+	if err != nil {
+		return nil, err
+	}
+	if HasM(nh) {
+		return nil, ErrInsecureBundleHash
+	}
+	return nh, nil
+}
+
+// SignInputs signs all inputs of the finalized bundle bs with s. Signature
+// fragments following the first one are put into the subsequent transactions
+// with the same address and zero value. Drafts and attached bundles can't be
+// signed, ErrWrongState is returned for them.
+func (bs Bundle) SignInputs(s Signer) error {
+	if st := bs.State(); st == BundleDraft || st == BundleAttached {
+		return wrongState("signed", st)
+	}
+
+	nHash, err := bs.NormalizedHash()
+	if err != nil {
+		return err
+	}
+
+	for i := range bs {
+		if bs[i].Value >= 0 {
+			continue
+		}
+
+		frags, err := s.Sign(bs[i].Address, nHash)
+		if err != nil {
+			return err
+		}
+
+		for j, f := range frags {
+			if j > 0 && (i+j >= len(bs) || bs[i+j].Address != bs[i].Address || bs[i+j].Value != 0) {
+				return errors.New("bundle has too few transactions for the signature of " + string(bs[i].Address))
+			}
+			bs[i+j].SignatureMessageFragment = f
+		}
+	}
+	return nil
+}
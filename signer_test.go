@@ -0,0 +1,49 @@
+package giota
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBundleSignInputs(t *testing.T) {
+	in := AddressInfo{Seed: accountTestSeed, Index: 1, Security: 3}
+	adr, err := in.Address()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := NewKeys([]AddressInfo{in})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var bs Bundle
+	bs.Add(1, filterAddr1, 10, time.Now(), "")
+	bs.Add(3, adr, -10, time.Now(), "")
+	bs.Finalize(nil)
+
+	if err := bs.SignInputs(keys); err != nil {
+		t.Fatal(err)
+	}
+	if err := bs.IsValid(); err != nil {
+		t.Errorf("SignInputs() produced an invalid bundle: %s", err)
+	}
+
+	short := bs[:3]
+	if err := short.SignInputs(keys); err == nil {
+		t.Error("SignInputs() should fail if signature transactions are missing")
+	}
+	if err := bs.SignInputs(Keys{}); err == nil {
+		t.Error("SignInputs() should fail without the key of an input")
+	}
+
+	for tag := int64(0); !HasM(bs.Hash().Normalize()); tag++ {
+		bs[0].ObsoleteTag = Tag(Int2Trits(tag, ObsoleteTagTrinarySize).Trytes())
+	}
+	for i := range bs {
+		bs[i].Bundle = bs.Hash()
+	}
+	if err := bs.SignInputs(keys); err != ErrInsecureBundleHash {
+		t.Errorf("SignInputs() of a hash with M returned %v", err)
+	}
+}
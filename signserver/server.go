@@ -0,0 +1,295 @@
+// Package signserver is a cold signing daemon for an isolated host. It keeps
+// the seed and offers only two operations over HTTP, on TCP or a unix
+// socket:
+//
+//	POST /address  {"index", "security", "checksum"} -> {"address"}
+//	POST /sign     {"trytes", "inputs", "remainders"} -> {"trytes"}
+//
+// /sign signs the inputs of a finalized bundle prepared by an online host,
+// e.g. with giota.Bundle.AddEntry and Finalize, if it satisfies the Policy of
+// the server. The keys of the inputs are given by index, and so are the
+// remainders, which are outputs to addresses of the seed exempt from the
+// policy. The private keys never leave the server.
+package signserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/iotaledger/giota"
+)
+
+// MaxRequestSize is the max size of a request body in bytes.
+var MaxRequestSize int64 = 1 << 20
+
+var (
+	// ErrPolicy is returned if a bundle violates the policy of the server.
+	ErrPolicy = errors.New("bundle violates the signing policy")
+	// ErrReusedInput is returned if an input was signed for another bundle
+	// before. Signing it again would reveal more of its key.
+	ErrReusedInput = errors.New("input is signed for another bundle")
+)
+
+// Policy restricts the bundles signed by a Server. Outputs to remainders
+// are not restricted.
+type Policy struct {
+	// MaxValuePerDay is the max value of outputs signed per UTC day. If it
+	// is zero, the value is not limited.
+	MaxValuePerDay int64
+	// Destinations are the allowed output addresses. If empty, all
+	// addresses are allowed.
+	Destinations []giota.Address
+}
+
+// Server is an http.Handler signing bundles with Seed. The spent value of
+// the day and the signed inputs are kept in memory only, so they are reset
+// by a restart.
+type Server struct {
+	Seed   giota.Trytes
+	Policy Policy
+	// Clock tells the day of Policy.MaxValuePerDay. If nil,
+	// giota.SystemClock is used.
+	Clock giota.Clock
+
+	mu     sync.Mutex
+	day    string
+	spent  int64
+	signed map[giota.Address]giota.BundleHash
+}
+
+// Key is the index and security level of a key of the seed.
+type Key struct {
+	Index    uint64              `json:"index"`
+	Security giota.SecurityLevel `json:"security"`
+}
+
+type addressRequest struct {
+	Key
+	Checksum bool `json:"checksum"`
+}
+
+type addressResponse struct {
+	Address giota.Trytes `json:"address"`
+}
+
+// SignRequest is the request of /sign.
+type SignRequest struct {
+	// Trytes are the transactions of the bundle in the order of their index.
+	Trytes []giota.Trytes `json:"trytes"`
+	// Inputs are the keys of the inputs of the bundle.
+	Inputs []Key `json:"inputs"`
+	// Remainders are the keys of the addresses of the seed receiving change.
+	Remainders []Key `json:"remainders"`
+}
+
+// SignResponse is the response of /sign.
+type SignResponse struct {
+	// Trytes are the transactions of the signed bundle.
+	Trytes []giota.Trytes `json:"trytes"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// ListenAndServe serves s on address of network, e.g. "tcp" or "unix".
+func (s *Server) ListenAndServe(network, address string) error {
+	l, err := net.Listen(network, address)
+	if err != nil {
+		return err
+	}
+	return http.Serve(l, s)
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var (
+		resp interface{}
+		err  error
+	)
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, MaxRequestSize))
+	dec.DisallowUnknownFields()
+	switch r.URL.Path {
+	case "/address":
+		var req addressRequest
+		if err = dec.Decode(&req); err == nil {
+			resp, err = s.address(&req)
+		}
+	case "/sign":
+		var req SignRequest
+		if err = dec.Decode(&req); err == nil {
+			resp, err = s.Sign(&req)
+		}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		code := http.StatusBadRequest
+		if errors.Is(err, ErrPolicy) || errors.Is(err, ErrReusedInput) {
+			code = http.StatusForbidden
+		}
+		w.WriteHeader(code)
+		resp = &errorResponse{Error: err.Error()}
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (s *Server) address(req *addressRequest) (*addressResponse, error) {
+	adr, err := s.key(req.Key)
+	if err != nil {
+		return nil, err
+	}
+	resp := &addressResponse{Address: giota.Trytes(adr)}
+	if req.Checksum {
+		resp.Address = adr.WithChecksum()
+	}
+	return resp, nil
+}
+
+// key returns the address of k.
+func (s *Server) key(k Key) (giota.Address, error) {
+	if err := k.Security.IsValid(); err != nil {
+		return "", err
+	}
+	return giota.NewAddress(s.Seed, k.Index, k.Security)
+}
+
+// Sign signs the inputs of the bundle of req if it satisfies the policy.
+func (s *Server) Sign(req *SignRequest) (*SignResponse, error) {
+	bd := make(giota.Bundle, len(req.Trytes))
+	for i, t := range req.Trytes {
+		tx, err := giota.NewTransaction(t)
+		if err != nil {
+			return nil, fmt.Errorf("transaction %d: %w", i, err)
+		}
+		bd[i] = *tx
+	}
+	if st := bd.State(); st != giota.BundleFinalized {
+		return nil, fmt.Errorf("%s bundle can't be signed", st)
+	}
+
+	keys := make(map[giota.Address]giota.AddressInfo, len(req.Inputs))
+	for _, k := range req.Inputs {
+		adr, err := s.key(k)
+		if err != nil {
+			return nil, err
+		}
+		keys[adr] = giota.AddressInfo{Seed: s.Seed, Index: k.Index, Security: k.Security}
+	}
+	own := make(map[giota.Address]bool, len(req.Remainders))
+	for _, k := range req.Remainders {
+		adr, err := s.key(k)
+		if err != nil {
+			return nil, err
+		}
+		own[adr] = true
+	}
+
+	var (
+		inputs []giota.AddressInfo
+		sum    int64
+		out    int64
+	)
+	for i := range bd {
+		tx := &bd[i]
+		sum += tx.Value
+		switch {
+		case tx.Value < 0:
+			ai, ok := keys[tx.Address]
+			if !ok {
+				return nil, fmt.Errorf("no key of input %s", tx.Address)
+			}
+			inputs = append(inputs, ai)
+		case tx.Value == 0 || own[tx.Address]:
+		case !s.allowed(tx.Address):
+			return nil, fmt.Errorf("%w: output to %s", ErrPolicy, tx.Address)
+		default:
+			out += tx.Value
+		}
+	}
+	if sum != 0 {
+		return nil, fmt.Errorf("values of the bundle sum up to %d", sum)
+	}
+	for _, ai := range inputs {
+		if adr, _ := ai.Address(); own[adr] {
+			return nil, fmt.Errorf("remainder %s is an input of the bundle", adr)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h := bd[0].Bundle
+	again := len(inputs) > 0
+	for _, ai := range inputs {
+		adr, _ := ai.Address()
+		switch prev, ok := s.signed[adr]; {
+		case ok && prev != h:
+			return nil, fmt.Errorf("%w: %s", ErrReusedInput, adr)
+		case !ok:
+			again = false
+		}
+	}
+
+	clock := s.Clock
+	if clock == nil {
+		clock = giota.SystemClock
+	}
+	if day := clock.Now().UTC().Format("2006-01-02"); day != s.day {
+		s.day, s.spent = day, 0
+	}
+	if again {
+		// signing the same bundle again doesn't spend more
+		out = 0
+	}
+	if max := s.Policy.MaxValuePerDay; max > 0 && s.spent+out > max {
+		return nil, fmt.Errorf("%w: %di exceed the limit of %di per day", ErrPolicy, s.spent+out, max)
+	}
+
+	ks, err := giota.NewKeys(inputs)
+	if err != nil {
+		return nil, err
+	}
+	if err := bd.SignInputs(ks); err != nil {
+		return nil, err
+	}
+
+	s.spent += out
+	if s.signed == nil {
+		s.signed = make(map[giota.Address]giota.BundleHash)
+	}
+	for adr := range ks {
+		s.signed[adr] = h
+	}
+
+	resp := &SignResponse{Trytes: make([]giota.Trytes, len(bd))}
+	for i := range bd {
+		resp.Trytes[i] = bd[i].Trytes()
+	}
+	return resp, nil
+}
+
+func (s *Server) allowed(adr giota.Address) bool {
+	if len(s.Policy.Destinations) == 0 {
+		return true
+	}
+	for _, d := range s.Policy.Destinations {
+		if d == adr {
+			return true
+		}
+	}
+	return false
+}
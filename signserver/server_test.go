@@ -0,0 +1,160 @@
+package signserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/iotaledger/giota"
+)
+
+const (
+	testSeed giota.Trytes  = "WQNZOHUT99PWKEBFSKQSYNC9XHT9GEBMOSJAQDQAXPEZPJNDIUB9TSNWVMHKWICW9WVZXSMDFGISOD9FZ"
+	dest     giota.Address = "PQZKJKZAHOPZLXRZSPKFHZDLITQTQYDCHSYJYECIFGIISVJXKDGTSSMZE9XAXVUVQWIYLLJLKQDJBMMTZ"
+	other    giota.Address = "RVORZ9SIIP9RCYMREUIXXVPQIPHVCNPQ9HZWYKFWYWZRE9JQKG9REPKIASHUUECPSQO9JT9XNMVKWYGVA"
+)
+
+// testClock is a clock which can be advanced.
+type testClock struct {
+	t time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.t
+}
+
+// newBundle returns the finalized bundle sending value of the input with
+// index to output and change of 10 to the next index.
+func newBundle(t *testing.T, index uint64, output giota.Address, value int64) []giota.Trytes {
+	in, err := giota.NewAddress(testSeed, index, giota.SecurityLevelLow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rem, err := giota.NewAddress(testSeed, index+1, giota.SecurityLevelLow)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var bd giota.Bundle
+	ts := time.Unix(1500000000, 0)
+	for _, e := range []giota.EntryParams{
+		{Address: output, Value: value, Timestamp: ts},
+		{Address: in, Value: -value - 10, Timestamp: ts, FragmentCount: 1},
+		{Address: rem, Value: 10, Timestamp: ts},
+	} {
+		if err := bd.AddEntry(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := bd.Finalize(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	trytes := make([]giota.Trytes, len(bd))
+	for i := range bd {
+		trytes[i] = bd[i].Trytes()
+	}
+	return trytes
+}
+
+func post(t *testing.T, url string, req interface{}, out interface{}) int {
+	b, err := json.Marshal(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.Post(url, "application/json", bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode
+}
+
+func TestSign(t *testing.T) {
+	clock := &testClock{t: time.Date(2018, 1, 1, 12, 0, 0, 0, time.UTC)}
+	s := &Server{
+		Seed:   testSeed,
+		Policy: Policy{MaxValuePerDay: 100, Destinations: []giota.Address{dest}},
+		Clock:  clock,
+	}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	key := func(index uint64) Key { return Key{Index: index, Security: giota.SecurityLevelLow} }
+	first := newBundle(t, 0, dest, 70)
+
+	tests := []struct {
+		name    string
+		req     *SignRequest
+		nextDay bool
+		code    int
+	}{
+		{name: "allowed", req: &SignRequest{Trytes: first, Inputs: []Key{key(0)}, Remainders: []Key{key(1)}}, code: http.StatusOK},
+		{name: "same bundle again", req: &SignRequest{Trytes: first, Inputs: []Key{key(0)}, Remainders: []Key{key(1)}}, code: http.StatusOK},
+		{name: "reused input", req: &SignRequest{Trytes: newBundle(t, 0, dest, 20), Inputs: []Key{key(0)}, Remainders: []Key{key(1)}}, code: http.StatusForbidden},
+		{name: "daily limit", req: &SignRequest{Trytes: newBundle(t, 2, dest, 50), Inputs: []Key{key(2)}, Remainders: []Key{key(3)}}, code: http.StatusForbidden},
+		{name: "next day", req: &SignRequest{Trytes: newBundle(t, 2, dest, 50), Inputs: []Key{key(2)}, Remainders: []Key{key(3)}}, nextDay: true, code: http.StatusOK},
+		{name: "destination", req: &SignRequest{Trytes: newBundle(t, 4, other, 1), Inputs: []Key{key(4)}, Remainders: []Key{key(5)}}, code: http.StatusForbidden},
+		{name: "remainder not own", req: &SignRequest{Trytes: newBundle(t, 4, dest, 1), Inputs: []Key{key(4)}}, code: http.StatusForbidden},
+		{name: "unknown input", req: &SignRequest{Trytes: newBundle(t, 4, dest, 1), Inputs: []Key{key(5)}, Remainders: []Key{key(5)}}, code: http.StatusBadRequest},
+		{name: "not finalized", req: &SignRequest{Trytes: first[:2], Inputs: []Key{key(0)}}, code: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		if tt.nextDay {
+			clock.t = clock.t.Add(24 * time.Hour)
+		}
+
+		var resp struct {
+			SignResponse
+			errorResponse
+		}
+		code := post(t, srv.URL+"/sign", tt.req, &resp)
+		if code != tt.code {
+			t.Errorf("%s: /sign returned status %d (%s), expected %d", tt.name, code, resp.Error, tt.code)
+			continue
+		}
+		if code != http.StatusOK {
+			continue
+		}
+
+		bd := make(giota.Bundle, len(resp.Trytes))
+		for i, tr := range resp.Trytes {
+			tx, err := giota.NewTransaction(tr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			bd[i] = *tx
+		}
+		if err := bd.IsValid(); err != nil {
+			t.Errorf("%s: /sign returned invalid bundle: %s", tt.name, err)
+		}
+	}
+}
+
+func TestAddress(t *testing.T) {
+	srv := httptest.NewServer(&Server{Seed: testSeed})
+	defer srv.Close()
+
+	want, err := giota.NewAddress(testSeed, 3, giota.SecurityLevelMedium)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var resp addressResponse
+	code := post(t, srv.URL+"/address", map[string]interface{}{"index": 3, "security": 2, "checksum": true}, &resp)
+	if code != http.StatusOK || resp.Address != want.WithChecksum() {
+		t.Errorf("/address returned %d %s, expected %s", code, resp.Address, want.WithChecksum())
+	}
+
+	var eresp errorResponse
+	if code := post(t, srv.URL+"/address", map[string]interface{}{"index": 3, "security": 4}, &eresp); code != http.StatusBadRequest {
+		t.Errorf("/address returned %d for security level 4", code)
+	}
+}
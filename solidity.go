@@ -0,0 +1,87 @@
+package giota
+
+import (
+	"errors"
+)
+
+// MaxSolidityWalk is the maximum number of transactions MissingReferences
+// visits before giving up.
+var MaxSolidityWalk = 10000
+
+// ErrSolidityWalkTooLong is returned if the walk of MissingReferences doesn't
+// reach confirmed transactions within MaxSolidityWalk transactions.
+var ErrSolidityWalkTooLong = errors.New("too many transactions to walk for solidity check")
+
+// IsSolid returns true if all transactions referenced by hash, directly or
+// indirectly through trunk and branch, are known to the node down to the
+// confirmed part of the Tangle.
+func (api *API) IsSolid(hash Hash) (bool, error) {
+	missing, err := api.MissingReferences(hash)
+	if err != nil {
+		return false, err
+	}
+	return len(missing) == 0, nil
+}
+
+// MissingReferences walks trunk and branch of hash level by level until it
+// reaches transactions confirmed by the latest solid milestone and returns
+// the hashes the node doesn't know.
+func (api *API) MissingReferences(hash Hash) ([]Hash, error) {
+	ni, err := api.GetNodeInfo()
+	if err != nil {
+		return nil, err
+	}
+	ms := []Hash{ni.LatestSolidSubtangleMilestone}
+
+	var missing []Hash
+	visited := map[Hash]bool{hash: true}
+	frontier := []Hash{hash}
+
+	for len(frontier) > 0 {
+		if len(visited) > MaxSolidityWalk {
+			return nil, ErrSolidityWalkTooLong
+		}
+
+		inc, err := api.GetInclusionStates(frontier, ms)
+		if err != nil {
+			return nil, err
+		}
+
+		var unconfirmed []Hash
+		for i, h := range frontier {
+			if i >= len(inc.States) || !inc.States[i] {
+				unconfirmed = append(unconfirmed, h)
+			}
+		}
+		if len(unconfirmed) == 0 {
+			break
+		}
+
+		// the fallback nodes would hide the transactions missing on the node
+		gt, err := api.getTrytes(unconfirmed)
+		if err != nil {
+			return nil, err
+		}
+		if len(gt.Trytes) != len(unconfirmed) {
+			return nil, errors.New("GetTrytes returned a wrong number of transactions")
+		}
+
+		frontier = nil
+		for i := range gt.Trytes {
+			tx := &gt.Trytes[i]
+			if tx.IsNull() {
+				missing = append(missing, unconfirmed[i])
+				continue
+			}
+
+			for _, ref := range []Hash{tx.TrunkTransaction, tx.BranchTransaction} {
+				if ref == EmptyHash || visited[ref] {
+					continue
+				}
+				visited[ref] = true
+				frontier = append(frontier, ref)
+			}
+		}
+	}
+	return missing, nil
+}
@@ -0,0 +1,69 @@
+package giota
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestMissingReferences(t *testing.T) {
+	bs := filterTestBundle()
+	m, c, b, a := bs[0], bs[1], bs[2], bs[2]
+
+	b.TrunkTransaction = Hash(m.Hash())
+	b.BranchTransaction = EmptyHash
+	a.Tag = Tag(pad("SOLID", 27))
+	a.TrunkTransaction = Hash(b.Hash())
+	a.BranchTransaction = Hash(c.Hash())
+
+	known := map[Hash]Transaction{m.Hash(): m, b.Hash(): b, a.Hash(): a}
+	null, err := NewTransaction(Trytes(strings.Repeat("9", TransactionTrinarySize/3)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	api, done := newFakeNode(t, map[string]fakeNodeHandler{
+		"getNodeInfo": func(map[string]json.RawMessage) interface{} {
+			return &GetNodeInfoResponse{LatestSolidSubtangleMilestone: EmptyHash}
+		},
+		"getInclusionStates": func(req map[string]json.RawMessage) interface{} {
+			var hs []Hash
+			json.Unmarshal(req["transactions"], &hs)
+			resp := &GetInclusionStatesResponse{States: make([]bool, len(hs))}
+			for i, h := range hs {
+				resp.States[i] = h == m.Hash()
+			}
+			return resp
+		},
+		"getTrytes": func(req map[string]json.RawMessage) interface{} {
+			var hs []Hash
+			json.Unmarshal(req["hashes"], &hs)
+			resp := &GetTrytesResponse{}
+			for _, h := range hs {
+				tx, ok := known[h]
+				if !ok {
+					tx = *null
+				}
+				resp.Trytes = append(resp.Trytes, tx)
+			}
+			return resp
+		},
+	})
+	defer done()
+
+	missing, err := api.MissingReferences(a.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(missing) != 1 || missing[0] != c.Hash() {
+		t.Errorf("MissingReferences() returned %v, expected %v", missing, c.Hash())
+	}
+
+	solid, err := api.IsSolid(b.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !solid {
+		t.Error("IsSolid() returned false for a solid transaction")
+	}
+}
@@ -0,0 +1,304 @@
+// Package stats collects statistics of the Tangle as seen by a node: the
+// rates of new and confirmed transactions, confirmation latencies, the
+// interval of milestones and the number of tips. It is fed by the tx, sn and
+// lmi events of the ZMQ feed of a node and by polling the node:
+//
+//	c := stats.NewCollector(time.Minute)
+//	go c.Run(ctx, api, 10*time.Second)
+//	for msg := range zmqMessages {
+//		c.ObserveZMQ(msg)
+//	}
+//	json.NewEncoder(w).Encode(c.Snapshot())
+package stats
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iotaledger/giota"
+)
+
+// DefaultMaxLatency is the time a transaction waits for its confirmation
+// before it is no longer tracked, if Collector.MaxLatency is zero.
+const DefaultMaxLatency = time.Hour
+
+// maxIntervals is the number of milestone intervals averaged.
+const maxIntervals = 10
+
+// Percentiles are percentiles of durations.
+type Percentiles struct {
+	P50 time.Duration `json:"p50"`
+	P90 time.Duration `json:"p90"`
+	P99 time.Duration `json:"p99"`
+}
+
+// Snapshot are the statistics at a point in time. Rates are per second over
+// the window of the Collector.
+type Snapshot struct {
+	At time.Time `json:"at"`
+	// TPS is the rate of new transactions.
+	TPS float64 `json:"tps"`
+	// CTPS is the rate of confirmed transactions.
+	CTPS float64 `json:"ctps"`
+	// ConfirmationRate is CTPS/TPS, or 0.
+	ConfirmationRate float64 `json:"confirmationRate"`
+	// ConfirmationLatency are the times from the arrival to the
+	// confirmation of the transactions confirmed in the window.
+	ConfirmationLatency Percentiles `json:"confirmationLatency"`
+	// NewTipsPerSecond is the rate of tips not seen at the previous poll,
+	// a lower bound of TPS if there is no ZMQ feed.
+	NewTipsPerSecond float64 `json:"newTipsPerSecond"`
+	Tips             int     `json:"tips"`
+	MilestoneIndex   int64   `json:"milestoneIndex"`
+	// MilestoneInterval is the mean time between the last milestones.
+	MilestoneInterval time.Duration `json:"milestoneInterval"`
+}
+
+type latency struct {
+	at time.Time
+	d  time.Duration
+}
+
+// Collector collects statistics. It is safe for concurrent use.
+type Collector struct {
+	// Window is the time over which rates and latencies are computed.
+	Window time.Duration
+	// MaxLatency is the time a transaction waits for its confirmation
+	// before it is no longer tracked.
+	MaxLatency time.Duration
+	// Clock tells the time of events. If nil, giota.SystemClock is used.
+	Clock giota.Clock
+
+	mu        sync.Mutex
+	txs       []time.Time
+	confs     []time.Time
+	newTips   []time.Time
+	latencies []latency
+	pending   map[giota.Hash]time.Time
+	tips      map[giota.Hash]bool
+
+	milestone   int64
+	milestoneAt time.Time
+	intervals   []time.Duration
+}
+
+// NewCollector returns a collector computing rates over window.
+func NewCollector(window time.Duration) *Collector {
+	return &Collector{
+		Window:  window,
+		pending: make(map[giota.Hash]time.Time),
+	}
+}
+
+func (c *Collector) now() time.Time {
+	if c.Clock == nil {
+		return giota.SystemClock.Now()
+	}
+	return c.Clock.Now()
+}
+
+// ObserveTransaction records the arrival of the transaction hash.
+func (c *Collector) ObserveTransaction(hash giota.Hash) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	c.txs = append(c.txs, now)
+	if c.pending == nil {
+		c.pending = make(map[giota.Hash]time.Time)
+	}
+	if _, ok := c.pending[hash]; !ok {
+		c.pending[hash] = now
+		if len(c.pending)%1024 == 0 {
+			c.prunePending(now)
+		}
+	}
+	c.prune(now)
+}
+
+// ObserveConfirmation records the confirmation of the transaction hash.
+func (c *Collector) ObserveConfirmation(hash giota.Hash) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	c.confs = append(c.confs, now)
+	if at, ok := c.pending[hash]; ok {
+		delete(c.pending, hash)
+		c.latencies = append(c.latencies, latency{at: now, d: now.Sub(at)})
+	}
+	c.prune(now)
+}
+
+// ObserveMilestone records the latest milestone index.
+func (c *Collector) ObserveMilestone(index int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	if index <= c.milestone {
+		return
+	}
+	if c.milestone > 0 {
+		// skipped milestones share the interval
+		d := now.Sub(c.milestoneAt) / time.Duration(index-c.milestone)
+		c.intervals = append(c.intervals, d)
+		if len(c.intervals) > maxIntervals {
+			c.intervals = c.intervals[1:]
+		}
+	}
+	c.milestone = index
+	c.milestoneAt = now
+}
+
+// ObserveZMQ records a message of the ZMQ feed of a node. It uses the tx,
+// sn and lmi events and ignores the others.
+func (c *Collector) ObserveZMQ(msg string) {
+	f := strings.Fields(msg)
+	if len(f) < 3 {
+		return
+	}
+	switch f[0] {
+	case "tx":
+		c.ObserveTransaction(giota.Hash(f[1]))
+	case "sn":
+		c.ObserveConfirmation(giota.Hash(f[2]))
+	case "lmi":
+		if index, err := strconv.ParseInt(f[2], 10, 64); err == nil {
+			c.ObserveMilestone(index)
+		}
+	}
+}
+
+// Poll samples the latest milestone and the tips of the node of api.
+func (c *Collector) Poll(api giota.APIClient) error {
+	ni, err := api.GetNodeInfo()
+	if err != nil {
+		return err
+	}
+	c.ObserveMilestone(ni.LatestMilestoneIndex)
+
+	gt, err := api.GetTips()
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	tips := make(map[giota.Hash]bool, len(gt.Hashes))
+	for _, h := range gt.Hashes {
+		tips[h] = true
+		// the first poll only records the tips
+		if c.tips != nil && !c.tips[h] {
+			c.newTips = append(c.newTips, now)
+		}
+	}
+	c.tips = tips
+	c.prune(now)
+	return nil
+}
+
+// Run polls api every interval until ctx is done. Failed polls are skipped.
+func (c *Collector) Run(ctx context.Context, api giota.APIClient, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		c.Poll(api)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// prune drops the events before the window.
+func (c *Collector) prune(now time.Time) {
+	from := now.Add(-c.Window)
+	c.txs = dropBefore(c.txs, from)
+	c.confs = dropBefore(c.confs, from)
+	c.newTips = dropBefore(c.newTips, from)
+
+	i := 0
+	for i < len(c.latencies) && c.latencies[i].at.Before(from) {
+		i++
+	}
+	c.latencies = c.latencies[i:]
+}
+
+// prunePending drops the transactions waiting for their confirmation for
+// longer than MaxLatency.
+func (c *Collector) prunePending(now time.Time) {
+	max := c.MaxLatency
+	if max <= 0 {
+		max = DefaultMaxLatency
+	}
+	for h, at := range c.pending {
+		if now.Sub(at) > max {
+			delete(c.pending, h)
+		}
+	}
+}
+
+// dropBefore drops the times before from of the ordered ts.
+func dropBefore(ts []time.Time, from time.Time) []time.Time {
+	i := sort.Search(len(ts), func(i int) bool { return !ts[i].Before(from) })
+	return ts[i:]
+}
+
+// Snapshot returns the statistics now.
+func (c *Collector) Snapshot() Snapshot {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	c.prune(now)
+	c.prunePending(now)
+
+	secs := c.Window.Seconds()
+	s := Snapshot{
+		At:             now,
+		Tips:           len(c.tips),
+		MilestoneIndex: c.milestone,
+	}
+	if secs > 0 {
+		s.TPS = float64(len(c.txs)) / secs
+		s.CTPS = float64(len(c.confs)) / secs
+		s.NewTipsPerSecond = float64(len(c.newTips)) / secs
+	}
+	if s.TPS > 0 {
+		s.ConfirmationRate = s.CTPS / s.TPS
+	}
+
+	if len(c.latencies) > 0 {
+		ds := make([]time.Duration, len(c.latencies))
+		for i, l := range c.latencies {
+			ds[i] = l.d
+		}
+		sort.Slice(ds, func(i, j int) bool { return ds[i] < ds[j] })
+		s.ConfirmationLatency = Percentiles{
+			P50: percentile(ds, 50),
+			P90: percentile(ds, 90),
+			P99: percentile(ds, 99),
+		}
+	}
+
+	if len(c.intervals) > 0 {
+		var sum time.Duration
+		for _, d := range c.intervals {
+			sum += d
+		}
+		s.MilestoneInterval = sum / time.Duration(len(c.intervals))
+	}
+	return s
+}
+
+// percentile returns the p-th percentile of the sorted ds by nearest rank.
+func percentile(ds []time.Duration, p int) time.Duration {
+	i := (len(ds)*p + 99) / 100
+	if i < 1 {
+		i = 1
+	}
+	return ds[i-1]
+}
@@ -0,0 +1,88 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iotaledger/giota"
+	"github.com/iotaledger/giota/apimock"
+)
+
+// testClock is a clock which can be advanced.
+type testClock struct {
+	t time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.t
+}
+
+func TestCollector(t *testing.T) {
+	clock := &testClock{t: time.Unix(1500000000, 0)}
+	c := NewCollector(10 * time.Second)
+	c.Clock = clock
+
+	// 20 transactions in 10s, of which the first 10 are confirmed after
+	// 1 to 10s
+	for i := 0; i < 20; i++ {
+		c.ObserveZMQ("tx " + string(txHash(i)) + " ADDRESS 0 9 1500000000 0 0")
+		clock.t = clock.t.Add(500 * time.Millisecond)
+	}
+	for i := 0; i < 10; i++ {
+		c.ObserveZMQ("sn 1 " + string(txHash(i)) + " ADDRESS")
+	}
+	s := c.Snapshot()
+	switch {
+	case s.TPS != 2 || s.CTPS != 1 || s.ConfirmationRate != 0.5:
+		t.Errorf("Snapshot() = %+v, expected 2 TPS and 1 CTPS", s)
+	case s.ConfirmationLatency.P50 != 7500*time.Millisecond || s.ConfirmationLatency.P99 != 10*time.Second:
+		t.Errorf("Snapshot() = %+v", s.ConfirmationLatency)
+	}
+
+	c.ObserveZMQ("lmi 0 100")
+	clock.t = clock.t.Add(30 * time.Second)
+	c.ObserveZMQ("lmi 100 103")
+	c.ObserveZMQ("lmi 100 99")
+	c.ObserveZMQ("garbage")
+
+	s = c.Snapshot()
+	switch {
+	case s.TPS != 0 || s.CTPS != 0:
+		t.Errorf("Snapshot() = %+v, expected no transactions after the window", s)
+	case s.MilestoneIndex != 103 || s.MilestoneInterval != 10*time.Second:
+		t.Errorf("Snapshot() = %+v, expected milestone 103 every 10s", s)
+	}
+}
+
+func TestCollectorPoll(t *testing.T) {
+	clock := &testClock{t: time.Unix(1500000000, 0)}
+	c := NewCollector(10 * time.Second)
+	c.Clock = clock
+
+	tips := []giota.Hash{txHash(0), txHash(1)}
+	api := &apimock.Client{
+		GetNodeInfoFunc: func() (*giota.GetNodeInfoResponse, error) {
+			return &giota.GetNodeInfoResponse{LatestMilestoneIndex: 42}, nil
+		},
+		GetTipsFunc: func() (*giota.GetTipsResponse, error) {
+			return &giota.GetTipsResponse{Hashes: tips}, nil
+		},
+	}
+
+	if err := c.Poll(api); err != nil {
+		t.Fatal(err)
+	}
+	clock.t = clock.t.Add(time.Second)
+	tips = []giota.Hash{txHash(1), txHash(2), txHash(3), txHash(4), txHash(5), txHash(6)}
+	if err := c.Poll(api); err != nil {
+		t.Fatal(err)
+	}
+
+	if s := c.Snapshot(); s.Tips != 6 || s.NewTipsPerSecond != 0.5 || s.MilestoneIndex != 42 {
+		t.Errorf("Snapshot() = %+v", s)
+	}
+}
+
+func txHash(i int) giota.Hash {
+	return giota.Hash(giota.Int2Trits(int64(i), giota.HashSize).Trytes())
+}
@@ -0,0 +1,123 @@
+package stream
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/iotaledger/giota"
+)
+
+var (
+	// ErrManifestNotFound is returned by ReadFile if the manifest is not
+	// published to the address.
+	ErrManifestNotFound = errors.New("manifest is not found")
+	// ErrIncompleteFile is returned by ReadFile if a part of the file is not
+	// published to the address.
+	ErrIncompleteFile = errors.New("file is incomplete")
+	// ErrFileChecksum is returned by ReadFile if the reassembled file doesn't
+	// match the size or checksum of the manifest.
+	ErrFileChecksum = errors.New("file doesn't match its manifest")
+)
+
+// Manifest describes a file published by PublishFile.
+type Manifest struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+	// Parts are the IDs of the payloads holding the content, in order.
+	Parts []giota.Trytes `json:"parts"`
+}
+
+// PublishFile publishes the content of r with name. The content is split into
+// parts of one bundle each, which are published as payloads, followed by the
+// Manifest as JSON payload. It returns the ID of the manifest payload.
+func (p *Publisher) PublishFile(name string, r io.Reader) (giota.Trytes, *Manifest, error) {
+	m := &Manifest{Name: name}
+	h := sha256.New()
+	part := make([]byte, ChunkSize*MaxChunksPerBundle)
+
+	for {
+		n, err := io.ReadFull(r, part)
+		if n > 0 {
+			h.Write(part[:n])
+			m.Size += int64(n)
+
+			id, perr := p.Publish(part[:n])
+			if perr != nil {
+				return "", nil, perr
+			}
+			m.Parts = append(m.Parts, id)
+		}
+
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	m.SHA256 = hex.EncodeToString(h.Sum(nil))
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		return "", nil, err
+	}
+	id, err := p.Publish(b)
+	if err != nil {
+		return "", nil, err
+	}
+	return id, m, nil
+}
+
+// ReadFile fetches the file with the manifest payload id from adr, verifies
+// it and writes its content to w.
+func ReadFile(api *giota.API, adr giota.Address, id giota.Trytes, w io.Writer) (*Manifest, error) {
+	pls, err := Read(api, adr)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeFile(pls, id, w)
+}
+
+// DecodeFile reassembles the file with the manifest payload id from pls,
+// verifies it and writes its content to w.
+func DecodeFile(pls []Payload, id giota.Trytes, w io.Writer) (*Manifest, error) {
+	byID := make(map[giota.Trytes][]byte, len(pls))
+	for _, pl := range pls {
+		byID[pl.ID] = pl.Data
+	}
+
+	b, ok := byID[id]
+	if !ok {
+		return nil, ErrManifestNotFound
+	}
+	m := &Manifest{}
+	if err := json.Unmarshal(b, m); err != nil {
+		return nil, err
+	}
+
+	// verify before writing anything
+	h := sha256.New()
+	var size int64
+	for _, part := range m.Parts {
+		data, ok := byID[part]
+		if !ok {
+			return nil, ErrIncompleteFile
+		}
+		h.Write(data)
+		size += int64(len(data))
+	}
+	if size != m.Size || hex.EncodeToString(h.Sum(nil)) != m.SHA256 {
+		return nil, ErrFileChecksum
+	}
+
+	for _, part := range m.Parts {
+		if _, err := w.Write(byID[part]); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
@@ -0,0 +1,240 @@
+// Package stream publishes arbitrary byte payloads as zero-value transactions
+// to an address and reads them back, e.g. for logging data of IoT devices.
+//
+// A payload is split into chunks, one per transaction. Besides the data,
+// the message of each transaction holds the SHA-256 checksum of the whole
+// payload, which identifies the payload, the position of the chunk and the
+// number of chunks:
+//
+//	checksum (64 trytes) | index (9) | count (9) | length (3) | data
+//
+// Chunks are published in bundles of up to MaxChunksPerBundle transactions.
+package stream
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/iotaledger/giota"
+)
+
+const (
+	messageSize  = giota.SignatureMessageFragmentTrinarySize / 3
+	checksumSize = sha256.Size * 2
+	indexSize    = 9
+	lengthSize   = 3
+	headerSize   = checksumSize + 2*indexSize + lengthSize
+
+	// ChunkSize is the number of payload bytes per transaction.
+	ChunkSize = (messageSize - headerSize) / 2
+)
+
+// MaxChunksPerBundle is the max number of transactions of a bundle published
+// by Publish.
+var MaxChunksPerBundle = 10
+
+// MaxPayloadSize is the max size in bytes of a payload published by Publish.
+// Chunks of larger payloads are invalid, so that a forged count can't make
+// readers allocate more.
+var MaxPayloadSize = 1 << 20
+
+var (
+	// ErrEmptyPayload is returned by Publish for an empty payload.
+	ErrEmptyPayload = errors.New("payload is empty")
+	// ErrPayloadTooLarge is returned by Publish for a payload larger than
+	// MaxPayloadSize.
+	ErrPayloadTooLarge = errors.New("payload is too large")
+	// ErrInvalidChunk is returned if a message is not a chunk of a stream.
+	ErrInvalidChunk = errors.New("message is not a chunk of a stream")
+)
+
+// Publisher publishes payloads to Address.
+type Publisher struct {
+	API     *giota.API
+	Address giota.Address
+	Tag     giota.Trytes
+	// Depth, MWM and Pow are passed to giota.SendTrytes.
+	Depth int64
+	MWM   int64
+	Pow   giota.PowFunc
+}
+
+// Publish publishes payload and returns its ID, the trytes of its checksum.
+// If publishing fails, some chunks may be published already; publishing the
+// same payload again completes it.
+func (p *Publisher) Publish(payload []byte) (giota.Trytes, error) {
+	switch {
+	case len(payload) == 0:
+		return "", ErrEmptyPayload
+	case len(payload) > MaxPayloadSize:
+		return "", ErrPayloadTooLarge
+	}
+
+	id, chunks := Chunks(payload)
+	for len(chunks) > 0 {
+		n := len(chunks)
+		if n > MaxChunksPerBundle {
+			n = MaxChunksPerBundle
+		}
+
+		trs := make([]giota.Transfer, n)
+		for i := range trs {
+			trs[i] = giota.Transfer{Address: p.Address, Message: chunks[i], Tag: p.Tag}
+		}
+
+		bd, err := giota.PrepareTransfers(p.API, "", trs, nil, "", giota.SecurityLevelMedium)
+		if err != nil {
+			return "", err
+		}
+		if _, err := giota.SendTrytes(p.API, p.Depth, bd, p.MWM, p.Pow); err != nil {
+			return "", err
+		}
+		chunks = chunks[n:]
+	}
+	return id, nil
+}
+
+// Chunks splits payload into the messages of its chunks and returns them with
+// the ID of the payload.
+func Chunks(payload []byte) (giota.Trytes, []giota.Trytes) {
+	sum := sha256.Sum256(payload)
+	id := giota.EncodeMessage(sum[:])
+
+	count := (len(payload) + ChunkSize - 1) / ChunkSize
+	chunks := make([]giota.Trytes, count)
+	for i := range chunks {
+		data := payload[i*ChunkSize:]
+		if len(data) > ChunkSize {
+			data = data[:ChunkSize]
+		}
+
+		chunks[i] = id +
+			giota.Int2Trits(int64(i), indexSize*3).Trytes() +
+			giota.Int2Trits(int64(count), indexSize*3).Trytes() +
+			giota.Int2Trits(int64(len(data)), lengthSize*3).Trytes() +
+			giota.EncodeMessage(data)
+	}
+	return id, chunks
+}
+
+// Chunk is a parsed chunk of a payload.
+type Chunk struct {
+	ID    giota.Trytes
+	Index int
+	Count int
+	Data  []byte
+}
+
+// maxChunks returns the number of chunks of a payload of MaxPayloadSize.
+func maxChunks() int64 {
+	return int64((MaxPayloadSize + ChunkSize - 1) / ChunkSize)
+}
+
+// ParseChunk parses the message of a transaction of a stream. Padding of the
+// message is ignored. Chunks of payloads larger than MaxPayloadSize are
+// invalid.
+func ParseChunk(msg giota.Trytes) (*Chunk, error) {
+	if len(msg) < headerSize {
+		return nil, ErrInvalidChunk
+	}
+
+	c := &Chunk{ID: msg[:checksumSize]}
+	if _, err := giota.DecodeMessage(c.ID); err != nil {
+		return nil, ErrInvalidChunk
+	}
+
+	h, err := msg[checksumSize:headerSize].ToTrits()
+	if err != nil {
+		return nil, ErrInvalidChunk
+	}
+	index := h[:indexSize*3].Int()
+	count := h[indexSize*3 : 2*indexSize*3].Int()
+	n := int(h[2*indexSize*3:].Int())
+	if count <= 0 || count > maxChunks() || index < 0 || index >= count || n <= 0 || n > ChunkSize || headerSize+n*2 > len(msg) {
+		return nil, ErrInvalidChunk
+	}
+	c.Index, c.Count = int(index), int(count)
+
+	data, err := giota.DecodeMessage(msg[headerSize : headerSize+n*2])
+	if err != nil {
+		return nil, ErrInvalidChunk
+	}
+	c.Data = data
+	return c, nil
+}
+
+// Payload is a payload reconstructed by Decode.
+type Payload struct {
+	ID   giota.Trytes
+	Data []byte
+	// Timestamp is the earliest timestamp of the transactions of the payload.
+	Timestamp time.Time
+}
+
+// Decode reconstructs the payloads of the chunks in txs, ordered by their
+// Timestamp. Transactions which are not chunks, incomplete payloads and
+// payloads which don't match their checksum are skipped.
+func Decode(txs []giota.Transaction) []Payload {
+	// chunks are kept by index, as their count isn't trusted before the
+	// payload matches its checksum
+	type partial struct {
+		count  int
+		chunks map[int][]byte
+		ts     time.Time
+	}
+	partials := make(map[giota.Trytes]*partial)
+
+	for i := range txs {
+		c, err := ParseChunk(txs[i].SignatureMessageFragment)
+		if err != nil {
+			continue
+		}
+
+		p, ok := partials[c.ID]
+		switch {
+		case !ok:
+			p = &partial{count: c.Count, chunks: make(map[int][]byte), ts: txs[i].Timestamp}
+			partials[c.ID] = p
+		case p.count != c.Count:
+			continue
+		case txs[i].Timestamp.Before(p.ts):
+			p.ts = txs[i].Timestamp
+		}
+		p.chunks[c.Index] = c.Data
+	}
+
+	var payloads []Payload
+	for id, p := range partials {
+		if len(p.chunks) != p.count {
+			continue
+		}
+		chunks := make([][]byte, p.count)
+		for i, c := range p.chunks {
+			chunks[i] = c
+		}
+		data := bytes.Join(chunks, nil)
+		sum := sha256.Sum256(data)
+		if giota.EncodeMessage(sum[:]) != id {
+			continue
+		}
+		payloads = append(payloads, Payload{ID: id, Data: data, Timestamp: p.ts})
+	}
+
+	sort.Slice(payloads, func(i, j int) bool {
+		return payloads[i].Timestamp.Before(payloads[j].Timestamp)
+	})
+	return payloads
+}
+
+// Read fetches the transactions of adr and returns the payloads published to
+// it, see Decode.
+func Read(api *giota.API, adr giota.Address) ([]Payload, error) {
+	txs, err := api.FindTransactionObjects(&giota.FindTransactionsRequest{Addresses: []giota.Address{adr}})
+	if err != nil {
+		return nil, err
+	}
+	return Decode(txs), nil
+}
@@ -0,0 +1,236 @@
+package stream
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/iotaledger/giota"
+)
+
+const testAddress giota.Address = "PQZKJKZAHOPZLXRZSPKFHZDLITQTQYDCHSYJYECIFGIISVJXKDGTSSMZE9XAXVUVQWIYLLJLKQDJBMMTZ"
+
+// newFakeNode returns an API of a node which stores broadcasted transactions
+// and returns all of them for any findTransactions.
+func newFakeNode(t *testing.T) (*giota.API, func()) {
+	var (
+		mu  sync.Mutex
+		txs = map[giota.Hash]giota.Transaction{}
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Command string
+			Trytes  []giota.Transaction
+			Hashes  []giota.Hash
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("fake node could not decode request: %s", err)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		var resp interface{} = struct{}{}
+		switch req.Command {
+		case "getTransactionsToApprove":
+			resp = &giota.GetTransactionsToApproveResponse{TrunkTransaction: giota.EmptyHash, BranchTransaction: giota.EmptyHash}
+		case "broadcastTransactions":
+			for _, tx := range req.Trytes {
+				txs[tx.Hash()] = tx
+			}
+		case "findTransactions":
+			hashes := []giota.Hash{}
+			for h := range txs {
+				hashes = append(hashes, h)
+			}
+			resp = &giota.FindTransactionsResponse{Hashes: hashes}
+		case "getTrytes":
+			found := []giota.Transaction{}
+			for _, h := range req.Hashes {
+				found = append(found, txs[h])
+			}
+			resp = &giota.GetTrytesResponse{Trytes: found}
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	return giota.NewAPI(srv.URL, nil), srv.Close
+}
+
+func TestPublishRead(t *testing.T) {
+	api, done := newFakeNode(t)
+	defer done()
+
+	r := rand.New(rand.NewSource(1))
+	payloads := [][]byte{
+		[]byte("temperature=21.5"),
+		make([]byte, ChunkSize*MaxChunksPerBundle+1),
+	}
+	r.Read(payloads[1])
+
+	p := &Publisher{
+		API:     api,
+		Address: testAddress,
+		Tag:     "STREAM",
+		Depth:   3,
+		MWM:     1,
+		Pow:     func(giota.Trytes, int) (giota.Trytes, error) { return "NONCE", nil },
+	}
+
+	ids := map[giota.Trytes][]byte{}
+	for _, pl := range payloads {
+		id, err := p.Publish(pl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[id] = pl
+	}
+
+	read, err := Read(api, testAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(read) != len(payloads) {
+		t.Fatalf("Read() returned %d payloads, expected %d", len(read), len(payloads))
+	}
+	for _, pl := range read {
+		if !bytes.Equal(pl.Data, ids[pl.ID]) {
+			t.Errorf("Read() returned wrong data for payload %s", pl.ID)
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	payload := bytes.Repeat([]byte("0123456789"), ChunkSize/5)
+	_, chunks := Chunks(payload)
+	if len(chunks) != 2 {
+		t.Fatalf("Chunks() returned %d chunks", len(chunks))
+	}
+
+	tx := func(msg giota.Trytes) giota.Transaction {
+		return giota.Transaction{SignatureMessageFragment: msg + giota.Trytes(bytes.Repeat([]byte("9"), 2187-len(msg)))}
+	}
+	corrupted := []byte(chunks[1])
+	corrupted[len(corrupted)-1] = 'A'
+	forged := withCount(chunks[0], giota.Trytes("MMMMMMMMM"))
+
+	tests := []struct {
+		name  string
+		txs   []giota.Transaction
+		found bool
+	}{
+		{name: "in order", txs: []giota.Transaction{tx(chunks[0]), tx(chunks[1])}, found: true},
+		{name: "reversed with duplicates", txs: []giota.Transaction{tx(chunks[1]), tx(chunks[0]), tx(chunks[1])}, found: true},
+		{name: "incomplete", txs: []giota.Transaction{tx(chunks[1])}},
+		{name: "corrupted", txs: []giota.Transaction{tx(chunks[0]), tx(giota.Trytes(corrupted))}},
+		{name: "no chunk", txs: []giota.Transaction{tx("HELLO")}},
+		{name: "forged count", txs: []giota.Transaction{tx(forged), tx(chunks[1])}},
+	}
+
+	for _, tt := range tests {
+		pls := Decode(tt.txs)
+		switch {
+		case !tt.found && len(pls) != 0:
+			t.Errorf("%s: Decode() returned %d payloads", tt.name, len(pls))
+		case tt.found && (len(pls) != 1 || !bytes.Equal(pls[0].Data, payload)):
+			t.Errorf("%s: Decode() didn't return the payload", tt.name)
+		}
+	}
+}
+
+// withCount returns chunk with the count trytes replaced by count.
+func withCount(chunk, count giota.Trytes) giota.Trytes {
+	return chunk[:checksumSize+indexSize] + count + chunk[checksumSize+2*indexSize:]
+}
+
+func TestParseChunk(t *testing.T) {
+	_, chunks := Chunks([]byte("payload"))
+	count := func(n int64) giota.Trytes {
+		return giota.Int2Trits(n, indexSize*3).Trytes()
+	}
+
+	tests := []struct {
+		name  string
+		msg   giota.Trytes
+		valid bool
+	}{
+		{name: "chunk", msg: chunks[0], valid: true},
+		{name: "max count", msg: withCount(chunks[0], count(maxChunks())), valid: true},
+		{name: "count beyond MaxPayloadSize", msg: withCount(chunks[0], count(maxChunks()+1))},
+		{name: "max trytes count", msg: withCount(chunks[0], "MMMMMMMMM")},
+		{name: "negative count", msg: withCount(chunks[0], count(-1))},
+		{name: "short", msg: chunks[0][:headerSize-1]},
+	}
+	for _, tt := range tests {
+		c, err := ParseChunk(tt.msg)
+		switch {
+		case tt.valid && err != nil:
+			t.Errorf("%s: %s", tt.name, err)
+		case !tt.valid && err != ErrInvalidChunk:
+			t.Errorf("%s: ParseChunk() returned %+v, %v", tt.name, c, err)
+		}
+	}
+}
+
+func TestPublishTooLarge(t *testing.T) {
+	p := &Publisher{Address: testAddress}
+	if _, err := p.Publish(make([]byte, MaxPayloadSize+1)); err != ErrPayloadTooLarge {
+		t.Errorf("Publish() returned %v, expected ErrPayloadTooLarge", err)
+	}
+}
+
+func TestPublishFile(t *testing.T) {
+	api, done := newFakeNode(t)
+	defer done()
+
+	content := make([]byte, 2*ChunkSize*MaxChunksPerBundle+100)
+	rand.New(rand.NewSource(2)).Read(content)
+
+	p := &Publisher{
+		API:     api,
+		Address: testAddress,
+		Depth:   3,
+		MWM:     1,
+		Pow:     func(giota.Trytes, int) (giota.Trytes, error) { return "NONCE", nil },
+	}
+
+	id, m, err := p.PublishFile("data.bin", bytes.NewReader(content))
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case len(m.Parts) != 3 || m.Size != int64(len(content)):
+		t.Fatalf("PublishFile() returned manifest %+v", m)
+	}
+
+	var buf bytes.Buffer
+	rm, err := ReadFile(api, testAddress, id, &buf)
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case rm.Name != "data.bin" || rm.SHA256 != m.SHA256:
+		t.Errorf("ReadFile() returned manifest %+v", rm)
+	case !bytes.Equal(buf.Bytes(), content):
+		t.Error("ReadFile() returned wrong content")
+	}
+
+	pls, err := Read(api, testAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var missing []Payload
+	for _, pl := range pls {
+		if pl.ID != m.Parts[1] {
+			missing = append(missing, pl)
+		}
+	}
+	if _, err := DecodeFile(missing, id, &buf); err != ErrIncompleteFile {
+		t.Errorf("DecodeFile() returned %v, expected ErrIncompleteFile", err)
+	}
+	if _, err := DecodeFile(pls, m.Parts[0], &buf); err == nil {
+		t.Error("DecodeFile() should fail for a payload which is no manifest")
+	}
+}
@@ -0,0 +1,119 @@
+package giota
+
+import (
+	"sync"
+	"time"
+)
+
+// BalanceChange is an event sent by SubscribeBalance.
+type BalanceChange struct {
+	Address Address
+	Old     int64
+	New     int64
+	// Transactions are the hashes of transactions on Address which were not
+	// known at the previous poll, i.e. the ones causing the change.
+	Transactions []Hash
+	// Err is set instead of the other fields if polling the node failed.
+	Err error
+}
+
+// SubscribeBalance polls the balances of adrs every interval and sends a
+// BalanceChange whenever one of them differs from the previous poll. The
+// first poll only records the initial state. Calling the returned func stops
+// polling and closes the channel.
+func (api *API) SubscribeBalance(adrs []Address, interval time.Duration) (<-chan BalanceChange, func()) {
+	ch := make(chan BalanceChange)
+	stop := make(chan struct{})
+
+	go func() {
+		defer close(ch)
+
+		var bals []int64
+		known := make(map[Address]map[Hash]bool, len(adrs))
+
+		t := time.NewTicker(interval)
+		defer t.Stop()
+
+		for {
+			changes, nbals, err := api.pollBalances(adrs, bals, known)
+			switch {
+			case err != nil:
+				changes = []BalanceChange{{Err: err}}
+			default:
+				bals = nbals
+			}
+
+			for _, c := range changes {
+				select {
+				case ch <- c:
+				case <-stop:
+					return
+				}
+			}
+
+			select {
+			case <-t.C:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() { close(stop) })
+	}
+}
+
+func (api *API) pollBalances(adrs []Address, prev []int64, known map[Address]map[Hash]bool) ([]BalanceChange, []int64, error) {
+	r, err := api.GetBalances(adrs, 100)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var changes []BalanceChange
+	seen := make(map[Address][]Hash)
+	for i, adr := range adrs {
+		if i >= len(r.Balances) {
+			break
+		}
+
+		first := prev == nil || known[adr] == nil
+		if !first && r.Balances[i] == prev[i] {
+			continue
+		}
+
+		ft, err := api.FindTransactions(&FindTransactionsRequest{Addresses: []Address{adr}})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var txs []Hash
+		for _, h := range ft.Hashes {
+			if !known[adr][h] {
+				txs = append(txs, h)
+			}
+		}
+		seen[adr] = txs
+
+		if !first {
+			changes = append(changes, BalanceChange{
+				Address:      adr,
+				Old:          prev[i],
+				New:          r.Balances[i],
+				Transactions: txs,
+			})
+		}
+	}
+
+	// only remember transactions once the whole poll succeeded
+	for adr, txs := range seen {
+		if known[adr] == nil {
+			known[adr] = make(map[Hash]bool)
+		}
+		for _, h := range txs {
+			known[adr][h] = true
+		}
+	}
+	return changes, r.Balances, nil
+}
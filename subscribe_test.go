@@ -0,0 +1,57 @@
+package giota
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSubscribeBalance(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		balance = "0"
+		hashes  = []Hash{}
+	)
+
+	api, done := newFakeNode(t, map[string]fakeNodeHandler{
+		"getBalances": func(map[string]json.RawMessage) interface{} {
+			mu.Lock()
+			defer mu.Unlock()
+			return map[string]interface{}{"balances": []string{balance}}
+		},
+		"findTransactions": func(map[string]json.RawMessage) interface{} {
+			mu.Lock()
+			defer mu.Unlock()
+			return &FindTransactionsResponse{Hashes: hashes}
+		},
+	})
+	defer done()
+
+	ch, stop := api.SubscribeBalance([]Address{filterAddr1}, 10*time.Millisecond)
+	defer stop()
+
+	time.Sleep(30 * time.Millisecond)
+	mu.Lock()
+	balance = "42"
+	hashes = []Hash{EmptyHash}
+	mu.Unlock()
+
+	select {
+	case c := <-ch:
+		switch {
+		case c.Err != nil:
+			t.Fatal(c.Err)
+		case c.Address != filterAddr1 || c.Old != 0 || c.New != 42:
+			t.Errorf("SubscribeBalance() sent %+v", c)
+		case len(c.Transactions) != 1 || c.Transactions[0] != EmptyHash:
+			t.Errorf("SubscribeBalance() sent transactions %v", c.Transactions)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("SubscribeBalance() sent no change")
+	}
+
+	stop()
+	for range ch {
+	}
+}
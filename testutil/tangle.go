@@ -0,0 +1,211 @@
+// Package testutil provides a private Tangle for integration tests, so value
+// transfers can be tested without relying on public nodes.
+//
+// The Tangle is either an already running node whose endpoint is given in
+// GIOTA_TANGLE, or a docker compose project (e.g. IRI or Hornet together with
+// a coordinator) given in GIOTA_TANGLE_COMPOSE, which is started and stopped
+// by the harness. Without either, the compose project of DefaultCompose is
+// started, a Hornet node with its coordinator, if docker is installed. Tests
+// should skip if Start returns ErrNoTangle. The MWM and depth of the Tangle
+// default to DefaultMWM and DefaultDepth and can be set in GIOTA_TANGLE_MWM
+// and GIOTA_TANGLE_DEPTH.
+package testutil
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"time"
+)
+
+// Environment variables configuring the private Tangle.
+const (
+	// EnvEndpoint is the API endpoint of a running private Tangle node.
+	EnvEndpoint = "GIOTA_TANGLE"
+	// EnvCompose is the path of a docker compose file starting a private
+	// Tangle whose node API is published on EnvEndpoint, or on
+	// DefaultEndpoint if that is empty. It defaults to DefaultCompose if
+	// EnvEndpoint is not set either.
+	EnvCompose = "GIOTA_TANGLE_COMPOSE"
+	// EnvSeed is a seed owning funds on the private Tangle, usually the one
+	// of its genesis snapshot. It defaults to GenesisSeed with
+	// DefaultCompose.
+	EnvSeed = "GIOTA_TANGLE_SEED"
+	// EnvMWM is the minimum weight magnitude of the private Tangle.
+	EnvMWM = "GIOTA_TANGLE_MWM"
+	// EnvDepth is the depth of the tip selection on the private Tangle.
+	EnvDepth = "GIOTA_TANGLE_DEPTH"
+)
+
+// DefaultEndpoint is the node endpoint used with EnvCompose if EnvEndpoint
+// is not set.
+const DefaultEndpoint = "http://localhost:14265"
+
+// Defaults of the network parameters, as private Tangles are usually run.
+const (
+	DefaultMWM   = 1
+	DefaultDepth = 3
+)
+
+// GenesisSeed owns all funds of the snapshot of DefaultCompose.
+const GenesisSeed = "GIOTA9PRIVATE9TANGLE9GENESIS99999999999999999999999999999999999999999999999999999"
+
+// DefaultCompose is the path of the docker compose file of the private
+// Tangle in testdata/tangle of giota.
+var DefaultCompose = defaultCompose()
+
+func defaultCompose() string {
+	_, file, _, ok := runtime.Caller(0)
+	if !ok {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(file), "..", "testdata", "tangle", "docker-compose.yml")
+}
+
+// ErrNoTangle is returned by Start if no private Tangle is configured and
+// docker is not installed to start the one of DefaultCompose.
+var ErrNoTangle = errors.New("no private tangle configured, set " + EnvEndpoint + " or " + EnvCompose + " or install docker")
+
+// Tangle is a private Tangle used by integration tests.
+type Tangle struct {
+	// Endpoint is the API endpoint of the node.
+	Endpoint string
+	// Seed is the funded seed from EnvSeed, which may be empty.
+	Seed string
+	// MWM and Depth are the network parameters of the Tangle.
+	MWM   int64
+	Depth int64
+
+	compose string
+	// docker is the command running docker compose.
+	docker []string
+}
+
+// Start starts the private Tangle configured by the environment and waits up
+// to timeout until its node answers API calls.
+func Start(timeout time.Duration) (*Tangle, error) {
+	t := &Tangle{
+		Endpoint: os.Getenv(EnvEndpoint),
+		Seed:     os.Getenv(EnvSeed),
+		compose:  os.Getenv(EnvCompose),
+	}
+
+	var err error
+	if t.MWM, err = envInt(EnvMWM, DefaultMWM); err != nil {
+		return nil, err
+	}
+	if t.Depth, err = envInt(EnvDepth, DefaultDepth); err != nil {
+		return nil, err
+	}
+
+	if t.compose == "" && t.Endpoint == "" {
+		t.docker = composeCommand()
+		if t.docker == nil || DefaultCompose == "" {
+			return nil, ErrNoTangle
+		}
+		t.compose = DefaultCompose
+		if t.Seed == "" {
+			t.Seed = GenesisSeed
+		}
+	}
+
+	if t.compose != "" {
+		if t.Endpoint == "" {
+			t.Endpoint = DefaultEndpoint
+		}
+		if t.docker == nil {
+			if t.docker = composeCommand(); t.docker == nil {
+				return nil, errors.New("neither docker compose nor docker-compose is installed")
+			}
+		}
+		if err := t.dockerCompose("up", "-d"); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := WaitForNode(t.Endpoint, timeout); err != nil {
+		t.Stop()
+		return nil, err
+	}
+	return t, nil
+}
+
+// envInt returns the positive integer in the environment variable name, or
+// def if it is not set.
+func envInt(name string, def int64) (int64, error) {
+	v := os.Getenv(name)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("%s must be a positive integer, not %q", name, v)
+	}
+	return n, nil
+}
+
+// Stop tears down the private Tangle if Start brought it up.
+func (t *Tangle) Stop() error {
+	if t.compose == "" {
+		return nil
+	}
+	return t.dockerCompose("down", "-v")
+}
+
+func (t *Tangle) dockerCompose(args ...string) error {
+	cmd := append(append([]string{}, t.docker[1:]...), "-f", t.compose)
+	out, err := exec.Command(t.docker[0], append(cmd, args...)...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s %v: %s: %s", t.docker[0], args, err, out)
+	}
+	return nil
+}
+
+// composeCommand returns the command running docker compose, the compose
+// plugin of docker or the legacy docker-compose, or nil if neither is
+// installed.
+func composeCommand() []string {
+	if docker, err := exec.LookPath("docker"); err == nil && exec.Command(docker, "compose", "version").Run() == nil {
+		return []string{docker, "compose"}
+	}
+	if compose, err := exec.LookPath("docker-compose"); err == nil {
+		return []string{compose}
+	}
+	return nil
+}
+
+// WaitForNode polls getNodeInfo on endpoint until the node answers or
+// timeout passes.
+func WaitForNode(endpoint string, timeout time.Duration) error {
+	c := &http.Client{Timeout: 5 * time.Second}
+	deadline := time.Now().Add(timeout)
+
+	for {
+		req, err := http.NewRequest("POST", endpoint, bytes.NewBufferString(`{"command":"getNodeInfo"}`))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-IOTA-API-Version", "1")
+
+		resp, err := c.Do(req)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return nil
+			}
+			err = fmt.Errorf("node returned http status %d", resp.StatusCode)
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("node %s is not ready: %s", endpoint, err)
+		}
+		time.Sleep(time.Second)
+	}
+}
@@ -0,0 +1,50 @@
+package testutil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestWaitForNode(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	if err := WaitForNode(srv.URL, 10*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Errorf("WaitForNode() polled %d times, expected 2", calls)
+	}
+
+	srv.Close()
+	if err := WaitForNode(srv.URL, 0); err == nil {
+		t.Error("WaitForNode() should fail for a closed node")
+	}
+}
+
+func TestStartWithoutConfig(t *testing.T) {
+	if os.Getenv(EnvEndpoint) != "" || os.Getenv(EnvCompose) != "" {
+		t.Skip("private tangle is configured")
+	}
+
+	// without docker, the default compose project can't be started
+	t.Setenv("PATH", "")
+	if _, err := Start(time.Second); err != ErrNoTangle {
+		t.Errorf("Start() returned %v, expected ErrNoTangle", err)
+	}
+}
+
+func TestDefaultCompose(t *testing.T) {
+	if _, err := os.Stat(DefaultCompose); err != nil {
+		t.Error(err)
+	}
+}
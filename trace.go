@@ -0,0 +1,77 @@
+package giota
+
+import (
+	"time"
+)
+
+// Tracer receives spans of the calls of an API, SendTrytes, PrepareTransfers
+// and the local PoW, e.g. to export them with OpenTelemetry, see package
+// otelgiota. A Tracer must be safe for concurrent use.
+type Tracer interface {
+	StartSpan(name string, attrs ...Attribute) Span
+}
+
+// Span is an operation traced by a Tracer.
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	// End ends the span with the error of the operation, or nil.
+	End(err error)
+}
+
+// Attribute is a key of a span with a string, int64 or bool value.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Attributes of spans.
+const (
+	AttrCommand    = "giota.command"
+	AttrEndpoint   = "giota.endpoint"
+	AttrNode       = "giota.node"
+	AttrDurationMS = "giota.duration_ms"
+	AttrBundleSize = "giota.bundle_size"
+	AttrTransfers  = "giota.transfers"
+	AttrDepth      = "giota.depth"
+	AttrMWM        = "giota.mwm"
+	AttrAttempts   = "giota.attempts"
+	AttrSecurity   = "giota.security"
+	AttrRemotePoW  = "giota.remote_pow"
+)
+
+// SetTracer sets the tracer of the calls of api and the funcs using api. A
+// nil t disables tracing. SetTracer must not be called concurrently with API
+// calls.
+func (api *API) SetTracer(t Tracer) {
+	api.tracer = t
+}
+
+// span is a Span which adds its duration as attribute.
+type span struct {
+	Span
+	start time.Time
+}
+
+func (s *span) End(err error) {
+	s.Span.SetAttributes(Attribute{AttrDurationMS, time.Since(s.start).Milliseconds()})
+	s.Span.End(err)
+}
+
+// nopSpan is returned by startSpan without tracer.
+type nopSpan struct{}
+
+func (nopSpan) SetAttributes(...Attribute) {}
+func (nopSpan) End(error)                  {}
+
+// startSpan starts a span of the tracer of api, if any. api may be nil.
+func (api *API) startSpan(name string, attrs ...Attribute) Span {
+	if api == nil || api.tracer == nil {
+		return nopSpan{}
+	}
+	return &span{Span: api.tracer.StartSpan(name, attrs...), start: time.Now()}
+}
+
+// traced reports whether spans of api are recorded.
+func (api *API) traced() bool {
+	return api != nil && api.tracer != nil
+}
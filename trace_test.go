@@ -0,0 +1,92 @@
+package giota
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+)
+
+// recordingTracer records the names and attributes of ended spans.
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []recordedSpan
+}
+
+type recordedSpan struct {
+	t     *recordingTracer
+	name  string
+	attrs map[string]interface{}
+	err   error
+}
+
+func (t *recordingTracer) StartSpan(name string, attrs ...Attribute) Span {
+	s := &recordedSpan{t: t, name: name, attrs: make(map[string]interface{})}
+	s.SetAttributes(attrs...)
+	return s
+}
+
+func (s *recordedSpan) SetAttributes(attrs ...Attribute) {
+	for _, a := range attrs {
+		s.attrs[a.Key] = a.Value
+	}
+}
+
+func (s *recordedSpan) End(err error) {
+	s.err = err
+	s.t.mu.Lock()
+	s.t.spans = append(s.t.spans, *s)
+	s.t.mu.Unlock()
+}
+
+func TestTracer(t *testing.T) {
+	api, done := newFakeNode(t, map[string]fakeNodeHandler{
+		"getTransactionsToApprove": func(map[string]json.RawMessage) interface{} {
+			return &GetTransactionsToApproveResponse{TrunkTransaction: EmptyHash, BranchTransaction: EmptyHash}
+		},
+		"broadcastTransactions": func(map[string]json.RawMessage) interface{} {
+			return struct{}{}
+		},
+		"storeTransactions": func(map[string]json.RawMessage) interface{} {
+			return struct{}{}
+		},
+	})
+	defer done()
+
+	tr := &recordingTracer{}
+	api.SetTracer(tr)
+
+	bd, err := PrepareTransfers(api, accountTestSeed, []Transfer{{Address: filterAddr1}}, nil, "", SecurityLevelLow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pow := func(Trytes, int) (Trytes, error) { return "NONCE", nil }
+	if _, err := SendTrytes(api, 0, bd, 1, pow); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		name  string
+		key   string
+		value interface{}
+	}{
+		{"giota.PrepareTransfers", AttrBundleSize, int64(1)},
+		{"giota.getTransactionsToApprove", AttrCommand, "getTransactionsToApprove"},
+		{"giota.PoW", AttrMWM, int64(1)},
+		{"giota.broadcastTransactions", AttrEndpoint, api.endpoint},
+		{"giota.storeTransactions", AttrNode, "IRI"},
+		{"giota.SendTrytes", AttrAttempts, int64(1)},
+	}
+	if len(tr.spans) != len(want) {
+		t.Fatalf("recorded %d spans, expected %d", len(tr.spans), len(want))
+	}
+	for i, w := range want {
+		s := tr.spans[i]
+		_, hasDuration := s.attrs[AttrDurationMS]
+		switch {
+		case s.name != w.name:
+			t.Errorf("span %d is %s, expected %s", i, s.name, w.name)
+		case s.attrs[w.key] != w.value || !hasDuration || s.err != nil:
+			t.Errorf("span %s has attributes %v and error %v", s.name, s.attrs, s.err)
+		}
+	}
+}
@@ -26,6 +26,7 @@ SOFTWARE.
 package giota
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"time"
@@ -36,14 +37,14 @@ type Transaction struct {
 	SignatureMessageFragment      Trytes
 	Address                       Address
 	Value                         int64 `json:",string"`
-	ObsoleteTag                   Trytes
+	ObsoleteTag                   Tag
 	Timestamp                     time.Time `json:",string"`
 	CurrentIndex                  int64     `json:",string"`
 	LastIndex                     int64     `json:",string"`
-	Bundle                        Trytes
-	TrunkTransaction              Trytes
-	BranchTransaction             Trytes
-	Tag                           Trytes
+	Bundle                        BundleHash
+	TrunkTransaction              Hash
+	BranchTransaction             Hash
+	Tag                           Tag
 	AttachmentTimestamp           Trytes
 	AttachmentTimestampLowerBound Trytes
 	AttachmentTimestampUpperBound Trytes
@@ -98,6 +99,11 @@ const (
 		TagTrinarySize + AttachmentTimestampTrinarySize +
 		AttachmentTimestampLowerBoundTrinarySize + AttachmentTimestampUpperBoundTrinarySize +
 		NonceTrinarySize
+
+	// EssenceTrinarySize is the size of the essence of a transaction, the
+	// part of it hashed for the bundle hash.
+	EssenceTrinarySize = AddressTrinarySize + ValueTrinarySize + ObsoleteTagTrinarySize +
+		TimestampTrinarySize + CurrentIndexTrinarySize + LastIndexTrinarySize
 )
 
 // NewTransaction makes tx from trits.
@@ -107,10 +113,13 @@ func NewTransaction(trytes Trytes) (*Transaction, error) {
 		return nil, err
 	}
 
-	err := t.parser(trytes.Trits())
+	trits, err := trytes.ToTrits()
 	if err != nil {
 		return nil, err
 	}
+	if err := t.parser(trits); err != nil {
+		return nil, err
+	}
 
 	return &t, nil
 }
@@ -138,15 +147,15 @@ func (t *Transaction) parser(trits Trits) error {
 		return err
 	}
 	t.Value = trits[ValueTrinaryOffset : ValueTrinaryOffset+ValueTrinarySize].Int()
-	t.ObsoleteTag = trits[ObsoleteTagTrinaryOffset : ObsoleteTagTrinaryOffset+ObsoleteTagTrinarySize].Trytes()
+	t.ObsoleteTag = Tag(trits[ObsoleteTagTrinaryOffset : ObsoleteTagTrinaryOffset+ObsoleteTagTrinarySize].Trytes())
 	timestamp := trits[TimestampTrinaryOffset : TimestampTrinaryOffset+TimestampTrinarySize].Int()
 	t.Timestamp = time.Unix(timestamp, 0)
 	t.CurrentIndex = trits[CurrentIndexTrinaryOffset : CurrentIndexTrinaryOffset+CurrentIndexTrinarySize].Int()
 	t.LastIndex = trits[LastIndexTrinaryOffset : LastIndexTrinaryOffset+LastIndexTrinarySize].Int()
-	t.Bundle = trits[BundleTrinaryOffset : BundleTrinaryOffset+BundleTrinarySize].Trytes()
-	t.TrunkTransaction = trits[TrunkTransactionTrinaryOffset : TrunkTransactionTrinaryOffset+TrunkTransactionTrinarySize].Trytes()
-	t.BranchTransaction = trits[BranchTransactionTrinaryOffset : BranchTransactionTrinaryOffset+BranchTransactionTrinarySize].Trytes()
-	t.Tag = trits[TagTrinaryOffset : TagTrinaryOffset+TagTrinarySize].Trytes()
+	t.Bundle = BundleHash(trits[BundleTrinaryOffset : BundleTrinaryOffset+BundleTrinarySize].Trytes())
+	t.TrunkTransaction = Hash(trits[TrunkTransactionTrinaryOffset : TrunkTransactionTrinaryOffset+TrunkTransactionTrinarySize].Trytes())
+	t.BranchTransaction = Hash(trits[BranchTransactionTrinaryOffset : BranchTransactionTrinaryOffset+BranchTransactionTrinarySize].Trytes())
+	t.Tag = Tag(trits[TagTrinaryOffset : TagTrinaryOffset+TagTrinarySize].Trytes())
 	t.AttachmentTimestamp = trits[AttachmentTimestampTrinaryOffset : AttachmentTimestampTrinaryOffset+AttachmentTimestampTrinarySize].Trytes()
 	t.AttachmentTimestampLowerBound = trits[AttachmentTimestampLowerBoundTrinaryOffset : AttachmentTimestampLowerBoundTrinaryOffset+AttachmentTimestampLowerBoundTrinarySize].Trytes()
 	t.AttachmentTimestampUpperBound = trits[AttachmentTimestampUpperBoundTrinaryOffset : AttachmentTimestampUpperBoundTrinaryOffset+AttachmentTimestampUpperBoundTrinarySize].Trytes()
@@ -184,16 +193,71 @@ func (t *Transaction) HasValidNonce(mwm int64) bool {
 }
 
 // Hash returns the hash of the transaction.
-func (t *Transaction) Hash() Trytes {
-	return t.Trytes().Hash()
+func (t *Transaction) Hash() Hash {
+	return Hash(t.Trytes().Hash())
+}
+
+// IsEmpty returns true if t is the zero Transaction or the null placeholder,
+// so that it carries no transaction.
+func (t *Transaction) IsEmpty() bool {
+	return *t == Transaction{} || t.IsNull()
+}
+
+// IsNull returns true if t is the all-9s placeholder which nodes return for
+// unknown transaction hashes.
+func (t *Transaction) IsNull() bool {
+	for _, c := range t.Trytes() {
+		if c != '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// WithNewAttachment returns a copy of t attached to trunk and branch with
+// nonce and the attachment timestamps. t itself is not changed.
+func (t *Transaction) WithNewAttachment(trunk, branch Hash, nonce, timestamp, lower, upper Trytes) Transaction {
+	c := *t
+	c.TrunkTransaction = trunk
+	c.BranchTransaction = branch
+	c.Nonce = nonce
+	c.AttachmentTimestamp = timestamp
+	c.AttachmentTimestampLowerBound = lower
+	c.AttachmentTimestampUpperBound = upper
+	return c
 }
 
-// UnmarshalJSON makes transaction struct from json.
+// AttachmentTime returns AttachmentTimestamp, which is set by attachToTangle
+// in milliseconds, as time.Time. It returns the zero time if the transaction
+// isn't attached.
+func (t *Transaction) AttachmentTime() time.Time {
+	if t.AttachmentTimestamp.IsValid() != nil {
+		return time.Time{}
+	}
+
+	ms := t.AttachmentTimestamp.Trits().Int()
+	if ms <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(ms/1000, (ms%1000)*int64(time.Millisecond))
+}
+
+// UnmarshalJSON makes transaction struct from json, either its trytes or an
+// object of its fields. The numbers of objects may be JSON numbers or
+// strings.
 func (t *Transaction) UnmarshalJSON(b []byte) error {
 	var s Trytes
 	var err error
 
-	if err = json.Unmarshal(b, &s); err != nil {
+	if bytes.HasPrefix(bytes.TrimSpace(b), []byte("{")) {
+		var j jsonTransaction
+		if err = json.Unmarshal(b, &j); err != nil {
+			return err
+		}
+		if s, err = j.trytes(); err != nil {
+			return err
+		}
+	} else if err = json.Unmarshal(b, &s); err != nil {
 		return err
 	}
 
@@ -201,7 +265,11 @@ func (t *Transaction) UnmarshalJSON(b []byte) error {
 		return err
 	}
 
-	return t.parser(s.Trits())
+	trits, err := s.ToTrits()
+	if err != nil {
+		return err
+	}
+	return t.parser(trits)
 }
 
 // MarshalJSON makes trytes ([]byte) from a transaction.
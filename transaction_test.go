@@ -26,6 +26,8 @@ package giota
 
 import (
 	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 )
@@ -96,7 +98,7 @@ func TestTransaction_Hash(t *testing.T) {
 	tests := []struct {
 		name    string
 		trytes  Trytes
-		hashP81 Trytes
+		hashP81 Hash
 	}{
 		{
 			name:    "test 1",
@@ -112,7 +114,7 @@ func TestTransaction_Hash(t *testing.T) {
 
 	for _, tt := range tests {
 		// test input trytes hash
-		if tt.trytes.Hash() != tt.hashP81 {
+		if Hash(tt.trytes.Hash()) != tt.hashP81 {
 			t.Errorf("%s: trytes p81 hash is illegal %s\n", tt.name, tt.trytes.Hash())
 		}
 
@@ -129,3 +131,64 @@ func TestTransaction_Hash(t *testing.T) {
 		}
 	}
 }
+
+func TestTransactionWithNewAttachment(t *testing.T) {
+	bs := filterTestBundle()
+	orig := bs[0].Trytes()
+
+	tx := bs[0].WithNewAttachment(filterTestBundle()[1].Hash(), EmptyHash, "ABC", "MMMMMMMMM", "", "MMMMMMMMM")
+	switch {
+	case bs[0].Trytes() != orig:
+		t.Error("WithNewAttachment() changed the original transaction")
+	case tx.TrunkTransaction == bs[0].TrunkTransaction || tx.Nonce != "ABC":
+		t.Error("WithNewAttachment() didn't set the attachment")
+	case tx.Address != bs[0].Address || tx.Bundle != bs[0].Bundle:
+		t.Error("WithNewAttachment() changed the essence")
+	}
+
+	attached := Bundle{tx}
+	attached.ResetAttachment()
+	if attached[0].TrunkTransaction != EmptyHash || attached[0].Nonce != EmptyHash || !attached[0].AttachmentTime().IsZero() {
+		t.Error("ResetAttachment() didn't clear the attachment")
+	}
+}
+
+func TestTransactionIsNull(t *testing.T) {
+	null, err := NewTransaction(Trytes(strings.Repeat("9", TransactionTrinarySize/3)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := filterTestBundle()[0]
+
+	tests := []struct {
+		name        string
+		tx          *Transaction
+		null, empty bool
+	}{
+		{name: "null", tx: null, null: true, empty: true},
+		{name: "zero", tx: &Transaction{}, empty: true},
+		{name: "transaction", tx: &tx},
+	}
+	for _, tt := range tests {
+		if null, empty := tt.tx.IsNull(), tt.tx.IsEmpty(); null != tt.null || empty != tt.empty {
+			t.Errorf("%s: IsNull() = %v, IsEmpty() = %v", tt.name, null, empty)
+		}
+	}
+}
+
+func TestGetTransactionObjects(t *testing.T) {
+	bs := filterTestBundle()
+	var calls int
+	api, done := newTrytesNode(t, &calls, bs[0])
+	defer done()
+
+	txs, err := api.GetTransactionObjects([]Hash{bs[0].Hash()})
+	if err != nil || len(txs) != 1 || txs[0].Hash() != bs[0].Hash() {
+		t.Errorf("GetTransactionObjects() = %v, %v", txs, err)
+	}
+
+	_, err = api.GetTransactionObjects([]Hash{bs[0].Hash(), bs[1].Hash()})
+	if !errors.Is(err, ErrTransactionNotFound) || !strings.Contains(err.Error(), string(bs[1].Hash())) {
+		t.Errorf("GetTransactionObjects() returned %v, expected ErrTransactionNotFound", err)
+	}
+}
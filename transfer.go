@@ -26,7 +26,9 @@ package giota
 
 import (
 	"errors"
+	"fmt"
 	"math"
+	"strings"
 	"time"
 )
 
@@ -38,9 +40,13 @@ const DefaultNumberOfWalks = 5
 
 // GetUsedAddress generates a new address which is not found in the tangle
 // and returns its new address and used addresses.
-func GetUsedAddress(api *API, seed Trytes, security int) (Address, []Address, error) {
+func GetUsedAddress(api APIClient, seed Trytes, security SecurityLevel) (Address, []Address, error) {
+	if err := apiOf(api).validateSecurity("GetUsedAddress", security); err != nil {
+		return "", nil, err
+	}
+
 	var all []Address
-	for index := 0; ; index++ {
+	for index := uint64(0); ; index++ {
 		adr, err := NewAddress(seed, index, security)
 		if err != nil {
 			return "", nil, err
@@ -66,17 +72,20 @@ func GetUsedAddress(api *API, seed Trytes, security int) (Address, []Address, er
 
 // GetInputs gets all possible inputs of a seed and returns them with the total balance.
 // end must be under start+500.
-func GetInputs(api *API, seed Trytes, start, end int, threshold int64, security int) (Balances, error) {
+func GetInputs(api APIClient, seed Trytes, start, end uint64, threshold int64, security SecurityLevel) (Balances, error) {
 	var err error
 	var adrs []Address
 
 	if start > end || end > (start+500) {
 		return nil, errors.New("Invalid start/end provided")
 	}
+	if err := apiOf(api).validateSecurity("GetInputs", security); err != nil {
+		return nil, err
+	}
 
 	switch {
 	case end > 0:
-		adrs, err = NewAddresses(seed, start, end-start, security)
+		adrs, err = NewAddresses(seed, start, int(end-start), security)
 	default:
 		_, adrs, err = GetUsedAddress(api, seed, security)
 	}
@@ -98,7 +107,7 @@ type Transfer struct {
 
 const sigSize = SignatureMessageFragmentTrinarySize / 3
 
-func addOutputs(trs []Transfer) (Bundle, []Trytes, int64) {
+func addOutputs(trs []Transfer, timestamp time.Time) (Bundle, []Trytes, int64, error) {
 	var (
 		bundle Bundle
 		frags  []Trytes
@@ -132,20 +141,28 @@ func addOutputs(trs []Transfer) (Bundle, []Trytes, int64) {
 		}
 
 		// Add first entries to the bundle
-		// Slice the address in case the user provided a checksummed one
-		bundle.Add(nsigs, tr.Address, tr.Value, time.Now(), tr.Tag)
+		err := bundle.AddEntry(EntryParams{
+			Address:       tr.Address,
+			Value:         tr.Value,
+			Timestamp:     timestamp,
+			Tag:           tr.Tag,
+			FragmentCount: nsigs,
+		})
+		if err != nil {
+			return nil, nil, 0, err
+		}
 
 		// Sum up total value
 		total += tr.Value
 	}
-	return bundle, frags, total
+	return bundle, frags, total, nil
 }
 
 // AddressInfo includes an address and its infomation for signing.
 type AddressInfo struct {
 	Seed     Trytes
-	Index    int
-	Security int
+	Index    uint64
+	Security SecurityLevel
 }
 
 // Address makes an Address from an AddressInfo
@@ -158,7 +175,7 @@ func (a *AddressInfo) Key() (Trytes, error) {
 	return NewKey(a.Seed, a.Index, a.Security)
 }
 
-func setupInputs(api *API, seed Trytes, inputs []AddressInfo, security int, total int64) (Balances, []AddressInfo, error) {
+func setupInputs(api APIClient, seed Trytes, inputs []AddressInfo, security SecurityLevel, total int64) (Balances, []AddressInfo, error) {
 	var bals Balances
 	var err error
 
@@ -184,6 +201,9 @@ func setupInputs(api *API, seed Trytes, inputs []AddressInfo, security int, tota
 		//  Case 1: user provided inputs
 		adrs := make([]Address, len(inputs))
 		for i, ai := range inputs {
+			if err := ai.Security.IsValid(); err != nil {
+				return nil, nil, &ValidationError{Func: "PrepareTransfers", Arg: "inputs", Index: i, Err: err}
+			}
 			adrs[i], err = ai.Address()
 			if err != nil {
 				return nil, nil, err
@@ -195,6 +215,13 @@ func setupInputs(api *API, seed Trytes, inputs []AddressInfo, security int, tota
 		if err != nil {
 			return nil, nil, err
 		}
+
+		// keep the inputs with balance, in the order of bals
+		funded := make([]AddressInfo, len(bals))
+		for i, bal := range bals {
+			funded[i] = inputs[bal.Index]
+		}
+		inputs = funded
 	}
 
 	// Return not enough balance error
@@ -207,153 +234,402 @@ func setupInputs(api *API, seed Trytes, inputs []AddressInfo, security int, tota
 // PrepareTransfers gets an array of transfer objects as input, and then prepares
 // the transfer by generating the correct bundle as well as choosing and signing the
 // inputs if necessary (if it's a value transfer).
-func PrepareTransfers(api *API, seed Trytes, trs []Transfer, inputs []AddressInfo, remainder Address, security int) (Bundle, error) {
-	var err error
+func PrepareTransfers(api APIClient, seed Trytes, trs []Transfer, inputs []AddressInfo, remainder Address, security SecurityLevel) (Bundle, error) {
+	return PrepareTransfersWithOptions(api, seed, trs, inputs, remainder, security, nil)
+}
+
+// PrepareOptions are options of PrepareTransfersWithOptions.
+type PrepareOptions struct {
+	// Clock tells the timestamp of the transactions of the bundle. It is
+	// read once, so all transactions have the same timestamp. If nil,
+	// SystemClock is used.
+	Clock Clock
+	// Remainder chooses the addresses of the change. If nil, the change
+	// goes to the remainder address or a new address of the seed.
+	Remainder *RemainderOptions
+}
+
+// PrepareTransfersWithOptions is like PrepareTransfers, but with options for
+// the timestamp and the change of the bundle. opts may be nil.
+func PrepareTransfersWithOptions(api APIClient, seed Trytes, trs []Transfer, inputs []AddressInfo, remainder Address, security SecurityLevel, opts *PrepareOptions) (Bundle, error) {
+	bundle, _, err := PrepareTransfersWithReport(api, seed, trs, inputs, remainder, security, opts)
+	return bundle, err
+}
+
+// ChangeReport tells which inputs a bundle spends and where the value of the
+// inputs in excess of the outputs goes.
+type ChangeReport struct {
+	// Inputs are the inputs spent by the bundle, in bundle order.
+	Inputs Balances
+	// Unused are the inputs which were not needed to cover the outputs.
+	Unused Balances
+	// Remainder is the first address receiving change. It is empty if the
+	// inputs cover the outputs exactly.
+	Remainder Address
+	// RemainderValue is the whole change.
+	RemainderValue int64
+	// Remainders are the outputs of the change, with the key index of their
+	// address, or -1 if it is not known.
+	Remainders Balances
+}
+
+// PrepareTransfersWithReport is like PrepareTransfersWithOptions, but also
+// returns which inputs the bundle spends and its remainder. The report is
+// nil if the bundle doesn't spend inputs.
+func PrepareTransfersWithReport(api APIClient, seed Trytes, trs []Transfer, inputs []AddressInfo, remainder Address, security SecurityLevel, opts *PrepareOptions) (Bundle, *ChangeReport, error) {
+	sp := apiOf(api).startSpan("giota.PrepareTransfers",
+		Attribute{AttrTransfers, int64(len(trs))},
+		Attribute{AttrSecurity, int64(security)},
+	)
+	bundle, report, err := prepareTransfers(api, seed, trs, inputs, remainder, security, opts)
+	sp.SetAttributes(Attribute{AttrBundleSize, int64(len(bundle))})
+	sp.End(err)
+	if err == nil {
+		apiOf(api).publishBundle(EventTransferPrepared, bundle, nil)
+	}
+	return bundle, report, err
+}
+
+func prepareTransfers(api APIClient, seed Trytes, trs []Transfer, inputs []AddressInfo, remainder Address, security SecurityLevel, opts *PrepareOptions) (Bundle, *ChangeReport, error) {
+	clock := SystemClock
+	if opts != nil && opts.Clock != nil {
+		clock = opts.Clock
+	}
+	timestamp := clock.Now()
+	change := &RemainderOptions{}
+	if opts != nil && opts.Remainder != nil {
+		change = opts.Remainder
+	}
+	if err := change.validate(remainder); err != nil {
+		return nil, nil, err
+	}
 
-	bundle, frags, total := addOutputs(trs)
+	bundle, frags, total, err := addOutputs(trs, timestamp)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	// Get inputs if we are sending tokens
 	if total <= 0 {
 		// If no input required, don't sign and simply finalize the bundle
-		bundle.Finalize(frags)
-		return bundle, nil
+		if err := bundle.Finalize(frags); err != nil {
+			return nil, nil, err
+		}
+		return bundle, nil, nil
+	}
+	if err := apiOf(api).validateSecurity("PrepareTransfers", security); err != nil {
+		return nil, nil, err
+	}
+	if err := apiOf(api).allowTransfers(trs); err != nil {
+		return nil, nil, err
 	}
 
 	bals, inputs, err := setupInputs(api, seed, inputs, security, total)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	err = addRemainder(api, bals, &bundle, security, remainder, seed, total)
+	keys, err := NewKeys(inputs)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	bundle.Finalize(frags)
-	err = signInputs(inputs, bundle)
-	return bundle, err
+	report, err := addRemainder(api, bals, inputs, &bundle, security, remainder, seed, total, timestamp, change)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(bundle) > MaxBundleSize {
+		return nil, nil, fmt.Errorf("bundle has %d transactions, more than %d", len(bundle), MaxBundleSize)
+	}
+
+	if err := bundle.Finalize(frags); err != nil {
+		return nil, nil, err
+	}
+	if err := bundle.SignInputs(keys); err != nil {
+		return nil, nil, err
+	}
+	apiOf(api).publishBundle(EventBundleSigned, bundle, nil)
+	return bundle, report, nil
 }
 
-func addRemainder(api *API, in Balances, bundle *Bundle, security int, remainder Address, seed Trytes, total int64) error {
-	for _, bal := range in {
-		var err error
-
-		// Add input as bundle entry
-		bundle.Add(security, bal.Address, -bal.Value, time.Now(), EmptyHash)
-
-		// If there is a remainder value add extra output to send remaining funds to
-		if remain := bal.Value - total; remain > 0 {
-			// If user has provided remainder address use it to send remaining funds to
-			adr := remainder
-			if adr == "" {
-				// Generate a new Address by calling getNewAddress
-				adr, _, err = GetUsedAddress(api, seed, security)
-				if err != nil {
-					return err
-				}
-			}
+// addRemainder adds the inputs in, whose security levels are the ones of
+// inputs with the same index, to bundle until they cover total, and the
+// outputs of the change chosen by opts.
+func addRemainder(api APIClient, in Balances, inputs []AddressInfo, bundle *Bundle, security SecurityLevel, remainder Address, seed Trytes, total int64, timestamp time.Time, opts *RemainderOptions) (*ChangeReport, error) {
+	report := &ChangeReport{}
+	var spent []AddressInfo
+	for i, bal := range in {
+		if total <= 0 || bal.Value <= 0 {
+			report.Unused = append(report.Unused, bal)
+			continue
+		}
+		if len(report.Inputs) >= MaxInputsPerBundle {
+			return nil, fmt.Errorf("transfer needs more than %d inputs", MaxInputsPerBundle)
+		}
 
-			// Remainder bundle entry
-			bundle.Add(1, adr, remain, time.Now(), EmptyHash)
-			return nil
+		// Add input as bundle entry with one transaction per key fragment
+		err := bundle.AddEntry(EntryParams{
+			Address:       bal.Address,
+			Value:         -bal.Value,
+			Timestamp:     timestamp,
+			FragmentCount: int(inputs[i].Security),
+		})
+		if err != nil {
+			return nil, err
 		}
+		report.Inputs = append(report.Inputs, bal)
+		spent = append(spent, inputs[i])
 
 		// If multiple inputs provided, subtract the totalTransferValue by
 		// the inputs balance
-		if total -= bal.Value; total == 0 {
-			return nil
-		}
-	}
-	return nil
-}
-
-func signInputs(inputs []AddressInfo, bundle Bundle) error {
-	//  Get the normalized bundle hash
-	nHash := bundle.Hash().Normalize()
-
-	// SIGNING OF INPUTS
-	// Here we do the actual signing of the inputs. Iterate over all bundle transactions,
-	// find the inputs, get the corresponding private key, and calculate signatureFragment
-	for i, bd := range bundle {
-		if bd.Value >= 0 {
+		if bal.Value <= total {
+			total -= bal.Value
 			continue
 		}
+		report.RemainderValue = bal.Value - total
+		total = 0
+	}
 
-		// Get the corresponding keyIndex and security of the address
-		var ai AddressInfo
-		for _, in := range inputs {
-			adr, err := in.Address()
-			if err != nil {
-				return err
-			}
+	if total > 0 {
+		return nil, fmt.Errorf("inputs lack %di to cover the outputs", total)
+	}
+	if report.RemainderValue == 0 {
+		return report, nil
+	}
 
-			if adr == bd.Address {
-				ai = in
-				break
+	outs, err := opts.outputs(api, seed, security, remainder, spent, report.RemainderValue)
+	if err != nil {
+		return nil, err
+	}
+	for _, out := range outs {
+		// the key of a spent input is partially revealed, so change sent
+		// back to an input could be stolen
+		if opts.Policy != RemainderHighestInput {
+			for _, bal := range report.Inputs {
+				if bal.Address == out.Address {
+					return nil, fmt.Errorf("remainder %s is an input of the bundle: %w", bal.Address, ErrSpentAddress)
+				}
 			}
 		}
 
-		// Get corresponding private key of the address
-		key, err := ai.Key()
+		// Remainder bundle entry
+		err := bundle.AddEntry(EntryParams{
+			Address:       out.Address,
+			Value:         out.Value,
+			Timestamp:     timestamp,
+			FragmentCount: 1,
+		})
 		if err != nil {
-			return err
+			return nil, err
 		}
+	}
+	report.Remainder = outs[0].Address
+	report.Remainders = outs
+	return report, nil
+}
 
-		// Calculate the new signatureFragment with the first bundle fragment
-		bundle[i].SignatureMessageFragment = Sign(nHash[:27], key[:6561/3])
-
-		// if user chooses higher than 27-tryte security
-		// for each security level, add an additional signature
-		for j := 1; j < ai.Security; j++ {
-			//  Because the signature is > 2187 trytes, we need to find the subsequent
-			// transaction to add the remainder of the signature same address as well
-			// as value = 0 (as we already spent the input)
-			if bundle[i+j].Address == bd.Address && bundle[i+j].Value == 0 {
-				//  Calculate the new signature
-				nfrag := Sign(nHash[(j%3)*27:(j%3)*27+27], key[6561*j/3:(j+1)*6561/3])
-				//  Convert signature to trytes and assign it again to this bundle entry
-				bundle[i+j].SignatureMessageFragment = nfrag
-			}
-		}
+// DoPoW attaches a copy of the signed bundle bs to trunk and branch, doing
+// the PoW with pow, and returns it. bs itself is not changed. Attached bundles
+// are attached again. Drafts and bundles with unsigned inputs can't be
+// attached, ErrWrongState is returned for them.
+func (bs Bundle) DoPoW(trunk, branch Hash, mwm int64, pow PowFunc) (Bundle, error) {
+	if st := bs.State(); st < BundleSigned {
+		return nil, wrongState("attached", st)
+	}
+
+	tra := &GetTransactionsToApproveResponse{TrunkTransaction: trunk, BranchTransaction: branch}
+	attached, err := doPow(tra, 0, bs, mwm, pow)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	return Bundle(attached), nil
 }
 
-func doPow(tra *GetTransactionsToApproveResponse, depth int64, trytes []Transaction, mwm int64, pow PowFunc) error {
-	var prev Trytes
-	var err error
+// doPow attaches copies of trytes to the transactions in tra and returns
+// them. trytes itself is not changed.
+func doPow(tra *GetTransactionsToApproveResponse, depth int64, trytes []Transaction, mwm int64, pow PowFunc) ([]Transaction, error) {
+	// the tips come from the node and must not crash the conversion to trits
+	if err := tra.TrunkTransaction.IsValid(); err != nil {
+		return nil, fmt.Errorf("invalid trunk transaction of node: %w", err)
+	}
+	if err := tra.BranchTransaction.IsValid(); err != nil {
+		return nil, fmt.Errorf("invalid branch transaction of node: %w", err)
+	}
+
+	attached := make([]Transaction, len(trytes))
+	var prev Hash
 	for i := len(trytes) - 1; i >= 0; i-- {
-		switch {
-		case i == len(trytes)-1:
-			trytes[i].TrunkTransaction = tra.TrunkTransaction
-			trytes[i].BranchTransaction = tra.BranchTransaction
-		default:
-			trytes[i].TrunkTransaction = prev
-			trytes[i].BranchTransaction = tra.TrunkTransaction
+		trunk, branch := prev, tra.TrunkTransaction
+		if i == len(trytes)-1 {
+			trunk, branch = tra.TrunkTransaction, tra.BranchTransaction
 		}
 
 		timestamp := Int2Trits(time.Now().UnixNano()/1000000, TimestampTrinarySize).Trytes()
-		trytes[i].AttachmentTimestamp = timestamp
-		trytes[i].AttachmentTimestampLowerBound = ""
-		trytes[i].AttachmentTimestampUpperBound = maxTimestampTrytes
+		tx := trytes[i].WithNewAttachment(trunk, branch, "", timestamp, "", maxTimestampTrytes)
 
-		trytes[i].Nonce, err = pow(trytes[i].Trytes(), int(mwm))
+		nonce, err := pow(tx.Trytes(), int(mwm))
 		if err != nil {
-			return err
+			return nil, err
 		}
+		// the nonce may come from a remote PoW service
+		if err := IsTrytes(nonce); err != nil {
+			return nil, fmt.Errorf("invalid nonce of PoW: %w", err)
+		}
+		tx.Nonce = nonce
+
+		attached[i] = tx
+		prev = tx.Hash()
+	}
+	return attached, nil
+}
+
+// DefaultSendAttempts is the number of tip selections SendTrytes tries if
+// the selected tips turn out to be inconsistent.
+var DefaultSendAttempts = 3
+
+// referenceErrors are parts of the error messages of nodes rejecting the
+// selected tips or references.
+var referenceErrors = []string{
+	"inconsistent",
+	"not consistent",
+	"not solid",
+	"below max depth",
+	"invalid reference",
+}
 
-		prev = trytes[i].Hash()
+// IsReferenceError returns true if err is an error of a node rejecting the
+// trunk, branch or reference transaction, i.e. new tips must be selected.
+func IsReferenceError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, e := range referenceErrors {
+		if strings.Contains(msg, e) {
+			return true
+		}
 	}
-	return nil
+	return false
+}
+
+// SendResult describes how a bundle was attached by SendTrytes.
+type SendResult struct {
+	// Transactions are the attached transactions.
+	Transactions []Transaction
+	// Trunk and Branch are the tips selected for the attachment.
+	Trunk  Hash
+	Branch Hash
+	// Tail is the hash of the attached tail transaction.
+	Tail Hash
+	// AttachmentTimes are the attachment timestamps of Transactions.
+	AttachmentTimes []time.Time
+	// PowDuration is the time spent on the PoW, including attachToTangle
+	// calls if the PoW is done by the node.
+	PowDuration time.Duration
+	// Attempts is the number of tip selections needed.
+	Attempts int
+}
+
+// SendOptions are options of SendTrytesWithOptions.
+type SendOptions struct {
+	// Attempts is the max number of tip selections. If zero,
+	// DefaultSendAttempts is used.
+	Attempts int
+	// Reference is the transaction the tip selection must approve.
+	Reference Hash
+	// Sticky keeps Reference when retrying. Otherwise retries select tips
+	// without a reference.
+	Sticky bool
+	// Network supplies the depth and MWM if they are zero, e.g. Devnet.
+	Network *NetworkProfile
 }
 
 // SendTrytes does attachToTangle and finally, it broadcasts and stores the transactions.
-func SendTrytes(api *API, depth int64, trytes []Transaction, mwm int64, pow PowFunc) error {
-	tra, err := api.GetTransactionsToApprove(depth, DefaultNumberOfWalks, "")
-	if err != nil {
-		return err
+// It returns the attached transactions and how they were attached; trytes
+// itself is not changed. If the node rejects the selected tips, new tips are
+// selected up to DefaultSendAttempts times. If depth or mwm are zero,
+// DefaultDepth and DefaultMinWeightMagnitude are used; pass a NetworkProfile
+// to SendTrytesWithOptions to use those of another network.
+func SendTrytes(api APIClient, depth int64, trytes []Transaction, mwm int64, pow PowFunc) (*SendResult, error) {
+	return SendTrytesWithOptions(api, depth, trytes, mwm, pow, nil)
+}
+
+// SendTrytesWithOptions is like SendTrytes, but with options for retrying,
+// the tip selection and the network. opts may be nil.
+func SendTrytesWithOptions(api APIClient, depth int64, trytes []Transaction, mwm int64, pow PowFunc, opts *SendOptions) (*SendResult, error) {
+	if opts == nil {
+		opts = &SendOptions{}
+	}
+	if n := opts.Network; n != nil {
+		if depth == 0 {
+			depth = n.Depth
+		}
+		if mwm == 0 {
+			mwm = n.MinWeightMagnitude
+		}
+	}
+	if depth == 0 {
+		depth = DefaultDepth
 	}
+	if mwm == 0 {
+		mwm = DefaultMinWeightMagnitude
+	}
+	if err := apiOf(api).validateMWM("SendTrytes", mwm); err != nil {
+		return nil, err
+	}
+	attempts := opts.Attempts
+	if attempts <= 0 {
+		attempts = DefaultSendAttempts
+	}
+
+	sp := apiOf(api).startSpan("giota.SendTrytes",
+		Attribute{AttrBundleSize, int64(len(trytes))},
+		Attribute{AttrDepth, depth},
+		Attribute{AttrMWM, mwm},
+		Attribute{AttrRemotePoW, pow == nil},
+	)
+	res, err := sendTrytes(api, depth, trytes, mwm, pow, opts, attempts)
+	if res != nil {
+		sp.SetAttributes(Attribute{AttrAttempts, int64(res.Attempts)})
+	}
+	sp.End(err)
+	return res, err
+}
+
+func sendTrytes(api APIClient, depth int64, trytes []Transaction, mwm int64, pow PowFunc, opts *SendOptions, attempts int) (*SendResult, error) {
+	var err error
+	ref := opts.Reference
+	for i := 0; i < attempts; i++ {
+		var tra *GetTransactionsToApproveResponse
+		tra, err = api.GetTransactionsToApprove(depth, DefaultNumberOfWalks, ref)
+		err = sendError(ErrTipSelection, "", err)
+		if err == nil {
+			var res *SendResult
+			res, err = attachAndBroadcast(api, tra, depth, trytes, mwm, pow)
+			if err == nil {
+				res.Attempts = i + 1
+				return res, nil
+			}
+		}
+
+		if !IsReferenceError(err) {
+			return nil, err
+		}
+		if !opts.Sticky {
+			ref = ""
+		}
+	}
+	return nil, err
+}
+
+func attachAndBroadcast(api APIClient, tra *GetTransactionsToApproveResponse, depth int64, trytes []Transaction, mwm int64, pow PowFunc) (*SendResult, error) {
+	var err error
 
+	// the transactions of bundles attached before have tips
+	reattached := len(trytes) > 0 && trytes[0].TrunkTransaction != "" && trytes[0].TrunkTransaction != EmptyHash
+
+	start := time.Now()
+	apiOf(api).publishBundle(EventPowStarted, trytes, nil)
 	switch {
 	case pow == nil:
 		at := AttachToTangleRequest{
@@ -366,83 +642,113 @@ func SendTrytes(api *API, depth int64, trytes []Transaction, mwm int64, pow PowF
 		// attach to tangle - do pow
 		attached, err := api.AttachToTangle(&at)
 		if err != nil {
-			return err
+			apiOf(api).publishBundle(EventPowFinished, trytes, err)
+			return nil, sendError(ErrAttach, "", err)
 		}
 
 		trytes = attached.Trytes
 	default:
-		err := doPow(tra, depth, trytes, mwm, pow)
+		sp := apiOf(api).startSpan("giota.PoW",
+			Attribute{AttrBundleSize, int64(len(trytes))},
+			Attribute{AttrMWM, mwm},
+		)
+		attached, err := doPow(tra, depth, trytes, mwm, pow)
+		sp.End(err)
 		if err != nil {
-			return err
+			apiOf(api).publishBundle(EventPowFinished, trytes, err)
+			return nil, sendError(ErrAttach, "", err)
+		}
+		trytes = attached
+	}
+	apiOf(api).publishBundle(EventPowFinished, trytes, nil)
+
+	res := &SendResult{
+		Transactions:    trytes,
+		Trunk:           tra.TrunkTransaction,
+		Branch:          tra.BranchTransaction,
+		AttachmentTimes: make([]time.Time, len(trytes)),
+		PowDuration:     time.Since(start),
+	}
+	for i := range trytes {
+		res.AttachmentTimes[i] = trytes[i].AttachmentTime()
+		if trytes[i].CurrentIndex == 0 {
+			res.Tail = trytes[i].Hash()
 		}
 	}
 
 	// Broadcast and store tx
-	err = api.BroadcastTransactions(trytes)
-	if err != nil {
-		return err
+	if err = api.BroadcastTransactions(trytes); err != nil {
+		return nil, sendError(ErrBroadcast, res.Tail, err)
 	}
+	if err = api.StoreTransactions(trytes); err != nil {
+		return nil, sendError(ErrStore, res.Tail, err)
+	}
+	apiOf(api).publishBundle(EventBroadcasted, trytes, nil)
+	if reattached {
+		apiOf(api).publishBundle(EventReattached, trytes, nil)
+	}
+	return res, nil
+}
 
-	return api.StoreTransactions(trytes)
+// Promote sends transanction using tail as reference (promotes the tail transaction).
+// It returns how the transactions were attached; trytes itself is not changed.
+// If tail is below the max depth of DefaultDepthPolicy, it returns
+// ErrReattachRequired.
+func Promote(api APIClient, tail Hash, depth int64, trytes []Transaction, mwm int64, pow PowFunc) (*SendResult, error) {
+	return PromoteWithOptions(api, tail, depth, trytes, mwm, pow, nil)
 }
 
-// Promote sends transanction using tail as reference (promotes the tail transaction)
-func Promote(api *API, tail Trytes, depth int64, trytes []Transaction, mwm int64, pow PowFunc) error {
+// PromoteWithOptions is like Promote, but with the options of
+// SendTrytesWithOptions, whose Reference and Sticky are set to promote tail.
+// The max depth is that of the DepthPolicy of opts.Network. opts may be nil.
+func PromoteWithOptions(api APIClient, tail Hash, depth int64, trytes []Transaction, mwm int64, pow PowFunc, opts *SendOptions) (*SendResult, error) {
 	if len(trytes) == 0 {
-		return errors.New("empty transfer")
+		return nil, errors.New("empty transfer")
 	}
-	resp, err := api.CheckConsistency([]Trytes{tail})
-	if err != nil {
-		return err
-	} else if !resp.State {
-		return errors.New(resp.Info)
+	var o SendOptions
+	if opts != nil {
+		o = *opts
 	}
 
-	tra, err := api.GetTransactionsToApprove(depth, DefaultNumberOfWalks, tail)
+	gt, err := api.GetTrytes([]Hash{tail})
 	if err != nil {
-		return err
+		return nil, err
 	}
-
-	switch {
-	case pow == nil:
-		at := AttachToTangleRequest{
-			TrunkTransaction:   tra.TrunkTransaction,
-			BranchTransaction:  tra.BranchTransaction,
-			MinWeightMagnitude: mwm,
-			Trytes:             trytes,
-		}
-
-		// attach to tangle - do pow
-		attached, err := api.AttachToTangle(&at)
-		if err != nil {
-			return err
-		}
-
-		trytes = attached.Trytes
-	default:
-		err := doPow(tra, depth, trytes, mwm, pow)
-		if err != nil {
-			return err
-		}
+	if o.Network.depthPolicy().NeedsReattach(&gt.Trytes[0]) {
+		return nil, ErrReattachRequired
 	}
 
-	// Broadcast and store tx
-	err = api.BroadcastTransactions(trytes)
+	resp, err := api.CheckConsistency([]Hash{tail})
 	if err != nil {
-		return err
+		return nil, err
+	} else if !resp.State {
+		return nil, errors.New(resp.Info)
 	}
 
-	return api.StoreTransactions(trytes)
+	o.Reference, o.Sticky = tail, true
+	return SendTrytesWithOptions(api, depth, trytes, mwm, pow, &o)
 }
 
 // Send sends tokens. If you need to do pow locally, you must specifiy pow func,
-// otherwise this calls the AttachToTangle API
-func Send(api *API, seed Trytes, security int, trs []Transfer, mwm int64, pow PowFunc) (Bundle, error) {
+// otherwise this calls the AttachToTangle API. It returns the attached bundle.
+// The tips are selected with DefaultDepth, if mwm is zero
+// DefaultMinWeightMagnitude is used.
+func Send(api APIClient, seed Trytes, security SecurityLevel, trs []Transfer, mwm int64, pow PowFunc) (Bundle, error) {
+	return SendWithOptions(api, seed, security, trs, mwm, pow, nil)
+}
+
+// SendWithOptions is like Send, but sends the bundle with
+// SendTrytesWithOptions. The tips are selected with the depth of
+// opts.Network if it is set. opts may be nil.
+func SendWithOptions(api APIClient, seed Trytes, security SecurityLevel, trs []Transfer, mwm int64, pow PowFunc, opts *SendOptions) (Bundle, error) {
 	bd, err := PrepareTransfers(api, seed, trs, nil, "", security)
 	if err != nil {
 		return nil, err
 	}
 
-	err = SendTrytes(api, Depth, []Transaction(bd), mwm, pow)
-	return bd, err
+	res, err := SendTrytesWithOptions(api, 0, []Transaction(bd), mwm, pow, opts)
+	if err != nil {
+		return bd, err
+	}
+	return Bundle(res.Transactions), nil
 }
@@ -0,0 +1,159 @@
+package giota
+
+import (
+	"errors"
+)
+
+// TransferBuilder collects the transfers, inputs and remainder address of a
+// bundle of the seed. Its methods validate their arguments; the first error
+// is kept, later calls are ignored and Err and Build return it.
+//
+//	trs, inputs, remainder, err := NewTransferBuilder(seed).
+//		To(adr, 100).WithMessage("hello").WithTag("GIOTA").
+//		AddInput(0, SecurityLevelMedium).
+//		WithRemainder(change).
+//		Build()
+type TransferBuilder struct {
+	seed      Trytes
+	transfers []Transfer
+	inputs    []AddressInfo
+	remainder Address
+	err       error
+}
+
+// NewTransferBuilder returns a builder for transfers spending inputs of seed.
+func NewTransferBuilder(seed Trytes) *TransferBuilder {
+	return &TransferBuilder{seed: seed}
+}
+
+func (b *TransferBuilder) fail(fn, arg string, err error) *TransferBuilder {
+	b.err = &ValidationError{Func: fn, Arg: arg, Index: -1, Err: err}
+	return b
+}
+
+// To adds a transfer of value to the address adr, which may have a checksum.
+func (b *TransferBuilder) To(adr string, value int64) *TransferBuilder {
+	if b.err != nil {
+		return b
+	}
+
+	a, err := parseAddress(adr)
+	switch {
+	case err != nil:
+		return b.fail("To", "adr", err)
+	case value < 0 || value > MaxSupply:
+		return b.fail("To", "value", errors.New("value must be between 0 and the supply"))
+	}
+
+	b.transfers = append(b.transfers, Transfer{Address: a, Value: value})
+	return b
+}
+
+// WithMessage sets the message of the last transfer added by To. msg is
+// encoded by EncodeMessage.
+func (b *TransferBuilder) WithMessage(msg string) *TransferBuilder {
+	if b.err != nil {
+		return b
+	}
+	if len(b.transfers) == 0 {
+		return b.fail("WithMessage", "msg", errors.New("no transfer to add the message to"))
+	}
+
+	b.transfers[len(b.transfers)-1].Message = EncodeMessage([]byte(msg))
+	return b
+}
+
+// WithTag sets the tag of the last transfer added by To. tag must be at most
+// 27 trytes.
+func (b *TransferBuilder) WithTag(tag string) *TransferBuilder {
+	if b.err != nil {
+		return b
+	}
+
+	t := Trytes(tag)
+	switch {
+	case len(b.transfers) == 0:
+		return b.fail("WithTag", "tag", errors.New("no transfer to add the tag to"))
+	case len(t) > TagTrinarySize/3:
+		return b.fail("WithTag", "tag", errors.New("tag must be at most 27 trytes"))
+	case IsTrytes(t) != nil:
+		return b.fail("WithTag", "tag", IsTrytes(t))
+	}
+
+	b.transfers[len(b.transfers)-1].Tag = t
+	return b
+}
+
+// AddInput adds the address of the seed with index and security as input.
+// Without inputs, PrepareTransfers looks them up at the node.
+func (b *TransferBuilder) AddInput(index uint64, security SecurityLevel) *TransferBuilder {
+	if b.err != nil {
+		return b
+	}
+
+	if err := security.IsValid(); err != nil {
+		return b.fail("AddInput", "security", err)
+	}
+
+	b.inputs = append(b.inputs, AddressInfo{Seed: b.seed, Index: index, Security: security})
+	return b
+}
+
+// WithRemainder sets the address, which may have a checksum, receiving the
+// remainder of the inputs.
+func (b *TransferBuilder) WithRemainder(adr string) *TransferBuilder {
+	if b.err != nil {
+		return b
+	}
+
+	a, err := parseAddress(adr)
+	if err != nil {
+		return b.fail("WithRemainder", "adr", err)
+	}
+
+	b.remainder = a
+	return b
+}
+
+// Err returns the first error of the builder.
+func (b *TransferBuilder) Err() error {
+	return b.err
+}
+
+// Build returns the arguments of PrepareTransfers, or the first error of the
+// builder. The inputs are nil if AddInput wasn't called.
+func (b *TransferBuilder) Build() ([]Transfer, []AddressInfo, Address, error) {
+	switch {
+	case b.err != nil:
+		return nil, nil, "", b.err
+	case len(b.transfers) == 0:
+		return nil, nil, "", &ValidationError{Func: "Build", Arg: "transfers", Index: -1, Err: errors.New("no transfers")}
+	}
+
+	trs := make([]Transfer, len(b.transfers))
+	copy(trs, b.transfers)
+	var inputs []AddressInfo
+	if len(b.inputs) > 0 {
+		inputs = make([]AddressInfo, len(b.inputs))
+		copy(inputs, b.inputs)
+	}
+	return trs, inputs, b.remainder, nil
+}
+
+// Prepare builds the transfers and passes them to PrepareTransfers.
+func (b *TransferBuilder) Prepare(api APIClient, security SecurityLevel) (Bundle, error) {
+	trs, inputs, remainder, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+	return PrepareTransfers(api, b.seed, trs, inputs, remainder, security)
+}
+
+// parseAddress converts s to an address, verifying the checksum if s has
+// one.
+func parseAddress(s string) (Address, error) {
+	if len(s) == 90 {
+		return ValidateChecksummedString(s)
+	}
+	return Trytes(s).ToAddress()
+}
@@ -0,0 +1,99 @@
+package giota
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTransferBuilder(t *testing.T) {
+	adr := string(filterAddr1)
+	tests := []struct {
+		name  string
+		build func(b *TransferBuilder) *TransferBuilder
+		// errArg is the argument of the ValidationError, or "" if valid
+		errArg string
+	}{
+		{
+			name: "full",
+			build: func(b *TransferBuilder) *TransferBuilder {
+				return b.To(adr, 100).WithMessage("hi").WithTag("GIOTA").
+					To(string(filterAddr2.WithChecksum()), 0).
+					AddInput(3, SecurityLevelMedium).WithRemainder(adr)
+			},
+		},
+		{
+			name:   "no transfers",
+			build:  func(b *TransferBuilder) *TransferBuilder { return b.AddInput(0, SecurityLevelLow) },
+			errArg: "transfers",
+		},
+		{
+			name:   "bad address",
+			build:  func(b *TransferBuilder) *TransferBuilder { return b.To("ABC", 1) },
+			errArg: "adr",
+		},
+		{
+			name:   "bad checksum",
+			build:  func(b *TransferBuilder) *TransferBuilder { return b.To(adr+"999999999", 1) },
+			errArg: "adr",
+		},
+		{
+			name:   "negative value",
+			build:  func(b *TransferBuilder) *TransferBuilder { return b.To(adr, -1) },
+			errArg: "value",
+		},
+		{
+			name:   "message without transfer",
+			build:  func(b *TransferBuilder) *TransferBuilder { return b.WithMessage("hi") },
+			errArg: "msg",
+		},
+		{
+			name:   "long tag",
+			build:  func(b *TransferBuilder) *TransferBuilder { return b.To(adr, 1).WithTag("A234567890123456789012345678") },
+			errArg: "tag",
+		},
+		{
+			name:   "invalid tag",
+			build:  func(b *TransferBuilder) *TransferBuilder { return b.To(adr, 1).WithTag("abc") },
+			errArg: "tag",
+		},
+		{
+			name:   "bad security",
+			build:  func(b *TransferBuilder) *TransferBuilder { return b.To(adr, 1).AddInput(0, 4) },
+			errArg: "security",
+		},
+		{
+			name: "first error sticks",
+			build: func(b *TransferBuilder) *TransferBuilder {
+				return b.To(adr, -1).To("ABC", 1).WithRemainder(adr)
+			},
+			errArg: "value",
+		},
+	}
+
+	for _, tt := range tests {
+		b := tt.build(NewTransferBuilder(accountTestSeed))
+		trs, inputs, remainder, err := b.Build()
+
+		var verr *ValidationError
+		switch {
+		case tt.errArg == "" && err != nil:
+			t.Errorf("%s: unexpected error %s", tt.name, err)
+		case tt.errArg != "" && (!errors.As(err, &verr) || verr.Arg != tt.errArg):
+			t.Errorf("%s: expected error of %s, got %v", tt.name, tt.errArg, err)
+		}
+		if tt.errArg != "" {
+			continue
+		}
+
+		switch {
+		case len(trs) != 2 || trs[0].Address != filterAddr1 || trs[0].Value != 100 || trs[1].Address != filterAddr2:
+			t.Errorf("%s: unexpected transfers %+v", tt.name, trs)
+		case trs[0].Message != EncodeMessage([]byte("hi")) || trs[0].Tag != "GIOTA" || trs[1].Tag != "":
+			t.Errorf("%s: unexpected message or tag %+v", tt.name, trs)
+		case len(inputs) != 1 || inputs[0] != (AddressInfo{Seed: accountTestSeed, Index: 3, Security: SecurityLevelMedium}):
+			t.Errorf("%s: unexpected inputs %+v", tt.name, inputs)
+		case remainder != filterAddr1:
+			t.Errorf("%s: unexpected remainder %s", tt.name, remainder)
+		}
+	}
+}
@@ -24,8 +24,11 @@ SOFTWARE.
 package giota
 
 import (
+	"encoding/json"
+	"errors"
 	"os"
 	"testing"
+	"time"
 )
 
 var (
@@ -153,3 +156,365 @@ func TestTransfer2(t *testing.T) {
 		t.Log(tx.Trytes())
 	}
 }
+
+func TestPrepareTransfersSecurityLevels(t *testing.T) {
+	inputs := []AddressInfo{
+		{Seed: accountTestSeed, Index: 0, Security: SecurityLevelHigh},
+		{Seed: accountTestSeed, Index: 1, Security: SecurityLevelLow},
+	}
+
+	api, done := newFakeNode(t, map[string]fakeNodeHandler{
+		"getBalances": func(map[string]json.RawMessage) interface{} {
+			return map[string]interface{}{"balances": []string{"30", "50"}}
+		},
+	})
+	defer done()
+
+	trs := []Transfer{{Address: filterAddr1, Value: 70}}
+	bdl, err := PrepareTransfers(api, accountTestSeed, trs, inputs, filterAddr2, SecurityLevelMedium)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// output, 3 fragments of the first input, 1 of the second one, remainder
+	if len(bdl) != 6 {
+		t.Fatalf("PrepareTransfers() returned %d transactions, expected 6", len(bdl))
+	}
+	if err := bdl.IsValid(); err != nil {
+		t.Error(err)
+	}
+	if bdl[5].Address != filterAddr2 || bdl[5].Value != 10 {
+		t.Errorf("PrepareTransfers() added remainder %d to %s", bdl[5].Value, bdl[5].Address)
+	}
+}
+
+func TestPrepareTransfersWithReport(t *testing.T) {
+	adr0, _ := NewAddress(accountTestSeed, 0, SecurityLevelLow)
+	adr1, _ := NewAddress(accountTestSeed, 1, SecurityLevelLow)
+	adr2, _ := NewAddress(accountTestSeed, 2, SecurityLevelLow)
+	inputs := []AddressInfo{
+		{Seed: accountTestSeed, Index: 0, Security: SecurityLevelLow},
+		{Seed: accountTestSeed, Index: 1, Security: SecurityLevelLow},
+		{Seed: accountTestSeed, Index: 2, Security: SecurityLevelLow},
+	}
+
+	tests := []struct {
+		name      string
+		balances  []string
+		inputs    []AddressInfo
+		remainder Address
+		used      int
+		unused    int
+		change    int64
+		err       error
+		invalid   bool
+	}{
+		{name: "exact", balances: []string{"40", "30", "50"}, inputs: inputs, used: 2, unused: 1},
+		{name: "remainder", balances: []string{"40", "0", "50"}, inputs: inputs, remainder: filterAddr2, used: 2, change: 20},
+		{name: "remainder to input", balances: []string{"40", "50", "0"}, inputs: inputs, remainder: adr1, err: ErrSpentAddress},
+		{name: "remainder to unused input", balances: []string{"40", "50", "50"}, inputs: inputs, remainder: adr2, used: 2, unused: 1, change: 20},
+		{name: "not enough", balances: []string{"40", "20", "0"}, inputs: inputs, invalid: true},
+		{name: "no security", balances: []string{"40", "30"}, inputs: []AddressInfo{inputs[0], {Seed: accountTestSeed, Index: 1}}, invalid: true},
+	}
+
+	for _, tt := range tests {
+		api, done := newFakeNode(t, map[string]fakeNodeHandler{
+			"getBalances": func(map[string]json.RawMessage) interface{} {
+				return map[string]interface{}{"balances": tt.balances}
+			},
+		})
+		trs := []Transfer{{Address: filterAddr1, Value: 70}}
+		bdl, report, err := PrepareTransfersWithReport(api, accountTestSeed, trs, tt.inputs, tt.remainder, SecurityLevelLow, nil)
+		done()
+
+		switch {
+		case tt.err != nil && !errors.Is(err, tt.err):
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.err, err)
+		case tt.invalid && err == nil:
+			t.Errorf("%s: PrepareTransfersWithReport() accepted inputs %v", tt.name, tt.balances)
+		case tt.err != nil || tt.invalid:
+		case err != nil:
+			t.Errorf("%s: %s", tt.name, err)
+		case len(report.Inputs) != tt.used || len(report.Unused) != tt.unused:
+			t.Errorf("%s: reported %d used and %d unused inputs", tt.name, len(report.Inputs), len(report.Unused))
+		case report.RemainderValue != tt.change || (tt.change > 0 && report.Remainder != tt.remainder):
+			t.Errorf("%s: reported remainder %d to %s", tt.name, report.RemainderValue, report.Remainder)
+		case len(bdl) != 1+tt.used+map[bool]int{true: 1}[tt.change > 0]:
+			t.Errorf("%s: PrepareTransfersWithReport() returned %d transactions", tt.name, len(bdl))
+		case bdl[1].Address != adr0:
+			t.Errorf("%s: first input is %s", tt.name, bdl[1].Address)
+		}
+	}
+}
+
+func TestSendTrytesDoesNotMutate(t *testing.T) {
+	api, done := newFakeNode(t, map[string]fakeNodeHandler{
+		"getTransactionsToApprove": func(map[string]json.RawMessage) interface{} {
+			return &GetTransactionsToApproveResponse{TrunkTransaction: filterTestBundle()[0].Hash(), BranchTransaction: EmptyHash}
+		},
+		"broadcastTransactions": func(map[string]json.RawMessage) interface{} {
+			return struct{}{}
+		},
+		"storeTransactions": func(map[string]json.RawMessage) interface{} {
+			return struct{}{}
+		},
+	})
+	defer done()
+
+	bs := Bundle(filterTestBundle())
+	orig := bs.Clone()
+	pow := func(Trytes, int) (Trytes, error) { return "NONCE", nil }
+
+	res, err := SendTrytes(api, 3, bs, 1, pow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	attached := res.Transactions
+
+	for i := range bs {
+		if bs[i].Trytes() != orig[i].Trytes() {
+			t.Fatalf("SendTrytes() changed transaction %d of its input", i)
+		}
+	}
+
+	last := len(attached) - 1
+	switch {
+	case len(attached) != len(bs):
+		t.Fatalf("SendTrytes() returned %d transactions", len(attached))
+	case attached[last].TrunkTransaction != Hash(filterTestBundle()[0].Hash()):
+		t.Error("SendTrytes() didn't attach the last transaction to trunk")
+	case attached[0].TrunkTransaction != Hash(attached[1].Hash()):
+		t.Error("SendTrytes() didn't chain the transactions")
+	case res.Trunk != filterTestBundle()[0].Hash() || res.Branch != EmptyHash:
+		t.Errorf("SendTrytes() returned trunk %s and branch %s", res.Trunk, res.Branch)
+	case res.Tail != attached[0].Hash():
+		t.Errorf("SendTrytes() returned tail %s", res.Tail)
+	case len(res.AttachmentTimes) != len(attached) || res.AttachmentTimes[0].IsZero():
+		t.Errorf("SendTrytes() returned attachment times %v", res.AttachmentTimes)
+	case res.Attempts != 1 || res.PowDuration <= 0:
+		t.Errorf("SendTrytes() returned %d attempts and PoW duration %s", res.Attempts, res.PowDuration)
+	}
+}
+
+func TestSendTrytesMalformedTips(t *testing.T) {
+	api, done := newFakeNode(t, map[string]fakeNodeHandler{
+		"getTransactionsToApprove": func(map[string]json.RawMessage) interface{} {
+			return &GetTransactionsToApproveResponse{TrunkTransaction: "not a hash", BranchTransaction: EmptyHash}
+		},
+	})
+	defer done()
+
+	pow := func(Trytes, int) (Trytes, error) { return "NONCE", nil }
+	if _, err := SendTrytes(api, 3, filterTestBundle(), 1, pow); err == nil {
+		t.Error("SendTrytes() accepted malformed tips of the node")
+	}
+}
+
+func TestSendTrytesWithOptions(t *testing.T) {
+	tail := filterTestBundle()[0].Hash()
+
+	tests := []struct {
+		name       string
+		opts       *SendOptions
+		failures   int
+		failure    string
+		wantErr    bool
+		broadcasts int
+		references []Hash
+	}{
+		{
+			name:       "no failure",
+			broadcasts: 1,
+			references: []Hash{""},
+		},
+		{
+			name:       "inconsistent tips",
+			opts:       &SendOptions{Reference: tail},
+			failures:   1,
+			failure:    "inconsistent tips pair selected",
+			broadcasts: 2,
+			references: []Hash{tail, ""},
+		},
+		{
+			name:       "sticky reference",
+			opts:       &SendOptions{Reference: tail, Sticky: true},
+			failures:   2,
+			failure:    "tails are not consistent",
+			broadcasts: 3,
+			references: []Hash{tail, tail, tail},
+		},
+		{
+			name:       "too many failures",
+			opts:       &SendOptions{Attempts: 2},
+			failures:   3,
+			failure:    "inconsistent tips pair selected",
+			wantErr:    true,
+			broadcasts: 2,
+			references: []Hash{"", ""},
+		},
+		{
+			name:       "other failure",
+			failures:   1,
+			failure:    "invalid trytes",
+			wantErr:    true,
+			broadcasts: 1,
+			references: []Hash{""},
+		},
+	}
+
+	for _, tt := range tests {
+		var (
+			broadcasts int
+			references []Hash
+		)
+
+		api, done := newFakeNode(t, map[string]fakeNodeHandler{
+			"getTransactionsToApprove": func(req map[string]json.RawMessage) interface{} {
+				var ref Hash
+				json.Unmarshal(req["reference"], &ref)
+				references = append(references, ref)
+				return &GetTransactionsToApproveResponse{TrunkTransaction: tail, BranchTransaction: EmptyHash}
+			},
+			"broadcastTransactions": func(map[string]json.RawMessage) interface{} {
+				broadcasts++
+				if broadcasts <= tt.failures {
+					return &ErrorResponse{Error: tt.failure}
+				}
+				return struct{}{}
+			},
+			"storeTransactions": func(map[string]json.RawMessage) interface{} {
+				return struct{}{}
+			},
+		})
+
+		pow := func(Trytes, int) (Trytes, error) { return "NONCE", nil }
+		_, err := SendTrytesWithOptions(api, 3, filterTestBundle(), 1, pow, tt.opts)
+		done()
+
+		switch {
+		case (err != nil) != tt.wantErr:
+			t.Errorf("%s: SendTrytesWithOptions() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		case broadcasts != tt.broadcasts:
+			t.Errorf("%s: broadcasted %d times, expected %d", tt.name, broadcasts, tt.broadcasts)
+		case len(references) != len(tt.references):
+			t.Errorf("%s: selected tips with references %v, expected %v", tt.name, references, tt.references)
+		default:
+			for i := range references {
+				if references[i] != tt.references[i] {
+					t.Errorf("%s: selected tips with references %v, expected %v", tt.name, references, tt.references)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestPrepareTransfersWithClock(t *testing.T) {
+	trs := []Transfer{
+		{Address: filterAddr1, Message: "HELLO", Tag: "FOO"},
+		{Address: filterAddr2, Tag: "BAR"},
+	}
+	opts := &PrepareOptions{Clock: FixedClock(time.Unix(1500000000, 0))}
+
+	var hashes []Trytes
+	for i := 0; i < 2; i++ {
+		bd, err := PrepareTransfersWithOptions(nil, "", trs, nil, "", SecurityLevelMedium, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, tx := range bd {
+			if tx.Timestamp.Unix() != 1500000000 {
+				t.Errorf("transaction %d has timestamp %s", tx.CurrentIndex, tx.Timestamp)
+			}
+		}
+		hashes = append(hashes, Trytes(bd[0].Bundle))
+	}
+
+	if hashes[0] != hashes[1] {
+		t.Error("bundles prepared with a fixed clock have different hashes")
+	}
+}
+
+func TestSendTrytesDefaults(t *testing.T) {
+	var depth int64
+	api, done := newFakeNode(t, map[string]fakeNodeHandler{
+		"getTransactionsToApprove": func(req map[string]json.RawMessage) interface{} {
+			json.Unmarshal(req["depth"], &depth)
+			return &GetTransactionsToApproveResponse{TrunkTransaction: EmptyHash, BranchTransaction: EmptyHash}
+		},
+		"broadcastTransactions": func(map[string]json.RawMessage) interface{} {
+			return struct{}{}
+		},
+		"storeTransactions": func(map[string]json.RawMessage) interface{} {
+			return struct{}{}
+		},
+	})
+	defer done()
+
+	var mwm int
+	pow := func(_ Trytes, m int) (Trytes, error) {
+		mwm = m
+		return "NONCE", nil
+	}
+	if _, err := SendTrytes(api, 0, filterTestBundle(), 0, pow); err != nil {
+		t.Fatal(err)
+	}
+	if depth != DefaultDepth || mwm != DefaultMinWeightMagnitude {
+		t.Errorf("SendTrytes() used depth %d and MWM %d", depth, mwm)
+	}
+}
+
+func TestPromoteDepthPolicy(t *testing.T) {
+	tail := filterTestBundle()[:1]
+	tail[0].AttachmentTimestamp = Int2Trits(time.Now().Add(-5*time.Minute).UnixNano()/int64(time.Millisecond), TimestampTrinarySize).Trytes()
+
+	var sent bool
+	api, done := newFakeNode(t, map[string]fakeNodeHandler{
+		"getTrytes": func(map[string]json.RawMessage) interface{} {
+			return &GetTrytesResponse{Trytes: tail}
+		},
+		"checkConsistency": func(map[string]json.RawMessage) interface{} {
+			return &CheckConsistencyResponse{State: true}
+		},
+		"getTransactionsToApprove": func(map[string]json.RawMessage) interface{} {
+			return &GetTransactionsToApproveResponse{TrunkTransaction: EmptyHash, BranchTransaction: EmptyHash}
+		},
+		"attachToTangle": func(req map[string]json.RawMessage) interface{} {
+			sent = true
+			var txs []Transaction
+			json.Unmarshal(req["trytes"], &txs)
+			return &AttachToTangleResponse{Trytes: txs}
+		},
+		"broadcastTransactions": func(map[string]json.RawMessage) interface{} {
+			return struct{}{}
+		},
+		"storeTransactions": func(map[string]json.RawMessage) interface{} {
+			return struct{}{}
+		},
+	})
+	defer done()
+
+	// the tail is 5 minutes old, too old for a max depth of 3 milestones
+	// 10 seconds apart
+	n := Devnet
+	n.DepthPolicy = AttachmentDepthPolicy{MilestoneInterval: 10 * time.Second, MaxDepth: 3}
+	tests := []struct {
+		name string
+		opts *SendOptions
+		err  error
+	}{
+		{"default policy", nil, nil},
+		{"network policy", &SendOptions{Network: &n}, ErrReattachRequired},
+	}
+
+	for _, tt := range tests {
+		sent = false
+		_, err := PromoteWithOptions(api, tail[0].Hash(), 3, filterTestBundle(), 9, nil, tt.opts)
+		if err != tt.err {
+			t.Errorf("%s: PromoteWithOptions() returned %v, expected %v", tt.name, err, tt.err)
+		}
+		if sent != (tt.err == nil) {
+			t.Errorf("%s: PromoteWithOptions() attached %v", tt.name, sent)
+		}
+	}
+}
@@ -131,21 +131,36 @@ func (t Trits) TrailingZeros() int64 {
 	return z
 }
 
-// Trytes converts a slice of trits into trytes. panics if len(t)%3!=0
+// Trytes converts a slice of trits into trytes. It panics if len(t)%3!=0 or t
+// has invalid trits, use ToTrytes for trits which are not known to be valid.
 func (t Trits) Trytes() Trytes {
+	tr, err := t.ToTrytes()
+	if err != nil {
+		panic(err)
+	}
+	return tr
+}
+
+// ToTrytes converts a slice of trits into trytes like Trytes, but returns an
+// error instead of panicking if t can't be converted.
+func (t Trits) ToTrytes() (Trytes, error) {
 	if !t.CanTrytes() {
-		panic("length of trits must be a multiple of three")
+		return "", errors.New("length of trits must be a multiple of three")
 	}
 
 	o := make([]byte, len(t)/3)
 	for i := 0; i < len(t)/3; i++ {
+		if t[i*3:i*3+3].IsValid() != nil {
+			return "", fmt.Errorf("invalid number in trits at %d", i*3)
+		}
+
 		j := t[i*3] + t[i*3+1]*3 + t[i*3+2]*9
 		if j < 0 {
 			j += int8(len(TryteAlphabet))
 		}
 		o[i] = TryteAlphabet[j]
 	}
-	return Trytes(o)
+	return Trytes(o), nil
 }
 
 // constants regarding byte and trit lengths
@@ -339,19 +354,53 @@ func ToTrytes(t string) (Trytes, error) {
 	return tr, err
 }
 
-// Trits converts a slice of trytes into trits,
+// Trits converts a slice of trytes into trits. It panics if t has invalid
+// trytes, use ToTrits for trytes which are not known to be valid, e.g. the
+// ones of node responses.
 func (t Trytes) Trits() Trits {
+	trits, err := t.ToTrits()
+	if err != nil {
+		panic(err)
+	}
+	return trits
+}
+
+// ToTrits converts a slice of trytes into trits like Trits, but returns an
+// error instead of panicking if t has invalid trytes.
+func (t Trytes) ToTrits() (Trits, error) {
 	trits := make(Trits, len(t)*3)
 	for i := range t {
-		idx := strings.Index(TryteAlphabet, string(t[i:i+1]))
+		idx := strings.IndexByte(TryteAlphabet, t[i])
+		if idx < 0 {
+			return nil, fmt.Errorf("invalid character in trytes at %d", i)
+		}
 		copy(trits[i*3:i*3+3], tryteToTritsMappings[idx])
 	}
-	return trits
+	return trits, nil
 }
 
 // Normalize normalized bits into trits so that the sum of trits TODO: (and?) bits is zero.
-// nolint: gocyclo
+// It panics if t is not a valid hash, use ToNormalized for hashes which are not
+// known to be valid.
 func (t Trytes) Normalize() []int8 {
+	normalized, err := t.ToNormalized()
+	if err != nil {
+		panic(err)
+	}
+	return normalized
+}
+
+// ToNormalized normalizes the hash t like Normalize, but returns an error
+// instead of panicking if t is not a valid hash.
+// nolint: gocyclo
+func (t Trytes) ToNormalized() ([]int8, error) {
+	if len(t) < HashSize/3 {
+		return nil, fmt.Errorf("hash must be at least %d trytes", HashSize/3)
+	}
+	if err := t.IsValid(); err != nil {
+		return nil, err
+	}
+
 	normalized := make([]int8, len(t))
 	sum := 0
 	for i := 0; i < 3; i++ {
@@ -381,7 +430,31 @@ func (t Trytes) Normalize() []int8 {
 			}
 		}
 	}
-	return normalized
+	return normalized, nil
+}
+
+// NormalizedFragmentSize is the number of trytes of a normalized hash which
+// one key fragment signs.
+const NormalizedFragmentSize = 27
+
+// NormalizedFragment returns the part of the normalized hash which the i-th
+// fragment of a key signs. A key of security level s has s fragments, which
+// sign the first s parts of the hash.
+func NormalizedFragment(normalized []int8, i int) []int8 {
+	start := NormalizedFragmentSize * (i % 3)
+	return normalized[start : start+NormalizedFragmentSize]
+}
+
+// HasM reports whether the normalized hash contains 13, an M. A signature
+// of such a hash reveals a whole key fragment, so nodes reject bundles with
+// inputs whose normalized hash has an M.
+func HasM(normalized []int8) bool {
+	for _, n := range normalized {
+		if n == 13 {
+			return true
+		}
+	}
+	return false
 }
 
 // IsValidTryte returns the validity of a tryte( must be rune A-Z or 9 )
@@ -402,14 +475,106 @@ func (t Trytes) IsValid() error {
 	return nil
 }
 
-func incTrits(t Trits) {
+// Trits are numbers in balanced ternary with the least significant trit
+// first, like in Int and Int2Trits. The arithmetic funcs below work on trits
+// of any length; missing trits of shorter operands are 0.
+
+// IncTrits increments t in place and reports whether it overflowed. An
+// overflow wraps around to the smallest value of the length of t, like the
+// tag increments of bundle hashing.
+func IncTrits(t Trits) bool {
 	for j := range t {
 		t[j]++
 
 		if t[j] <= 1 {
-			break
+			return false
 		}
 
 		t[j] = -1
 	}
+	return true
+}
+
+// AddTrits returns the sum of a and b with the length of the longer one and
+// the carry out of its most significant trit, which is -1, 0 or 1.
+func AddTrits(a, b Trits) (Trits, int8) {
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+
+	sum := make(Trits, len(a))
+	var carry int8
+	for j := range a {
+		s := a[j] + carry
+		if j < len(b) {
+			s += b[j]
+		}
+		sum[j], carry = addTrit(s)
+	}
+	return sum, carry
+}
+
+// addTrit returns the trit and carry of the sum s of at most three trits.
+func addTrit(s int8) (int8, int8) {
+	switch {
+	case s > 1:
+		return s - 3, 1
+	case s < -1:
+		return s + 3, -1
+	}
+	return s, 0
+}
+
+// NegateTrits returns -t.
+func NegateTrits(t Trits) Trits {
+	n := make(Trits, len(t))
+	for j := range t {
+		n[j] = -t[j]
+	}
+	return n
+}
+
+// CompareTrits returns -1 if a < b, 0 if a == b and 1 if a > b.
+func CompareTrits(a, b Trits) int {
+	l := len(a)
+	if len(b) > l {
+		l = len(b)
+	}
+
+	for j := l - 1; j >= 0; j-- {
+		var x, y int8
+		if j < len(a) {
+			x = a[j]
+		}
+		if j < len(b) {
+			y = b[j]
+		}
+
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	return 0
+}
+
+// addTrits adds n to t in place. Like IncTrits, an overflow wraps around.
+func addTrits(t Trits, n uint64) {
+	var carry int8
+	for j := range t {
+		if n == 0 && carry == 0 {
+			break
+		}
+
+		d := int8(n % 3)
+		n /= 3
+		if d == 2 {
+			d = -1
+			n++
+		}
+
+		t[j], carry = addTrit(t[j] + d + carry)
+	}
 }
@@ -0,0 +1,95 @@
+package giota
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// TryteReader reads newline separated trytes, e.g. a dump of transaction
+// trytes, from an underlying io.Reader one line at a time.
+type TryteReader struct {
+	s    *bufio.Scanner
+	line int
+}
+
+// NewTryteReader returns a TryteReader reading from r.
+func NewTryteReader(r io.Reader) *TryteReader {
+	return &TryteReader{
+		s: bufio.NewScanner(r),
+	}
+}
+
+// ReadTrytes returns the next non-empty line as validated Trytes.
+// It returns io.EOF when there are no more lines.
+func (r *TryteReader) ReadTrytes() (Trytes, error) {
+	for r.s.Scan() {
+		r.line++
+
+		l := strings.TrimSpace(r.s.Text())
+		if l == "" {
+			continue
+		}
+
+		t, err := ToTrytes(l)
+		if err != nil {
+			return "", fmt.Errorf("line %d: %s", r.line, err)
+		}
+		return t, nil
+	}
+
+	if err := r.s.Err(); err != nil {
+		return "", err
+	}
+	return "", io.EOF
+}
+
+// ReadTransaction reads the next line and parses it as transaction trytes.
+func (r *TryteReader) ReadTransaction() (*Transaction, error) {
+	t, err := r.ReadTrytes()
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := NewTransaction(t)
+	if err != nil {
+		return nil, fmt.Errorf("line %d: %s", r.line, err)
+	}
+	return tx, nil
+}
+
+// TryteWriter writes trytes as newline separated lines to an underlying io.Writer.
+// Flush must be called after the last write.
+type TryteWriter struct {
+	w *bufio.Writer
+}
+
+// NewTryteWriter returns a TryteWriter writing to w.
+func NewTryteWriter(w io.Writer) *TryteWriter {
+	return &TryteWriter{
+		w: bufio.NewWriter(w),
+	}
+}
+
+// WriteTrytes validates t and writes it as a single line.
+func (w *TryteWriter) WriteTrytes(t Trytes) error {
+	if err := t.IsValid(); err != nil {
+		return err
+	}
+
+	if _, err := w.w.WriteString(string(t)); err != nil {
+		return err
+	}
+	return w.w.WriteByte('\n')
+}
+
+// WriteTransaction writes the trytes of tx as a single line.
+func (w *TryteWriter) WriteTransaction(tx *Transaction) error {
+	return w.WriteTrytes(tx.Trytes())
+}
+
+// Flush writes any buffered data to the underlying io.Writer.
+func (w *TryteWriter) Flush() error {
+	return w.w.Flush()
+}
@@ -0,0 +1,68 @@
+package giota
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTryteReaderWriter(t *testing.T) {
+	var bs Bundle
+	bs.Add(1, "PQTDJXXKSNYZGRJDXEHHMNCLUVOIRZC9VXYLSITYMVCQDQERAHAUZJKRNBQEUHOLEAXRUSQBNYVJWESYR", 0, time.Unix(1500000000, 0), "")
+	bs.Add(1, "KTXFP9XOVMVWIXEWMOISJHMQEXMYMZCUGEQNKGUNVRPUDPRX9IR9LBASIARWNFXXESPITSLYAQMLCLVTL", 0, time.Unix(1500000000, 0), "")
+	bs.Finalize(nil)
+
+	var buf bytes.Buffer
+	w := NewTryteWriter(&buf)
+	for i := range bs {
+		if err := w.WriteTransaction(&bs[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewTryteReader(&buf)
+	for i := range bs {
+		tx, err := r.ReadTransaction()
+		if err != nil {
+			t.Fatalf("ReadTransaction() #%d failed: %s", i, err)
+		}
+		if tx.Trytes() != bs[i].Trytes() {
+			t.Errorf("ReadTransaction() #%d returned a different transaction", i)
+		}
+	}
+
+	if _, err := r.ReadTransaction(); err != io.EOF {
+		t.Errorf("ReadTransaction() expected io.EOF but got %v", err)
+	}
+}
+
+func TestTryteReaderInvalid(t *testing.T) {
+	r := NewTryteReader(strings.NewReader("ABC\n\n  DEF9  \nabc\n"))
+
+	for _, want := range []Trytes{"ABC", "DEF9"} {
+		tr, err := r.ReadTrytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tr != want {
+			t.Errorf("ReadTrytes() returned %q, expected %q", tr, want)
+		}
+	}
+
+	_, err := r.ReadTrytes()
+	if err == nil || !strings.HasPrefix(err.Error(), "line 4") {
+		t.Errorf("ReadTrytes() expected an error for line 4 but got %v", err)
+	}
+}
+
+func TestTryteWriterInvalid(t *testing.T) {
+	w := NewTryteWriter(&bytes.Buffer{})
+	if err := w.WriteTrytes("abc"); err == nil {
+		t.Error("WriteTrytes() should fail for invalid trytes")
+	}
+}
@@ -26,6 +26,7 @@ package giota
 
 import (
 	"bytes"
+	"strings"
 	"testing"
 )
 
@@ -220,3 +221,154 @@ func TestNormalize(t *testing.T) {
 		}
 	}
 }
+
+func TestCheckedConversions(t *testing.T) {
+	tests := []struct {
+		name string
+		f    func() error
+		ok   bool
+	}{
+		{"ToTrits", func() error { _, err := Trytes("ABC9").ToTrits(); return err }, true},
+		{"ToTrits invalid", func() error { _, err := Trytes("AbC").ToTrits(); return err }, false},
+		{"ToTrytes", func() error { _, err := Trits{1, 0, -1}.ToTrytes(); return err }, true},
+		{"ToTrytes length", func() error { _, err := Trits{1, 0}.ToTrytes(); return err }, false},
+		{"ToTrytes invalid", func() error { _, err := Trits{1, 0, 2}.ToTrytes(); return err }, false},
+		{"ToNormalized", func() error { _, err := Trytes(strings.Repeat("A", 81)).ToNormalized(); return err }, true},
+		{"ToNormalized short", func() error { _, err := Trytes("ABC").ToNormalized(); return err }, false},
+		{"ToNormalized invalid", func() error { _, err := Trytes(strings.Repeat("a", 81)).ToNormalized(); return err }, false},
+		{"PowGo short", func() error { _, err := PowGo("ABC", 1); return err }, false},
+		{"PowGo invalid", func() error { _, err := PowGo(Trytes(strings.Repeat("a", TransactionTrinarySize/3)), 1); return err }, false},
+	}
+
+	for _, tt := range tests {
+		if err := tt.f(); (err == nil) != tt.ok {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		}
+	}
+}
+
+func TestIsValidSigMalformed(t *testing.T) {
+	adr := Address(strings.Repeat("A", 81))
+	tests := []struct {
+		name  string
+		frags []Trytes
+		hash  Trytes
+	}{
+		{"short hash", []Trytes{Trytes(strings.Repeat("A", keyFragmentSize))}, "ABC"},
+		{"invalid hash", []Trytes{Trytes(strings.Repeat("A", keyFragmentSize))}, Trytes(strings.Repeat("!", 81))},
+		{"short fragment", []Trytes{"ABC"}, Trytes(strings.Repeat("A", 81))},
+		{"invalid fragment", []Trytes{Trytes(strings.Repeat("a", keyFragmentSize))}, Trytes(strings.Repeat("A", 81))},
+	}
+
+	for _, tt := range tests {
+		if IsValidSig(adr, tt.frags, tt.hash) {
+			t.Errorf("%s: malformed signature is valid", tt.name)
+		}
+	}
+}
+
+func TestAddTrits(t *testing.T) {
+	start := Trytes("NOPQ99ABC").Trits()
+
+	exp := make(Trits, len(start))
+	copy(exp, start)
+	for n := uint64(0); n < 500; n++ {
+		got := make(Trits, len(start))
+		copy(got, start)
+		addTrits(got, n)
+
+		if got.Trytes() != exp.Trytes() {
+			t.Fatalf("addTrits(%d) = %s, expected %s", n, got.Trytes(), exp.Trytes())
+		}
+		IncTrits(exp)
+	}
+
+	large := make(Trits, HashSize)
+	addTrits(large, 1<<62)
+	if v := large.Int(); v != 1<<62 {
+		t.Errorf("addTrits(1<<62) = %d", v)
+	}
+
+	twice := make(Trits, HashSize)
+	copy(twice, large)
+	addTrits(twice, 1<<62)
+	once := make(Trits, HashSize)
+	addTrits(once, 1<<63)
+	if twice.Trytes() != once.Trytes() {
+		t.Error("addTrits() of values above 2^63 is inconsistent")
+	}
+}
+
+func TestTritsArithmetic(t *testing.T) {
+	// the values of 3 trits are -13 to 13
+	const size = 3
+	for x := int64(-13); x <= 13; x++ {
+		a := Int2Trits(x, size)
+
+		if n := NegateTrits(a); n.Int() != -x {
+			t.Errorf("NegateTrits(%d) = %d", x, n.Int())
+		}
+
+		inc := Int2Trits(x, size)
+		overflow := IncTrits(inc)
+		switch {
+		case x == 13 && (!overflow || inc.Int() != -13):
+			t.Errorf("IncTrits(13) = %d, %v, expected an overflow to -13", inc.Int(), overflow)
+		case x < 13 && (overflow || inc.Int() != x+1):
+			t.Errorf("IncTrits(%d) = %d, %v", x, inc.Int(), overflow)
+		}
+
+		for y := int64(-13); y <= 13; y++ {
+			b := Int2Trits(y, size)
+
+			sum, carry := AddTrits(a, b)
+			if got := sum.Int() + int64(carry)*27; len(sum) != size || got != x+y {
+				t.Errorf("AddTrits(%d, %d) = %d with carry %d", x, y, sum.Int(), carry)
+			}
+
+			want := 0
+			switch {
+			case x < y:
+				want = -1
+			case x > y:
+				want = 1
+			}
+			if c := CompareTrits(a, b); c != want {
+				t.Errorf("CompareTrits(%d, %d) = %d", x, y, c)
+			}
+		}
+	}
+
+	// operands of different lengths
+	long := Int2Trits(100, 9)
+	short := Int2Trits(-4, 2)
+	if sum, carry := AddTrits(short, long); len(sum) != 9 || carry != 0 || sum.Int() != 96 {
+		t.Errorf("AddTrits(-4, 100) = %d with carry %d", sum.Int(), carry)
+	}
+	if CompareTrits(short, long) != -1 || CompareTrits(long, short) != 1 || CompareTrits(Trits{1, 0, 0}, Trits{1}) != 0 {
+		t.Error("CompareTrits() of different lengths is wrong")
+	}
+}
+
+func TestNormalizedFragment(t *testing.T) {
+	h := Trytes("QYTRDVKPBDNQUIBAAZMMIKRBOIKZ9NRV9BAMWJEJDWBWEUKNQDRLSNMIWFHHGQZNFVCCOQOJRNHANVAMZ")
+	n := h.Normalize()
+
+	for i := 0; i < 4; i++ {
+		f := NormalizedFragment(n, i)
+		sum := 0
+		for _, v := range f {
+			sum += int(v)
+		}
+		if len(f) != NormalizedFragmentSize || sum != 0 {
+			t.Errorf("fragment %d has %d trytes summing up to %d", i, len(f), sum)
+		}
+		if f[0] != n[(i%3)*NormalizedFragmentSize] {
+			t.Errorf("fragment %d doesn't start at %d", i, (i%3)*NormalizedFragmentSize)
+		}
+	}
+
+	if HasM([]int8{0, -13, 12}) || !HasM([]int8{1, 13}) {
+		t.Error("HasM() is wrong")
+	}
+}
@@ -0,0 +1,49 @@
+package giota
+
+import (
+	"io"
+	"net/http"
+)
+
+// Version is the version of giota.
+const Version = "0.4.0"
+
+// DefaultUserAgent is the User-Agent of requests to nodes if no application
+// is set by SetUserAgent.
+const DefaultUserAgent = "giota/" + Version
+
+// SetUserAgent identifies the application app to nodes by sending the
+// User-Agent "<app> giota/<Version>" with all requests, e.g. "mywallet/1.2".
+// An empty app restores DefaultUserAgent. SetUserAgent must not be called
+// concurrently with API calls.
+func (api *API) SetUserAgent(app string) {
+	api.userAgent = ""
+	if app != "" {
+		api.userAgent = app + " " + DefaultUserAgent
+	}
+}
+
+// UserAgent returns the User-Agent sent to nodes.
+func (api *API) UserAgent() string {
+	if api.userAgent == "" {
+		return DefaultUserAgent
+	}
+	return api.userAgent
+}
+
+// newRequest returns a request to the node with the headers of the API,
+// including the ones of WithHeaders.
+func (api *API) newRequest(method, url string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", api.UserAgent())
+	for k, vs := range api.headers {
+		req.Header.Del(k)
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	return req, nil
+}
@@ -0,0 +1,36 @@
+package giota
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSetUserAgent(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("User-Agent")
+		w.Write([]byte(`{"appName":"IRI"}`))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		app  string
+		want string
+	}{
+		{"", "giota/" + Version},
+		{"mywallet/1.2", "mywallet/1.2 giota/" + Version},
+		{"", DefaultUserAgent},
+	}
+
+	api := NewAPI(srv.URL, nil)
+	for _, tt := range tests {
+		api.SetUserAgent(tt.app)
+		if _, err := api.GetNodeInfo(); err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want || api.UserAgent() != tt.want {
+			t.Errorf("User-Agent of %q is %q, want %q", tt.app, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package giota
+
+import (
+	"errors"
+	"fmt"
+)
+
+// InvalidTryteError is returned by the validators if a value contains a
+// character which is not a tryte.
+type InvalidTryteError struct {
+	Index int
+	Char  byte
+}
+
+func (e *InvalidTryteError) Error() string {
+	return fmt.Sprintf("invalid character %q at index %d of trytes", e.Char, e.Index)
+}
+
+// TrytesLengthError is returned by the validators if a value doesn't have the
+// expected number of trytes.
+type TrytesLengthError struct {
+	Length   int
+	Expected int
+}
+
+func (e *TrytesLengthError) Error() string {
+	return fmt.Sprintf("trytes must be %d in length, not %d", e.Expected, e.Length)
+}
+
+// IsTrytes returns nil if t only contains the characters A-Z and 9. It doesn't
+// allocate unless t is invalid.
+func IsTrytes(t Trytes) error {
+	for i := 0; i < len(t); i++ {
+		if c := t[i]; (c < 'A' || c > 'Z') && c != '9' {
+			return &InvalidTryteError{Index: i, Char: c}
+		}
+	}
+	return nil
+}
+
+// IsHash returns nil if t is a valid transaction or bundle hash of 81 trytes.
+func IsHash(t Trytes) error {
+	return isTrytesOfLength(t, HashSize/3)
+}
+
+// IsTransactionTrytes returns nil if t is the valid trytes of a transaction.
+// It doesn't check the content of the transaction.
+func IsTransactionTrytes(t Trytes) error {
+	return isTrytesOfLength(t, TransactionTrinarySize/3)
+}
+
+func isTrytesOfLength(t Trytes, n int) error {
+	if len(t) != n {
+		return &TrytesLengthError{Length: len(t), Expected: n}
+	}
+	return IsTrytes(t)
+}
+
+// Validation configures the client-side validation of the arguments of API
+// calls, which rejects malformed arguments before a node is called.
+type Validation struct {
+	// Disabled turns the validation off, arguments are passed to the node as
+	// they are.
+	Disabled bool
+	// MinWeightMagnitude is the lowest MWM accepted, usually the one of the
+	// NetworkProfile. If zero, any positive MWM is accepted.
+	MinWeightMagnitude int64
+}
+
+// SetValidation configures the validation of arguments. It is enabled by
+// default. SetValidation must not be called concurrently with API calls.
+func (api *API) SetValidation(v Validation) {
+	api.validation = v
+}
+
+// ValidationError is returned by API calls if an argument is rejected by
+// the client-side validation.
+type ValidationError struct {
+	// Func is the name of the called func.
+	Func string
+	// Arg is the name of the offending argument.
+	Arg string
+	// Index is the index of the offending element if Arg is a slice, or -1.
+	Index int
+	Err   error
+}
+
+func (e *ValidationError) Error() string {
+	arg := e.Arg
+	if e.Index >= 0 {
+		arg = fmt.Sprintf("%s[%d]", e.Arg, e.Index)
+	}
+	return fmt.Sprintf("%s: invalid %s: %s", e.Func, arg, e.Err)
+}
+
+// Unwrap returns the reason of the rejection.
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+func (api *API) validating() bool {
+	return api != nil && !api.validation.Disabled
+}
+
+func (api *API) validateHashes(fn, arg string, hashes []Hash) error {
+	if !api.validating() {
+		return nil
+	}
+	for i, h := range hashes {
+		if err := h.IsValid(); err != nil {
+			return &ValidationError{Func: fn, Arg: arg, Index: i, Err: err}
+		}
+	}
+	return nil
+}
+
+func (api *API) validateHash(fn, arg string, hash Hash) error {
+	if !api.validating() {
+		return nil
+	}
+	if err := hash.IsValid(); err != nil {
+		return &ValidationError{Func: fn, Arg: arg, Index: -1, Err: err}
+	}
+	return nil
+}
+
+func (api *API) validateDepth(fn string, depth int64) error {
+	if api.validating() && depth <= 0 {
+		return &ValidationError{Func: fn, Arg: "depth", Index: -1, Err: errors.New("depth must be positive")}
+	}
+	return nil
+}
+
+func (api *API) validateMWM(fn string, mwm int64) error {
+	if !api.validating() {
+		return nil
+	}
+
+	min := api.validation.MinWeightMagnitude
+	if min <= 0 {
+		min = 1
+	}
+	if mwm < min {
+		return &ValidationError{Func: fn, Arg: "mwm", Index: -1, Err: fmt.Errorf("mwm must be at least %d", min)}
+	}
+	return nil
+}
+
+func (api *API) validateSecurity(fn string, security SecurityLevel) error {
+	if !api.validating() {
+		return nil
+	}
+	if err := security.IsValid(); err != nil {
+		return &ValidationError{Func: fn, Arg: "security", Index: -1, Err: err}
+	}
+	return nil
+}
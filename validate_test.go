@@ -0,0 +1,135 @@
+package giota
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestIsTrytes(t *testing.T) {
+	tx := Trytes(strings.Repeat("9", TransactionTrinarySize/3))
+
+	tests := []struct {
+		name  string
+		valid func(Trytes) error
+		in    Trytes
+		index int
+		char  byte
+		len   int
+	}{
+		{name: "trytes", valid: IsTrytes, in: "ABCXYZ9", index: -1},
+		{name: "empty trytes", valid: IsTrytes, in: "", index: -1},
+		{name: "lower case", valid: IsTrytes, in: "ABc", index: 2, char: 'c'},
+		{name: "digit", valid: IsTrytes, in: "1AB", index: 0, char: '1'},
+		{name: "hash", valid: IsHash, in: EmptyHash, index: -1},
+		{name: "short hash", valid: IsHash, in: Trytes(EmptyHash[1:]), index: -1, len: 80},
+		{name: "hash with checksum", valid: IsHash, in: EmptyHash + "999999999", index: -1, len: 90},
+		{name: "invalid hash", valid: IsHash, in: Trytes("A-" + EmptyHash[2:]), index: 1, char: '-'},
+		{name: "transaction", valid: IsTransactionTrytes, in: tx, index: -1},
+		{name: "short transaction", valid: IsTransactionTrytes, in: tx[1:], index: -1, len: len(tx) - 1},
+	}
+
+	for _, tt := range tests {
+		err := tt.valid(tt.in)
+		switch e := err.(type) {
+		case nil:
+			if tt.index >= 0 || tt.len != 0 {
+				t.Errorf("%s: should be invalid", tt.name)
+			}
+		case *InvalidTryteError:
+			if e.Index != tt.index || e.Char != tt.char {
+				t.Errorf("%s: returned %s", tt.name, err)
+			}
+		case *TrytesLengthError:
+			if e.Length != tt.len {
+				t.Errorf("%s: returned %s", tt.name, err)
+			}
+		default:
+			t.Errorf("%s: returned unexpected error %s", tt.name, err)
+		}
+	}
+}
+
+func TestAPIValidation(t *testing.T) {
+	api, done := newFakeNode(t, map[string]fakeNodeHandler{
+		"getTrytes": func(map[string]json.RawMessage) interface{} {
+			// the placeholder of an unknown transaction
+			return map[string][]Trytes{"trytes": {Trytes(strings.Repeat("9", TransactionTrinarySize/3))}}
+		},
+	})
+	defer done()
+
+	tests := []struct {
+		name  string
+		call  func() error
+		fn    string
+		arg   string
+		index int
+	}{
+		{
+			name: "GetTrytes",
+			call: func() error {
+				_, err := api.GetTrytes([]Hash{EmptyHash, "ABC"})
+				return err
+			},
+			fn: "GetTrytes", arg: "hashes", index: 1,
+		},
+		{
+			name: "CheckConsistency",
+			call: func() error {
+				_, err := api.CheckConsistency([]Hash{Hash("a" + EmptyHash[1:])})
+				return err
+			},
+			fn: "CheckConsistency", arg: "tails", index: 0,
+		},
+		{
+			name: "depth",
+			call: func() error {
+				_, err := api.GetTransactionsToApprove(0, 0, "")
+				return err
+			},
+			fn: "GetTransactionsToApprove", arg: "depth", index: -1,
+		},
+		{
+			name: "mwm",
+			call: func() error {
+				_, err := SendTrytes(api, 3, filterTestBundle(), 8, nil)
+				return err
+			},
+			fn: "SendTrytes", arg: "mwm", index: -1,
+		},
+		{
+			name: "security",
+			call: func() error {
+				_, _, err := GetUsedAddress(api, accountTestSeed, 4)
+				return err
+			},
+			fn: "GetUsedAddress", arg: "security", index: -1,
+		},
+	}
+
+	api.SetValidation(Validation{MinWeightMagnitude: Devnet.MinWeightMagnitude})
+	for _, tt := range tests {
+		err, ok := tt.call().(*ValidationError)
+		switch {
+		case !ok:
+			t.Errorf("%s: returned no ValidationError", tt.name)
+		case err.Func != tt.fn || err.Arg != tt.arg || err.Index != tt.index:
+			t.Errorf("%s: returned %s", tt.name, err)
+		}
+	}
+
+	api.SetValidation(Validation{Disabled: true})
+	if _, err := api.GetTrytes([]Hash{"ABC"}); err != nil {
+		t.Errorf("GetTrytes() without validation returned %s", err)
+	}
+}
+
+func BenchmarkIsHash(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := IsHash(EmptyHash); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
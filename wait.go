@@ -0,0 +1,151 @@
+package giota
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrReattachRequired is returned by WaitForConfirmationWithOptions and
+// Promote if the tail can't be confirmed anymore, see AdviceReattach.
+var ErrReattachRequired = errors.New("tail must be reattached")
+
+// promotionTag is the tag of the zero-value transactions promoting tails.
+const promotionTag = "PROMOTESPAM"
+
+// WaitOptions are options of WaitForConfirmationWithOptions. The zero value
+// waits without promoting.
+type WaitOptions struct {
+	// Promote enables promoting the tail whenever EstimateConfirmation
+	// advises it with Params. If Params is nil, DefaultResendParams is used
+	// with the depth policy and MWM of Network.
+	Promote bool
+	Params  *ResendParams
+	// Depth and MWM are passed to Promote. If they are zero, those of
+	// Network are used.
+	Depth int64
+	MWM   int64
+	// Network is the network of the tail, e.g. Devnet. If nil, the
+	// defaults of the mainnet are used.
+	Network *NetworkProfile
+	// Pow is the PoW func of the promotions. If nil, the PoW is done by
+	// GetBestPoW unless RemotePoW is set, which lets the node do it.
+	Pow       PowFunc
+	RemotePoW bool
+}
+
+// WaitForConfirmation checks every poll, DefaultConfirmationPoll if zero,
+// whether tail is confirmed by the latest solid milestone. It returns the
+// index of the confirming milestone, or the error of ctx if ctx is done
+// first.
+func (api *API) WaitForConfirmation(ctx context.Context, tail Hash, poll time.Duration) (int64, error) {
+	return api.WaitForConfirmationWithOptions(ctx, tail, poll, nil)
+}
+
+// WaitForConfirmationWithOptions is like WaitForConfirmation, but may
+// promote the tail while waiting. opts may be nil.
+//
+// The confirming milestone is exact if the tail was seen unconfirmed by the
+// previous milestone. Otherwise it is searched among the older milestones,
+// which requires the coordinator, see SetCoordinator; without it the index
+// of the latest solid milestone is returned.
+func (api *API) WaitForConfirmationWithOptions(ctx context.Context, tail Hash, poll time.Duration, opts *WaitOptions) (int64, error) {
+	if err := api.validateHash("WaitForConfirmation", "tail", tail); err != nil {
+		return 0, err
+	}
+	if opts == nil {
+		opts = &WaitOptions{}
+	}
+	if poll <= 0 {
+		poll = DefaultConfirmationPoll
+	}
+
+	// lo is the latest milestone not confirming tail
+	var lo int64
+	t := time.NewTimer(0)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-t.C:
+		}
+
+		ni, err := api.GetNodeInfo()
+		if err != nil {
+			return 0, err
+		}
+		inc, err := api.GetInclusionStates([]Hash{tail}, []Hash{ni.LatestSolidSubtangleMilestone})
+		if err != nil {
+			return 0, err
+		}
+
+		hi := ni.LatestSolidSubtangleMilestoneIndex
+		if len(inc.States) > 0 && inc.States[0] {
+			return api.confirmingMilestone(tail, lo, hi)
+		}
+		lo = hi
+
+		if opts.Promote {
+			if err := api.promoteIfAdvised(tail, opts); err != nil {
+				return 0, err
+			}
+		}
+		t.Reset(poll)
+	}
+}
+
+// confirmingMilestone returns the index of the first milestone after lo
+// confirming tail, which is confirmed by the milestone hi.
+func (api *API) confirmingMilestone(tail Hash, lo, hi int64) (int64, error) {
+	for hi-lo > 1 {
+		mid := lo + (hi-lo)/2
+		inc, err := api.GetInclusionStatesSince([]Hash{tail}, mid)
+		switch {
+		case errors.Is(err, ErrNoCoordinator):
+			return hi, nil
+		case errors.Is(err, ErrMilestoneNotFound):
+			// pruned by the node, so it is older than the tail
+			lo = mid
+		case err != nil:
+			return 0, err
+		case len(inc.States) > 0 && inc.States[0]:
+			hi = mid
+		default:
+			lo = mid
+		}
+	}
+	return hi, nil
+}
+
+// promoteIfAdvised promotes tail if EstimateConfirmation advises it.
+func (api *API) promoteIfAdvised(tail Hash, opts *WaitOptions) error {
+	params := opts.Params
+	if params == nil {
+		params = opts.Network.resendParams()
+	}
+	est, err := api.EstimateConfirmation(tail, params)
+	if err != nil {
+		return err
+	}
+
+	switch est.Advice {
+	case AdviceReattach:
+		return ErrReattachRequired
+	case AdvicePromote:
+	default:
+		return nil
+	}
+
+	bd, err := NewTransferBuilder("").To(EmptyHash, 0).WithTag(promotionTag).Prepare(api, SecurityLevelMedium)
+	if err != nil {
+		return err
+	}
+
+	pow := opts.Pow
+	if pow == nil && !opts.RemotePoW {
+		_, pow = GetBestPoW()
+	}
+	_, err = PromoteWithOptions(api, tail, opts.Depth, []Transaction(bd), opts.MWM, pow, &SendOptions{Network: opts.Network})
+	return err
+}
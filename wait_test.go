@@ -0,0 +1,72 @@
+package giota
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newWaitNode returns an API whose latest solid milestone advances from
+// start by one per getNodeInfo, and which confirms all transactions from
+// the milestone confirmedAt on.
+func newWaitNode(t *testing.T, start, confirmedAt int64) (*API, func()) {
+	index := start - 1
+	milestone := func(i int64) Hash {
+		return Hash(Int2Trits(i, HashSize).Trytes())
+	}
+
+	return newFakeNode(t, map[string]fakeNodeHandler{
+		"getNodeInfo": func(map[string]json.RawMessage) interface{} {
+			i := atomic.AddInt64(&index, 1)
+			return &GetNodeInfoResponse{
+				LatestMilestone:                    milestone(i),
+				LatestMilestoneIndex:               i,
+				LatestSolidSubtangleMilestone:      milestone(i),
+				LatestSolidSubtangleMilestoneIndex: i,
+			}
+		},
+		"getInclusionStates": func(req map[string]json.RawMessage) interface{} {
+			var tips []Trytes
+			json.Unmarshal(req["tips"], &tips)
+			confirmed := len(tips) == 1 && tips[0].Trits().Int() >= confirmedAt
+			return &GetInclusionStatesResponse{States: []bool{confirmed}}
+		},
+	})
+}
+
+func TestWaitForConfirmation(t *testing.T) {
+	tail := filterTestBundle()[0].Hash()
+
+	tests := []struct {
+		name         string
+		start        int64
+		confirmedAt  int64
+		timeout      time.Duration
+		wantIndex    int64
+		wantDeadline bool
+	}{
+		{name: "confirmed while waiting", start: 3, confirmedAt: 5, timeout: 5 * time.Second, wantIndex: 5},
+		{name: "confirmed before waiting", start: 7, confirmedAt: 5, timeout: 5 * time.Second, wantIndex: 7},
+		{name: "deadline", start: 1, confirmedAt: 1000, timeout: 50 * time.Millisecond, wantDeadline: true},
+	}
+
+	for _, tt := range tests {
+		api, done := newWaitNode(t, tt.start, tt.confirmedAt)
+		ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+		index, err := api.WaitForConfirmation(ctx, tail, time.Millisecond)
+		cancel()
+		done()
+
+		switch {
+		case tt.wantDeadline && err != context.DeadlineExceeded:
+			t.Errorf("%s: expected DeadlineExceeded, got %v", tt.name, err)
+		case tt.wantDeadline:
+		case err != nil:
+			t.Errorf("%s: %s", tt.name, err)
+		case index != tt.wantIndex:
+			t.Errorf("%s: confirmed by milestone %d, expected %d", tt.name, index, tt.wantIndex)
+		}
+	}
+}